@@ -7,4 +7,9 @@ const (
 	ErrorMessageAttemptedToExportTableToFile   = "table can not be exported to a file"
 	ErrorMessageExpectedFilePathToBeADirectory = "expected file path to be a directory"
 	ErrorMessageExpectedAQueryForAnAlias       = "expected a query to exist for the alias %v, but none was found"
+	ErrorMessageInvalidErrorPolicy             = "expected error policy to be one of the supported error policies: %v"
+	ErrorMessageInvalidIgnorePolicy            = "expected ignore policy to be one of the supported ignore policies: %v"
+	ErrorMessageFilesFromWithoutStdinSource    = "expected the query to read from stdin with the filesFrom flag, for example, select name from stdin"
+	ErrorMessageInvalidContentsSizeLimit       = "expected contents size limit to be a size like 512KiB or 10MB, received %v"
+	WarningMessageSkippedEntries               = "skipped %v entries that could not be read:"
 )
@@ -1,9 +1,9 @@
 package cmd
 
 import (
-	"bytes"
 	"errors"
 	"fmt"
+	"github.com/dustin/go-humanize"
 	"github.com/spf13/cobra"
 	"goselect/parser"
 	"goselect/parser/alias"
@@ -11,27 +11,58 @@ import (
 	"goselect/parser/executor"
 	"goselect/parser/source"
 	"goselect/parser/writer"
+	"io"
 	"os"
 	"strings"
 )
 
+const streamingTablePageSize = 100
+
 func newExecuteCommand() *cobra.Command {
 	return &cobra.Command{
 		Use:     "execute",
 		Aliases: []string{"ex"},
 		Short:   "Execute a select query",
-		Long:    `Execute a select query. Select query syntax: select <attributes> from <source directory> [where <condition>] [order by] [limit]`,
+		Long:    `Execute a select query. Select query syntax: select <attributes> from <source paths> [where <condition>] [group by] [having <condition>] [order by] [limit]`,
 		Example: `
 1. goselect execute -q='select filename, absolutepath from .'
 2. goselect ex -q='select name, size, extension from . where like(name, results.*) order by 2'
 3. goselect ex -q='select name, size, extension from . where or(like(name, results.*), gt(size, 2048)) order by 2 limit 5'
+4. goselect ex -q='select ext, count(), fmtsize(sum(size)) from . group by ext order by 2 desc'
+5. goselect ex -q='select ext, count() from . group by ext having gt(count(), 10)'
+6. goselect ex -q='select name, size from . where size > 1024 and (ext = .log or ext = .txt) order by 2 desc'
+7. goselect ex -q='select fmtsize(size) as hsize, name from . order by size desc, modtime'
+8. goselect ex -q='select ext, count(), fmtsize(sum(size)) from ~/projects group by ext' --parallelism=8
+9. goselect ex -q='select path, depth from . where eq(isdir, true)' --maxDepth=2
+10. goselect ex -q='select path, linktarget, resolvedpath from ./build where issymlink = true' --followSymbolicLinks=true
+11. goselect ex -q='select path, size from ~/projects/goselect where isdir = false' --ignorePolicy=skip
+12. goselect ex -q='select root, name, size from ./src, ./test, ~/logs/**/*.log order by 3 desc'
+13. goselect ex -q='select path, size, compressedsize from ./dist/release.zip'
+14. goselect ex -q='select path, size, lastauthor, lastcommittime from git:v1.0:./src where isdir = false order by 4 desc'
+15. fd -e go -0 | goselect ex -q='select path, size from stdin order by 2 desc limit 10'
+16. goselect ex -q="select path, countmatches(contents, TODO), firstmatch(contents, 'TODO\(.*\)') from ./src where grep(contents, TODO)"
+17. goselect ex -q="select path, lines, words, maxlinelength from ./src order by 2 desc limit 10"
+18. goselect ex -q="select count(), sum(size), min(path) from ./artifacts group by sha256 order by 2 desc" --duplicates
+19. goselect ex -q="select path, inode, nlink, birthtime from . where gt(nlink, 1) order by inode"
+20. goselect ex -q="select path, capabilities, xattr(path, 'user.origin') from /usr/bin where or(issetuid, gt(len(capabilities), 0))"
+21. goselect ex -q="select path, imagewidth, imageheight, exif_model from ~/Photos where and(gt(imagewidth, 4000), exif_hasgps) order by exif_datetime desc"
+22. goselect ex -q="select path, duration, videocodec, audiocodec from ./uploads where or(gt(duration, 600), ne(videocodec, h264)) order by duration desc"
+23. goselect ex -q="select path, arch, goversion, gomodule from ./dist where and(gt(len(goversion), 0), or(not(isstripped), ne(arch, amd64)))"
 `,
 		Run: func(cmd *cobra.Command, args []string) {
 			errorColor := "\033[31m"
 
-			buildOptions := func() *executor.Options {
+			buildOptions := func() (*executor.Options, error) {
 				nestedTraversal, _ := cmd.Flags().GetBool("nestedTraversal")
+				followSymbolicLinks, _ := cmd.Flags().GetBool("followSymbolicLinks")
+				archiveTraversal, _ := cmd.Flags().GetBool("archiveTraversal")
 				ignoreTraversal, _ := cmd.Flags().GetStringSlice("skipDirectoryTraversal")
+				parallelism, _ := cmd.Flags().GetUint16("parallelism")
+				errorPolicy, _ := cmd.Flags().GetString("errorPolicy")
+				ignorePolicy, _ := cmd.Flags().GetString("ignorePolicy")
+				minDepth, _ := cmd.Flags().GetUint16("minDepth")
+				maxDepth, _ := cmd.Flags().GetUint16("maxDepth")
+				duplicates, _ := cmd.Flags().GetBool("duplicates")
 
 				options := executor.NewDefaultOptions()
 				if nestedTraversal {
@@ -39,27 +70,86 @@ func newExecuteCommand() *cobra.Command {
 				} else {
 					options.DisableNestedTraversal()
 				}
+				if followSymbolicLinks {
+					options.EnableSymbolicLinkTraversal()
+				} else {
+					options.DisableSymbolicLinkTraversal()
+				}
+				if archiveTraversal {
+					options.EnableArchiveTraversal()
+				} else {
+					options.DisableArchiveTraversal()
+				}
+				if duplicates {
+					options.EnableDuplicates()
+				} else {
+					options.DisableDuplicates()
+				}
 				options.DirectoriesToIgnoreTraversal(ignoreTraversal)
-				return options
+				options.WithParallelism(int(parallelism))
+				options.WithMinDepth(int(minDepth)).WithMaxDepth(int(maxDepth))
+				switch strings.ToLower(errorPolicy) {
+				case "fail":
+					options.WithErrorPolicy(executor.ErrorPolicyFailFast)
+				case "skip":
+					options.WithErrorPolicy(executor.ErrorPolicySkip)
+				case "collect":
+					options.WithErrorPolicy(executor.ErrorPolicyCollect)
+				default:
+					return nil, fmt.Errorf(ErrorMessageInvalidErrorPolicy, SupportedErrorPolicies())
+				}
+				switch strings.ToLower(ignorePolicy) {
+				case "none":
+					options.WithIgnorePolicy(executor.IgnorePolicyNone)
+				case "skip":
+					options.WithIgnorePolicy(executor.IgnorePolicySkip)
+				case "mark":
+					options.WithIgnorePolicy(executor.IgnorePolicyMark)
+				default:
+					return nil, fmt.Errorf(ErrorMessageInvalidIgnorePolicy, SupportedIgnorePolicies())
+				}
+				return options, nil
 			}
-			executeQuery := func(cmd *cobra.Command) (*executor.EvaluatingRows, *parser.SelectQuery, error) {
+			executeQuery := func(cmd *cobra.Command, fileList io.Reader) (executor.Rows, *parser.SelectQuery, *executor.SelectQueryExecutor, error) {
 				rawQuery, _ := cmd.Flags().GetString("query")
-				newContext := context.NewContext(context.NewFunctions(), context.NewAttributes())
+				contentsSizeLimit, _ := cmd.Flags().GetString("contentsSizeLimit")
+				sizeLimit, err := humanize.ParseBytes(contentsSizeLimit)
+				if err != nil {
+					return nil, nil, nil, fmt.Errorf(ErrorMessageInvalidContentsSizeLimit, contentsSizeLimit)
+				}
+				newContext := context.NewContext(context.NewFunctions(), context.NewAttributes()).
+					WithFileList(fileList).
+					WithContentsSizeLimit(int64(sizeLimit))
 				newParser, err := parser.NewParser(rawQuery, newContext)
 				if err != nil {
-					return nil, nil, err
+					return nil, nil, nil, err
 				}
 				query, err := newParser.Parse()
 				if err != nil {
-					return nil, nil, err
+					return nil, nil, nil, err
+				}
+				if filesFrom, _ := cmd.Flags().GetString("filesFrom"); len(filesFrom) != 0 && !readsFileList(query) {
+					return nil, nil, nil, errors.New(ErrorMessageFilesFromWithoutStdinSource)
+				}
+				options, err := buildOptions()
+				if err != nil {
+					return nil, nil, nil, err
 				}
-				rows, err := executor.NewSelectQueryExecutor(query, newContext, buildOptions()).Execute()
+				queryExecutor := executor.NewSelectQueryExecutor(query, newContext, options)
+				if queryExecutor.IsStreamable() {
+					rows, err := queryExecutor.ExecuteStreaming()
+					if err != nil {
+						return nil, nil, nil, err
+					}
+					return rows, query, queryExecutor, nil
+				}
+				rows, err := queryExecutor.Execute()
 				if err != nil {
-					return nil, nil, err
+					return nil, nil, nil, err
 				}
-				return rows, query, nil
+				return rows, query, queryExecutor, nil
 			}
-			formatter := func(cmd *cobra.Command) (writer.Formatter, string, error) {
+			formatter := func(cmd *cobra.Command, isStreaming bool) (writer.Formatter, string, error) {
 				exportFormat, _ := cmd.Flags().GetString("format")
 				switch strings.ToLower(exportFormat) {
 				case "json":
@@ -70,35 +160,36 @@ func newExecuteCommand() *cobra.Command {
 					minWidth, _ := cmd.Flags().GetUint16("minWidth")
 					maxWidth, _ := cmd.Flags().GetUint16("maxWidth")
 
-					if minWidth == 0 && maxWidth == 0 {
-						return writer.NewTableFormatter(), strings.ToLower(exportFormat), nil
-					}
+					tableFormatter := writer.NewTableFormatter()
 					if minWidth == 0 && maxWidth != 0 {
-						return writer.NewTableFormatterWithWidthOptions(writer.NewAttributeWidthOptions(
+						tableFormatter = writer.NewTableFormatterWithWidthOptions(writer.NewAttributeWidthOptions(
 							writer.UnspecifiedMinWidth,
 							int(maxWidth),
-						)), strings.ToLower(exportFormat), nil
+						))
 					}
 					if minWidth != 0 && maxWidth == 0 {
-						return writer.NewTableFormatterWithWidthOptions(writer.NewAttributeWidthOptions(
+						tableFormatter = writer.NewTableFormatterWithWidthOptions(writer.NewAttributeWidthOptions(
 							int(minWidth),
 							writer.UnspecifiedMaxWidth,
-						)), strings.ToLower(exportFormat), nil
+						))
 					}
-					return writer.NewTableFormatterWithWidthOptions(
-						writer.NewAttributeWidthOptions(int(minWidth), int(maxWidth)),
-					), strings.ToLower(exportFormat), nil
+					if minWidth != 0 && maxWidth != 0 {
+						tableFormatter = writer.NewTableFormatterWithWidthOptions(
+							writer.NewAttributeWidthOptions(int(minWidth), int(maxWidth)),
+						)
+					}
+					if isStreaming {
+						tableFormatter.WithPageSize(streamingTablePageSize)
+					}
+					return tableFormatter, strings.ToLower(exportFormat), nil
 				default:
 					return nil, "", fmt.Errorf(ErrorMessageInvalidExportFormat, SupportedExportFormats())
 				}
 			}
-			writeToConsole := func(formattedResult string) {
-				buffer := new(bytes.Buffer)
-				consoleWriter := writer.NewWriter(buffer)
-				_ = consoleWriter.Write(formattedResult)
-				cmd.Print(buffer.String())
+			writeToConsole := func(formatTo func(writer io.Writer) error) error {
+				return writer.NewWriter(cmd.OutOrStderr()).WriteUsing(formatTo)
 			}
-			writeToFile := func(format, formattedResult string) error {
+			writeToFile := func(format string, formatTo func(writer io.Writer) error) error {
 				directoryPath, _ := cmd.Flags().GetString("path")
 				if strings.EqualFold(format, "table") {
 					return errors.New(ErrorMessageAttemptedToExportTableToFile)
@@ -122,16 +213,15 @@ func newExecuteCommand() *cobra.Command {
 					if err != nil {
 						return err
 					}
-					return fileWriter.Write(formattedResult)
+					return fileWriter.WriteUsing(formatTo)
 				}
 			}
-			write := func(format string, formattedResult string) error {
+			write := func(format string, formatTo func(writer io.Writer) error) error {
 				directoryPath, _ := cmd.Flags().GetString("path")
 				if len(directoryPath) == 0 {
-					writeToConsole(formattedResult)
-					return nil
+					return writeToConsole(formatTo)
 				}
-				return writeToFile(format, formattedResult)
+				return writeToFile(format, formatTo)
 			}
 			run := func() {
 				queryAliasReference := alias.NewQueryAlias()
@@ -148,21 +238,57 @@ func newExecuteCommand() *cobra.Command {
 					}
 					_ = cmd.Flags().Set("query", query)
 				}
-				rows, query, err := executeQuery(cmd)
+				fileList := cmd.InOrStdin()
+				if filesFrom, _ := cmd.Flags().GetString("filesFrom"); len(filesFrom) != 0 {
+					filesFrom, err := source.ExpandDirectoryPath(filesFrom)
+					if err != nil {
+						cmd.Println(errorColor, err)
+						return
+					}
+					file, err := os.Open(filesFrom)
+					if err != nil {
+						cmd.Println(errorColor, err)
+						return
+					}
+					defer file.Close()
+					fileList = file
+				}
+				rows, query, queryExecutor, err := executeQuery(cmd, fileList)
 				if err != nil {
 					cmd.Println(errorColor, err)
 					return
 				}
-				exportFormatter, format, err := formatter(cmd)
+				streamingRows, isStreaming := rows.(*executor.StreamingRows)
+				if isStreaming {
+					defer streamingRows.Close()
+				}
+				exportFormatter, format, err := formatter(cmd, isStreaming)
 				if err != nil {
 					cmd.Println(errorColor, err)
 					return
 				}
-				err = write(format, exportFormatter.Format(query.Projections, rows))
+				err = write(format, func(writer io.Writer) error {
+					return exportFormatter.FormatTo(writer, query.Projections, rows)
+				})
 				if err != nil {
 					cmd.Println(errorColor, err)
 					return
 				}
+				if isStreaming {
+					streamingRows.Close()
+					if err := streamingRows.Err(); err != nil {
+						cmd.Println(errorColor, err)
+						return
+					}
+				}
+				if errorPolicy, _ := cmd.Flags().GetString("errorPolicy"); strings.EqualFold(errorPolicy, "skip") {
+					if skippedEntries := queryExecutor.SkippedEntries(); len(skippedEntries) > 0 {
+						cmd.PrintErrln(fmt.Sprintf(WarningMessageSkippedEntries, len(skippedEntries)))
+						for _, skippedEntry := range skippedEntries {
+							cmd.PrintErrln(fmt.Sprintf("%v: %v", skippedEntry.Path, skippedEntry.Err))
+						}
+					}
+				}
 				queryAlias, _ := cmd.Flags().GetString("createAlias")
 				if len(strings.TrimSpace(queryAlias)) != 0 {
 					query, _ := cmd.Flags().GetString("query")
@@ -177,10 +303,27 @@ func newExecuteCommand() *cobra.Command {
 	}
 }
 
+func readsFileList(query *parser.SelectQuery) bool {
+	for _, root := range query.Source.Roots {
+		if root.IsFileList() {
+			return true
+		}
+	}
+	return false
+}
+
 func SupportedExportFormats() []string {
 	return []string{"json", "html", "table"}
 }
 
+func SupportedErrorPolicies() []string {
+	return []string{"fail", "skip", "collect"}
+}
+
+func SupportedIgnorePolicies() []string {
+	return []string{"none", "skip", "mark"}
+}
+
 func init() {
 	executeCmd := newExecuteCommand()
 	rootCmd.AddCommand(executeCmd)
@@ -196,12 +339,67 @@ func init() {
 		true,
 		"specify if nested directories should be traversed. Use --nestedTraversal=<true/false> or -n=<true/false>",
 	)
+	executeCmd.PersistentFlags().BoolP(
+		"followSymbolicLinks",
+		"L",
+		false,
+		"specify if the symbolic links to directories should be traversed. A symbolic link that points to one of its parent directories is not traversed and has the 'error' attribute. Use --followSymbolicLinks=<true/false> or -L=<true/false>",
+	)
+	executeCmd.PersistentFlags().BoolP(
+		"archiveTraversal",
+		"a",
+		false,
+		"specify if the zip and tar archives found during the traversal should be traversed as directories. Use --archiveTraversal=<true/false> or -a=<true/false>",
+	)
 	executeCmd.PersistentFlags().StringSliceP(
 		"skipDirectoryTraversal",
 		"s",
 		[]string{".git", ".github"},
 		"specify the directory names that should not be traversed. Use --skipDirectoryTraversal=<directory> or -s=<directory>. Multiple directory names can be passed by using --skipDirectoryTraversal=.git --skipDirectoryTraversal=.github",
 	)
+	executeCmd.PersistentFlags().Uint16P(
+		"parallelism",
+		"l",
+		1,
+		"specify the number of workers that read the directories and evaluate the rows concurrently. The results are identical to a sequential run, except that a limit without order by may return a different set of files. Use --parallelism=<value greater than zero>",
+	)
+	executeCmd.PersistentFlags().StringP(
+		"errorPolicy",
+		"e",
+		"fail",
+		"specify the behavior on the files or directories that can not be read. Supported values include: fail, skip and collect. 'fail' stops the query, 'skip' skips the entries and reports them at the end, 'collect' keeps the entries in the results with the 'error' attribute. Use --errorPolicy=<policy>",
+	)
+	executeCmd.PersistentFlags().StringP(
+		"ignorePolicy",
+		"i",
+		"none",
+		"specify the behavior on the files ignored by the '.gitignore', '.ignore' and '.git/info/exclude' files. Supported values include: none, skip and mark. 'none' does not read the ignore files, 'skip' skips the ignored files and directories, 'mark' keeps them in the results with the 'isignored' attribute set to true. Use --ignorePolicy=<policy>",
+	)
+	executeCmd.PersistentFlags().Uint16(
+		"minDepth",
+		0,
+		"specify the minimum depth of the files to be included in the results. The files directly inside the source directory have a depth of 1. Use --minDepth=<value greater than zero>",
+	)
+	executeCmd.PersistentFlags().Uint16(
+		"maxDepth",
+		0,
+		"specify the maximum depth of the directories to be traversed. The files directly inside the source directory have a depth of 1, and 0 traverses all the directories. Use --maxDepth=<value greater than zero>",
+	)
+	executeCmd.PersistentFlags().Bool(
+		"duplicates",
+		false,
+		"specify if only the duplicate files should be returned, the files that have the same contents as at least one other file. The files are compared by size first, and only the files with the same size are hashed using sha256. Use --duplicates=<true/false>",
+	)
+	executeCmd.PersistentFlags().String(
+		"filesFrom",
+		"",
+		"specify a file with the newline or NUL separated paths to be read by the 'stdin' source instead of the standard input. The paths are not walked, and the query must read from stdin. Use --filesFrom=<filePath>",
+	)
+	executeCmd.PersistentFlags().String(
+		"contentsSizeLimit",
+		"1 MiB",
+		"specify the maximum size of a file that the contents attribute reads, the rest of the file is left out. The functions grep, countmatches and firstmatch read the whole file. Use --contentsSizeLimit=<size>, for example, --contentsSizeLimit=10MiB",
+	)
 	executeCmd.PersistentFlags().StringP(
 		"format",
 		"f",
@@ -15,7 +15,7 @@ var rootCmd = &cobra.Command{
 3. goselect ex -q='select name, size, extension from . where or(like(name, results.*), gt(size, 2048)) order by 2 limit 5'
 `,
 	Long: `goselect provides SQL like 'select' interface for file systems. 
-The syntax for select query is: select <attributes> from <directory> [where condition] [order by] [limit].
+The syntax for select query is: select <attributes> from <directory> [where condition] [group by] [having] [order by] [limit].
 Queries are case-insensitive in nature. 
 
 goselect provides various features including:
@@ -23,14 +23,34 @@ goselect provides various features including:
 2. Support for function aliases. For example, lower is same as low 
 3. Support for various scalar functions like lower, upper, now, concat etc
 4. Support for various aggregate functions like count, countdistinct, average etc
-5. Support for exporting the results in table, json and html format
+5. Support for grouping the results using 'group by'. For example, select ext, count() from . group by ext
+6. Support for filtering the groups using 'having'. For example, select ext, count() from . group by ext having gt(count(), 10)
+7. Support for exporting the results in table, json and html format
+8. Support for column aliases using 'as'. For example, select fmtsize(size) as hsize, name from . order by hsize desc
+9. Support for operators like + - * / = != < <= > >= like and or not along with the functions. For example, select name from . where size > 1024 and ext = .log
+10. Support for concurrent directory traversal using the parallelism flag. For example, goselect ex -q='select ext, count() from . group by ext' --parallelism=8
+11. Support for streaming the results of the queries without order by, group by, having and aggregate functions
+12. Support for tolerating the unreadable files and directories using the errorPolicy flag. For example, goselect ex -q='select path, error from /var where like(error, .+)' --errorPolicy=collect
+13. Support for limiting the depth of the traversal using the minDepth and maxDepth flags, and the depth attribute. For example, goselect ex -q='select path, depth from .' --maxDepth=2
+14. Support for following the symbolic links to directories using the followSymbolicLinks flag. For example, goselect ex -q='select path, linktarget, resolvedpath from ./build' -L=true
+15. Support for skipping or marking the files ignored by .gitignore, .ignore and .git/info/exclude using the ignorePolicy flag. For example, goselect ex -q='select path from .' --ignorePolicy=skip
+16. Support for multiple sources, glob patterns and files in the from clause. For example, goselect ex -q='select root, name from ./src, ./test, ~/logs/**/*.log'
+17. Support for querying the entries of zip and tar archives, and traversing the archives using the archiveTraversal flag. For example, goselect ex -q='select name, size, compressedsize from ./release.zip'
+18. Support for querying the files at a git revision using the git:<revision>:<path> source. For example, goselect ex -q='select path, githash, lastcommittime from git:HEAD~10:./src'
+19. Support for reading the files from the standard input using the stdin source, or from a file using the filesFrom flag. For example, git diff --name-only | goselect ex -q='select path, size from stdin'
+20. Support for searching inside the files using the contents attribute and the grep, countmatches and firstmatch functions. For example, goselect ex -q="select path from . where grep(contents, 'TODO\(.*\)')"
+21. Support for wc style statistics using the lines, words, chars and maxlinelength attributes. For example, goselect ex -q="select ext, sum(lines) from . where eq(isdir, false) group by ext"
+22. Support for the md5, sha1, sha256 and xxhash attributes, and for finding the duplicate files using the duplicates flag. For example, goselect ex -q="select sha256, path from . order by 1" --duplicates
+23. Support for the inode, device, fileid, nlink, rdev, changetime and birthtime attributes, and hard link aware sizes using uniquesum. For example, goselect ex -q="select uniquesum(size, fileid) from ."
+24. Support for the issetuid, issetgid, issticky, xattrs, hasacl and capabilities attributes, and the xattr function. For example, goselect ex -q="select path from / where issetuid"
+25. Support for the image attributes imagewidth, imageheight and imageformat, and the EXIF attributes exif_datetime, exif_make, exif_model, exif_orientation and exif_hasgps. For example, goselect ex -q="select path from ~/Photos where gt(imagewidth, 4000) order by exif_datetime"
+26. Support for the audio and video attributes mediaformat, duration, bitrate, samplerate, channels, videowidth, videoheight, videocodec and audiocodec. For example, goselect ex -q="select path, duration from . where gt(duration, 600)"
+27. Support for the binary attributes binarytype, architecture, isstripped, isstatic, dynamiclibs, goversion and gomodule of ELF, Mach-O and PE executables. For example, goselect ex -q="select path, arch, goversion from ./dist where not(isstripped)"
 
 Features that are different from SQL:
-1. goselect does not support 'group by'. All the aggregating functions return results that repeat for each row
-2. goselect does not support expressions like 1+2 or 1*2. Instead, goselect gives functions like 'add' and 'mul' etc to write such expressions
-3. goselect does not support expressions like name=sample.log in 'where' clause. Instead, various functions are given to represent such expressions. These functions include: 'eq', 'ne', 'lt' etc
-4. goselect has a weak grammar. For example, a query like: select 1+2, name from /home/projects will ignore 1+2 and return file names
-5. goselect's 'order by' clause supports only attribute positions. For example, a query like: select name, size from /home/projects order by 1 will order the results by first attribute
+1. goselect needs the arithmetic operators to be separated by a space. For example, select 1 + 2, name from /home/projects works, whereas 1+2 is treated as a value
+2. goselect's 'order by' clause supports attribute positions, aliases, attributes, functions and expressions. For example, a query like: select name from /home/projects order by size desc will order the results by size and display only name
+3. Without 'group by', all the aggregating functions return results that repeat for each row. With 'group by', the attributes that are neither grouped nor aggregated return the value of the first row of the group
 
 goselect is available here: https://github.com/SarthakMakhija/goselect
 `,
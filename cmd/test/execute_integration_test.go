@@ -205,6 +205,52 @@ func TestExecutesWithInvalidExportFormat(t *testing.T) {
 	}
 }
 
+func TestExecutesWithInvalidErrorPolicy(t *testing.T) {
+	cmd.GetRootCommand().SetArgs([]string{"execute", "--query", "select name from ./resources/log/ order by 1", "--errorPolicy", "unknown"})
+	buffer := new(bytes.Buffer)
+	cmd.GetRootCommand().SetOut(buffer)
+	defer func() {
+		executeCommand, _, _ := cmd.GetRootCommand().Find([]string{"execute"})
+		_ = executeCommand.PersistentFlags().Set("errorPolicy", "fail")
+	}()
+
+	_ = cmd.GetRootCommand().Execute()
+
+	contents := buffer.String()
+	expected := fmt.Sprintf(cmd.ErrorMessageInvalidErrorPolicy, cmd.SupportedErrorPolicies())
+
+	if !strings.Contains(contents, expected) {
+		t.Fatalf(
+			"Expected an error %v while trying to execute with an unknown error policy but received %v",
+			expected,
+			contents,
+		)
+	}
+}
+
+func TestExecutesWithInvalidIgnorePolicy(t *testing.T) {
+	cmd.GetRootCommand().SetArgs([]string{"execute", "--query", "select name from ./resources/log/ order by 1", "--ignorePolicy", "unknown"})
+	buffer := new(bytes.Buffer)
+	cmd.GetRootCommand().SetOut(buffer)
+	defer func() {
+		executeCommand, _, _ := cmd.GetRootCommand().Find([]string{"execute"})
+		_ = executeCommand.PersistentFlags().Set("ignorePolicy", "none")
+	}()
+
+	_ = cmd.GetRootCommand().Execute()
+
+	contents := buffer.String()
+	expected := fmt.Sprintf(cmd.ErrorMessageInvalidIgnorePolicy, cmd.SupportedIgnorePolicies())
+
+	if !strings.Contains(contents, expected) {
+		t.Fatalf(
+			"Expected an error %v while trying to execute with an unknown ignore policy but received %v",
+			expected,
+			contents,
+		)
+	}
+}
+
 func TestExecutesWithJsonExport(t *testing.T) {
 	cmd.GetRootCommand().SetArgs([]string{"execute", "--query", "select name from ./resources/log/ order by 1", "-f", "json"})
 	buffer := new(bytes.Buffer)
@@ -247,6 +293,147 @@ func TestExecutesWithHtmlExport(t *testing.T) {
 	}
 }
 
+func TestExecutesAStreamingQueryWithJsonExport(t *testing.T) {
+	cmd.GetRootCommand().SetArgs([]string{"execute", "--query", "select name from ./resources/log/ where eq(isdir, false)", "-f", "json"})
+	buffer := new(bytes.Buffer)
+	cmd.GetRootCommand().SetOut(buffer)
+
+	_ = cmd.GetRootCommand().Execute()
+
+	contents := buffer.String()
+	expected := []string{"TestResultsWithProjections_A.log", "TestResultsWithProjections_B.log", "TestResultsWithProjections_C.txt"}
+
+	for _, name := range expected {
+		if !strings.Contains(contents, name) {
+			t.Fatalf(
+				"Expected file name %v to be contained in the json result but was not, received %v",
+				name,
+				contents,
+			)
+		}
+	}
+}
+
+func TestExecutesAQueryWithFilesFromStandardInput(t *testing.T) {
+	cmd.GetRootCommand().SetArgs([]string{"execute", "--query", "select name from stdin where ext = .log", "-f", "json"})
+	cmd.GetRootCommand().SetIn(strings.NewReader("./resources/log/TestResultsWithProjections_A.log\n./resources/log/TestResultsWithProjections_C.txt\n"))
+	buffer := new(bytes.Buffer)
+	cmd.GetRootCommand().SetOut(buffer)
+	defer cmd.GetRootCommand().SetIn(nil)
+
+	_ = cmd.GetRootCommand().Execute()
+
+	contents := buffer.String()
+	if !strings.Contains(contents, "TestResultsWithProjections_A.log") || strings.Contains(contents, "TestResultsWithProjections_C.txt") {
+		t.Fatalf("Expected only the log file from the standard input to be contained in the result, received %v", contents)
+	}
+}
+
+func TestExecutesAQueryWithABareFileNameFromStandardInput(t *testing.T) {
+	cmd.GetRootCommand().SetArgs([]string{"execute", "--query", "select path from stdin", "-f", "json"})
+	cmd.GetRootCommand().SetIn(strings.NewReader("version_integration_test.go\n"))
+	buffer := new(bytes.Buffer)
+	cmd.GetRootCommand().SetOut(buffer)
+	defer cmd.GetRootCommand().SetIn(nil)
+
+	_ = cmd.GetRootCommand().Execute()
+
+	contents := buffer.String()
+	expected := `[{"path" : "version_integration_test.go"}]`
+	if !strings.Contains(contents, expected) {
+		t.Fatalf("Expected %v to be contained in the result but was not, received %v", expected, contents)
+	}
+}
+
+func TestExecutesAQueryWithFilesFromAFile(t *testing.T) {
+	fileList := t.TempDir() + string(os.PathSeparator) + "files.txt"
+	content := "./resources/log/TestResultsWithProjections_B.log\x00./resources/log\x00"
+	if err := os.WriteFile(fileList, []byte(content), 0644); err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	cmd.GetRootCommand().SetArgs([]string{"execute", "--query", "select name from stdin order by 1", "--filesFrom", fileList, "-f", "json"})
+	buffer := new(bytes.Buffer)
+	cmd.GetRootCommand().SetOut(buffer)
+	defer func() {
+		executeCommand, _, _ := cmd.GetRootCommand().Find([]string{"execute"})
+		_ = executeCommand.PersistentFlags().Set("filesFrom", "")
+	}()
+
+	_ = cmd.GetRootCommand().Execute()
+
+	contents := buffer.String()
+	if !strings.Contains(contents, "TestResultsWithProjections_B.log") || !strings.Contains(contents, "\"log\"") ||
+		strings.Contains(contents, "TestResultsWithProjections_A.log") {
+		t.Fatalf("Expected only the files in the file list to be contained in the result, received %v", contents)
+	}
+}
+
+func TestExecutesAQueryWithFilesFromWithoutStandardInputAsTheSource(t *testing.T) {
+	cmd.GetRootCommand().SetArgs([]string{"execute", "--query", "select name from ./resources/log", "--filesFrom", "./resources/log/TestResultsWithProjections_A.log"})
+	buffer := new(bytes.Buffer)
+	cmd.GetRootCommand().SetOut(buffer)
+	defer func() {
+		executeCommand, _, _ := cmd.GetRootCommand().Find([]string{"execute"})
+		_ = executeCommand.PersistentFlags().Set("filesFrom", "")
+	}()
+
+	_ = cmd.GetRootCommand().Execute()
+
+	contents := buffer.String()
+	if !strings.Contains(contents, cmd.ErrorMessageFilesFromWithoutStdinSource) {
+		t.Fatalf("Expected an error %v while using filesFrom without stdin but received %v", cmd.ErrorMessageFilesFromWithoutStdinSource, contents)
+	}
+}
+
+func TestExecutesAQueryWithDuplicates(t *testing.T) {
+	cmd.GetRootCommand().SetArgs([]string{"execute", "--query", "select name from ./resources/log order by 1", "--duplicates", "-f", "json"})
+	buffer := new(bytes.Buffer)
+	cmd.GetRootCommand().SetOut(buffer)
+	defer func() {
+		executeCommand, _, _ := cmd.GetRootCommand().Find([]string{"execute"})
+		_ = executeCommand.PersistentFlags().Set("duplicates", "false")
+	}()
+
+	_ = cmd.GetRootCommand().Execute()
+
+	contents := buffer.String()
+	expected := `[{"name" : "TestResultsWithProjections_B.log"}, {"name" : "TestResultsWithProjections_C.txt"}]`
+
+	if !strings.Contains(contents, expected) {
+		t.Fatalf("Expected %v to be contained in the result but was not, received %v", expected, contents)
+	}
+}
+
+func TestExecutesAQueryWithGroupBy(t *testing.T) {
+	cmd.GetRootCommand().SetArgs([]string{"execute", "--query", "select ext, count() from ./resources/log group by ext order by 1", "--format=json"})
+	buffer := new(bytes.Buffer)
+	cmd.GetRootCommand().SetOut(buffer)
+
+	_ = cmd.GetRootCommand().Execute()
+
+	contents := buffer.String()
+	expected := `[{"ext" : ".log", "count()" : "2"}, {"ext" : ".txt", "count()" : "1"}]`
+
+	if !strings.Contains(contents, expected) {
+		t.Fatalf("Expected %v to be contained in the result but was not, received %v", expected, contents)
+	}
+}
+
+func TestExecutesAQueryWithAliasesInJsonHeader(t *testing.T) {
+	cmd.GetRootCommand().SetArgs([]string{"execute", "--query", "select ext as extension, count() as total from ./resources/log group by ext order by total desc, extension", "--format=json"})
+	buffer := new(bytes.Buffer)
+	cmd.GetRootCommand().SetOut(buffer)
+
+	_ = cmd.GetRootCommand().Execute()
+
+	contents := buffer.String()
+	expected := `[{"extension" : ".log", "total" : "2"}, {"extension" : ".txt", "total" : "1"}]`
+
+	if !strings.Contains(contents, expected) {
+		t.Fatalf("Expected %v to be contained in the result but was not, received %v", expected, contents)
+	}
+}
+
 func TestAttemptsToExecuteWithTableFormatExportToAFile(t *testing.T) {
 	cmd.GetRootCommand().SetArgs([]string{"execute", "--query", "select name from ./resources/log/ order by 1", "-f", "table", "-p", "."})
 	buffer := new(bytes.Buffer)
@@ -1,6 +1,6 @@
 module goselect
 
-go 1.17
+go 1.18
 
 require (
 	github.com/dustin/go-humanize v1.0.0
@@ -8,6 +8,7 @@ require (
 	github.com/ivanpirog/coloredcobra v1.0.1
 	github.com/jedib0t/go-pretty/v6 v6.3.8
 	github.com/spf13/cobra v1.5.0
+	golang.org/x/sys v0.0.0-20220909162455-aba9fc2a8ff2
 	golang.org/x/text v0.3.7
 )
 
@@ -20,5 +21,4 @@ require (
 	github.com/rivo/uniseg v0.2.0 // indirect
 	github.com/spf13/pflag v1.0.5 // indirect
 	golang.org/x/net v0.0.0-20220624214902-1bab6f366d9e // indirect
-	golang.org/x/sys v0.0.0-20220909162455-aba9fc2a8ff2 // indirect
 )
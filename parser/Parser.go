@@ -4,6 +4,8 @@ import (
 	"errors"
 	"goselect/parser/context"
 	"goselect/parser/error/messages"
+	"goselect/parser/group"
+	"goselect/parser/having"
 	"goselect/parser/limit"
 	"goselect/parser/order"
 	"goselect/parser/projection"
@@ -19,12 +21,22 @@ type SelectQuery struct {
 	Order       *order.Order
 	Limit       *limit.Limit
 	Where       *where.Where
+	Group       *group.Group
+	Having      *having.Having
 }
 
 func (selectQuery *SelectQuery) IsLimitDefined() bool {
 	return selectQuery.Limit != nil
 }
 
+func (selectQuery *SelectQuery) IsGroupDefined() bool {
+	return selectQuery.Group != nil
+}
+
+func (selectQuery *SelectQuery) IsHavingDefined() bool {
+	return selectQuery.Having != nil
+}
+
 func (selectQuery *SelectQuery) IsOrderDefined() bool {
 	return selectQuery.Order != nil
 }
@@ -52,18 +64,31 @@ func (parser *Parser) Parse() (*SelectQuery, error) {
 	if err != nil {
 		return nil, err
 	}
-	fileSource, err := source.NewSource(iterator)
+	fileSource, err := source.NewSource(iterator, parser.context.FileSystem())
 	if err != nil {
 		return nil, err
 	}
-	if iterator.HasNext() && !iterator.Peek().Equals("where") && !iterator.Peek().Equals("order") && !iterator.Peek().Equals("limit") {
+	if iterator.HasNext() &&
+		!iterator.Peek().Equals("where") &&
+		!iterator.Peek().Equals("group") &&
+		!iterator.Peek().Equals("having") &&
+		!iterator.Peek().Equals("order") &&
+		!iterator.Peek().Equals("limit") {
 		return nil, errors.New(messages.ErrorMessageInvalidKeywordAfterFrom)
 	}
 	whereClause, err := where.NewWhere(iterator, parser.context)
 	if err != nil {
 		return nil, err
 	}
-	orderBy, err := order.NewOrder(iterator, projections.Count())
+	groupBy, err := group.NewGroup(iterator, projections, parser.context)
+	if err != nil {
+		return nil, err
+	}
+	havingClause, err := having.NewHaving(iterator, groupBy != nil, parser.context)
+	if err != nil {
+		return nil, err
+	}
+	orderBy, err := order.NewOrder(iterator, projections, parser.context)
 	if err != nil {
 		return nil, err
 	}
@@ -76,6 +101,8 @@ func (parser *Parser) Parse() (*SelectQuery, error) {
 		Projections: projections,
 		Source:      fileSource,
 		Where:       whereClause,
+		Group:       groupBy,
+		Having:      havingClause,
 		Order:       orderBy,
 		Limit:       limitResults,
 	}, nil
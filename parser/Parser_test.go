@@ -4,6 +4,8 @@
 package parser
 
 import (
+	"goselect/parser/group"
+	"goselect/parser/having"
 	"goselect/parser/limit"
 	"goselect/parser/order"
 	"testing"
@@ -44,3 +46,39 @@ func TestSelectQueryWithLimitDefined(t *testing.T) {
 		t.Fatalf("Expected limit to be defined but was not")
 	}
 }
+
+func TestSelectQueryWithGroupByNotDefined(t *testing.T) {
+	query := SelectQuery{Group: nil}
+	groupDefined := query.IsGroupDefined()
+
+	if groupDefined != false {
+		t.Fatalf("Expected group by to be undefined but was defined")
+	}
+}
+
+func TestSelectQueryWithGroupByDefined(t *testing.T) {
+	query := SelectQuery{Group: &group.Group{}}
+	groupDefined := query.IsGroupDefined()
+
+	if groupDefined != true {
+		t.Fatalf("Expected group by to be defined but was not")
+	}
+}
+
+func TestSelectQueryWithHavingNotDefined(t *testing.T) {
+	query := SelectQuery{Having: nil}
+	havingDefined := query.IsHavingDefined()
+
+	if havingDefined != false {
+		t.Fatalf("Expected having to be undefined but was defined")
+	}
+}
+
+func TestSelectQueryWithHavingDefined(t *testing.T) {
+	query := SelectQuery{Having: &having.Having{}}
+	havingDefined := query.IsHavingDefined()
+
+	if havingDefined != true {
+		t.Fatalf("Expected having to be defined but was not")
+	}
+}
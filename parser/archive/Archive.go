@@ -0,0 +1,158 @@
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/bzip2"
+	"compress/gzip"
+	"goselect/parser/filesystem"
+	"io"
+	"io/fs"
+	"strings"
+)
+
+const UnknownCompressedSize int64 = -1
+
+type Entry struct {
+	Path           string
+	Info           fs.FileInfo
+	CompressedSize int64
+	IsEmpty        bool
+}
+
+type format struct {
+	extensions  []string
+	readEntries func(fileSystem filesystem.FileSystem, path string) ([]*Entry, error)
+}
+
+var formats = []format{
+	{extensions: []string{".zip", ".jar"}, readEntries: readZip},
+	{extensions: []string{".tar"}, readEntries: readTarWith(nil)},
+	{extensions: []string{".tar.gz", ".tgz"}, readEntries: readTarWith(func(reader io.Reader) (io.Reader, error) {
+		return gzip.NewReader(reader)
+	})},
+	{extensions: []string{".tar.bz2", ".tbz2"}, readEntries: readTarWith(func(reader io.Reader) (io.Reader, error) {
+		return bzip2.NewReader(reader), nil
+	})},
+}
+
+func IsArchive(path string) bool {
+	_, ok := formatOf(path)
+	return ok
+}
+
+/*
+ReadEntries reads the headers of all the entries in a zip or a tar archive, without extracting the contents.
+The path of an entry is relative to the archive and uses '/' as the separator.
+A tar archive compressed as a whole does not store the compressed size of an entry, so the compressed size is
+the size for an uncompressed tar archive and UnknownCompressedSize for a compressed one.
+*/
+func ReadEntries(fileSystem filesystem.FileSystem, path string) ([]*Entry, error) {
+	format, ok := formatOf(path)
+	if !ok {
+		return nil, nil
+	}
+	entries, err := format.readEntries(fileSystem, path)
+	if err != nil {
+		return nil, err
+	}
+	markEmptyDirectories(entries)
+	return entries, nil
+}
+
+func formatOf(path string) (format, bool) {
+	lowerCasePath := strings.ToLower(path)
+	for _, format := range formats {
+		for _, extension := range format.extensions {
+			if strings.HasSuffix(lowerCasePath, extension) {
+				return format, true
+			}
+		}
+	}
+	return format{}, false
+}
+
+func readZip(fileSystem filesystem.FileSystem, path string) ([]*Entry, error) {
+	file, err := fileSystem.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	readerAt, size, err := filesystem.ReaderAtOf(file)
+	if err != nil {
+		return nil, err
+	}
+	reader, err := zip.NewReader(readerAt, size)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []*Entry
+	for _, file := range reader.File {
+		if entryPath, ok := normalize(file.Name); ok {
+			entries = append(entries, &Entry{
+				Path:           entryPath,
+				Info:           file.FileInfo(),
+				CompressedSize: int64(file.CompressedSize64),
+			})
+		}
+	}
+	return entries, nil
+}
+
+func readTarWith(decompress func(reader io.Reader) (io.Reader, error)) func(fileSystem filesystem.FileSystem, path string) ([]*Entry, error) {
+	return func(fileSystem filesystem.FileSystem, path string) ([]*Entry, error) {
+		file, err := fileSystem.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+
+		var reader io.Reader = file
+		if decompress != nil {
+			if reader, err = decompress(file); err != nil {
+				return nil, err
+			}
+		}
+		var entries []*Entry
+		tarReader := tar.NewReader(reader)
+		for {
+			header, err := tarReader.Next()
+			if err == io.EOF {
+				return entries, nil
+			}
+			if err != nil {
+				return nil, err
+			}
+			if entryPath, ok := normalize(header.Name); ok {
+				compressedSize := header.Size
+				if decompress != nil {
+					compressedSize = UnknownCompressedSize
+				}
+				entries = append(entries, &Entry{Path: entryPath, Info: header.FileInfo(), CompressedSize: compressedSize})
+			}
+		}
+	}
+}
+
+func normalize(name string) (string, bool) {
+	name = strings.TrimSuffix(strings.TrimPrefix(name, "./"), "/")
+	return name, name != "" && name != "."
+}
+
+func markEmptyDirectories(entries []*Entry) {
+	parents := make(map[string]bool)
+	for _, entry := range entries {
+		for index := strings.LastIndex(entry.Path, "/"); index > 0; index = strings.LastIndex(entry.Path[0:index], "/") {
+			parents[entry.Path[0:index]] = true
+		}
+	}
+	for _, entry := range entries {
+		if entry.Info.IsDir() {
+			entry.IsEmpty = !parents[entry.Path]
+		} else {
+			entry.IsEmpty = entry.Info.Size() == 0
+		}
+	}
+}
@@ -0,0 +1,173 @@
+//go:build unit
+// +build unit
+
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"goselect/parser/filesystem"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type archivedFile struct {
+	name    string
+	content string
+	mode    int64
+}
+
+var archivedFiles = []archivedFile{
+	{name: "docs/", mode: 0755},
+	{name: "docs/README.md", content: "readme", mode: 0644},
+	{name: "empty/", mode: 0755},
+	{name: "bin/run.sh", content: "#!/bin/sh", mode: 0755},
+}
+
+func writeZip(t *testing.T, path string) {
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	defer file.Close()
+
+	writer := zip.NewWriter(file)
+	for _, archived := range archivedFiles {
+		header := &zip.FileHeader{Name: archived.name, Method: zip.Deflate, Modified: time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC)}
+		header.SetMode(os.FileMode(archived.mode))
+		entry, err := writer.CreateHeader(header)
+		if err != nil {
+			t.Fatalf("error is %v", err)
+		}
+		_, _ = entry.Write([]byte(archived.content))
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("error is %v", err)
+	}
+}
+
+func writeTar(t *testing.T, path string, compress bool) {
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	defer file.Close()
+
+	var writer io.Writer = file
+	if compress {
+		gzipWriter := gzip.NewWriter(file)
+		defer gzipWriter.Close()
+		writer = gzipWriter
+	}
+	tarWriter := tar.NewWriter(writer)
+	for _, archived := range archivedFiles {
+		header := &tar.Header{Name: "./" + archived.name, Mode: archived.mode, Size: int64(len(archived.content)), Typeflag: tar.TypeReg}
+		if archived.name[len(archived.name)-1] == '/' {
+			header.Typeflag = tar.TypeDir
+		}
+		if err := tarWriter.WriteHeader(header); err != nil {
+			t.Fatalf("error is %v", err)
+		}
+		_, _ = tarWriter.Write([]byte(archived.content))
+	}
+	if err := tarWriter.Close(); err != nil {
+		t.Fatalf("error is %v", err)
+	}
+}
+
+func TestIsArchive(t *testing.T) {
+	for _, path := range []string{"a.zip", "a.JAR", "a.tar", "a.tar.gz", "a.tgz", "a.tar.bz2", "a.tbz2"} {
+		if !IsArchive(path) {
+			t.Fatalf("Expected %v to be an archive", path)
+		}
+	}
+	if IsArchive("a.gz") || IsArchive("a.txt") {
+		t.Fatalf("Expected a.gz and a.txt to not be archives")
+	}
+}
+
+func TestReadEntriesOfAZip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "release.zip")
+	writeZip(t, path)
+
+	entries, err := ReadEntries(filesystem.Os(), path)
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	assertEntries(t, entries)
+	if entries[1].CompressedSize <= 0 {
+		t.Fatalf("Expected a compressed size for docs/README.md, received %v", entries[1].CompressedSize)
+	}
+}
+
+func TestReadEntriesOfATar(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "backup.tar")
+	writeTar(t, path, false)
+
+	entries, err := ReadEntries(filesystem.Os(), path)
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	assertEntries(t, entries)
+	if entries[1].CompressedSize != 6 {
+		t.Fatalf("Expected compressed size of docs/README.md to be the size %v, received %v", 6, entries[1].CompressedSize)
+	}
+}
+
+func TestReadEntriesOfACompressedTar(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "backup.tar.gz")
+	writeTar(t, path, true)
+
+	entries, err := ReadEntries(filesystem.Os(), path)
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	assertEntries(t, entries)
+	if entries[1].CompressedSize != UnknownCompressedSize {
+		t.Fatalf("Expected compressed size of docs/README.md to be unknown, received %v", entries[1].CompressedSize)
+	}
+}
+
+func TestReadEntriesOfACorruptArchive(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "corrupt.zip")
+	if err := os.WriteFile(path, []byte("not a zip"), 0644); err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	if _, err := ReadEntries(filesystem.Os(), path); err == nil {
+		t.Fatalf("Expected an error while reading a corrupt archive")
+	}
+}
+
+func assertEntries(t *testing.T, entries []*Entry) {
+	if len(entries) != 4 {
+		t.Fatalf("Expected 4 entries, received %v", len(entries))
+	}
+	expected := []struct {
+		path    string
+		isDir   bool
+		isEmpty bool
+		mode    os.FileMode
+		size    int64
+	}{
+		{path: "docs", isDir: true, isEmpty: false, mode: 0755, size: 0},
+		{path: "docs/README.md", isDir: false, isEmpty: false, mode: 0644, size: 6},
+		{path: "empty", isDir: true, isEmpty: true, mode: 0755, size: 0},
+		{path: "bin/run.sh", isDir: false, isEmpty: false, mode: 0755, size: 9},
+	}
+	for index, entry := range entries {
+		if entry.Path != expected[index].path ||
+			entry.Info.IsDir() != expected[index].isDir ||
+			entry.IsEmpty != expected[index].isEmpty ||
+			entry.Info.Mode().Perm() != expected[index].mode ||
+			entry.Info.Size() != expected[index].size {
+			t.Fatalf(
+				"Expected entry %v to be %+v, received path %v, isDir %v, isEmpty %v, mode %v, size %v",
+				index, expected[index], entry.Path, entry.Info.IsDir(), entry.IsEmpty, entry.Info.Mode().Perm(), entry.Info.Size(),
+			)
+		}
+	}
+}
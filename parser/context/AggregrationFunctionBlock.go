@@ -8,6 +8,7 @@ import (
 type CountFunctionBlock struct{}
 type CountDistinctFunctionBlock struct{}
 type SumFunctionBlock struct{}
+type UniqueSumFunctionBlock struct{}
 type AverageFunctionBlock struct{}
 type MinFunctionBlock struct{}
 type MaxFunctionBlock struct{}
@@ -89,6 +90,50 @@ func (c *SumFunctionBlock) finalValue(currentState *FunctionState, values []Valu
 	}
 }
 
+func (u *UniqueSumFunctionBlock) initialState() *FunctionState {
+	return &FunctionState{
+		Initial:   Float64Value(0),
+		extras:    make(map[interface{}]Value),
+		isUpdated: false,
+	}
+}
+
+func (u *UniqueSumFunctionBlock) run(initialState *FunctionState, args ...Value) (*FunctionState, error) {
+	if err := ensureNParametersOrError(args, FunctionNameUniqueSum, 2); err != nil {
+		return nil, err
+	}
+	value, err := args[0].GetNumericAsFloat64()
+	if err != nil {
+		return nil, fmt.Errorf(messages.ErrorMessageFunctionNamePrefixWithExistingError, FunctionNameUniqueSum, err)
+	}
+	key, seenKeys := args[1].GetAsString(), initialState.extras
+	if key != "" {
+		if _, ok := seenKeys[key]; ok {
+			return &FunctionState{Initial: initialState.Initial, extras: seenKeys, isUpdated: true}, nil
+		}
+		seenKeys[key] = trueBooleanValue
+	}
+	return &FunctionState{
+		Initial:   Float64Value(initialState.Initial.float64Value + value),
+		extras:    seenKeys,
+		isUpdated: true,
+	}, nil
+}
+
+func (u *UniqueSumFunctionBlock) finalValue(currentState *FunctionState, values []Value) (Value, error) {
+	if currentState.isUpdated {
+		return currentState.Initial, nil
+	}
+	if err := ensureNParametersOrError(values, FunctionNameUniqueSum, 2); err != nil {
+		return EmptyValue, err
+	}
+	if v, err := values[0].GetNumericAsFloat64(); err != nil {
+		return EmptyValue, fmt.Errorf(messages.ErrorMessageFunctionNamePrefixWithExistingError, FunctionNameUniqueSum, err)
+	} else {
+		return Float64Value(v), nil
+	}
+}
+
 func (a *AverageFunctionBlock) initialState() *FunctionState {
 	return &FunctionState{
 		Initial:   Float64Value(0.0),
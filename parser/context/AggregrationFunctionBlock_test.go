@@ -352,3 +352,29 @@ func TestMaxGivenTheInitialStateIsNotUpdated(t *testing.T) {
 		t.Fatalf("Expected max to be %v, received %v", "pqr", actualValue)
 	}
 }
+
+func TestUniqueSum(t *testing.T) {
+	allFunctions := NewFunctions()
+	initialState := allFunctions.InitialState("uniquesum")
+
+	state, _ := allFunctions.ExecuteAggregate("uniquesum", initialState, IntValue(10), StringValue("1:100"))
+	state, _ = allFunctions.ExecuteAggregate("uniquesum", state, IntValue(10), StringValue("1:100"))
+	state, _ = allFunctions.ExecuteAggregate("uniquesum", state, IntValue(5), StringValue("1:200"))
+	state, _ = allFunctions.ExecuteAggregate("uniquesum", state, IntValue(7), StringValue(""))
+
+	finalValue, _ := allFunctions.FinalValue("uniquesum", state, nil)
+	actualValue := finalValue.GetAsString()
+	if actualValue != "22.00" {
+		t.Fatalf("Expected unique sum to be %v, received %v", "22.00", actualValue)
+	}
+}
+
+func TestUniqueSumWithMissingParameter(t *testing.T) {
+	allFunctions := NewFunctions()
+	initialState := allFunctions.InitialState("uniquesum")
+
+	_, err := allFunctions.ExecuteAggregate("uniquesum", initialState, IntValue(10))
+	if err == nil {
+		t.Fatalf("Expected an error while running uniquesum given insufficient parameters")
+	}
+}
@@ -1,19 +1,291 @@
 package context
 
 import (
+	"encoding/hex"
 	"github.com/gabriel-vasile/mimetype"
+	"goselect/parser/context/platform"
+	"goselect/parser/executable"
+	"goselect/parser/filesystem"
+	"goselect/parser/git"
+	"goselect/parser/media"
+	"hash"
+	"io"
+	"io/fs"
+	"os"
+	"sort"
+	"strings"
 )
 
 type AttributeLazyEvaluationBlock interface {
-	evaluate(filePath string) Value
+	evaluate(filePath string, fileSystem filesystem.FileSystem) Value
 }
 
 type MimeTypeAttributeEvaluationBlock struct{}
 
-func (m MimeTypeAttributeEvaluationBlock) evaluate(filePath string) Value {
-	mime, err := mimetype.DetectFile(filePath)
+func (m MimeTypeAttributeEvaluationBlock) evaluate(filePath string, fileSystem filesystem.FileSystem) Value {
+	file, err := fileSystem.Open(filePath)
+	if err != nil {
+		return StringValue("NA")
+	}
+	defer file.Close()
+
+	mime, err := mimetype.DetectReader(file)
 	if err != nil {
 		return StringValue("NA")
 	}
 	return StringValue(mime.String())
 }
+
+type ContentsAttributeEvaluationBlock struct {
+	sizeLimit int64
+}
+
+func (c ContentsAttributeEvaluationBlock) evaluate(filePath string, fileSystem filesystem.FileSystem) Value {
+	contents := openContents(filePath, fileSystem)
+	defer contents.Close()
+
+	content, err := io.ReadAll(io.LimitReader(contents, c.sizeLimit))
+	if err != nil {
+		return StringValue("")
+	}
+	return StringValue(string(content))
+}
+
+type LinesAttributeEvaluationBlock struct {
+	statistics *textStatistics
+}
+
+func (l LinesAttributeEvaluationBlock) evaluate(filePath string, fileSystem filesystem.FileSystem) Value {
+	return Int64Value(textStatisticsOf(l.statistics, filePath, fileSystem).lines)
+}
+
+type WordsAttributeEvaluationBlock struct {
+	statistics *textStatistics
+}
+
+func (w WordsAttributeEvaluationBlock) evaluate(filePath string, fileSystem filesystem.FileSystem) Value {
+	return Int64Value(textStatisticsOf(w.statistics, filePath, fileSystem).words)
+}
+
+type CharsAttributeEvaluationBlock struct {
+	statistics *textStatistics
+}
+
+func (c CharsAttributeEvaluationBlock) evaluate(filePath string, fileSystem filesystem.FileSystem) Value {
+	return Int64Value(textStatisticsOf(c.statistics, filePath, fileSystem).chars)
+}
+
+type MaxLineLengthAttributeEvaluationBlock struct {
+	statistics *textStatistics
+}
+
+func (m MaxLineLengthAttributeEvaluationBlock) evaluate(filePath string, fileSystem filesystem.FileSystem) Value {
+	return Int64Value(textStatisticsOf(m.statistics, filePath, fileSystem).maxLineLength)
+}
+
+func textStatisticsOf(statistics *textStatistics, filePath string, fileSystem filesystem.FileSystem) *textStatistics {
+	if statistics == nil {
+		statistics = newTextStatistics(filePath, fileSystem)
+	}
+	return statistics.compute()
+}
+
+type ImageAttributeEvaluationBlock struct {
+	metadata *imageMetadata
+	valueOf  func(image *media.Image) Value
+}
+
+func (i ImageAttributeEvaluationBlock) evaluate(filePath string, fileSystem filesystem.FileSystem) Value {
+	metadata := i.metadata
+	if metadata == nil {
+		metadata = newImageMetadata(filePath, fileSystem)
+	}
+	return i.valueOf(metadata.read())
+}
+
+type MediaAttributeEvaluationBlock struct {
+	metadata *mediaMetadata
+	valueOf  func(audioVideo *media.AudioVideo) Value
+}
+
+func (m MediaAttributeEvaluationBlock) evaluate(filePath string, fileSystem filesystem.FileSystem) Value {
+	metadata := m.metadata
+	if metadata == nil {
+		metadata = newMediaMetadata(filePath, fileSystem)
+	}
+	return m.valueOf(metadata.read())
+}
+
+type ExecutableAttributeEvaluationBlock struct {
+	metadata *executableMetadata
+	valueOf  func(binary *executable.Executable) Value
+}
+
+func (e ExecutableAttributeEvaluationBlock) evaluate(filePath string, fileSystem filesystem.FileSystem) Value {
+	metadata := e.metadata
+	if metadata == nil {
+		metadata = newExecutableMetadata(filePath, fileSystem)
+	}
+	return e.valueOf(metadata.read())
+}
+
+type ContentHashAttributeEvaluationBlock struct {
+	newHash func() hash.Hash
+}
+
+func (c ContentHashAttributeEvaluationBlock) evaluate(filePath string, fileSystem filesystem.FileSystem) Value {
+	file, err := fileSystem.Open(filePath)
+	if err != nil {
+		return StringValue("")
+	}
+	defer file.Close()
+
+	contentHash := c.newHash()
+	if _, err := io.Copy(contentHash, file); err != nil {
+		return StringValue("")
+	}
+	return StringValue(hex.EncodeToString(contentHash.Sum(nil)))
+}
+
+type BirthTimeAttributeEvaluationBlock struct {
+	file fs.FileInfo
+}
+
+func (b BirthTimeAttributeEvaluationBlock) evaluate(filePath string, fileSystem filesystem.FileSystem) Value {
+	file := b.file
+	if file == nil {
+		lstat, err := fileSystem.Lstat(filePath)
+		if err != nil {
+			return EmptyValue
+		}
+		file = lstat
+	}
+	birthTime, ok := platform.FileBirthTime(filePath, file)
+	if !ok {
+		return EmptyValue
+	}
+	return DateTimeValue(birthTime)
+}
+
+var aclAttributes = []string{"system.posix_acl_access", "system.posix_acl_default"}
+
+type ExtendedAttributesAttributeEvaluationBlock struct{}
+
+func (e ExtendedAttributesAttributeEvaluationBlock) evaluate(filePath string, fileSystem filesystem.FileSystem) Value {
+	if !filesystem.IsOs(fileSystem) {
+		return StringValue("")
+	}
+	names, err := platform.ExtendedAttributeNames(filePath)
+	if err != nil {
+		return StringValue("")
+	}
+	sort.Strings(names)
+	return StringValue(strings.Join(names, ","))
+}
+
+type HasAclAttributeEvaluationBlock struct{}
+
+func (h HasAclAttributeEvaluationBlock) evaluate(filePath string, fileSystem filesystem.FileSystem) Value {
+	if !filesystem.IsOs(fileSystem) {
+		return booleanValueUsing(false)
+	}
+	names, err := platform.ExtendedAttributeNames(filePath)
+	if err != nil {
+		return booleanValueUsing(false)
+	}
+	for _, name := range names {
+		for _, aclAttribute := range aclAttributes {
+			if name == aclAttribute {
+				return booleanValueUsing(true)
+			}
+		}
+	}
+	return booleanValueUsing(false)
+}
+
+type CapabilitiesAttributeEvaluationBlock struct{}
+
+func (c CapabilitiesAttributeEvaluationBlock) evaluate(filePath string, fileSystem filesystem.FileSystem) Value {
+	if !filesystem.IsOs(fileSystem) {
+		return StringValue("")
+	}
+	value, err := platform.ExtendedAttribute(filePath, platform.CapabilityAttribute)
+	if err != nil {
+		return StringValue("")
+	}
+	capabilities, err := platform.DecodeCapabilities(value)
+	if err != nil {
+		return StringValue("")
+	}
+	return StringValue(capabilities)
+}
+
+type IsBrokenLinkAttributeEvaluationBlock struct{}
+
+func (i IsBrokenLinkAttributeEvaluationBlock) evaluate(filePath string, fileSystem filesystem.FileSystem) Value {
+	file, err := fileSystem.Lstat(filePath)
+	if err != nil || file.Mode()&os.ModeSymlink != os.ModeSymlink {
+		return booleanValueUsing(false)
+	}
+	_, err = fileSystem.Stat(filePath)
+	return booleanValueUsing(err != nil)
+}
+
+type LinkTargetAttributeEvaluationBlock struct{}
+
+func (l LinkTargetAttributeEvaluationBlock) evaluate(filePath string, fileSystem filesystem.FileSystem) Value {
+	target, err := fileSystem.ReadLink(filePath)
+	if err != nil {
+		return StringValue("")
+	}
+	return StringValue(target)
+}
+
+type ResolvedPathAttributeEvaluationBlock struct{}
+
+func (r ResolvedPathAttributeEvaluationBlock) evaluate(filePath string, fileSystem filesystem.FileSystem) Value {
+	resolvedPath, err := fileSystem.EvalSymlinks(filePath)
+	if err != nil {
+		return StringValue("")
+	}
+	return StringValue(resolvedPath)
+}
+
+type LastCommitAttributeEvaluationBlock struct{}
+
+func (l LastCommitAttributeEvaluationBlock) evaluate(filePath string, fileSystem filesystem.FileSystem) Value {
+	commit, ok := lastCommitOf(filePath, fileSystem)
+	if !ok {
+		return StringValue("")
+	}
+	return StringValue(commit.Hash.String())
+}
+
+type LastCommitTimeAttributeEvaluationBlock struct{}
+
+func (l LastCommitTimeAttributeEvaluationBlock) evaluate(filePath string, fileSystem filesystem.FileSystem) Value {
+	commit, ok := lastCommitOf(filePath, fileSystem)
+	if !ok {
+		return EmptyValue
+	}
+	return DateTimeValue(commit.Time)
+}
+
+type LastAuthorAttributeEvaluationBlock struct{}
+
+func (l LastAuthorAttributeEvaluationBlock) evaluate(filePath string, fileSystem filesystem.FileSystem) Value {
+	commit, ok := lastCommitOf(filePath, fileSystem)
+	if !ok {
+		return StringValue("")
+	}
+	return StringValue(commit.Author)
+}
+
+func lastCommitOf(filePath string, fileSystem filesystem.FileSystem) (*git.Commit, bool) {
+	treeFileSystem, ok := fileSystem.(*git.TreeFileSystem)
+	if !ok {
+		return nil, false
+	}
+	commit, err := treeFileSystem.LastCommit(filePath)
+	return commit, err == nil
+}
@@ -4,12 +4,13 @@
 package context
 
 import (
+	"goselect/parser/filesystem"
 	"testing"
 )
 
 func TestMimeTypeAsText(t *testing.T) {
 	block := MimeTypeAttributeEvaluationBlock{}
-	value := block.evaluate("./Value.go")
+	value := block.evaluate("./Value.go", filesystem.Os())
 	expected := "text/plain; charset=utf-8"
 
 	if value.GetAsString() != expected {
@@ -19,7 +20,7 @@ func TestMimeTypeAsText(t *testing.T) {
 
 func TestMimeTypeAsImage(t *testing.T) {
 	block := MimeTypeAttributeEvaluationBlock{}
-	value := block.evaluate("../test/resources/images/where.png")
+	value := block.evaluate("../test/resources/images/where.png", filesystem.Os())
 	expected := "image/png"
 
 	if value.GetAsString() != expected {
@@ -29,7 +30,7 @@ func TestMimeTypeAsImage(t *testing.T) {
 
 func TestMimeTypeForANonExistingFile(t *testing.T) {
 	block := MimeTypeAttributeEvaluationBlock{}
-	value := block.evaluate("non-existent")
+	value := block.evaluate("non-existent", filesystem.Os())
 
 	if value.GetAsString() != "NA" {
 		t.Fatalf("Expected %v while determining the mime type of a non-existent file, received %v", "NA", value.GetAsString())
@@ -1,11 +1,19 @@
 package context
 
-import "strings"
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"goselect/parser/xxhash"
+	"hash"
+	"strings"
+)
 
 type AttributeDefinition struct {
 	aliases             []string
 	description         string
 	lazyEvaluationBlock AttributeLazyEvaluationBlock
+	isBoolean           bool
 }
 
 const (
@@ -19,9 +27,14 @@ const (
 	AttributeNameIsHidden       = "ishidden"
 	AttributeNameIsEmpty        = "isempty"
 	AttributeNameIsSymbolicLink = "issymboliclink"
+	AttributeNameIsBrokenLink   = "isbrokenlink"
+	AttributeLinkTarget         = "linktarget"
+	AttributeResolvedPath       = "resolvedpath"
 	AttributeCreatedTime        = "createdtime"
 	AttributeModifiedTime       = "modifiedtime"
 	AttributeAccessedTime       = "accessedtime"
+	AttributeChangeTime         = "changetime"
+	AttributeBirthTime          = "birthtime"
 	AttributeExtension          = "extension"
 	AttributePermission         = "permission"
 	AttributeUserRead           = "userread"
@@ -33,15 +46,74 @@ const (
 	AttributeOthersRead         = "otherread"
 	AttributeOthersWrite        = "otherwrite"
 	AttributeOthersExecute      = "otherexecute"
+	AttributeIsSetUid           = "issetuid"
+	AttributeIsSetGid           = "issetgid"
+	AttributeIsSticky           = "issticky"
 	AttributeBlockSize          = "blocksize"
 	AttributeBlocks             = "blocks"
 	AttributeUserId             = "userid"
 	AttributeUserName           = "username"
 	AttributeGroupId            = "groupid"
 	AttributeGroupName          = "groupname"
+	AttributeInode              = "inode"
+	AttributeDevice             = "device"
+	AttributeFileId             = "fileid"
+	AttributeLinks              = "nlink"
+	AttributeSpecialDevice      = "rdev"
 	AttributeMimeType           = "mimetype"
+	AttributeContents           = "contents"
+	AttributeLines              = "lines"
+	AttributeWords              = "words"
+	AttributeChars              = "chars"
+	AttributeMaxLineLength      = "maxlinelength"
+	AttributeImageWidth         = "imagewidth"
+	AttributeImageHeight        = "imageheight"
+	AttributeImageFormat        = "imageformat"
+	AttributeExifDateTime       = "exif_datetime"
+	AttributeExifMake           = "exif_make"
+	AttributeExifModel          = "exif_model"
+	AttributeExifOrientation    = "exif_orientation"
+	AttributeExifHasGps         = "exif_hasgps"
+	AttributeMediaFormat        = "mediaformat"
+	AttributeDuration           = "duration"
+	AttributeBitRate            = "bitrate"
+	AttributeSampleRate         = "samplerate"
+	AttributeChannels           = "channels"
+	AttributeVideoWidth         = "videowidth"
+	AttributeVideoHeight        = "videoheight"
+	AttributeVideoCodec         = "videocodec"
+	AttributeAudioCodec         = "audiocodec"
+	AttributeBinaryType         = "binarytype"
+	AttributeArchitecture       = "architecture"
+	AttributeNameIsStripped     = "isstripped"
+	AttributeNameIsStatic       = "isstatic"
+	AttributeDynamicLibraries   = "dynamiclibs"
+	AttributeGoVersion          = "goversion"
+	AttributeGoModule           = "gomodule"
+	AttributeMd5                = "md5"
+	AttributeSha1               = "sha1"
+	AttributeSha256             = "sha256"
+	AttributeXxHash             = "xxhash"
+	AttributeError              = "error"
+	AttributeDepth              = "depth"
+	AttributeNameIsIgnored      = "isignored"
+	AttributeRoot               = "root"
+	AttributeCompressedSize     = "compressedsize"
+	AttributeGitHash            = "githash"
+	AttributeGitMode            = "gitmode"
+	AttributeLastCommit         = "lastcommit"
+	AttributeLastCommitTime     = "lastcommittime"
+	AttributeLastAuthor         = "lastauthor"
+	AttributeExtendedAttributes = "xattrs"
+	AttributeHasAcl             = "hasacl"
+	AttributeCapabilities       = "capabilities"
 )
 
+var contentHashAttributes = []string{AttributeMd5, AttributeSha1, AttributeSha256, AttributeXxHash}
+var textStatisticsAttributes = []string{AttributeLines, AttributeWords, AttributeChars, AttributeMaxLineLength}
+var symbolicLinkAttributes = []string{AttributeNameIsBrokenLink, AttributeLinkTarget, AttributeResolvedPath}
+var extendedAttributes = []string{AttributeExtendedAttributes, AttributeHasAcl, AttributeCapabilities}
+
 var attributeDefinitions = map[string]*AttributeDefinition{
 	AttributeName: {
 		aliases:     []string{"filename", "name", "fname"},
@@ -66,26 +138,47 @@ var attributeDefinitions = map[string]*AttributeDefinition{
 	AttributeNameIsDir: {
 		aliases:     []string{"isdir", "isdirectory"},
 		description: "Returns true if the file is a directory, false otherwise.",
+		isBoolean:   true,
 	},
 	AttributeNameIsFile: {
 		aliases:     []string{"isfile"},
 		description: "Returns true if the file is a file, false otherwise.",
+		isBoolean:   true,
 	},
 	AttributeNameIsHidden: {
 		aliases:     []string{"ishidden"},
 		description: "Returns true if the file is hidden, false otherwise.",
+		isBoolean:   true,
 	},
 	AttributeNameIsEmpty: {
 		aliases:     []string{"isempty"},
 		description: "Returns true if the file is empty, false otherwise. \nIf the file is a directory, 'isempty' returns true if there are no entries, false otherwise.",
+		isBoolean:   true,
 	},
 	AttributeNameIsSymbolicLink: {
 		aliases:     []string{"issymboliclink", "issymlink"},
 		description: "Returns true if the file is a symbolic link.",
+		isBoolean:   true,
+	},
+	AttributeNameIsBrokenLink: {
+		aliases:             []string{"isbrokenlink", "isbrokensymlink"},
+		description:         "Returns true if the file is a symbolic link whose target does not exist.",
+		lazyEvaluationBlock: IsBrokenLinkAttributeEvaluationBlock{},
+		isBoolean:           true,
+	},
+	AttributeLinkTarget: {
+		aliases:             []string{"linktarget", "target"},
+		description:         "Returns the target of the symbolic link, as stored in the link. Returns blank if the file is not a symbolic link.",
+		lazyEvaluationBlock: LinkTargetAttributeEvaluationBlock{},
+	},
+	AttributeResolvedPath: {
+		aliases:             []string{"resolvedpath", "realpath"},
+		description:         "Returns the absolute path of the file after resolving all the symbolic links. \nReturns blank if the path can not be resolved, for example, for a broken link.",
+		lazyEvaluationBlock: ResolvedPathAttributeEvaluationBlock{},
 	},
 	AttributeCreatedTime: {
 		aliases:     []string{"createdtime", "ctime"},
-		description: "Returns the created time of the file.",
+		description: "Returns the created time of the file. \nOn linux, it is the time when the inode was last changed, use birthtime for the time when the file was created.",
 	},
 	AttributeModifiedTime: {
 		aliases:     []string{"modifiedtime", "mtime", "modtime"},
@@ -95,6 +188,15 @@ var attributeDefinitions = map[string]*AttributeDefinition{
 		aliases:     []string{"accessedtime", "accesstime", "atime"},
 		description: "Returns the access time of the file.",
 	},
+	AttributeChangeTime: {
+		aliases:     []string{"changetime", "chtime"},
+		description: "Returns the time when the inode of the file was last changed, for example, by a change of the contents, the permissions or the owner. \nReturns blank for windows.",
+	},
+	AttributeBirthTime: {
+		aliases:             []string{"birthtime", "btime"},
+		description:         "Returns the time when the file was created, read using statx on linux. \nReturns blank if the kernel or the file system does not record it, and for the files that are not on the disk.",
+		lazyEvaluationBlock: BirthTimeAttributeEvaluationBlock{},
+	},
 	AttributeExtension: {
 		aliases:     []string{"extension", "ext"},
 		description: "Return the file extension. \nFor example, extension of the file 'sample.log' is '.log'.",
@@ -106,38 +208,62 @@ var attributeDefinitions = map[string]*AttributeDefinition{
 	AttributeUserRead: {
 		aliases:     []string{"userread", "uread"},
 		description: "Returns true if the user can read the file.",
+		isBoolean:   true,
 	},
 	AttributeUserWrite: {
 		aliases:     []string{"userwrite", "uwrite"},
 		description: "Returns true if the user can write to the file.",
+		isBoolean:   true,
 	},
 	AttributeUserExecute: {
 		aliases:     []string{"userexecute", "uexecute"},
 		description: "Returns true if the user can execute the file.",
+		isBoolean:   true,
 	},
 	AttributeGroupRead: {
 		aliases:     []string{"groupread", "gread"},
 		description: "Returns true if the group can read the file.",
+		isBoolean:   true,
 	},
 	AttributeGroupWrite: {
 		aliases:     []string{"groupwrite", "gwrite"},
 		description: "Returns true if the group can write to the file.",
+		isBoolean:   true,
 	},
 	AttributeGroupExecute: {
 		aliases:     []string{"groupexecute", "gexecute"},
 		description: "Returns true if the group can execute the file.",
+		isBoolean:   true,
 	},
 	AttributeOthersRead: {
 		aliases:     []string{"otherread", "oread"},
 		description: "Returns true if others can read the file.",
+		isBoolean:   true,
 	},
 	AttributeOthersWrite: {
 		aliases:     []string{"otherwrite", "owrite"},
 		description: "Returns true if others can write to the file.",
+		isBoolean:   true,
 	},
 	AttributeOthersExecute: {
 		aliases:     []string{"otherexecute", "oexecute"},
 		description: "Returns true if others can execute the file.",
+		isBoolean:   true,
+	},
+	AttributeIsSetUid: {
+		aliases:     []string{"issetuid", "setuid"},
+		description: "Returns true if the setuid bit of the file is set. \nFor example, select path from / where issetuid.",
+		isBoolean:   true,
+	},
+	AttributeIsSetGid: {
+		aliases:     []string{"issetgid", "setgid"},
+		description: "Returns true if the setgid bit of the file is set.",
+		isBoolean:   true,
+	},
+	AttributeIsSticky: {
+		aliases:     []string{"issticky", "sticky"},
+		description: "Returns true if the sticky bit of the file is set.",
+		isBoolean:   true,
 	},
 	AttributeBlockSize: {
 		aliases:     []string{"blocksize", "bsize", "blksize"},
@@ -149,25 +275,273 @@ var attributeDefinitions = map[string]*AttributeDefinition{
 	},
 	AttributeUserId: {
 		aliases:     []string{"userid", "uid"},
-		description: "Returns the user id. Returns blank for windows.",
+		description: "Returns the id of the user who owns the file. Returns blank for windows.",
 	},
 	AttributeUserName: {
 		aliases:     []string{"username", "uname"},
-		description: "Returns the user name. Returns blank for windows.",
+		description: "Returns the name of the user who owns the file. \nReturns blank for windows, and if the user id has no name on this machine.",
 	},
 	AttributeGroupId: {
 		aliases:     []string{"groupid", "gid"},
-		description: "Returns the group id. Returns blank for windows.",
+		description: "Returns the id of the group of the file. Returns blank for windows.",
 	},
 	AttributeGroupName: {
 		aliases:     []string{"groupname", "gname"},
-		description: "Returns the group name. Returns blank for windows.",
+		description: "Returns the name of the group of the file. \nReturns blank for windows, and if the group id has no name on this machine.",
+	},
+	AttributeInode: {
+		aliases:     []string{"inode", "ino"},
+		description: "Returns the inode number of the file. Returns blank for windows.",
+	},
+	AttributeDevice: {
+		aliases:     []string{"device", "dev"},
+		description: "Returns the id of the device that contains the file. Returns blank for windows.",
+	},
+	AttributeFileId: {
+		aliases:     []string{"fileid"},
+		description: "Returns the device and the inode of the file as 'device:inode', which is the same for all the hard links of a file. \nFor example, uniquesum(size, fileid) returns the size of the files counting the hard linked files once. Returns blank for windows.",
+	},
+	AttributeLinks: {
+		aliases:     []string{"nlink", "links", "hardlinks"},
+		description: "Returns the number of hard links to the file. Returns blank for windows.",
+	},
+	AttributeSpecialDevice: {
+		aliases:     []string{"rdev"},
+		description: "Returns the id of the device that a character or a block device file represents, and 0 for the other files. Returns blank for windows.",
+	},
+	AttributeExtendedAttributes: {
+		aliases:             []string{"xattrs"},
+		description:         "Returns the comma separated names of the extended attributes of a file, in sorted order. \nReturns blank for windows, and for the files that are not on the disk. Use the function xattr to read the value of an extended attribute.",
+		lazyEvaluationBlock: ExtendedAttributesAttributeEvaluationBlock{},
+	},
+	AttributeHasAcl: {
+		aliases:             []string{"hasacl", "acl"},
+		description:         "Returns true if a file has a POSIX access control list, an access or a default ACL, beyond the permission bits. \nReturns false for windows and macOS, and for the files that are not on the disk.",
+		lazyEvaluationBlock: HasAclAttributeEvaluationBlock{},
+		isBoolean:           true,
+	},
+	AttributeCapabilities: {
+		aliases:             []string{"capabilities", "caps"},
+		description:         "Returns the linux file capabilities from the 'security.capability' extended attribute, in the form used by getcap. \nFor example, 'cap_net_bind_service,cap_net_raw=ep'. Returns blank if a file has no capabilities.",
+		lazyEvaluationBlock: CapabilitiesAttributeEvaluationBlock{},
 	},
 	AttributeMimeType: {
 		aliases:             []string{"mimetype", "mime"},
 		description:         "Returns the mime type of a file.",
 		lazyEvaluationBlock: MimeTypeAttributeEvaluationBlock{},
 	},
+	AttributeContents: {
+		aliases:             []string{"contents"},
+		description:         "Returns the contents of a file, read only when needed and up to the contents size limit, 1 MiB by default. \nReturns blank for a directory or a file that can not be read. The functions grep, countmatches and firstmatch read the whole file line by line, without the size limit. \nFor example, select path from . where grep(contents, 'TODO\\(.*\\)').",
+		lazyEvaluationBlock: ContentsAttributeEvaluationBlock{sizeLimit: DefaultContentsSizeLimit},
+	},
+	AttributeLines: {
+		aliases:             []string{"lines"},
+		description:         "Returns the number of lines in a file, counting a last line without a trailing newline. \nReturns 0 for a directory, a binary file detected using the mime type, or a file that can not be read. \nFor example, select ext, sum(lines) from . group by ext.",
+		lazyEvaluationBlock: LinesAttributeEvaluationBlock{},
+	},
+	AttributeWords: {
+		aliases:             []string{"words"},
+		description:         "Returns the number of words, separated by whitespace, in a file. \nReturns 0 for a directory, a binary file detected using the mime type, or a file that can not be read.",
+		lazyEvaluationBlock: WordsAttributeEvaluationBlock{},
+	},
+	AttributeChars: {
+		aliases:             []string{"chars"},
+		description:         "Returns the number of unicode characters in a file. \nReturns 0 for a directory, a binary file detected using the mime type, or a file that can not be read.",
+		lazyEvaluationBlock: CharsAttributeEvaluationBlock{},
+	},
+	AttributeMaxLineLength: {
+		aliases:             []string{"maxlinelength", "maxline"},
+		description:         "Returns the length of the longest line in a file in unicode characters, excluding the line ending. \nReturns 0 for a directory, a binary file detected using the mime type, or a file that can not be read.",
+		lazyEvaluationBlock: MaxLineLengthAttributeEvaluationBlock{},
+	},
+	AttributeImageWidth: {
+		aliases:             []string{"imagewidth", "width"},
+		description:         "Returns the width of an image in pixels, read from the headers of a jpeg, png, gif, bmp, webp or a tiff image without decoding it. \nReturns 0 for a file that is not an image. The width is the stored width, before applying the EXIF orientation. \nFor example, select path from . where gt(imagewidth, 4000).",
+		lazyEvaluationBlock: ImageAttributeEvaluationBlock{valueOf: imageAttributeValues[AttributeImageWidth]},
+	},
+	AttributeImageHeight: {
+		aliases:             []string{"imageheight", "height"},
+		description:         "Returns the height of an image in pixels, read from the headers of the image. \nReturns 0 for a file that is not an image. The height is the stored height, before applying the EXIF orientation.",
+		lazyEvaluationBlock: ImageAttributeEvaluationBlock{valueOf: imageAttributeValues[AttributeImageHeight]},
+	},
+	AttributeImageFormat: {
+		aliases:             []string{"imageformat", "imgformat"},
+		description:         "Returns the format of an image, one of jpeg, png, gif, bmp, webp and tiff, detected from the contents of the file. \nReturns blank for a file that is not an image.",
+		lazyEvaluationBlock: ImageAttributeEvaluationBlock{valueOf: imageAttributeValues[AttributeImageFormat]},
+	},
+	AttributeExifDateTime: {
+		aliases:             []string{"exif_datetime", "exif_date"},
+		description:         "Returns the time when a photo was taken, from the EXIF DateTimeOriginal, or the EXIF DateTime if the original is not present. \nThe time is in the local time zone unless the image has the EXIF offset of the time. Returns blank if the image does not have the time. \nFor example, select path from ~/Photos order by exif_datetime.",
+		lazyEvaluationBlock: ImageAttributeEvaluationBlock{valueOf: imageAttributeValues[AttributeExifDateTime]},
+	},
+	AttributeExifMake: {
+		aliases:             []string{"exif_make"},
+		description:         "Returns the make of the camera from the EXIF of an image. \nReturns blank if the image does not have it.",
+		lazyEvaluationBlock: ImageAttributeEvaluationBlock{valueOf: imageAttributeValues[AttributeExifMake]},
+	},
+	AttributeExifModel: {
+		aliases:             []string{"exif_model", "camera"},
+		description:         "Returns the model of the camera from the EXIF of an image. \nReturns blank if the image does not have it.",
+		lazyEvaluationBlock: ImageAttributeEvaluationBlock{valueOf: imageAttributeValues[AttributeExifModel]},
+	},
+	AttributeExifOrientation: {
+		aliases:             []string{"exif_orientation"},
+		description:         "Returns the EXIF orientation of an image, from 1 to 8, where 1 is the normal orientation and 6 is rotated by 90 degrees clockwise. \nReturns 0 if the image does not have it.",
+		lazyEvaluationBlock: ImageAttributeEvaluationBlock{valueOf: imageAttributeValues[AttributeExifOrientation]},
+	},
+	AttributeExifHasGps: {
+		aliases:             []string{"exif_hasgps", "hasgps"},
+		description:         "Returns true if the EXIF of an image has the GPS latitude and longitude. \nFor example, select path from ~/Photos where exif_hasgps.",
+		lazyEvaluationBlock: ImageAttributeEvaluationBlock{valueOf: imageAttributeValues[AttributeExifHasGps]},
+		isBoolean:           true,
+	},
+	AttributeMediaFormat: {
+		aliases:             []string{"mediaformat", "container"},
+		description:         "Returns the container of an audio or a video, one of mp4, mov, mp3, wav, flac, matroska and webm, detected from the contents of the file. \nReturns blank for a file that is not an audio or a video.",
+		lazyEvaluationBlock: MediaAttributeEvaluationBlock{valueOf: mediaAttributeValues[AttributeMediaFormat]},
+	},
+	AttributeDuration: {
+		aliases:             []string{"duration"},
+		description:         "Returns the duration of an audio or a video in seconds, read from the headers of the container without reading the media data. \nReturns 0 for a file that is not an audio or a video. The duration of an mp3 without a Xing or a VBRI header is estimated from its bit rate. \nFor example, select path, duration from . where gt(duration, 600) order by 2 desc.",
+		lazyEvaluationBlock: MediaAttributeEvaluationBlock{valueOf: mediaAttributeValues[AttributeDuration]},
+	},
+	AttributeBitRate: {
+		aliases:             []string{"bitrate"},
+		description:         "Returns the average bit rate of an audio or a video in bits per second, the size of the file over its duration for the compressed formats. \nReturns 0 for a file that is not an audio or a video.",
+		lazyEvaluationBlock: MediaAttributeEvaluationBlock{valueOf: mediaAttributeValues[AttributeBitRate]},
+	},
+	AttributeSampleRate: {
+		aliases:             []string{"samplerate"},
+		description:         "Returns the sample rate in Hz of the first audio track of an audio or a video. \nReturns 0 for a file without audio.",
+		lazyEvaluationBlock: MediaAttributeEvaluationBlock{valueOf: mediaAttributeValues[AttributeSampleRate]},
+	},
+	AttributeChannels: {
+		aliases:             []string{"channels"},
+		description:         "Returns the number of channels of the first audio track of an audio or a video. \nReturns 0 for a file without audio.",
+		lazyEvaluationBlock: MediaAttributeEvaluationBlock{valueOf: mediaAttributeValues[AttributeChannels]},
+	},
+	AttributeVideoWidth: {
+		aliases:             []string{"videowidth"},
+		description:         "Returns the width in pixels of the first video track of a video. \nReturns 0 for a file without video.",
+		lazyEvaluationBlock: MediaAttributeEvaluationBlock{valueOf: mediaAttributeValues[AttributeVideoWidth]},
+	},
+	AttributeVideoHeight: {
+		aliases:             []string{"videoheight"},
+		description:         "Returns the height in pixels of the first video track of a video. \nReturns 0 for a file without video.",
+		lazyEvaluationBlock: MediaAttributeEvaluationBlock{valueOf: mediaAttributeValues[AttributeVideoHeight]},
+	},
+	AttributeVideoCodec: {
+		aliases:             []string{"videocodec", "vcodec"},
+		description:         "Returns the codec of the first video track of a video, like h264, hevc, vp9 or av1. \nReturns blank for a file without video. \nFor example, select path from ./uploads where and(eq(mediaformat, mp4), ne(videocodec, h264)).",
+		lazyEvaluationBlock: MediaAttributeEvaluationBlock{valueOf: mediaAttributeValues[AttributeVideoCodec]},
+	},
+	AttributeAudioCodec: {
+		aliases:             []string{"audiocodec", "acodec"},
+		description:         "Returns the codec of the first audio track of an audio or a video, like aac, mp3, opus, flac or pcm_s16le. \nReturns blank for a file without audio.",
+		lazyEvaluationBlock: MediaAttributeEvaluationBlock{valueOf: mediaAttributeValues[AttributeAudioCodec]},
+	},
+	AttributeBinaryType: {
+		aliases:             []string{"binarytype"},
+		description:         "Returns the type of a binary, one of elf, macho and pe, detected from the contents of the file. \nReturns blank for a file that is not a binary.",
+		lazyEvaluationBlock: ExecutableAttributeEvaluationBlock{valueOf: executableAttributeValues[AttributeBinaryType]},
+	},
+	AttributeArchitecture: {
+		aliases:             []string{"architecture", "arch"},
+		description:         "Returns the architecture of a binary with the names used by GOARCH, like amd64, arm64 or 386. \nA universal Mach-O binary returns the architectures of all its binaries separated by a comma. Returns blank for a file that is not a binary. \nFor example, select path from ./dist where and(eq(binarytype, elf), ne(arch, arm64)).",
+		lazyEvaluationBlock: ExecutableAttributeEvaluationBlock{valueOf: executableAttributeValues[AttributeArchitecture]},
+	},
+	AttributeNameIsStripped: {
+		aliases:             []string{"isstripped", "stripped"},
+		description:         "Returns true if a binary does not have the symbol table. \nReturns false for a file that is not a binary. \nFor example, select path from ./dist where and(eq(binarytype, elf), not(isstripped)).",
+		lazyEvaluationBlock: ExecutableAttributeEvaluationBlock{valueOf: executableAttributeValues[AttributeNameIsStripped]},
+		isBoolean:           true,
+	},
+	AttributeNameIsStatic: {
+		aliases:             []string{"isstatic", "static"},
+		description:         "Returns true if a binary does not load any shared library, and for ELF, does not have an interpreter. \nReturns false for a file that is not a binary.",
+		lazyEvaluationBlock: ExecutableAttributeEvaluationBlock{valueOf: executableAttributeValues[AttributeNameIsStatic]},
+		isBoolean:           true,
+	},
+	AttributeDynamicLibraries: {
+		aliases:             []string{"dynamiclibs", "libs"},
+		description:         "Returns the shared libraries needed by a binary separated by a comma, the DT_NEEDED entries of ELF, the dylibs of Mach-O and the imported DLLs of PE. \nReturns blank for a static binary or a file that is not a binary.",
+		lazyEvaluationBlock: ExecutableAttributeEvaluationBlock{valueOf: executableAttributeValues[AttributeDynamicLibraries]},
+	},
+	AttributeGoVersion: {
+		aliases:             []string{"goversion"},
+		description:         "Returns the version of Go used to build a binary, like go1.21.5, read from the build information embedded by the Go linker. \nReturns blank for a binary not built by Go or a file that is not a binary. \nFor example, select path, goversion from ./bin where gt(len(goversion), 0).",
+		lazyEvaluationBlock: ExecutableAttributeEvaluationBlock{valueOf: executableAttributeValues[AttributeGoVersion]},
+	},
+	AttributeGoModule: {
+		aliases:             []string{"gomodule"},
+		description:         "Returns the path of the main module of a binary built by Go, read from the build information embedded by the Go linker. \nReturns blank for a binary not built by Go or a file that is not a binary.",
+		lazyEvaluationBlock: ExecutableAttributeEvaluationBlock{valueOf: executableAttributeValues[AttributeGoModule]},
+	},
+	AttributeMd5: {
+		aliases:             []string{"md5"},
+		description:         "Returns the md5 hash of the contents of a file in hex, computed only when needed. \nReturns blank for a directory or a file that can not be read.",
+		lazyEvaluationBlock: ContentHashAttributeEvaluationBlock{newHash: md5.New},
+	},
+	AttributeSha1: {
+		aliases:             []string{"sha1"},
+		description:         "Returns the sha1 hash of the contents of a file in hex, computed only when needed. \nReturns blank for a directory or a file that can not be read.",
+		lazyEvaluationBlock: ContentHashAttributeEvaluationBlock{newHash: sha1.New},
+	},
+	AttributeSha256: {
+		aliases:             []string{"sha256"},
+		description:         "Returns the sha256 hash of the contents of a file in hex, computed only when needed. \nReturns blank for a directory or a file that can not be read. \nFor example, select sha256, count(), min(path) from . where eq(isfile, true) group by sha256 having gt(count(), 1).",
+		lazyEvaluationBlock: ContentHashAttributeEvaluationBlock{newHash: sha256.New},
+	},
+	AttributeXxHash: {
+		aliases:             []string{"xxhash", "xxh64"},
+		description:         "Returns the 64 bit xxHash of the contents of a file in hex, computed only when needed. \nIt is faster than the cryptographic hashes but more likely to collide. Returns blank for a directory or a file that can not be read.",
+		lazyEvaluationBlock: ContentHashAttributeEvaluationBlock{newHash: func() hash.Hash { return xxhash.New() }},
+	},
+	AttributeDepth: {
+		aliases:     []string{"depth"},
+		description: "Returns the depth of the file relative to the source directory. \nThe files directly inside the source directory have a depth of 1.",
+	},
+	AttributeNameIsIgnored: {
+		aliases:     []string{"isignored", "ignored"},
+		description: "Returns true if the file is ignored by the '.gitignore', '.ignore' or '.git/info/exclude' files. \nReturns false if the ignore files are not read, use the ignore policy 'mark' to query the ignored files.",
+		isBoolean:   true,
+	},
+	AttributeCompressedSize: {
+		aliases:     []string{"compressedsize", "csize"},
+		description: "Returns the compressed size of an entry in a zip archive, or the size of an entry in an uncompressed tar archive. \nReturns -1 for an entry in a compressed tar archive, and blank for a file that is not inside an archive.",
+	},
+	AttributeGitHash: {
+		aliases:     []string{"githash", "objectid", "oid"},
+		description: "Returns the hash of the git object, the blob of a file or the tree of a directory, for a source at a git revision like 'git:HEAD:.'. \nReturns blank for the other sources.",
+	},
+	AttributeGitMode: {
+		aliases:     []string{"gitmode"},
+		description: "Returns the mode stored in git, for example, '100644' for a file, '100755' for an executable, '040000' for a directory and '120000' for a symbolic link, for a source at a git revision. \nReturns blank for the other sources.",
+	},
+	AttributeLastCommit: {
+		aliases:             []string{"lastcommit", "lcommit"},
+		description:         "Returns the hash of the last commit that changed the file, following the first parents from the revision, for a source at a git revision. \nReturns blank for the other sources.",
+		lazyEvaluationBlock: LastCommitAttributeEvaluationBlock{},
+	},
+	AttributeLastCommitTime: {
+		aliases:             []string{"lastcommittime", "lctime"},
+		description:         "Returns the committer time of the last commit that changed the file, for a source at a git revision. \nReturns blank for the other sources.",
+		lazyEvaluationBlock: LastCommitTimeAttributeEvaluationBlock{},
+	},
+	AttributeLastAuthor: {
+		aliases:             []string{"lastauthor", "lauthor"},
+		description:         "Returns the author name of the last commit that changed the file, for a source at a git revision. \nReturns blank for the other sources.",
+		lazyEvaluationBlock: LastAuthorAttributeEvaluationBlock{},
+	},
+	AttributeRoot: {
+		aliases:     []string{"root", "source"},
+		description: "Returns the path in the 'from' clause that the file was found in. \nFor example, 'select root, name from ./src, ./test' returns either './src' or './test' as the root.",
+	},
+	AttributeError: {
+		aliases:     []string{"error", "err"},
+		description: "Returns the error encountered while reading the file or the directory. Returns blank if there was no error. \nThe errors are tolerated only if the error policy is 'skip' or 'collect'.",
+	},
 }
 
 type AllAttributes struct {
@@ -189,6 +563,19 @@ func (attributes *AllAttributes) IsASupportedAttribute(attribute string) bool {
 	return ok
 }
 
+func (attributes *AllAttributes) IsAnAliasOf(attribute string, name string) bool {
+	definition, ok := attributes.supportedAttributes[strings.ToLower(attribute)]
+	return ok && definition == attributeDefinitions[name]
+}
+
+/*
+IsABooleanAttribute returns true if the attribute is always true or false, so that it can be the 'where' clause by itself.
+*/
+func (attributes *AllAttributes) IsABooleanAttribute(attribute string) bool {
+	definition, ok := attributes.supportedAttributes[strings.ToLower(attribute)]
+	return ok && definition.isBoolean
+}
+
 func (attributes *AllAttributes) AllAttributeWithAliases() map[string][]string {
 	supportedAttributes := make(map[string][]string)
 	for _, definition := range attributeDefinitions {
@@ -0,0 +1,88 @@
+package context
+
+import (
+	"goselect/parser/executable"
+	"goselect/parser/filesystem"
+	"strings"
+	"sync"
+)
+
+/*
+executableAttributeValues return the values of the binary attributes from the headers of an ELF, a Mach-O or a PE binary.
+The binary is nil for a file that is not a binary, a directory, or a file that can not be read.
+*/
+var executableAttributeValues = map[string]func(binary *executable.Executable) Value{
+	AttributeBinaryType: func(binary *executable.Executable) Value {
+		if binary == nil {
+			return StringValue("")
+		}
+		return StringValue(binary.Type)
+	},
+	AttributeArchitecture: func(binary *executable.Executable) Value {
+		if binary == nil {
+			return StringValue("")
+		}
+		return StringValue(binary.Architecture)
+	},
+	AttributeNameIsStripped: func(binary *executable.Executable) Value {
+		return booleanValueUsing(binary != nil && binary.IsStripped)
+	},
+	AttributeNameIsStatic: func(binary *executable.Executable) Value {
+		return booleanValueUsing(binary != nil && binary.IsStatic)
+	},
+	AttributeDynamicLibraries: func(binary *executable.Executable) Value {
+		if binary == nil {
+			return StringValue("")
+		}
+		return StringValue(strings.Join(binary.DynamicLibraries, ","))
+	},
+	AttributeGoVersion: func(binary *executable.Executable) Value {
+		if binary == nil {
+			return StringValue("")
+		}
+		return StringValue(binary.GoVersion)
+	},
+	AttributeGoModule: func(binary *executable.Executable) Value {
+		if binary == nil {
+			return StringValue("")
+		}
+		return StringValue(binary.GoModulePath)
+	},
+}
+
+/*
+executableMetadata is the metadata of a binary, shared by all the binary attributes of a file.
+The headers of the binary are read once, when the first of the binary attributes is needed.
+*/
+type executableMetadata struct {
+	filePath   string
+	fileSystem filesystem.FileSystem
+	once       sync.Once
+	binary     *executable.Executable
+}
+
+func newExecutableMetadata(filePath string, fileSystem filesystem.FileSystem) *executableMetadata {
+	return &executableMetadata{filePath: filePath, fileSystem: fileSystem}
+}
+
+func (metadata *executableMetadata) read() *executable.Executable {
+	metadata.once.Do(func() {
+		file, err := metadata.fileSystem.Open(metadata.filePath)
+		if err != nil {
+			return
+		}
+		defer file.Close()
+
+		if info, err := file.Stat(); err != nil || !info.Mode().IsRegular() {
+			return
+		}
+		readerAt, _, err := filesystem.HeaderReaderAtOf(file, maxHeaderBytes)
+		if err != nil {
+			return
+		}
+		if binary, err := executable.Read(readerAt); err == nil {
+			metadata.binary = binary
+		}
+	})
+	return metadata.binary
+}
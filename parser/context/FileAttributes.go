@@ -1,7 +1,10 @@
 package context
 
 import (
+	"fmt"
 	"goselect/parser/context/platform"
+	"goselect/parser/filesystem"
+	"goselect/parser/git"
 	"io/fs"
 	"os"
 	"path/filepath"
@@ -10,47 +13,209 @@ import (
 
 type EvaluatingValue struct {
 	value           Value
-	filePath        string
 	isEvaluated     bool
 	aliases         []string
 	evaluationBlock AttributeLazyEvaluationBlock
 }
 
+/*
+FileAttributes are the attributes of a file. The file system is the one the file is read through, by the lazily
+evaluated attributes and by the functions that read the file, and is nil for a file that is not on a file system,
+like an entry inside an archive or a file that could not be read.
+The attributes of a group, like the image attributes, are set only when a query refers to one of them, using the file,
+its path and the context.
+*/
 type FileAttributes struct {
 	attributes map[string]EvaluatingValue
+	fileSystem filesystem.FileSystem
+	file       fs.FileInfo
+	filePath   string
+	ctx        *ParsingApplicationContext
+}
+
+/*
+attributeGroupByAlias has the function that sets the attributes of a group for a file, by the alias of each attribute
+of the group. A group is set for a file only when a query refers to one of its attributes, so that a file does not
+carry the lazily evaluated attributes, like the image metadata, that the query does not refer to.
+*/
+var attributeGroupByAlias = newAttributeGroupByAlias()
+
+func newAttributeGroupByAlias() map[string]func(fileAttributes *FileAttributes) {
+	groupByAlias := make(map[string]func(fileAttributes *FileAttributes))
+	register := func(setGroup func(fileAttributes *FileAttributes), attributes ...string) {
+		for _, attribute := range attributes {
+			for _, alias := range attributeDefinitions[attribute].aliases {
+				groupByAlias[alias] = setGroup
+			}
+		}
+	}
+	register((*FileAttributes).setBirthTime, AttributeBirthTime)
+	register((*FileAttributes).setIdentity, AttributeInode, AttributeDevice, AttributeFileId, AttributeLinks, AttributeSpecialDevice)
+	register((*FileAttributes).setMimeType, AttributeMimeType)
+	register((*FileAttributes).setContents, AttributeContents)
+	register((*FileAttributes).setTextStatistics, textStatisticsAttributes...)
+	register((*FileAttributes).setContentHashes, contentHashAttributes...)
+	for attribute := range imageAttributeValues {
+		register((*FileAttributes).setImageMetadata, attribute)
+	}
+	for attribute := range mediaAttributeValues {
+		register((*FileAttributes).setMediaMetadata, attribute)
+	}
+	for attribute := range executableAttributeValues {
+		register((*FileAttributes).setExecutableMetadata, attribute)
+	}
+	register((*FileAttributes).setSymbolicLink, symbolicLinkAttributes...)
+	register((*FileAttributes).setExtendedAttributes, extendedAttributes...)
+	register((*FileAttributes).setGitObject, AttributeGitHash, AttributeGitMode, AttributeLastCommit, AttributeLastCommitTime, AttributeLastAuthor)
+	return groupByAlias
 }
 
 func ToFileAttributes(directory string, file fs.FileInfo, ctx *ParsingApplicationContext) *FileAttributes {
 	fileAttributes := newFileAttributes()
-	fileAttributes.setPath(directory, file, ctx.allAttributes)
+	fileAttributes.fileSystem = ctx.fileSystem
+	fileAttributes.file = file
+	fileAttributes.filePath = joinPath(directory, file.Name())
+	fileAttributes.ctx = ctx
+	fileAttributes.setPath(ctx)
 
 	hiddenFile, _ := platform.IsHiddenFile(fileAttributes.Get(AttributePath).GetAsString(), file.Name())
 	fileAttributes.setName(file, hiddenFile, ctx.allAttributes)
 	fileAttributes.setExtension(file, hiddenFile, ctx.allAttributes)
 	fileAttributes.setSize(file, ctx.allAttributes)
-	fileAttributes.setFileType(directory, file, hiddenFile, ctx.allAttributes)
+	fileAttributes.setFileType(file, hiddenFile, ctx)
 	fileAttributes.setTimes(file, ctx.allAttributes)
 	fileAttributes.setPermission(file, ctx.allAttributes)
 	fileAttributes.setBlock(file, ctx.allAttributes)
-	fileAttributes.setUserGroup(file, ctx.allAttributes)
-	fileAttributes.setMimeType(directory, file, ctx.allAttributes)
+	fileAttributes.setUserGroup(file, ctx)
+	fileAttributes.setError(nil, ctx.allAttributes)
+	fileAttributes.setIgnored(false, ctx.allAttributes)
+
+	return fileAttributes
+}
+
+func ToFileAttributesWithError(directory string, name string, err error, ctx *ParsingApplicationContext) *FileAttributes {
+	fileAttributes := newFileAttributes()
+	newPath := joinPath(directory, name)
+	if absolutePath, err := ctx.fileSystem.Abs(newPath); err == nil {
+		fileAttributes.setAllAliasesForEvaluatedAttribute(StringValue(absolutePath), ctx.allAttributes.aliasesFor(AttributeAbsolutePath))
+	}
+	fileAttributes.setAllAliasesForEvaluatedAttribute(StringValue(newPath), ctx.allAttributes.aliasesFor(AttributePath))
+	fileAttributes.setAllAliasesForEvaluatedAttribute(StringValue(name), ctx.allAttributes.aliasesFor(AttributeName))
+	fileAttributes.setAllAliasesForEvaluatedAttribute(StringValue(""), ctx.allAttributes.aliasesFor(AttributeContents))
+	fileAttributes.setEmptyTextStatistics(ctx.allAttributes)
+	fileAttributes.setEmptyContentHashes(ctx.allAttributes)
+	fileAttributes.setEmptyImageMetadata(ctx.allAttributes)
+	fileAttributes.setEmptyMediaMetadata(ctx.allAttributes)
+	fileAttributes.setEmptyExecutableMetadata(ctx.allAttributes)
+	fileAttributes.setError(err, ctx.allAttributes)
+	fileAttributes.setIgnored(false, ctx.allAttributes)
 
 	return fileAttributes
 }
 
+/*
+ToArchiveEntryAttributes returns the attributes of an entry inside an archive, from the header stored in the archive.
+The path of the entry is the path of the archive followed by the path of the entry inside the archive,
+and the attributes that need the file on the disk, like blocks, user, group and mime type, are not available.
+The contents, the hashes and the image, media and binary metadata of an entry are blank and its lines, words and chars are 0, the entries are never extracted.
+*/
+func ToArchiveEntryAttributes(
+	archivePath string,
+	entryPath string,
+	file fs.FileInfo,
+	compressedSize int64,
+	isEmpty bool,
+	ctx *ParsingApplicationContext,
+) *FileAttributes {
+	fileAttributes := newFileAttributes()
+	newPath := archivePath + string(os.PathSeparator) + filepath.FromSlash(entryPath)
+	if absolutePath, err := ctx.fileSystem.Abs(newPath); err == nil {
+		fileAttributes.setAllAliasesForEvaluatedAttribute(StringValue(absolutePath), ctx.allAttributes.aliasesFor(AttributeAbsolutePath))
+	}
+	fileAttributes.setAllAliasesForEvaluatedAttribute(StringValue(newPath), ctx.allAttributes.aliasesFor(AttributePath))
+
+	hiddenFile := strings.HasPrefix(file.Name(), ".")
+	fileAttributes.setName(file, hiddenFile, ctx.allAttributes)
+	fileAttributes.setExtension(file, hiddenFile, ctx.allAttributes)
+	fileAttributes.setSize(file, ctx.allAttributes)
+	fileAttributes.setAllAliasesForEvaluatedAttribute(booleanValueUsing(file.IsDir()), ctx.allAttributes.aliasesFor(AttributeNameIsDir))
+	fileAttributes.setAllAliasesForEvaluatedAttribute(booleanValueUsing(file.Mode().IsRegular()), ctx.allAttributes.aliasesFor(AttributeNameIsFile))
+	fileAttributes.setAllAliasesForEvaluatedAttribute(booleanValueUsing(file.Mode()&os.ModeSymlink == os.ModeSymlink), ctx.allAttributes.aliasesFor(AttributeNameIsSymbolicLink))
+	fileAttributes.setAllAliasesForEvaluatedAttribute(booleanValueUsing(isEmpty), ctx.allAttributes.aliasesFor(AttributeNameIsEmpty))
+	fileAttributes.setAllAliasesForEvaluatedAttribute(booleanValueUsing(hiddenFile), ctx.allAttributes.aliasesFor(AttributeNameIsHidden))
+	fileAttributes.setAllAliasesForEvaluatedAttribute(DateTimeValue(file.ModTime()), ctx.allAttributes.aliasesFor(AttributeModifiedTime))
+	fileAttributes.setPermission(file, ctx.allAttributes)
+	fileAttributes.setAllAliasesForEvaluatedAttribute(Int64Value(compressedSize), ctx.allAttributes.aliasesFor(AttributeCompressedSize))
+	fileAttributes.setAllAliasesForEvaluatedAttribute(StringValue(""), ctx.allAttributes.aliasesFor(AttributeContents))
+	fileAttributes.setEmptyTextStatistics(ctx.allAttributes)
+	fileAttributes.setEmptyContentHashes(ctx.allAttributes)
+	fileAttributes.setEmptyImageMetadata(ctx.allAttributes)
+	fileAttributes.setEmptyMediaMetadata(ctx.allAttributes)
+	fileAttributes.setEmptyExecutableMetadata(ctx.allAttributes)
+	fileAttributes.setError(nil, ctx.allAttributes)
+	fileAttributes.setIgnored(false, ctx.allAttributes)
+
+	return fileAttributes
+}
+
+func (fileAttributes *FileAttributes) WithDepth(depth int, ctx *ParsingApplicationContext) *FileAttributes {
+	fileAttributes.setAllAliasesForEvaluatedAttribute(IntValue(depth), ctx.allAttributes.aliasesFor(AttributeDepth))
+	return fileAttributes
+}
+
+func (fileAttributes *FileAttributes) WithRoot(root string, ctx *ParsingApplicationContext) *FileAttributes {
+	fileAttributes.setAllAliasesForEvaluatedAttribute(StringValue(root), ctx.allAttributes.aliasesFor(AttributeRoot))
+	return fileAttributes
+}
+
+func (fileAttributes *FileAttributes) WithIgnored(ignored bool, ctx *ParsingApplicationContext) *FileAttributes {
+	fileAttributes.setIgnored(ignored, ctx.allAttributes)
+	return fileAttributes
+}
+
+func (fileAttributes *FileAttributes) WithError(err error, ctx *ParsingApplicationContext) *FileAttributes {
+	if err != nil {
+		fileAttributes.setError(err, ctx.allAttributes)
+	}
+	return fileAttributes
+}
+
 func (fileAttributes *FileAttributes) Get(attribute string) Value {
-	evaluatingValue, ok := fileAttributes.attributes[strings.ToLower(attribute)]
+	alias := strings.ToLower(attribute)
+	evaluatingValue, ok := fileAttributes.attributes[alias]
+	if !ok {
+		evaluatingValue, ok = fileAttributes.setGroupOf(alias)
+	}
 	if ok {
 		if evaluatingValue.isEvaluated {
 			return evaluatingValue.value
 		}
-		value := evaluatingValue.evaluationBlock.evaluate(evaluatingValue.filePath)
+		value := evaluatingValue.evaluationBlock.evaluate(fileAttributes.filePath, fileAttributes.fileSystem)
 		fileAttributes.setAllAliasesForEvaluatedAttribute(value, evaluatingValue.aliases)
 		return value
 	}
 	return EmptyValue
 }
 
+/*
+setGroupOf sets the attributes of the group that the alias belongs to, on the first reference to one of them.
+An attribute that the group does not set for the file, like the inode on a platform without it, is set as empty so
+that the group is set only once.
+*/
+func (fileAttributes *FileAttributes) setGroupOf(alias string) (EvaluatingValue, bool) {
+	setGroup, ok := attributeGroupByAlias[alias]
+	if !ok || fileAttributes.ctx == nil {
+		return EvaluatingValue{}, false
+	}
+	setGroup(fileAttributes)
+	evaluatingValue, ok := fileAttributes.attributes[alias]
+	if !ok {
+		evaluatingValue = EvaluatingValue{value: EmptyValue, isEvaluated: true}
+		fileAttributes.attributes[alias] = evaluatingValue
+	}
+	return evaluatingValue, true
+}
+
 func newFileAttributes() *FileAttributes {
 	return &FileAttributes{attributes: make(map[string]EvaluatingValue)}
 }
@@ -66,13 +231,13 @@ func (fileAttributes *FileAttributes) setSize(file fs.FileInfo, attributes *AllA
 	fileAttributes.setAllAliasesForEvaluatedAttribute(Int64Value(file.Size()), attributes.aliasesFor(AttributeSize))
 }
 
-func (fileAttributes *FileAttributes) setFileType(directory string, file fs.FileInfo, hiddenFile bool, attributes *AllAttributes) {
+func (fileAttributes *FileAttributes) setFileType(file fs.FileInfo, hiddenFile bool, ctx *ParsingApplicationContext) {
+	attributes := ctx.allAttributes
 	fileAttributes.setAllAliasesForEvaluatedAttribute(booleanValueUsing(file.IsDir()), attributes.aliasesFor(AttributeNameIsDir))
 	fileAttributes.setAllAliasesForEvaluatedAttribute(booleanValueUsing(file.Mode().IsRegular()), attributes.aliasesFor(AttributeNameIsFile))
 	fileAttributes.setAllAliasesForEvaluatedAttribute(booleanValueUsing(file.Mode()&os.ModeSymlink == os.ModeSymlink), attributes.aliasesFor(AttributeNameIsSymbolicLink))
 	if file.Mode().IsDir() {
-		newPath := fileAttributes.filePath(directory, file)
-		entries, _ := os.ReadDir(newPath)
+		entries, _ := ctx.fileSystem.ReadDir(fileAttributes.filePath)
 		fileAttributes.setAllAliasesForEvaluatedAttribute(booleanValueUsing(len(entries) == 0), attributes.aliasesFor(AttributeNameIsEmpty))
 	} else {
 		fileAttributes.setAllAliasesForEvaluatedAttribute(booleanValueUsing(file.Size() == 0), attributes.aliasesFor(AttributeNameIsEmpty))
@@ -85,15 +250,21 @@ func (fileAttributes *FileAttributes) setTimes(file fs.FileInfo, attributes *All
 	fileAttributes.setAllAliasesForEvaluatedAttribute(DateTimeValue(created), attributes.aliasesFor(AttributeCreatedTime))
 	fileAttributes.setAllAliasesForEvaluatedAttribute(DateTimeValue(modified), attributes.aliasesFor(AttributeModifiedTime))
 	fileAttributes.setAllAliasesForEvaluatedAttribute(DateTimeValue(accessed), attributes.aliasesFor(AttributeAccessedTime))
+	if changed, ok := platform.FileChangeTime(file); ok {
+		fileAttributes.setAllAliasesForEvaluatedAttribute(DateTimeValue(changed), attributes.aliasesFor(AttributeChangeTime))
+	}
 }
 
-func (fileAttributes *FileAttributes) setPath(directory string, file fs.FileInfo, attributes *AllAttributes) {
-	newPath := fileAttributes.filePath(directory, file)
-	absolutePath, err := filepath.Abs(newPath)
+func (fileAttributes *FileAttributes) setBirthTime() {
+	fileAttributes.setAllAliasesForUnevaluatedAttributeUsing(AttributeBirthTime, BirthTimeAttributeEvaluationBlock{file: fileAttributes.file})
+}
+
+func (fileAttributes *FileAttributes) setPath(ctx *ParsingApplicationContext) {
+	absolutePath, err := ctx.fileSystem.Abs(fileAttributes.filePath)
 	if err == nil {
-		fileAttributes.setAllAliasesForEvaluatedAttribute(StringValue(absolutePath), attributes.aliasesFor(AttributeAbsolutePath))
+		fileAttributes.setAllAliasesForEvaluatedAttribute(StringValue(absolutePath), ctx.allAttributes.aliasesFor(AttributeAbsolutePath))
 	}
-	fileAttributes.setAllAliasesForEvaluatedAttribute(StringValue(newPath), attributes.aliasesFor(AttributePath))
+	fileAttributes.setAllAliasesForEvaluatedAttribute(StringValue(fileAttributes.filePath), ctx.allAttributes.aliasesFor(AttributePath))
 }
 
 func (fileAttributes *FileAttributes) setExtension(file fs.FileInfo, hiddenFile bool, attributes *AllAttributes) {
@@ -114,6 +285,11 @@ func (fileAttributes *FileAttributes) setPermission(file fs.FileInfo, attributes
 	fileAttributes.setAllAliasesForEvaluatedAttribute(booleanValueUsing(perm.othersRead()), attributes.aliasesFor(AttributeOthersRead))
 	fileAttributes.setAllAliasesForEvaluatedAttribute(booleanValueUsing(perm.othersWrite()), attributes.aliasesFor(AttributeOthersWrite))
 	fileAttributes.setAllAliasesForEvaluatedAttribute(booleanValueUsing(perm.othersExecute()), attributes.aliasesFor(AttributeOthersExecute))
+
+	mode := file.Mode()
+	fileAttributes.setAllAliasesForEvaluatedAttribute(booleanValueUsing(mode&fs.ModeSetuid != 0), attributes.aliasesFor(AttributeIsSetUid))
+	fileAttributes.setAllAliasesForEvaluatedAttribute(booleanValueUsing(mode&fs.ModeSetgid != 0), attributes.aliasesFor(AttributeIsSetGid))
+	fileAttributes.setAllAliasesForEvaluatedAttribute(booleanValueUsing(mode&fs.ModeSticky != 0), attributes.aliasesFor(AttributeIsSticky))
 }
 
 func (fileAttributes *FileAttributes) setBlock(file fs.FileInfo, attributes *AllAttributes) {
@@ -122,8 +298,24 @@ func (fileAttributes *FileAttributes) setBlock(file fs.FileInfo, attributes *All
 	fileAttributes.setAllAliasesForEvaluatedAttribute(Int64Value(blocks), attributes.aliasesFor(AttributeBlocks))
 }
 
-func (fileAttributes *FileAttributes) setUserGroup(file fs.FileInfo, attributes *AllAttributes) {
-	userId, userName, groupId, groupName := platform.UserGroup(file)
+func (fileAttributes *FileAttributes) setIdentity() {
+	file, attributes := fileAttributes.file, fileAttributes.ctx.allAttributes
+	if device, inode, ok := platform.FileIdentity(file); ok {
+		fileAttributes.setAllAliasesForEvaluatedAttribute(Uint64Value(inode), attributes.aliasesFor(AttributeInode))
+		fileAttributes.setAllAliasesForEvaluatedAttribute(Uint64Value(device), attributes.aliasesFor(AttributeDevice))
+		fileAttributes.setAllAliasesForEvaluatedAttribute(StringValue(fmt.Sprintf("%v:%v", device, inode)), attributes.aliasesFor(AttributeFileId))
+	}
+	if links, ok := platform.FileLinks(file); ok {
+		fileAttributes.setAllAliasesForEvaluatedAttribute(Uint64Value(links), attributes.aliasesFor(AttributeLinks))
+	}
+	if specialDevice, ok := platform.FileSpecialDevice(file); ok {
+		fileAttributes.setAllAliasesForEvaluatedAttribute(Uint64Value(specialDevice), attributes.aliasesFor(AttributeSpecialDevice))
+	}
+}
+
+func (fileAttributes *FileAttributes) setUserGroup(file fs.FileInfo, ctx *ParsingApplicationContext) {
+	attributes := ctx.allAttributes
+	userId, userName, groupId, groupName := platform.UserGroup(file, ctx.ownerNames)
 	fileAttributes.setUserId(userId, attributes)
 	fileAttributes.setUserName(userName, attributes)
 	fileAttributes.setGroupId(groupId, attributes)
@@ -146,8 +338,114 @@ func (fileAttributes *FileAttributes) setGroupName(groupName string, attributes
 	fileAttributes.setAllAliasesForEvaluatedAttribute(StringValue(groupName), attributes.aliasesFor(AttributeGroupName))
 }
 
-func (fileAttributes *FileAttributes) setMimeType(directory string, file fs.FileInfo, attributes *AllAttributes) {
-	fileAttributes.setAllAliasesForUnevaluatedAttribute(AttributeMimeType, fileAttributes.filePath(directory, file), attributes)
+func (fileAttributes *FileAttributes) setMimeType() {
+	fileAttributes.setAllAliasesForUnevaluatedAttribute(AttributeMimeType)
+}
+
+func (fileAttributes *FileAttributes) setContents() {
+	fileAttributes.setAllAliasesForUnevaluatedAttributeUsing(AttributeContents, ContentsAttributeEvaluationBlock{sizeLimit: fileAttributes.ctx.contentsLimit})
+}
+
+func (fileAttributes *FileAttributes) setTextStatistics() {
+	statistics := newTextStatistics(fileAttributes.filePath, fileAttributes.fileSystem)
+	fileAttributes.setAllAliasesForUnevaluatedAttributeUsing(AttributeLines, LinesAttributeEvaluationBlock{statistics: statistics})
+	fileAttributes.setAllAliasesForUnevaluatedAttributeUsing(AttributeWords, WordsAttributeEvaluationBlock{statistics: statistics})
+	fileAttributes.setAllAliasesForUnevaluatedAttributeUsing(AttributeChars, CharsAttributeEvaluationBlock{statistics: statistics})
+	fileAttributes.setAllAliasesForUnevaluatedAttributeUsing(AttributeMaxLineLength, MaxLineLengthAttributeEvaluationBlock{statistics: statistics})
+}
+
+func (fileAttributes *FileAttributes) setEmptyTextStatistics(attributes *AllAttributes) {
+	for _, attribute := range textStatisticsAttributes {
+		fileAttributes.setAllAliasesForEvaluatedAttribute(Int64Value(0), attributes.aliasesFor(attribute))
+	}
+}
+
+func (fileAttributes *FileAttributes) setContentHashes() {
+	for _, attribute := range contentHashAttributes {
+		fileAttributes.setAllAliasesForUnevaluatedAttribute(attribute)
+	}
+}
+
+func (fileAttributes *FileAttributes) setEmptyContentHashes(attributes *AllAttributes) {
+	for _, attribute := range contentHashAttributes {
+		fileAttributes.setAllAliasesForEvaluatedAttribute(StringValue(""), attributes.aliasesFor(attribute))
+	}
+}
+
+func (fileAttributes *FileAttributes) setImageMetadata() {
+	metadata := newImageMetadata(fileAttributes.filePath, fileAttributes.fileSystem)
+	for attribute, valueOf := range imageAttributeValues {
+		fileAttributes.setAllAliasesForUnevaluatedAttributeUsing(attribute, ImageAttributeEvaluationBlock{metadata: metadata, valueOf: valueOf})
+	}
+}
+
+func (fileAttributes *FileAttributes) setEmptyImageMetadata(attributes *AllAttributes) {
+	for attribute, valueOf := range imageAttributeValues {
+		fileAttributes.setAllAliasesForEvaluatedAttribute(valueOf(nil), attributes.aliasesFor(attribute))
+	}
+}
+
+func (fileAttributes *FileAttributes) setMediaMetadata() {
+	metadata := newMediaMetadata(fileAttributes.filePath, fileAttributes.fileSystem)
+	for attribute, valueOf := range mediaAttributeValues {
+		fileAttributes.setAllAliasesForUnevaluatedAttributeUsing(attribute, MediaAttributeEvaluationBlock{metadata: metadata, valueOf: valueOf})
+	}
+}
+
+func (fileAttributes *FileAttributes) setEmptyMediaMetadata(attributes *AllAttributes) {
+	for attribute, valueOf := range mediaAttributeValues {
+		fileAttributes.setAllAliasesForEvaluatedAttribute(valueOf(nil), attributes.aliasesFor(attribute))
+	}
+}
+
+func (fileAttributes *FileAttributes) setExecutableMetadata() {
+	metadata := newExecutableMetadata(fileAttributes.filePath, fileAttributes.fileSystem)
+	for attribute, valueOf := range executableAttributeValues {
+		fileAttributes.setAllAliasesForUnevaluatedAttributeUsing(attribute, ExecutableAttributeEvaluationBlock{metadata: metadata, valueOf: valueOf})
+	}
+}
+
+func (fileAttributes *FileAttributes) setEmptyExecutableMetadata(attributes *AllAttributes) {
+	for attribute, valueOf := range executableAttributeValues {
+		fileAttributes.setAllAliasesForEvaluatedAttribute(valueOf(nil), attributes.aliasesFor(attribute))
+	}
+}
+
+func (fileAttributes *FileAttributes) setSymbolicLink() {
+	for _, attribute := range symbolicLinkAttributes {
+		fileAttributes.setAllAliasesForUnevaluatedAttribute(attribute)
+	}
+}
+
+func (fileAttributes *FileAttributes) setExtendedAttributes() {
+	for _, attribute := range extendedAttributes {
+		fileAttributes.setAllAliasesForUnevaluatedAttribute(attribute)
+	}
+}
+
+func (fileAttributes *FileAttributes) setGitObject() {
+	hash, mode := "", ""
+	if object, ok := fileAttributes.file.Sys().(*git.ObjectInfo); ok {
+		hash, mode = object.Hash.String(), fmt.Sprintf("%06o", object.Mode)
+	}
+	fileAttributes.setAllAliasesForEvaluatedAttribute(StringValue(hash), fileAttributes.ctx.allAttributes.aliasesFor(AttributeGitHash))
+	fileAttributes.setAllAliasesForEvaluatedAttribute(StringValue(mode), fileAttributes.ctx.allAttributes.aliasesFor(AttributeGitMode))
+
+	fileAttributes.setAllAliasesForUnevaluatedAttribute(AttributeLastCommit)
+	fileAttributes.setAllAliasesForUnevaluatedAttribute(AttributeLastCommitTime)
+	fileAttributes.setAllAliasesForUnevaluatedAttribute(AttributeLastAuthor)
+}
+
+func (fileAttributes *FileAttributes) setError(err error, attributes *AllAttributes) {
+	if err != nil {
+		fileAttributes.setAllAliasesForEvaluatedAttribute(StringValue(err.Error()), attributes.aliasesFor(AttributeError))
+		return
+	}
+	fileAttributes.setAllAliasesForEvaluatedAttribute(StringValue(""), attributes.aliasesFor(AttributeError))
+}
+
+func (fileAttributes *FileAttributes) setIgnored(ignored bool, attributes *AllAttributes) {
+	fileAttributes.setAllAliasesForEvaluatedAttribute(booleanValueUsing(ignored), attributes.aliasesFor(AttributeNameIsIgnored))
 }
 
 func (fileAttributes *FileAttributes) setAllAliasesForEvaluatedAttribute(value Value, aliases []string) {
@@ -156,31 +454,32 @@ func (fileAttributes *FileAttributes) setAllAliasesForEvaluatedAttribute(value V
 	}
 }
 
-func (fileAttributes *FileAttributes) setAllAliasesForUnevaluatedAttribute(
-	attribute string,
-	filePath string,
-	attributes *AllAttributes,
-) {
-	aliases := attributes.aliasesFor(attribute)
-	definition := attributes.attributeDefinitionFor(attribute)
+func (fileAttributes *FileAttributes) setAllAliasesForUnevaluatedAttribute(attribute string) {
+	definition := fileAttributes.ctx.allAttributes.attributeDefinitionFor(attribute)
+	fileAttributes.setAllAliasesForUnevaluatedAttributeUsing(attribute, definition.lazyEvaluationBlock)
+}
 
+func (fileAttributes *FileAttributes) setAllAliasesForUnevaluatedAttributeUsing(attribute string, evaluationBlock AttributeLazyEvaluationBlock) {
+	aliases := fileAttributes.ctx.allAttributes.aliasesFor(attribute)
 	for _, alias := range aliases {
 		fileAttributes.attributes[alias] = EvaluatingValue{
 			isEvaluated:     false,
-			filePath:        filePath,
 			aliases:         aliases,
-			evaluationBlock: definition.lazyEvaluationBlock,
+			evaluationBlock: evaluationBlock,
 		}
 	}
 }
 
-func (fileAttributes *FileAttributes) filePath(directory string, file fs.FileInfo) string {
+/*
+joinPath joins the directory and the name as given, without cleaning the path, so that a path listed in a file list
+is returned as it was listed. An empty directory, the directory of a bare name like 'a.txt', gives the name alone.
+*/
+func joinPath(directory string, name string) string {
 	pathSeparator := string(os.PathSeparator)
-	newPath := directory + pathSeparator + file.Name()
-	if strings.HasSuffix(directory, pathSeparator) {
-		newPath = directory + file.Name()
+	if directory == "" || strings.HasSuffix(directory, pathSeparator) || strings.HasSuffix(directory, "/") {
+		return directory + name
 	}
-	return newPath
+	return directory + pathSeparator + name
 }
 
 type filePermission uint32
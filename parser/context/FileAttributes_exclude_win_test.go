@@ -4,9 +4,14 @@
 package context
 
 import (
+	"golang.org/x/sys/unix"
+	"goselect/parser/filesystem"
 	"os"
 	"os/user"
+	"path/filepath"
 	"reflect"
+	"strconv"
+	"syscall"
 	"testing"
 )
 
@@ -72,7 +77,7 @@ func TestUserGroup(t *testing.T) {
 	expectedUserId := currentUser.Uid
 	expectedUserName := currentUser.Username
 
-	expectedGroupId := currentUser.Gid
+	expectedGroupId := strconv.FormatUint(uint64(file.Sys().(*syscall.Stat_t).Gid), 10)
 	expectedGroup, _ := user.LookupGroupId(expectedGroupId)
 	expectedGroupName := expectedGroup.Name
 
@@ -144,3 +149,212 @@ func TestFilePermissionForOthers(t *testing.T) {
 		t.Fatalf("Expected permissions for others to be %v, received %v", expected, received)
 	}
 }
+
+func TestFileAttributesForASymbolicLink(t *testing.T) {
+	directory := t.TempDir()
+	if err := os.WriteFile(directory+"/target.log", []byte("log"), 0644); err != nil {
+		panic(err)
+	}
+	if err := os.Symlink("target.log", directory+"/link.log"); err != nil {
+		panic(err)
+	}
+	file, err := os.Lstat(directory + "/link.log")
+	if err != nil {
+		panic(err)
+	}
+	context := NewContext(nil, NewAttributes())
+	fileAttributes := ToFileAttributes(directory, file, context)
+
+	if target := fileAttributes.Get(AttributeLinkTarget).GetAsString(); target != "target.log" {
+		t.Fatalf("Expected link target to be %v, received %v", "target.log", target)
+	}
+	resolvedDirectory, _ := filepath.EvalSymlinks(directory)
+	if resolvedPath := fileAttributes.Get(AttributeResolvedPath).GetAsString(); resolvedPath != resolvedDirectory+"/target.log" {
+		t.Fatalf("Expected resolved path to be %v, received %v", resolvedDirectory+"/target.log", resolvedPath)
+	}
+	if isBroken, _ := fileAttributes.Get(AttributeNameIsBrokenLink).GetBoolean(); isBroken {
+		t.Fatalf("Expected link to not be broken but was broken")
+	}
+}
+
+func TestFileAttributesForABrokenSymbolicLink(t *testing.T) {
+	directory := t.TempDir()
+	if err := os.Symlink("non-existing.log", directory+"/link.log"); err != nil {
+		panic(err)
+	}
+	file, err := os.Lstat(directory + "/link.log")
+	if err != nil {
+		panic(err)
+	}
+	context := NewContext(nil, NewAttributes())
+	fileAttributes := ToFileAttributes(directory, file, context)
+
+	if isBroken, _ := fileAttributes.Get(AttributeNameIsBrokenLink).GetBoolean(); !isBroken {
+		t.Fatalf("Expected link to be broken but was not")
+	}
+	if resolvedPath := fileAttributes.Get(AttributeResolvedPath).GetAsString(); resolvedPath != "" {
+		t.Fatalf("Expected resolved path of a broken link to be blank, received %v", resolvedPath)
+	}
+}
+
+func TestFileAttributesForAFileThatIsNotASymbolicLink(t *testing.T) {
+	file, err := os.Stat("../test/resources/TestResultsWithProjections/single/TestResultsWithProjections_A.txt")
+	if err != nil {
+		panic(err)
+	}
+	context := NewContext(nil, NewAttributes())
+	fileAttributes := ToFileAttributes("../test/resources/TestResultsWithProjections/single", file, context)
+
+	if target := fileAttributes.Get(AttributeLinkTarget).GetAsString(); target != "" {
+		t.Fatalf("Expected link target to be blank, received %v", target)
+	}
+	if isBroken, _ := fileAttributes.Get(AttributeNameIsBrokenLink).GetBoolean(); isBroken {
+		t.Fatalf("Expected a regular file to not be a broken link")
+	}
+}
+
+func TestIdentityOfHardLinks(t *testing.T) {
+	directory := t.TempDir()
+	original, link := filepath.Join(directory, "original.txt"), filepath.Join(directory, "link.txt")
+	if err := os.WriteFile(original, []byte("linked"), 0644); err != nil {
+		panic(err)
+	}
+	if err := os.Link(original, link); err != nil {
+		t.Skipf("hard links are not supported: %v", err)
+	}
+	context := NewContext(nil, NewAttributes())
+	attributesOf := func(path string) *FileAttributes {
+		file, err := os.Lstat(path)
+		if err != nil {
+			panic(err)
+		}
+		return ToFileAttributes(directory, file, context)
+	}
+	originalAttributes, linkAttributes := attributesOf(original), attributesOf(link)
+
+	if links := originalAttributes.Get(AttributeLinks).GetAsString(); links != "2" {
+		t.Fatalf("Expected nlink to be %v, received %v", "2", links)
+	}
+	for _, attribute := range []string{AttributeInode, AttributeDevice, AttributeFileId} {
+		originalValue, linkValue := originalAttributes.Get(attribute).GetAsString(), linkAttributes.Get(attribute).GetAsString()
+		if originalValue == "" || originalValue != linkValue {
+			t.Fatalf("Expected %v of the hard links to be the same, received %v and %v", attribute, originalValue, linkValue)
+		}
+	}
+	if specialDevice := originalAttributes.Get(AttributeSpecialDevice).GetAsString(); specialDevice != "0" {
+		t.Fatalf("Expected rdev of a regular file to be %v, received %v", "0", specialDevice)
+	}
+}
+
+func TestChangeTimeAndBirthTime(t *testing.T) {
+	directory := t.TempDir()
+	path := filepath.Join(directory, "file.txt")
+	if err := os.WriteFile(path, []byte("content"), 0644); err != nil {
+		panic(err)
+	}
+	file, err := os.Lstat(path)
+	if err != nil {
+		panic(err)
+	}
+	fileAttributes := ToFileAttributes(directory, file, NewContext(nil, NewAttributes()))
+
+	changeTime, err := fileAttributes.Get(AttributeChangeTime).GetDateTime()
+	if err != nil || changeTime.IsZero() {
+		t.Fatalf("Expected the change time to be available, received %v", fileAttributes.Get(AttributeChangeTime).GetAsString())
+	}
+	birthTime := fileAttributes.Get(AttributeBirthTime)
+	if birthTime.GetAsString() == "" {
+		t.Skip("the file system does not record the birth time")
+	}
+	if value, _ := birthTime.GetDateTime(); value.After(changeTime) {
+		t.Fatalf("Expected the birth time %v to not be after the change time %v", value, changeTime)
+	}
+}
+
+func TestSpecialPermissionBits(t *testing.T) {
+	directory := t.TempDir()
+	path := filepath.Join(directory, "program")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"), 0755); err != nil {
+		panic(err)
+	}
+	if err := os.Chmod(path, 0755|os.ModeSetuid|os.ModeSetgid); err != nil {
+		t.Skipf("setuid can not be set: %v", err)
+	}
+	file, err := os.Lstat(path)
+	if err != nil {
+		panic(err)
+	}
+	fileAttributes := ToFileAttributes(directory, file, NewContext(nil, NewAttributes()))
+
+	setUid, _ := fileAttributes.Get(AttributeIsSetUid).GetBoolean()
+	setGid, _ := fileAttributes.Get(AttributeIsSetGid).GetBoolean()
+	sticky, _ := fileAttributes.Get(AttributeIsSticky).GetBoolean()
+	if !reflect.DeepEqual([]bool{setUid, setGid, sticky}, []bool{true, true, false}) {
+		t.Fatalf("Expected setuid, setgid and sticky to be %v, received %v", []bool{true, true, false}, []bool{setUid, setGid, sticky})
+	}
+}
+
+func TestExtendedAttributesAndCapabilities(t *testing.T) {
+	directory := t.TempDir()
+	path := filepath.Join(directory, "server")
+	if err := os.WriteFile(path, []byte("server"), 0755); err != nil {
+		panic(err)
+	}
+	if err := unix.Setxattr(path, "user.origin", []byte("https://example.com"), 0); err != nil {
+		t.Skipf("extended attributes are not supported: %v", err)
+	}
+	file, err := os.Lstat(path)
+	if err != nil {
+		panic(err)
+	}
+	functions := NewFunctions()
+	fileAttributes := ToFileAttributes(directory, file, NewContext(functions, NewAttributes()))
+
+	if names := fileAttributes.Get(AttributeExtendedAttributes).GetAsString(); names != "user.origin" {
+		t.Fatalf("Expected xattrs to be %v, received %v", "user.origin", names)
+	}
+	value, _ := functions.Execute("xattr", fileAttributes.Get(AttributePath), StringValue("user.origin"))
+	if value.GetAsString() != "https://example.com" {
+		t.Fatalf("Expected xattr user.origin to be %v, received %v", "https://example.com", value.GetAsString())
+	}
+	value, _ = functions.Execute("xattr", fileAttributes.Get(AttributePath), StringValue("user.missing"))
+	if value.GetAsString() != "" {
+		t.Fatalf("Expected a missing xattr to be blank, received %v", value.GetAsString())
+	}
+	if hasAcl, _ := fileAttributes.Get(AttributeHasAcl).GetBoolean(); hasAcl {
+		t.Fatalf("Expected a file without an ACL to not have an ACL")
+	}
+
+	capability := []byte{0x01, 0x00, 0x00, 0x02, 0x00, 0x04, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}
+	if err := unix.Setxattr(path, "security.capability", capability, 0); err != nil {
+		t.Skipf("file capabilities can not be set: %v", err)
+	}
+	fileAttributes = ToFileAttributes(directory, file, NewContext(functions, NewAttributes()))
+	if capabilities := fileAttributes.Get(AttributeCapabilities).GetAsString(); capabilities != "cap_net_bind_service=ep" {
+		t.Fatalf("Expected capabilities to be %v, received %v", "cap_net_bind_service=ep", capabilities)
+	}
+}
+
+func TestExtendedAttributeOfAFileNotOnTheDisk(t *testing.T) {
+	directory := t.TempDir()
+	path := filepath.Join(directory, "server")
+	if err := os.WriteFile(path, []byte("server"), 0755); err != nil {
+		panic(err)
+	}
+	if err := unix.Setxattr(path, "user.origin", []byte("https://example.com"), 0); err != nil {
+		t.Skipf("extended attributes are not supported: %v", err)
+	}
+	file, err := os.Lstat(path)
+	if err != nil {
+		panic(err)
+	}
+	notTheOs := struct{ filesystem.FileSystem }{filesystem.Os()}
+	functions := NewFunctions()
+	fileAttributes := ToFileAttributes(directory, file, NewContext(functions, NewAttributes()).WithFileSystem(notTheOs))
+
+	pathOfAFunction, _ := functions.Execute("concat", fileAttributes.Get(AttributePath), StringValue(""))
+	value, _ := functions.ExecuteOn("xattr", fileAttributes, pathOfAFunction, StringValue("user.origin"))
+	if value.GetAsString() != "" {
+		t.Fatalf("Expected xattr of a file not on the disk to be blank, received %v", value.GetAsString())
+	}
+}
@@ -4,10 +4,18 @@
 package context
 
 import (
+	"bytes"
+	"encoding/binary"
+	"errors"
 	"fmt"
+	"goselect/parser/filesystem"
 	"os"
+	"path/filepath"
 	"reflect"
+	"runtime"
+	"strings"
 	"testing"
+	"testing/fstest"
 	"time"
 )
 
@@ -318,3 +326,565 @@ func TestMimeType2(t *testing.T) {
 		t.Fatalf("Expected mime type to be %v, received %v", expected, mimeType)
 	}
 }
+
+func TestFileAttributesWithError(t *testing.T) {
+	file, err := os.Stat("../test/resources/TestResultsWithProjections/single")
+	if err != nil {
+		panic(err)
+	}
+	context := NewContext(nil, NewAttributes())
+	fileAttributes := ToFileAttributes("../test/resources/TestResultsWithProjections/", file, context).WithError(errors.New("permission denied"), context)
+	value := fileAttributes.Get(AttributeError).GetAsString()
+
+	if value != "permission denied" {
+		t.Fatalf("Expected error to be %v, received %v", "permission denied", value)
+	}
+}
+
+func TestFileAttributesWithoutError(t *testing.T) {
+	file, err := os.Stat("../test/resources/TestResultsWithProjections/single")
+	if err != nil {
+		panic(err)
+	}
+	context := NewContext(nil, NewAttributes())
+	fileAttributes := ToFileAttributes("../test/resources/TestResultsWithProjections/", file, context).WithError(nil, context)
+	value := fileAttributes.Get(AttributeError).GetAsString()
+
+	if value != "" {
+		t.Fatalf("Expected error to be blank, received %v", value)
+	}
+}
+
+func TestFileAttributesForAnEntryThatCouldNotBeRead(t *testing.T) {
+	context := NewContext(nil, NewAttributes())
+	fileAttributes := ToFileAttributesWithError("../test/resources/TestResultsWithProjections/", "deleted.log", errors.New("no such file"), context)
+
+	if name := fileAttributes.Get(AttributeName).GetAsString(); name != "deleted.log" {
+		t.Fatalf("Expected name to be %v, received %v", "deleted.log", name)
+	}
+	if path := fileAttributes.Get(AttributePath).GetAsString(); path != "../test/resources/TestResultsWithProjections/deleted.log" {
+		t.Fatalf("Expected path to be %v, received %v", "../test/resources/TestResultsWithProjections/deleted.log", path)
+	}
+	if value := fileAttributes.Get(AttributeError).GetAsString(); value != "no such file" {
+		t.Fatalf("Expected error to be %v, received %v", "no such file", value)
+	}
+	if size := fileAttributes.Get(AttributeSize); size != EmptyValue {
+		t.Fatalf("Expected size to be empty, received %v", size)
+	}
+}
+
+func TestFileAttributesWithDepth(t *testing.T) {
+	file, err := os.Stat("../test/resources/TestResultsWithProjections/single")
+	if err != nil {
+		panic(err)
+	}
+	context := NewContext(nil, NewAttributes())
+	fileAttributes := ToFileAttributes("../test/resources/TestResultsWithProjections/", file, context).WithDepth(2, context)
+	depth, _ := fileAttributes.Get(AttributeDepth).GetInt()
+
+	if depth != 2 {
+		t.Fatalf("Expected depth to be %v, received %v", 2, depth)
+	}
+}
+
+func TestArchiveEntryAttributes(t *testing.T) {
+	file, err := os.Stat("../test/resources/TestResultsWithProjections/single/TestResultsWithProjections_A.txt")
+	if err != nil {
+		panic(err)
+	}
+	context := NewContext(nil, NewAttributes())
+	fileAttributes := ToArchiveEntryAttributes("release.zip", "docs/TestResultsWithProjections_A.txt", file, 10, false, context)
+
+	expectedPath := "release.zip" + string(os.PathSeparator) + "docs" + string(os.PathSeparator) + "TestResultsWithProjections_A.txt"
+	if path := fileAttributes.Get(AttributePath).GetAsString(); path != expectedPath {
+		t.Fatalf("Expected path to be %v, received %v", expectedPath, path)
+	}
+	if name := fileAttributes.Get(AttributeName).GetAsString(); name != "TestResultsWithProjections_A.txt" {
+		t.Fatalf("Expected name to be %v, received %v", "TestResultsWithProjections_A.txt", name)
+	}
+	if compressedSize, _ := fileAttributes.Get(AttributeCompressedSize).GetNumericAsFloat64(); compressedSize != 10 {
+		t.Fatalf("Expected compressed size to be %v, received %v", 10, compressedSize)
+	}
+}
+
+func TestFileAttributesOfAFileWithoutPlatformInformation(t *testing.T) {
+	modifiedTime := time.Date(2022, 10, 1, 10, 0, 0, 0, time.UTC)
+	fileSystem := filesystem.FromFS(fstest.MapFS{"docs/README.md": {Data: []byte("readme"), ModTime: modifiedTime}})
+	file, err := fileSystem.Stat("docs/README.md")
+	if err != nil {
+		panic(err)
+	}
+	context := NewContext(nil, NewAttributes()).WithFileSystem(fileSystem)
+	fileAttributes := ToFileAttributes("docs", file, context)
+
+	if userName := fileAttributes.Get(AttributeUserName).GetAsString(); userName != "" {
+		t.Fatalf("Expected user name to be blank, received %v", userName)
+	}
+	if blocks, _ := fileAttributes.Get(AttributeBlocks).GetNumericAsFloat64(); blocks != -1 {
+		t.Fatalf("Expected blocks to be %v, received %v", -1, blocks)
+	}
+	if accessedTime, _ := fileAttributes.Get(AttributeAccessedTime).GetDateTime(); !accessedTime.Equal(modifiedTime) {
+		t.Fatalf("Expected accessed time to be the modified time %v, received %v", modifiedTime, accessedTime)
+	}
+	if mimeType := fileAttributes.Get(AttributeMimeType).GetAsString(); mimeType != "text/plain; charset=utf-8" {
+		t.Fatalf("Expected mime type to be %v, received %v", "text/plain; charset=utf-8", mimeType)
+	}
+}
+
+func TestContentsOfAFile(t *testing.T) {
+	fileSystem := filesystem.FromFS(fstest.MapFS{"main.go": {Data: []byte("package main\n\n// TODO(someone): add main\n")}})
+	file, err := fileSystem.Stat("main.go")
+	if err != nil {
+		panic(err)
+	}
+	context := NewContext(nil, NewAttributes()).WithFileSystem(fileSystem)
+	fileAttributes := ToFileAttributes(".", file, context)
+
+	contents := fileAttributes.Get(AttributeContents).GetAsString()
+	if contents != "package main\n\n// TODO(someone): add main\n" {
+		t.Fatalf("Expected contents to be %q, received %q", "package main\n\n// TODO(someone): add main\n", contents)
+	}
+}
+
+func TestContentsOfAFileWithASizeLimit(t *testing.T) {
+	fileSystem := filesystem.FromFS(fstest.MapFS{"main.go": {Data: []byte("package main\n\n// TODO(someone): add main\n")}})
+	file, err := fileSystem.Stat("main.go")
+	if err != nil {
+		panic(err)
+	}
+	context := NewContext(nil, NewAttributes()).WithFileSystem(fileSystem).WithContentsSizeLimit(7)
+	fileAttributes := ToFileAttributes(".", file, context)
+
+	if contents := fileAttributes.Get(AttributeContents).GetAsString(); contents != "package" {
+		t.Fatalf("Expected contents to be %v, received %v", "package", contents)
+	}
+}
+
+func TestContentsAreSearchedWithoutLoadingTheFile(t *testing.T) {
+	fileSystem := filesystem.FromFS(fstest.MapFS{"main.go": {Data: []byte("package main\n\n// TODO(someone): add main\n")}})
+	file, err := fileSystem.Stat("main.go")
+	if err != nil {
+		panic(err)
+	}
+	context := NewContext(nil, NewAttributes()).WithFileSystem(fileSystem).WithContentsSizeLimit(7)
+	fileAttributes := ToFileAttributes(".", file, context)
+
+	value, _ := NewFunctions().ExecuteOnContents("firstmatch", fileAttributes, StringValue("TODO\\(.*\\)"))
+	if value.GetAsString() != "TODO(someone)" {
+		t.Fatalf("Expected the first match beyond the size limit to be %v, received %v", "TODO(someone)", value.GetAsString())
+	}
+	if fileAttributes.attributes[AttributeContents].isEvaluated {
+		t.Fatalf("Expected the contents to not be loaded while searching")
+	}
+}
+
+func TestContentsAreSearchedWithALineLongerThan1MiB(t *testing.T) {
+	longLine := strings.Repeat("a", 2<<20) + " TODO(long)"
+	fileSystem := filesystem.FromFS(fstest.MapFS{"main.go": {Data: []byte(longLine + "\nTODO(short)\n")}})
+	file, err := fileSystem.Stat("main.go")
+	if err != nil {
+		panic(err)
+	}
+	context := NewContext(nil, NewAttributes()).WithFileSystem(fileSystem).WithContentsSizeLimit(7)
+	fileAttributes := ToFileAttributes(".", file, context)
+
+	value, err := NewFunctions().ExecuteOnContents("countmatches", fileAttributes, StringValue("TODO\\(\\w+\\)"))
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	if count, _ := value.GetInt(); count != 2 {
+		t.Fatalf("Expected countmatches with a line longer than 1 MiB to be %v, received %v", 2, count)
+	}
+}
+
+func TestContentsOfADirectory(t *testing.T) {
+	file, err := os.Stat("../test/resources/TestResultsWithProjections/single")
+	if err != nil {
+		panic(err)
+	}
+	context := NewContext(nil, NewAttributes())
+	fileAttributes := ToFileAttributes("../test/resources/TestResultsWithProjections/", file, context)
+
+	if contents := fileAttributes.Get(AttributeContents).GetAsString(); contents != "" {
+		t.Fatalf("Expected contents of a directory to be blank, received %v", contents)
+	}
+	value, _ := NewFunctions().ExecuteOnContents("grep", fileAttributes, StringValue(".*"))
+	if matched, _ := value.GetBoolean(); matched {
+		t.Fatalf("Expected grep on the contents of a directory to be false")
+	}
+}
+
+/*
+assertAttributes asserts the values of the attributes of a file, compared as strings.
+*/
+func assertAttributes(t *testing.T, fileAttributes *FileAttributes, expected map[string]string) {
+	t.Helper()
+	for attribute, expectedValue := range expected {
+		if value := fileAttributes.Get(attribute).GetAsString(); value != expectedValue {
+			t.Fatalf("Expected %v to be %v, received %v", attribute, expectedValue, value)
+		}
+	}
+}
+
+func TestAttributeGroupsAreSetOnlyWhenReferred(t *testing.T) {
+	fileSystem := filesystem.FromFS(fstest.MapFS{"notes.txt": {Data: []byte("first line\nsecond line\n")}})
+	file, err := fileSystem.Stat("notes.txt")
+	if err != nil {
+		panic(err)
+	}
+	context := NewContext(nil, NewAttributes()).WithFileSystem(fileSystem)
+	fileAttributes := ToFileAttributes(".", file, context)
+
+	if _, ok := fileAttributes.attributes[AttributeLines]; ok {
+		t.Fatalf("Expected the text statistics to not be set before a reference to one of them")
+	}
+	if lines := fileAttributes.Get(AttributeLines).GetAsString(); lines != "2" {
+		t.Fatalf("Expected lines to be %v, received %v", "2", lines)
+	}
+	if _, ok := fileAttributes.attributes[AttributeWords]; !ok {
+		t.Fatalf("Expected all the text statistics to be set after a reference to one of them")
+	}
+	if _, ok := fileAttributes.attributes[AttributeImageWidth]; ok {
+		t.Fatalf("Expected the image attributes to not be set without a reference to one of them")
+	}
+}
+
+func TestAllTheLazilyEvaluatedAttributesBelongToAGroup(t *testing.T) {
+	for attribute, definition := range attributeDefinitions {
+		if definition.lazyEvaluationBlock == nil {
+			continue
+		}
+		for _, alias := range definition.aliases {
+			if _, ok := attributeGroupByAlias[alias]; !ok {
+				t.Fatalf("Expected the lazily evaluated attribute %v to belong to a group, but %v does not", attribute, alias)
+			}
+		}
+	}
+}
+
+func TestTextStatisticsOfAFile(t *testing.T) {
+	fileSystem := filesystem.FromFS(fstest.MapFS{"notes.txt": {Data: []byte("first line\r\n\nthe third  line\nlast")}})
+	file, err := fileSystem.Stat("notes.txt")
+	if err != nil {
+		panic(err)
+	}
+	context := NewContext(nil, NewAttributes()).WithFileSystem(fileSystem)
+	fileAttributes := ToFileAttributes(".", file, context)
+
+	assertAttributes(t, fileAttributes, map[string]string{AttributeLines: "4", AttributeWords: "6", AttributeChars: "33", AttributeMaxLineLength: "15"})
+}
+
+func TestTextStatisticsOfAFileWithMultiByteCharacters(t *testing.T) {
+	fileSystem := filesystem.FromFS(fstest.MapFS{"greeting.txt": {Data: []byte("héllo wörld\n")}})
+	file, err := fileSystem.Stat("greeting.txt")
+	if err != nil {
+		panic(err)
+	}
+	context := NewContext(nil, NewAttributes()).WithFileSystem(fileSystem)
+	fileAttributes := ToFileAttributes(".", file, context)
+
+	if chars, _ := fileAttributes.Get(AttributeChars).GetNumericAsFloat64(); chars != 12 {
+		t.Fatalf("Expected chars to be %v, received %v", 12, chars)
+	}
+	if maxLineLength, _ := fileAttributes.Get(AttributeMaxLineLength).GetNumericAsFloat64(); maxLineLength != 11 {
+		t.Fatalf("Expected maxlinelength to be %v, received %v", 11, maxLineLength)
+	}
+}
+
+func TestTextStatisticsOfABinaryFile(t *testing.T) {
+	fileSystem := filesystem.FromFS(fstest.MapFS{"image.png": {Data: []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR\n\n")}})
+	file, err := fileSystem.Stat("image.png")
+	if err != nil {
+		panic(err)
+	}
+	context := NewContext(nil, NewAttributes()).WithFileSystem(fileSystem)
+	fileAttributes := ToFileAttributes(".", file, context)
+
+	for _, attribute := range []string{AttributeLines, AttributeWords, AttributeChars, AttributeMaxLineLength} {
+		if value, _ := fileAttributes.Get(attribute).GetNumericAsFloat64(); value != 0 {
+			t.Fatalf("Expected %v of a binary file to be 0, received %v", attribute, value)
+		}
+	}
+}
+
+func TestTextStatisticsOfADirectory(t *testing.T) {
+	file, err := os.Stat("../test/resources/TestResultsWithProjections/single")
+	if err != nil {
+		panic(err)
+	}
+	context := NewContext(nil, NewAttributes())
+	fileAttributes := ToFileAttributes("../test/resources/TestResultsWithProjections/", file, context)
+
+	if lines, _ := fileAttributes.Get(AttributeLines).GetNumericAsFloat64(); lines != 0 {
+		t.Fatalf("Expected lines of a directory to be 0, received %v", lines)
+	}
+}
+
+func TestContentHashesOfAFile(t *testing.T) {
+	fileSystem := filesystem.FromFS(fstest.MapFS{"notes.txt": {Data: []byte("abc")}})
+	file, err := fileSystem.Stat("notes.txt")
+	if err != nil {
+		panic(err)
+	}
+	context := NewContext(nil, NewAttributes()).WithFileSystem(fileSystem)
+	fileAttributes := ToFileAttributes(".", file, context)
+
+	expected := map[string]string{
+		AttributeMd5:    "900150983cd24fb0d6963f7d28e17f72",
+		AttributeSha1:   "a9993e364706816aba3e25717850c26c9cd0d89d",
+		AttributeSha256: "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad",
+		AttributeXxHash: "44bc2cf5ad770999",
+	}
+	for attribute, expectedValue := range expected {
+		if value := fileAttributes.Get(attribute).GetAsString(); value != expectedValue {
+			t.Fatalf("Expected %v to be %v, received %v", attribute, expectedValue, value)
+		}
+	}
+}
+
+func TestContentHashesOfADirectory(t *testing.T) {
+	file, err := os.Stat("../test/resources/TestResultsWithProjections/single")
+	if err != nil {
+		panic(err)
+	}
+	context := NewContext(nil, NewAttributes())
+	fileAttributes := ToFileAttributes("../test/resources/TestResultsWithProjections/", file, context)
+
+	if hash := fileAttributes.Get(AttributeSha256).GetAsString(); hash != "" {
+		t.Fatalf("Expected sha256 of a directory to be blank, received %v", hash)
+	}
+}
+
+func TestIdentityAndBirthTimeOfAFileNotOnTheDisk(t *testing.T) {
+	fileSystem := filesystem.FromFS(fstest.MapFS{"notes.txt": {Data: []byte("notes")}})
+	file, err := fileSystem.Stat("notes.txt")
+	if err != nil {
+		panic(err)
+	}
+	context := NewContext(nil, NewAttributes()).WithFileSystem(fileSystem)
+	fileAttributes := ToFileAttributes(".", file, context)
+
+	for _, attribute := range []string{AttributeInode, AttributeDevice, AttributeFileId, AttributeLinks, AttributeChangeTime, AttributeBirthTime} {
+		if value := fileAttributes.Get(attribute).GetAsString(); value != "" {
+			t.Fatalf("Expected %v of a file not on the disk to be blank, received %v", attribute, value)
+		}
+	}
+}
+
+func jpegWithExif() []byte {
+	var tiff bytes.Buffer
+	write := func(values ...interface{}) {
+		for _, value := range values {
+			_ = binary.Write(&tiff, binary.LittleEndian, value)
+		}
+	}
+	tiff.WriteString("II")
+	write(uint16(42), uint32(8))
+	write(uint16(3))
+	write(uint16(0x0110), uint16(2), uint32(8), uint32(50))
+	write(uint16(0x0112), uint16(3), uint32(1), uint16(6), uint16(0))
+	write(uint16(0x8769), uint16(4), uint32(1), uint32(58))
+	write(uint32(0))
+	tiff.WriteString("Pixel 6\x00")
+	write(uint16(1))
+	write(uint16(0x9003), uint16(2), uint32(20), uint32(76))
+	write(uint32(0))
+	tiff.WriteString("2022:07:30 18:45:12\x00")
+
+	var jpeg bytes.Buffer
+	jpeg.Write([]byte{0xff, 0xd8, 0xff, 0xe1})
+	_ = binary.Write(&jpeg, binary.BigEndian, uint16(2+6+tiff.Len()))
+	jpeg.WriteString("Exif\x00\x00")
+	jpeg.Write(tiff.Bytes())
+	jpeg.Write([]byte{0xff, 0xc0, 0x00, 0x0b, 0x08, 0x00, 0x30, 0x00, 0x40, 0x01, 0x01, 0x11, 0x00, 0xff, 0xd9})
+	return jpeg.Bytes()
+}
+
+func TestImageAttributesOfAJpegWithExif(t *testing.T) {
+	fileSystem := filesystem.FromFS(fstest.MapFS{"photo.jpg": {Data: jpegWithExif()}})
+	file, err := fileSystem.Stat("photo.jpg")
+	if err != nil {
+		panic(err)
+	}
+	context := NewContext(nil, NewAttributes()).WithFileSystem(fileSystem)
+	fileAttributes := ToFileAttributes(".", file, context)
+
+	expected := map[string]string{
+		AttributeImageWidth:      "64",
+		AttributeImageHeight:     "48",
+		AttributeImageFormat:     "jpeg",
+		AttributeExifMake:        "",
+		AttributeExifModel:       "Pixel 6",
+		AttributeExifOrientation: "6",
+		AttributeExifHasGps:      "N",
+	}
+	for attribute, expectedValue := range expected {
+		if value := fileAttributes.Get(attribute).GetAsString(); value != expectedValue {
+			t.Fatalf("Expected %v to be %v, received %v", attribute, expectedValue, value)
+		}
+	}
+	dateTime, _ := fileAttributes.Get(AttributeExifDateTime).GetDateTime()
+	if expectedDateTime := time.Date(2022, 7, 30, 18, 45, 12, 0, time.Local); !dateTime.Equal(expectedDateTime) {
+		t.Fatalf("Expected exif_datetime to be %v, received %v", expectedDateTime, dateTime)
+	}
+}
+
+func TestImageAttributesOfAPng(t *testing.T) {
+	file, err := os.Stat("../test/resources/images/where.png")
+	if err != nil {
+		panic(err)
+	}
+	context := NewContext(nil, NewAttributes())
+	fileAttributes := ToFileAttributes("../test/resources/images", file, context)
+
+	expected := map[string]string{
+		AttributeImageWidth:   "3078",
+		AttributeImageHeight:  "204",
+		AttributeImageFormat:  "png",
+		AttributeExifDateTime: "",
+		AttributeExifModel:    "",
+	}
+	for attribute, expectedValue := range expected {
+		if value := fileAttributes.Get(attribute).GetAsString(); value != expectedValue {
+			t.Fatalf("Expected %v to be %v, received %v", attribute, expectedValue, value)
+		}
+	}
+}
+
+func TestImageAttributesOfAFileThatIsNotAnImage(t *testing.T) {
+	fileSystem := filesystem.FromFS(fstest.MapFS{"notes.txt": {Data: []byte("a text file that is not an image")}})
+	file, err := fileSystem.Stat("notes.txt")
+	if err != nil {
+		panic(err)
+	}
+	context := NewContext(nil, NewAttributes()).WithFileSystem(fileSystem)
+	fileAttributes := ToFileAttributes(".", file, context)
+
+	expected := map[string]string{
+		AttributeImageWidth:      "0",
+		AttributeImageFormat:     "",
+		AttributeExifDateTime:    "",
+		AttributeExifOrientation: "0",
+		AttributeExifHasGps:      "N",
+	}
+	for attribute, expectedValue := range expected {
+		if value := fileAttributes.Get(attribute).GetAsString(); value != expectedValue {
+			t.Fatalf("Expected %v to be %v, received %v", attribute, expectedValue, value)
+		}
+	}
+}
+
+func wavOfOneSecond() []byte {
+	var wav bytes.Buffer
+	write := func(values ...interface{}) {
+		for _, value := range values {
+			_ = binary.Write(&wav, binary.LittleEndian, value)
+		}
+	}
+	wav.WriteString("RIFF")
+	write(uint32(4 + 8 + 16 + 8 + 16000))
+	wav.WriteString("WAVEfmt ")
+	write(uint32(16), uint16(1), uint16(1), uint32(8000), uint32(16000), uint16(2), uint16(16))
+	wav.WriteString("data")
+	write(uint32(16000))
+	wav.Write(make([]byte, 16000))
+	return wav.Bytes()
+}
+
+func TestMediaAttributesOfAWav(t *testing.T) {
+	fileSystem := filesystem.FromFS(fstest.MapFS{"tone.wav": {Data: wavOfOneSecond()}})
+	file, err := fileSystem.Stat("tone.wav")
+	if err != nil {
+		panic(err)
+	}
+	context := NewContext(nil, NewAttributes()).WithFileSystem(fileSystem)
+	fileAttributes := ToFileAttributes(".", file, context)
+
+	expected := map[string]string{
+		AttributeMediaFormat: "wav",
+		AttributeDuration:    "1.00",
+		AttributeBitRate:     "128000",
+		AttributeSampleRate:  "8000",
+		AttributeChannels:    "1",
+		AttributeAudioCodec:  "pcm_s16le",
+		AttributeVideoCodec:  "",
+		AttributeVideoWidth:  "0",
+	}
+	for attribute, expectedValue := range expected {
+		if value := fileAttributes.Get(attribute).GetAsString(); value != expectedValue {
+			t.Fatalf("Expected %v to be %v, received %v", attribute, expectedValue, value)
+		}
+	}
+}
+
+func TestMediaAttributesOfAFileThatIsNotAMedia(t *testing.T) {
+	file, err := os.Stat("../test/resources/images/where.png")
+	if err != nil {
+		panic(err)
+	}
+	context := NewContext(nil, NewAttributes())
+	fileAttributes := ToFileAttributes("../test/resources/images", file, context)
+
+	expected := map[string]string{
+		AttributeMediaFormat: "",
+		AttributeDuration:    "0.00",
+		AttributeBitRate:     "0",
+		AttributeAudioCodec:  "",
+	}
+	for attribute, expectedValue := range expected {
+		if value := fileAttributes.Get(attribute).GetAsString(); value != expectedValue {
+			t.Fatalf("Expected %v to be %v, received %v", attribute, expectedValue, value)
+		}
+	}
+}
+
+func TestExecutableAttributesOfTheTestBinary(t *testing.T) {
+	path, err := os.Executable()
+	if err != nil {
+		t.Skipf("test binary not available: %v", err)
+	}
+	file, err := os.Stat(path)
+	if err != nil {
+		panic(err)
+	}
+	context := NewContext(nil, NewAttributes())
+	fileAttributes := ToFileAttributes(filepath.Dir(path), file, context)
+
+	expected := map[string]string{
+		AttributeArchitecture: runtime.GOARCH,
+		"arch":                runtime.GOARCH,
+		AttributeGoVersion:    runtime.Version(),
+		AttributeGoModule:     "goselect",
+	}
+	for attribute, expectedValue := range expected {
+		if value := fileAttributes.Get(attribute).GetAsString(); value != expectedValue {
+			t.Fatalf("Expected %v to be %v, received %v", attribute, expectedValue, value)
+		}
+	}
+	if value := fileAttributes.Get(AttributeBinaryType).GetAsString(); value == "" {
+		t.Fatalf("Expected %v to not be blank", AttributeBinaryType)
+	}
+}
+
+func TestExecutableAttributesOfAFileThatIsNotABinary(t *testing.T) {
+	file, err := os.Stat("../test/resources/images/where.png")
+	if err != nil {
+		panic(err)
+	}
+	context := NewContext(nil, NewAttributes())
+	fileAttributes := ToFileAttributes("../test/resources/images", file, context)
+
+	expected := map[string]string{
+		AttributeBinaryType:       "",
+		AttributeArchitecture:     "",
+		AttributeNameIsStripped:   "N",
+		AttributeNameIsStatic:     "N",
+		AttributeDynamicLibraries: "",
+		AttributeGoVersion:        "",
+	}
+	for attribute, expectedValue := range expected {
+		if value := fileAttributes.Get(attribute).GetAsString(); value != expectedValue {
+			t.Fatalf("Expected %v to be %v, received %v", attribute, expectedValue, value)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package context
+
+import (
+	"goselect/parser/filesystem"
+	"io"
+	"strings"
+)
+
+const DefaultContentsSizeLimit int64 = 1 << 20
+
+/*
+openContents opens the contents of the file through the file system, to be read line by line by the content-search
+functions like grep, or up to the size limit by the 'contents' attribute.
+A directory, a file that can not be opened, or a file without a file system, like an entry inside an archive, has no
+contents.
+*/
+func openContents(filePath string, fileSystem filesystem.FileSystem) io.ReadCloser {
+	if fileSystem == nil {
+		return io.NopCloser(strings.NewReader(""))
+	}
+	file, err := fileSystem.Open(filePath)
+	if err != nil {
+		return io.NopCloser(strings.NewReader(""))
+	}
+	if info, err := file.Stat(); err == nil && info.IsDir() {
+		_ = file.Close()
+		return io.NopCloser(strings.NewReader(""))
+	}
+	return file
+}
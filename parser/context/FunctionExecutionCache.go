@@ -1,7 +1,10 @@
 package context
 
+import "sync"
+
 type FunctionExecutionCache struct {
 	entries map[Value]interface{}
+	lock    sync.RWMutex
 }
 
 func NewFunctionExecutionCache() *FunctionExecutionCache {
@@ -11,10 +14,16 @@ func NewFunctionExecutionCache() *FunctionExecutionCache {
 }
 
 func (functionExecutionCache *FunctionExecutionCache) Put(key Value, value interface{}) {
+	functionExecutionCache.lock.Lock()
+	defer functionExecutionCache.lock.Unlock()
+
 	functionExecutionCache.entries[key] = value
 }
 
 func (functionExecutionCache *FunctionExecutionCache) Get(key Value) (interface{}, bool) {
+	functionExecutionCache.lock.RLock()
+	defer functionExecutionCache.lock.RUnlock()
+
 	value, ok := functionExecutionCache.entries[key]
 	return value, ok
 }
@@ -3,6 +3,7 @@ package context
 import (
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
+	"goselect/parser/filesystem"
 	"strings"
 	"time"
 )
@@ -14,12 +15,23 @@ type FunctionDefinition struct {
 	description    string
 	aggregateBlock AggregationFunctionBlock
 	isAggregate    bool
+	contentsBlock  FileFunctionBlock
+	fileBlock      FileFunctionBlock
 }
 
 type FunctionBlock interface {
 	run(args ...Value) (Value, error)
 }
 
+/*
+FileFunctionBlock is the block of a scalar function that reads the file at the path in the first parameter value,
+through the file system of the file being evaluated, the same way an AttributeLazyEvaluationBlock reads the file.
+The file system is nil for a file that is not read through a file system, like an entry inside an archive.
+*/
+type FileFunctionBlock interface {
+	runOn(fileSystem filesystem.FileSystem, args ...Value) (Value, error)
+}
+
 type AggregationFunctionBlock interface {
 	initialState() *FunctionState
 	run(initialState *FunctionState, args ...Value) (*FunctionState, error)
@@ -77,6 +89,10 @@ const (
 	FunctionNameConcat              = "concat"
 	FunctionNameConcatWithSeparator = "concatws"
 	FunctionNameContains            = "contains"
+	FunctionNameGrep                = "grep"
+	FunctionNameCountMatches        = "countmatches"
+	FunctionNameFirstMatch          = "firstmatch"
+	FunctionNameExtendedAttribute   = "xattr"
 	FunctionNameSubstring           = "substr"
 	FunctionNameReplace             = "replace"
 	FunctionNameReplaceAll          = "replaceall"
@@ -91,6 +107,7 @@ const (
 	FunctionNameCount               = "count"
 	FunctionNameCountDistinct       = "countdistinct"
 	FunctionNameSum                 = "sum"
+	FunctionNameUniqueSum           = "uniquesum"
 	FunctionNameAverage             = "average"
 	FunctionNameMin                 = "min"
 	FunctionNameMax                 = "max"
@@ -314,6 +331,31 @@ var functionDefinitions = map[string]*FunctionDefinition{
 		block:       ContainsFunctionBlock{},
 		tags:        map[string]bool{"where": true},
 	},
+	FunctionNameGrep: {
+		aliases:       []string{"grep"},
+		description:   "Takes 2 parameter values and returns true if any line of the first parameter value matches the regular expression represented by the second parameter value, false otherwise. \nWith the contents attribute, the file is read line by line without the contents size limit and stops at the first matching line. \nFor example, grep(contents, 'TODO\\(.*\\)') will return true for the files with a TODO.",
+		block:         GrepFunctionBlock{executionCache: executionCache},
+		contentsBlock: GrepFunctionBlock{executionCache: executionCache},
+		tags:          map[string]bool{"where": true},
+	},
+	FunctionNameCountMatches: {
+		aliases:       []string{"countmatches", "cmatches"},
+		description:   "Takes 2 parameter values and returns the number of matches of the regular expression represented by the second parameter value in all the lines of the first parameter value. \nWith the contents attribute, the file is read line by line without the contents size limit. \nFor example, countmatches(contents, TODO) will return the number of TODOs in a file.",
+		block:         CountMatchesFunctionBlock{executionCache: executionCache},
+		contentsBlock: CountMatchesFunctionBlock{executionCache: executionCache},
+	},
+	FunctionNameFirstMatch: {
+		aliases:       []string{"firstmatch", "fmatch"},
+		description:   "Takes 2 parameter values and returns the text of the first match of the regular expression represented by the second parameter value in the lines of the first parameter value, blank if there is no match. \nWith the contents attribute, the file is read line by line without the contents size limit and stops at the first match. \nFor example, firstmatch(contents, 'version: .*') will return the first version declared in a file.",
+		block:         FirstMatchFunctionBlock{executionCache: executionCache},
+		contentsBlock: FirstMatchFunctionBlock{executionCache: executionCache},
+	},
+	FunctionNameExtendedAttribute: {
+		aliases:     []string{"xattr", "getxattr"},
+		description: "Takes 2 parameter values, the path of a file on the disk and the name of an extended attribute, and returns the value of the extended attribute of the file, blank if it is not set. \nA symbolic link is not followed, and it returns blank for a file in a git revision or in an archive, whichever path is passed. For example, xattr(path, 'user.origin') will return the value of 'user.origin' of the file.",
+		block:       ExtendedAttributeFunctionBlock{},
+		fileBlock:   ExtendedAttributeFunctionBlock{},
+	},
 	FunctionNameSubstring: {
 		aliases:     []string{"substr", "str"},
 		description: "Returns a substring from the main string. \nsubstr() takes 3 parameter values, first parameter value is the main string, second is the starting index (starting from 0) and the optional third \nparameter value is the end index(inclusive).",
@@ -418,6 +460,12 @@ var functionDefinitions = map[string]*FunctionDefinition{
 		isAggregate:    true,
 		aggregateBlock: &SumFunctionBlock{},
 	},
+	FunctionNameUniqueSum: {
+		aliases:        []string{"uniquesum", "usum"},
+		description:    "uniquesum is an aggregate function that takes 2 parameter values, and returns the sum of the first parameter value counted once for each distinct second parameter value. \nFor example, uniquesum(size, fileid) will return the size of all the files in the source directory, counting the hard links to the same file once.",
+		isAggregate:    true,
+		aggregateBlock: &UniqueSumFunctionBlock{},
+	},
 	FunctionNameAverage: {
 		aliases:        []string{"average", "avg"},
 		description:    "average is an aggregate function that returns the average of all the values corresponding to the provided parameter. \nFor example, avg(size) will return the average file size in the source directory.",
@@ -455,6 +503,11 @@ func (functions *AllFunctions) IsASupportedFunction(function string) bool {
 	return ok
 }
 
+func (functions *AllFunctions) IsAnAliasOf(function string, name string) bool {
+	definition, ok := functions.supportedFunctions[strings.ToLower(function)]
+	return ok && definition == functionDefinitions[name]
+}
+
 func (functions *AllFunctions) ContainsATag(function string, tag string) bool {
 	definition, ok := functions.supportedFunctions[strings.ToLower(function)]
 	if !ok {
@@ -501,6 +554,36 @@ func (functions *AllFunctions) Execute(fn string, args ...Value) (Value, error)
 	return functions.supportedFunctions[strings.ToLower(fn)].block.run(args...)
 }
 
+/*
+ExecuteOn executes the function for the file being evaluated. A function that reads the disk, like xattr, runs with the
+file system of the file, so that it does not read the disk for a file in a git revision or inside an archive.
+*/
+func (functions *AllFunctions) ExecuteOn(fn string, fileAttributes *FileAttributes, args ...Value) (Value, error) {
+	definition := functions.supportedFunctions[strings.ToLower(fn)]
+	if definition.fileBlock == nil || fileAttributes == nil {
+		return definition.block.run(args...)
+	}
+	return definition.fileBlock.runOn(fileAttributes.fileSystem, args...)
+}
+
+/*
+SearchesTheContents returns true if the function, like grep, searches the contents of the file line by line when the
+first parameter is the 'contents' attribute, instead of the value of the attribute that is read up to the size limit.
+*/
+func (functions *AllFunctions) SearchesTheContents(fn string) bool {
+	definition, ok := functions.supportedFunctions[strings.ToLower(fn)]
+	return ok && definition.contentsBlock != nil
+}
+
+/*
+ExecuteOnContents executes a content-search function on the contents of the file, read through the file system of the
+file. The args are the parameter values after the 'contents' attribute.
+*/
+func (functions *AllFunctions) ExecuteOnContents(fn string, fileAttributes *FileAttributes, args ...Value) (Value, error) {
+	file := []Value{fileAttributes.Get(AttributePath)}
+	return functions.supportedFunctions[strings.ToLower(fn)].contentsBlock.runOn(fileAttributes.fileSystem, append(file, args...)...)
+}
+
 func (functions *AllFunctions) ExecuteAggregate(fn string, initialState *FunctionState, args ...Value) (*FunctionState, error) {
 	return functions.supportedFunctions[strings.ToLower(fn)].aggregateBlock.run(initialState, args...)
 }
@@ -0,0 +1,96 @@
+package context
+
+import (
+	"goselect/parser/filesystem"
+	"goselect/parser/media"
+	"sync"
+)
+
+/*
+imageAttributeValues return the values of the image attributes from the metadata of an image.
+The image is nil for a file that is not an image, a directory, or a file that can not be read.
+*/
+var imageAttributeValues = map[string]func(image *media.Image) Value{
+	AttributeImageWidth: func(image *media.Image) Value {
+		if image == nil {
+			return Int64Value(0)
+		}
+		return Int64Value(image.Width)
+	},
+	AttributeImageHeight: func(image *media.Image) Value {
+		if image == nil {
+			return Int64Value(0)
+		}
+		return Int64Value(image.Height)
+	},
+	AttributeImageFormat: func(image *media.Image) Value {
+		if image == nil {
+			return StringValue("")
+		}
+		return StringValue(image.Format)
+	},
+	AttributeExifDateTime: func(image *media.Image) Value {
+		if image == nil || image.Exif.DateTime.IsZero() {
+			return EmptyValue
+		}
+		return DateTimeValue(image.Exif.DateTime)
+	},
+	AttributeExifMake: func(image *media.Image) Value {
+		if image == nil {
+			return StringValue("")
+		}
+		return StringValue(image.Exif.Make)
+	},
+	AttributeExifModel: func(image *media.Image) Value {
+		if image == nil {
+			return StringValue("")
+		}
+		return StringValue(image.Exif.Model)
+	},
+	AttributeExifOrientation: func(image *media.Image) Value {
+		if image == nil {
+			return IntValue(0)
+		}
+		return IntValue(image.Exif.Orientation)
+	},
+	AttributeExifHasGps: func(image *media.Image) Value {
+		return booleanValueUsing(image != nil && image.Exif.HasGps)
+	},
+}
+
+/*
+imageMetadata is the metadata of an image, shared by all the image attributes of a file.
+The headers of the file are read once, when the first of the image attributes is needed.
+*/
+type imageMetadata struct {
+	filePath   string
+	fileSystem filesystem.FileSystem
+	once       sync.Once
+	image      *media.Image
+}
+
+func newImageMetadata(filePath string, fileSystem filesystem.FileSystem) *imageMetadata {
+	return &imageMetadata{filePath: filePath, fileSystem: fileSystem}
+}
+
+func (metadata *imageMetadata) read() *media.Image {
+	metadata.once.Do(func() {
+		file, err := metadata.fileSystem.Open(metadata.filePath)
+		if err != nil {
+			return
+		}
+		defer file.Close()
+
+		if info, err := file.Stat(); err != nil || !info.Mode().IsRegular() {
+			return
+		}
+		readerAt, _, err := filesystem.HeaderReaderAtOf(file, maxHeaderBytes)
+		if err != nil {
+			return
+		}
+		if image, err := media.ReadImage(readerAt); err == nil {
+			metadata.image = image
+		}
+	})
+	return metadata.image
+}
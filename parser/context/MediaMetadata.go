@@ -0,0 +1,111 @@
+package context
+
+import (
+	"goselect/parser/filesystem"
+	"goselect/parser/media"
+	"sync"
+)
+
+/*
+mediaAttributeValues return the values of the audio and the video attributes from the metadata of a media file.
+The media is nil for a file that is not an audio or a video, a directory, or a file that can not be read.
+*/
+var mediaAttributeValues = map[string]func(audioVideo *media.AudioVideo) Value{
+	AttributeMediaFormat: func(audioVideo *media.AudioVideo) Value {
+		if audioVideo == nil {
+			return StringValue("")
+		}
+		return StringValue(audioVideo.Format)
+	},
+	AttributeDuration: func(audioVideo *media.AudioVideo) Value {
+		if audioVideo == nil {
+			return Float64Value(0)
+		}
+		return Float64Value(audioVideo.Duration)
+	},
+	AttributeBitRate: func(audioVideo *media.AudioVideo) Value {
+		if audioVideo == nil {
+			return Int64Value(0)
+		}
+		return Int64Value(audioVideo.BitRate)
+	},
+	AttributeSampleRate: func(audioVideo *media.AudioVideo) Value {
+		if audioVideo == nil {
+			return Int64Value(0)
+		}
+		return Int64Value(audioVideo.SampleRate)
+	},
+	AttributeChannels: func(audioVideo *media.AudioVideo) Value {
+		if audioVideo == nil {
+			return Int64Value(0)
+		}
+		return Int64Value(audioVideo.Channels)
+	},
+	AttributeVideoWidth: func(audioVideo *media.AudioVideo) Value {
+		if audioVideo == nil {
+			return Int64Value(0)
+		}
+		return Int64Value(audioVideo.Width)
+	},
+	AttributeVideoHeight: func(audioVideo *media.AudioVideo) Value {
+		if audioVideo == nil {
+			return Int64Value(0)
+		}
+		return Int64Value(audioVideo.Height)
+	},
+	AttributeVideoCodec: func(audioVideo *media.AudioVideo) Value {
+		if audioVideo == nil {
+			return StringValue("")
+		}
+		return StringValue(audioVideo.VideoCodec)
+	},
+	AttributeAudioCodec: func(audioVideo *media.AudioVideo) Value {
+		if audioVideo == nil {
+			return StringValue("")
+		}
+		return StringValue(audioVideo.AudioCodec)
+	},
+}
+
+/*
+maxHeaderBytes is the number of the bytes read into memory to find the headers of a file that can not be read at an
+offset, instead of reading the whole file.
+*/
+const maxHeaderBytes int64 = 16 * 1024 * 1024
+
+/*
+mediaMetadata is the metadata of an audio or a video, shared by all the media attributes of a file.
+The headers of the container are read once, when the first of the media attributes is needed.
+*/
+type mediaMetadata struct {
+	filePath   string
+	fileSystem filesystem.FileSystem
+	once       sync.Once
+	audioVideo *media.AudioVideo
+}
+
+func newMediaMetadata(filePath string, fileSystem filesystem.FileSystem) *mediaMetadata {
+	return &mediaMetadata{filePath: filePath, fileSystem: fileSystem}
+}
+
+func (metadata *mediaMetadata) read() *media.AudioVideo {
+	metadata.once.Do(func() {
+		file, err := metadata.fileSystem.Open(metadata.filePath)
+		if err != nil {
+			return
+		}
+		defer file.Close()
+
+		if info, err := file.Stat(); err != nil || !info.Mode().IsRegular() {
+			return
+		}
+		readerAt, size, err := filesystem.HeaderReaderAtOf(file, maxHeaderBytes)
+		if err != nil {
+			return
+		}
+		if audioVideo, err := media.ReadAudioVideo(readerAt, size); err == nil {
+			metadata.audioVideo = audioVideo
+		}
+	})
+	return metadata.audioVideo
+}
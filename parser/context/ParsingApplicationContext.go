@@ -1,18 +1,80 @@
 package context
 
+import (
+	"goselect/parser/context/platform"
+	"goselect/parser/filesystem"
+	"io"
+	"os"
+)
+
 type ParsingApplicationContext struct {
 	allFunctions  *AllFunctions
 	allAttributes *AllAttributes
+	fileSystem    filesystem.FileSystem
+	fileList      io.Reader
+	contentsLimit int64
+	ownerNames    *platform.OwnerNames
 }
 
 func NewContext(functions *AllFunctions, attributes *AllAttributes) *ParsingApplicationContext {
-	return &ParsingApplicationContext{allFunctions: functions, allAttributes: attributes}
+	return &ParsingApplicationContext{
+		allFunctions:  functions,
+		allAttributes: attributes,
+		fileSystem:    filesystem.Os(),
+		fileList:      os.Stdin,
+		contentsLimit: DefaultContentsSizeLimit,
+		ownerNames:    platform.NewOwnerNames(),
+	}
+}
+
+func (context *ParsingApplicationContext) WithFileSystem(fileSystem filesystem.FileSystem) *ParsingApplicationContext {
+	context.fileSystem = fileSystem
+	return context
+}
+
+/*
+WithFileList sets the reader of the newline or NUL separated paths for the 'stdin' source, which is the standard
+input by default.
+*/
+func (context *ParsingApplicationContext) WithFileList(fileList io.Reader) *ParsingApplicationContext {
+	context.fileList = fileList
+	return context
+}
+
+/*
+WithContentsSizeLimit sets the maximum number of bytes of a file that the 'contents' attribute reads.
+*/
+func (context *ParsingApplicationContext) WithContentsSizeLimit(sizeLimit int64) *ParsingApplicationContext {
+	context.contentsLimit = sizeLimit
+	return context
+}
+
+/*
+ForFileSystem returns a copy of the context that reads from another file system, for a root in the 'from' clause
+with its own file system, like a git revision.
+*/
+func (context *ParsingApplicationContext) ForFileSystem(fileSystem filesystem.FileSystem) *ParsingApplicationContext {
+	copied := *context
+	copied.fileSystem = fileSystem
+	return &copied
+}
+
+func (context *ParsingApplicationContext) FileSystem() filesystem.FileSystem {
+	return context.fileSystem
+}
+
+func (context *ParsingApplicationContext) FileList() io.Reader {
+	return context.fileList
 }
 
 func (context *ParsingApplicationContext) IsASupportedAttribute(attribute string) bool {
 	return context.allAttributes.IsASupportedAttribute(attribute)
 }
 
+func (context *ParsingApplicationContext) IsABooleanAttribute(attribute string) bool {
+	return context.allAttributes.IsABooleanAttribute(attribute)
+}
+
 func (context *ParsingApplicationContext) IsASupportedFunction(functionName string) bool {
 	return context.allFunctions.IsASupportedFunction(functionName)
 }
@@ -21,6 +83,14 @@ func (context *ParsingApplicationContext) FunctionContainsATag(function string,
 	return context.allFunctions.ContainsATag(function, tag)
 }
 
+/*
+SearchesTheContentsOf returns true if the function, like grep, searches the contents of the file line by line, which
+it does when its first parameter is the 'contents' attribute.
+*/
+func (context *ParsingApplicationContext) SearchesTheContentsOf(function string, attribute string) bool {
+	return context.allFunctions.SearchesTheContents(function) && context.allAttributes.IsAnAliasOf(attribute, AttributeContents)
+}
+
 func (context *ParsingApplicationContext) IsAnAggregateFunction(functionName string) bool {
 	return context.allFunctions.IsAnAggregateFunction(functionName)
 }
@@ -121,3 +121,23 @@ func TestAllFunctions(t *testing.T) {
 		t.Fatalf("Expected allFunctions to be non-nil but was nil")
 	}
 }
+
+func TestIsABooleanAttribute(t *testing.T) {
+	context := NewContext(nil, NewAttributes())
+
+	for _, attribute := range []string{"isdir", "issetuid", "hasacl", "stripped", "uread"} {
+		if !context.IsABooleanAttribute(attribute) {
+			t.Fatalf("Expected %v to be a boolean attribute", attribute)
+		}
+	}
+}
+
+func TestIsNotABooleanAttribute(t *testing.T) {
+	context := NewContext(nil, NewAttributes())
+
+	for _, attribute := range []string{"name", "size", "xattrs", "unknown"} {
+		if context.IsABooleanAttribute(attribute) {
+			t.Fatalf("Expected %v to not be a boolean attribute", attribute)
+		}
+	}
+}
@@ -1,12 +1,16 @@
 package context
 
 import (
+	"bufio"
 	b64 "encoding/base64"
 	"errors"
 	"fmt"
 	"github.com/dustin/go-humanize"
 	"golang.org/x/text/cases"
+	"goselect/parser/context/platform"
 	"goselect/parser/error/messages"
+	"goselect/parser/filesystem"
+	"io"
 	"os"
 	"regexp"
 	"strconv"
@@ -54,6 +58,10 @@ type WorkingDirectoryFunctionBlock struct{}
 type ConcatFunctionBlock struct{}
 type ConcatWithSeparatorFunctionBlock struct{}
 type ContainsFunctionBlock struct{}
+type GrepFunctionBlock struct{ executionCache *FunctionExecutionCache }
+type CountMatchesFunctionBlock struct{ executionCache *FunctionExecutionCache }
+type FirstMatchFunctionBlock struct{ executionCache *FunctionExecutionCache }
+type ExtendedAttributeFunctionBlock struct{}
 type SubstringFunctionBlock struct{}
 type ReplaceFunctionBlock struct{}
 type ReplaceAllFunctionBlock struct{}
@@ -403,6 +411,117 @@ func (c ContainsFunctionBlock) run(args ...Value) (Value, error) {
 	return booleanValueUsing(strings.Contains(args[0].stringValue, args[1].GetAsString())), nil
 }
 
+func (g GrepFunctionBlock) run(args ...Value) (Value, error) {
+	if err := ensureNParametersOrError(args, FunctionNameGrep, 2); err != nil {
+		return EmptyValue, err
+	}
+	return g.grep(strings.NewReader(args[0].GetAsString()), args[1])
+}
+
+func (g GrepFunctionBlock) runOn(fileSystem filesystem.FileSystem, args ...Value) (Value, error) {
+	if err := ensureNParametersOrError(args, FunctionNameGrep, 2); err != nil {
+		return EmptyValue, err
+	}
+	contents := openContents(args[0].GetAsString(), fileSystem)
+	defer contents.Close()
+	return g.grep(contents, args[1])
+}
+
+func (g GrepFunctionBlock) grep(lines io.Reader, pattern Value) (Value, error) {
+	matched := false
+	err := scanMatchingLines(lines, pattern, g.executionCache, func(expression *regexp.Regexp, line string) bool {
+		matched = expression.MatchString(line)
+		return !matched
+	})
+	if err != nil {
+		return EmptyValue, fmt.Errorf(messages.ErrorMessageFunctionNamePrefixWithExistingError, FunctionNameGrep, err)
+	}
+	return booleanValueUsing(matched), nil
+}
+
+func (c CountMatchesFunctionBlock) run(args ...Value) (Value, error) {
+	if err := ensureNParametersOrError(args, FunctionNameCountMatches, 2); err != nil {
+		return EmptyValue, err
+	}
+	return c.countMatches(strings.NewReader(args[0].GetAsString()), args[1])
+}
+
+func (c CountMatchesFunctionBlock) runOn(fileSystem filesystem.FileSystem, args ...Value) (Value, error) {
+	if err := ensureNParametersOrError(args, FunctionNameCountMatches, 2); err != nil {
+		return EmptyValue, err
+	}
+	contents := openContents(args[0].GetAsString(), fileSystem)
+	defer contents.Close()
+	return c.countMatches(contents, args[1])
+}
+
+func (c CountMatchesFunctionBlock) countMatches(lines io.Reader, pattern Value) (Value, error) {
+	count := 0
+	err := scanMatchingLines(lines, pattern, c.executionCache, func(expression *regexp.Regexp, line string) bool {
+		count = count + len(expression.FindAllStringIndex(line, -1))
+		return true
+	})
+	if err != nil {
+		return EmptyValue, fmt.Errorf(messages.ErrorMessageFunctionNamePrefixWithExistingError, FunctionNameCountMatches, err)
+	}
+	return IntValue(count), nil
+}
+
+func (f FirstMatchFunctionBlock) run(args ...Value) (Value, error) {
+	if err := ensureNParametersOrError(args, FunctionNameFirstMatch, 2); err != nil {
+		return EmptyValue, err
+	}
+	return f.firstMatch(strings.NewReader(args[0].GetAsString()), args[1])
+}
+
+func (f FirstMatchFunctionBlock) runOn(fileSystem filesystem.FileSystem, args ...Value) (Value, error) {
+	if err := ensureNParametersOrError(args, FunctionNameFirstMatch, 2); err != nil {
+		return EmptyValue, err
+	}
+	contents := openContents(args[0].GetAsString(), fileSystem)
+	defer contents.Close()
+	return f.firstMatch(contents, args[1])
+}
+
+func (f FirstMatchFunctionBlock) firstMatch(lines io.Reader, pattern Value) (Value, error) {
+	firstMatch := ""
+	err := scanMatchingLines(lines, pattern, f.executionCache, func(expression *regexp.Regexp, line string) bool {
+		if location := expression.FindStringIndex(line); location != nil {
+			firstMatch = line[location[0]:location[1]]
+			return false
+		}
+		return true
+	})
+	if err != nil {
+		return EmptyValue, fmt.Errorf(messages.ErrorMessageFunctionNamePrefixWithExistingError, FunctionNameFirstMatch, err)
+	}
+	return StringValue(firstMatch), nil
+}
+
+func (e ExtendedAttributeFunctionBlock) run(args ...Value) (Value, error) {
+	if err := ensureNParametersOrError(args, FunctionNameExtendedAttribute, 2); err != nil {
+		return EmptyValue, err
+	}
+	value, err := platform.ExtendedAttribute(args[0].GetAsString(), args[1].GetAsString())
+	if err != nil {
+		return StringValue(""), nil
+	}
+	return StringValue(strings.TrimRight(string(value), "\x00")), nil
+}
+
+/*
+runOn returns blank for a file that is not on the disk, like a file in a git revision, which has no extended attributes.
+*/
+func (e ExtendedAttributeFunctionBlock) runOn(fileSystem filesystem.FileSystem, args ...Value) (Value, error) {
+	if err := ensureNParametersOrError(args, FunctionNameExtendedAttribute, 2); err != nil {
+		return EmptyValue, err
+	}
+	if !filesystem.IsOs(fileSystem) {
+		return StringValue(""), nil
+	}
+	return e.run(args...)
+}
+
 func (s SubstringFunctionBlock) run(args ...Value) (Value, error) {
 	if err := ensureNParametersOrError(args, FunctionNameSubstring, 2); err != nil {
 		return EmptyValue, err
@@ -645,3 +764,45 @@ func ensureNParametersOrError(parameters []Value, fn string, n int) error {
 	}
 	return nil
 }
+
+/*
+scanMatchingLines compiles the pattern and passes each line to visit, until visit returns false.
+The lines are read one at a time, instead of loading the whole file for the contents of a file, and a line can be of
+any length. An error reading the lines is returned.
+*/
+func scanMatchingLines(
+	lines io.Reader,
+	pattern Value,
+	executionCache *FunctionExecutionCache,
+	visit func(expression *regexp.Regexp, line string) bool,
+) error {
+	expression, err := compiledExpression(pattern, executionCache)
+	if err != nil {
+		return err
+	}
+	reader := bufio.NewReader(lines)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return err
+		}
+		if len(line) == 0 && err == io.EOF {
+			return nil
+		}
+		if !visit(expression, strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")) || err == io.EOF {
+			return nil
+		}
+	}
+}
+
+func compiledExpression(pattern Value, executionCache *FunctionExecutionCache) (*regexp.Regexp, error) {
+	if cached, ok := executionCache.Get(pattern); ok {
+		return cached.(*regexp.Regexp), nil
+	}
+	compiled, err := regexp.Compile(pattern.GetAsString())
+	if err != nil {
+		return nil, err
+	}
+	executionCache.Put(pattern, compiled)
+	return compiled, nil
+}
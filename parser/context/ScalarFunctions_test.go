@@ -6,6 +6,7 @@ package context
 import (
 	"math"
 	"os"
+	"strings"
 	"testing"
 	"time"
 )
@@ -1789,3 +1790,93 @@ func TestIsArchive3(t *testing.T) {
 		t.Fatalf("Expected an error while invoking isArchive without any parameter values")
 	}
 }
+
+func TestGrep1(t *testing.T) {
+	value, _ := NewFunctions().Execute("grep", StringValue("first line\n// TODO(someone): fix"), StringValue("TODO\\(.*\\)"))
+	actualValue, _ := value.GetBoolean()
+
+	if actualValue != true {
+		t.Fatalf("Expected grep to be %v, received %v", true, actualValue)
+	}
+}
+
+func TestGrep2(t *testing.T) {
+	value, _ := NewFunctions().Execute("grep", StringValue("first line\nTODO later"), StringValue("TODO\\(.*\\)"))
+	actualValue, _ := value.GetBoolean()
+
+	if actualValue != false {
+		t.Fatalf("Expected grep to be %v, received %v", false, actualValue)
+	}
+}
+
+func TestGrepMatchesEachLine(t *testing.T) {
+	value, _ := NewFunctions().Execute("grep", StringValue("first line\r\nsecond line"), StringValue("^second line$"))
+	actualValue, _ := value.GetBoolean()
+
+	if actualValue != true {
+		t.Fatalf("Expected grep to be %v, received %v", true, actualValue)
+	}
+}
+
+func TestGrepWithALineLongerThan1MiB(t *testing.T) {
+	value, err := NewFunctions().Execute("grep", StringValue(strings.Repeat("a", 2<<20)+"TODO"), StringValue("a+TODO$"))
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	actualValue, _ := value.GetBoolean()
+
+	if actualValue != true {
+		t.Fatalf("Expected grep to be %v, received %v", true, actualValue)
+	}
+}
+
+func TestGrepWithAnInvalidRegularExpression(t *testing.T) {
+	_, err := NewFunctions().Execute("grep", StringValue("first line"), StringValue("TODO("))
+
+	if err == nil {
+		t.Fatalf("Expected an error while executing grep with an invalid regular expression")
+	}
+}
+
+func TestGrepWithInsufficientParameters(t *testing.T) {
+	_, err := NewFunctions().Execute("grep", StringValue("first line"))
+
+	if err == nil {
+		t.Fatalf("Expected an error on executing grep with insufficient parameters")
+	}
+}
+
+func TestCountMatches(t *testing.T) {
+	value, _ := NewFunctions().Execute("countmatches", StringValue("TODO one, TODO two\nnothing\nTODO three"), StringValue("TODO"))
+	actualValue, _ := value.GetInt()
+
+	if actualValue != 3 {
+		t.Fatalf("Expected countmatches to be %v, received %v", 3, actualValue)
+	}
+}
+
+func TestCountMatchesWithInsufficientParameters(t *testing.T) {
+	_, err := NewFunctions().Execute("countmatches", StringValue("TODO"))
+
+	if err == nil {
+		t.Fatalf("Expected an error on executing countmatches with insufficient parameters")
+	}
+}
+
+func TestFirstMatch1(t *testing.T) {
+	value, _ := NewFunctions().Execute("firstmatch", StringValue("name: goselect\nversion: 0.0.8\nversion: 0.0.9"), StringValue("version: .*"))
+	actualValue := value.GetAsString()
+
+	if actualValue != "version: 0.0.8" {
+		t.Fatalf("Expected firstmatch to be %v, received %v", "version: 0.0.8", actualValue)
+	}
+}
+
+func TestFirstMatch2(t *testing.T) {
+	value, _ := NewFunctions().Execute("firstmatch", StringValue("name: goselect"), StringValue("version: .*"))
+	actualValue := value.GetAsString()
+
+	if actualValue != "" {
+		t.Fatalf("Expected firstmatch to be blank, received %v", actualValue)
+	}
+}
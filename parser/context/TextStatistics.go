@@ -0,0 +1,110 @@
+package context
+
+import (
+	"bufio"
+	"bytes"
+	"github.com/gabriel-vasile/mimetype"
+	"goselect/parser/filesystem"
+	"io"
+	"sync"
+	"unicode"
+)
+
+const mimeTypeDetectionLength = 3072
+
+/*
+textStatistics are the values of the 'lines', 'words', 'chars' and 'maxlinelength' attributes of a file.
+The file is read once, when the first of these attributes is needed, by streaming it rune by rune.
+A directory, a binary file detected using the mime type, or a file that can not be read has all the values as 0.
+A last line without a trailing newline is counted as a line, and the length of a line does not include "\r\n".
+*/
+type textStatistics struct {
+	filePath      string
+	fileSystem    filesystem.FileSystem
+	once          sync.Once
+	lines         int64
+	words         int64
+	chars         int64
+	maxLineLength int64
+}
+
+func newTextStatistics(filePath string, fileSystem filesystem.FileSystem) *textStatistics {
+	return &textStatistics{filePath: filePath, fileSystem: fileSystem}
+}
+
+func (statistics *textStatistics) compute() *textStatistics {
+	statistics.once.Do(func() {
+		file, err := statistics.fileSystem.Open(statistics.filePath)
+		if err != nil {
+			return
+		}
+		defer file.Close()
+
+		head := make([]byte, mimeTypeDetectionLength)
+		length, err := io.ReadFull(file, head)
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			return
+		}
+		if !isText(mimetype.Detect(head[0:length])) {
+			return
+		}
+		statistics.count(bufio.NewReader(io.MultiReader(bytes.NewReader(head[0:length]), file)))
+	})
+	return statistics
+}
+
+func (statistics *textStatistics) count(reader *bufio.Reader) {
+	var lineLength int64
+	inWord, pendingCarriageReturn := false, false
+	endLine := func() {
+		statistics.lines = statistics.lines + 1
+		if lineLength > statistics.maxLineLength {
+			statistics.maxLineLength = lineLength
+		}
+		lineLength = 0
+	}
+	for {
+		character, _, err := reader.ReadRune()
+		if err != nil {
+			break
+		}
+		statistics.chars = statistics.chars + 1
+		if unicode.IsSpace(character) {
+			inWord = false
+		} else if !inWord {
+			inWord = true
+			statistics.words = statistics.words + 1
+		}
+		switch {
+		case character == '\n':
+			pendingCarriageReturn = false
+			endLine()
+		case character == '\r':
+			if pendingCarriageReturn {
+				lineLength = lineLength + 1
+			}
+			pendingCarriageReturn = true
+		default:
+			if pendingCarriageReturn {
+				lineLength = lineLength + 1
+			}
+			pendingCarriageReturn = false
+			lineLength = lineLength + 1
+		}
+	}
+	if pendingCarriageReturn {
+		lineLength = lineLength + 1
+	}
+	if lineLength > 0 {
+		endLine()
+	}
+}
+
+func isText(mime *mimetype.MIME) bool {
+	for current := mime; current != nil; current = current.Parent() {
+		if current.Is("text/plain") {
+			return true
+		}
+	}
+	return false
+}
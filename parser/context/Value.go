@@ -140,6 +140,24 @@ func (value Value) GetNumericAsFloat64() (float64, error) {
 	}
 }
 
+func (value Value) IsUndefined() bool {
+	return value.valueType == ValueTypeUndefined
+}
+
+func (value Value) IsDateTime() bool {
+	return value.valueType == ValueTypeDateTime
+}
+
+/*
+Key returns the value as a component of a composite key, like the key of a group. The key has the type and the length
+of the value, so that the values of different types that print the same, or the values that contain the characters of
+the other components, never make the same composite key.
+*/
+func (value Value) Key() string {
+	asString := value.GetAsString()
+	return strconv.Itoa(int(value.valueType)) + ":" + strconv.Itoa(len(asString)) + ":" + asString
+}
+
 func (value Value) GetAsString() string {
 	switch value.valueType {
 	case ValueTypeString:
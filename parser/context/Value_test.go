@@ -837,3 +837,23 @@ func TestGetCommonTypeUint64Int64NotEqual(t *testing.T) {
 		t.Fatalf("Expected first and second values to not match but they did")
 	}
 }
+
+func TestKeyOfValuesOfDifferentTypesThatPrintTheSame(t *testing.T) {
+	if StringValue("1").Key() == IntValue(1).Key() {
+		t.Fatalf("Expected the keys of a string 1 and an int 1 to be different")
+	}
+}
+
+func TestKeyOfTheSameValue(t *testing.T) {
+	if StringValue("log").Key() != StringValue("log").Key() {
+		t.Fatalf("Expected the keys of the same value to be the same")
+	}
+}
+
+func TestCompositeKeyOfValuesContainingASeparator(t *testing.T) {
+	first := StringValue("a\x1f").Key() + StringValue("b").Key()
+	second := StringValue("a").Key() + StringValue("\x1fb").Key()
+	if first == second {
+		t.Fatalf("Expected the composite keys to be different, received %q", first)
+	}
+}
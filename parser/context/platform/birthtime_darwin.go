@@ -0,0 +1,20 @@
+//go:build darwin
+// +build darwin
+
+package platform
+
+import (
+	"io/fs"
+	"syscall"
+	"time"
+)
+
+type BirthTime = time.Time
+
+func FileBirthTime(_ string, file fs.FileInfo) (BirthTime, bool) {
+	stat, ok := file.Sys().(*syscall.Stat_t)
+	if !ok {
+		return time.Time{}, false
+	}
+	return time.Unix(stat.Birthtimespec.Sec, stat.Birthtimespec.Nsec), true
+}
@@ -0,0 +1,31 @@
+//go:build linux
+// +build linux
+
+package platform
+
+import (
+	"golang.org/x/sys/unix"
+	"io/fs"
+	"syscall"
+	"time"
+)
+
+type BirthTime = time.Time
+
+/*
+FileBirthTime reads the creation time of a file on the disk using statx, which needs linux 4.11 or later and a file
+system that records the birth time. The birth time is not available for the files that are not on the disk.
+*/
+func FileBirthTime(path string, file fs.FileInfo) (BirthTime, bool) {
+	if _, ok := file.Sys().(*syscall.Stat_t); !ok {
+		return time.Time{}, false
+	}
+	var stat unix.Statx_t
+	if err := unix.Statx(unix.AT_FDCWD, path, unix.AT_SYMLINK_NOFOLLOW, unix.STATX_BTIME, &stat); err != nil {
+		return time.Time{}, false
+	}
+	if stat.Mask&unix.STATX_BTIME == 0 {
+		return time.Time{}, false
+	}
+	return time.Unix(stat.Btime.Sec, int64(stat.Btime.Nsec)), true
+}
@@ -0,0 +1,20 @@
+//go:build windows
+// +build windows
+
+package platform
+
+import (
+	"io/fs"
+	"syscall"
+	"time"
+)
+
+type BirthTime = time.Time
+
+func FileBirthTime(_ string, file fs.FileInfo) (BirthTime, bool) {
+	stat, ok := file.Sys().(*syscall.Win32FileAttributeData)
+	if !ok {
+		return time.Time{}, false
+	}
+	return time.Unix(0, stat.CreationTime.Nanoseconds()), true
+}
@@ -12,6 +12,9 @@ type BlockSize = int64
 type Blocks = int64
 
 func FileBlocks(file fs.FileInfo) (BlockSize, Blocks) {
-	stat := file.Sys().(*syscall.Stat_t)
+	stat, ok := file.Sys().(*syscall.Stat_t)
+	if !ok {
+		return -1, -1
+	}
 	return int64(stat.Blksize), stat.Blocks
 }
@@ -0,0 +1,100 @@
+package platform
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const CapabilityAttribute = "security.capability"
+
+const (
+	capabilityRevisionMask  uint32 = 0xFF000000
+	capabilityFlagEffective uint32 = 0x000001
+	capabilityRevision1     uint32 = 0x01000000
+	capabilityRevision2     uint32 = 0x02000000
+	capabilityRevision3     uint32 = 0x03000000
+	capabilityRevision1Size        = 4 + 2*4
+	capabilityRevision2Size        = 4 + 4*4
+	capabilityRevision3Size        = capabilityRevision2Size + 4
+)
+
+var capabilityNames = []string{
+	"cap_chown", "cap_dac_override", "cap_dac_read_search", "cap_fowner", "cap_fsetid", "cap_kill", "cap_setgid",
+	"cap_setuid", "cap_setpcap", "cap_linux_immutable", "cap_net_bind_service", "cap_net_broadcast", "cap_net_admin",
+	"cap_net_raw", "cap_ipc_lock", "cap_ipc_owner", "cap_sys_module", "cap_sys_rawio", "cap_sys_chroot",
+	"cap_sys_ptrace", "cap_sys_pacct", "cap_sys_admin", "cap_sys_boot", "cap_sys_nice", "cap_sys_resource",
+	"cap_sys_time", "cap_sys_tty_config", "cap_mknod", "cap_lease", "cap_audit_write", "cap_audit_control",
+	"cap_setfcap", "cap_mac_override", "cap_mac_admin", "cap_syslog", "cap_wake_alarm", "cap_block_suspend",
+	"cap_audit_read", "cap_perfmon", "cap_bpf", "cap_checkpoint_restore",
+}
+
+var errInvalidCapabilities = errors.New("invalid security.capability value")
+
+/*
+DecodeCapabilities decodes the value of the 'security.capability' extended attribute, the vfs_cap_data of the kernel,
+into the text form used by getcap, for example, 'cap_net_bind_service,cap_net_raw=ep'.
+The capabilities with the same flags are grouped together, 'e' is effective, 'i' is inheritable and 'p' is permitted.
+*/
+func DecodeCapabilities(value []byte) (string, error) {
+	if len(value) < 4 {
+		return "", errInvalidCapabilities
+	}
+	magic := binary.LittleEndian.Uint32(value)
+	words := 0
+	switch magic & capabilityRevisionMask {
+	case capabilityRevision1:
+		if len(value) < capabilityRevision1Size {
+			return "", errInvalidCapabilities
+		}
+		words = 1
+	case capabilityRevision2, capabilityRevision3:
+		if len(value) < capabilityRevision2Size {
+			return "", errInvalidCapabilities
+		}
+		words = 2
+	default:
+		return "", errInvalidCapabilities
+	}
+	var permitted, inheritable uint64
+	for word := 0; word < words; word++ {
+		permitted = permitted | uint64(binary.LittleEndian.Uint32(value[4+word*8:]))<<(32*word)
+		inheritable = inheritable | uint64(binary.LittleEndian.Uint32(value[8+word*8:]))<<(32*word)
+	}
+	effective := magic&capabilityFlagEffective != 0
+
+	var flagsInOrder []string
+	namesByFlags := make(map[string][]string)
+	for capability := 0; capability < 64; capability++ {
+		flags := ""
+		if effective && permitted&(1<<capability) != 0 {
+			flags = flags + "e"
+		}
+		if inheritable&(1<<capability) != 0 {
+			flags = flags + "i"
+		}
+		if permitted&(1<<capability) != 0 {
+			flags = flags + "p"
+		}
+		if flags == "" {
+			continue
+		}
+		if _, ok := namesByFlags[flags]; !ok {
+			flagsInOrder = append(flagsInOrder, flags)
+		}
+		namesByFlags[flags] = append(namesByFlags[flags], capabilityName(capability))
+	}
+	var groups []string
+	for _, flags := range flagsInOrder {
+		groups = append(groups, strings.Join(namesByFlags[flags], ",")+"="+flags)
+	}
+	return strings.Join(groups, " "), nil
+}
+
+func capabilityName(capability int) string {
+	if capability < len(capabilityNames) {
+		return capabilityNames[capability]
+	}
+	return fmt.Sprintf("cap_%v", capability)
+}
@@ -0,0 +1,48 @@
+//go:build unit
+// +build unit
+
+package platform
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+func capabilityValue(magic uint32, words ...uint32) []byte {
+	value := make([]byte, 4+4*len(words))
+	binary.LittleEndian.PutUint32(value, magic)
+	for index, word := range words {
+		binary.LittleEndian.PutUint32(value[4+4*index:], word)
+	}
+	return value
+}
+
+func TestDecodeCapabilitiesWithEffectiveFlag(t *testing.T) {
+	permitted := uint32(1<<10 | 1<<13)
+	capabilities, err := DecodeCapabilities(capabilityValue(0x02000001, permitted, 0, 0, 0))
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	if capabilities != "cap_net_bind_service,cap_net_raw=ep" {
+		t.Fatalf("Expected capabilities to be %v, received %v", "cap_net_bind_service,cap_net_raw=ep", capabilities)
+	}
+}
+
+func TestDecodeCapabilitiesWithDifferentFlags(t *testing.T) {
+	capabilities, err := DecodeCapabilities(capabilityValue(0x03000000, 1<<0, 1<<0|1<<5, 1<<(38-32), 0, 0))
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	if capabilities != "cap_chown=ip cap_kill=i cap_perfmon=p" {
+		t.Fatalf("Expected capabilities to be %v, received %v", "cap_chown=ip cap_kill=i cap_perfmon=p", capabilities)
+	}
+}
+
+func TestDecodeCapabilitiesWithAnInvalidValue(t *testing.T) {
+	if _, err := DecodeCapabilities([]byte{0x01, 0x02}); err == nil {
+		t.Fatalf("Expected an error while decoding a short capability value")
+	}
+	if _, err := DecodeCapabilities(capabilityValue(0x05000000, 0, 0)); err == nil {
+		t.Fatalf("Expected an error while decoding an unknown revision")
+	}
+}
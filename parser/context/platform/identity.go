@@ -0,0 +1,40 @@
+//go:build !windows
+// +build !windows
+
+package platform
+
+import (
+	"io/fs"
+	"syscall"
+)
+
+type Device = uint64
+type Inode = uint64
+type Links = uint64
+
+func FileIdentity(file fs.FileInfo) (Device, Inode, bool) {
+	stat, ok := file.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, 0, false
+	}
+	return uint64(stat.Dev), uint64(stat.Ino), true
+}
+
+func FileLinks(file fs.FileInfo) (Links, bool) {
+	stat, ok := file.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, false
+	}
+	return uint64(stat.Nlink), true
+}
+
+/*
+FileSpecialDevice returns the device that a character or a block device file represents, and 0 for the other files.
+*/
+func FileSpecialDevice(file fs.FileInfo) (Device, bool) {
+	stat, ok := file.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, false
+	}
+	return uint64(stat.Rdev), true
+}
@@ -0,0 +1,24 @@
+//go:build windows
+// +build windows
+
+package platform
+
+import (
+	"io/fs"
+)
+
+type Device = uint64
+type Inode = uint64
+type Links = uint64
+
+func FileIdentity(file fs.FileInfo) (Device, Inode, bool) {
+	return 0, 0, false
+}
+
+func FileLinks(file fs.FileInfo) (Links, bool) {
+	return 0, false
+}
+
+func FileSpecialDevice(file fs.FileInfo) (Device, bool) {
+	return 0, false
+}
@@ -0,0 +1,69 @@
+package platform
+
+import (
+	"os/user"
+	"sync"
+)
+
+/*
+OwnerNames caches the user and the group names looked up by their ids, including the ids without a name, so that a query
+over a large number of files looks up each owner only once. It is shared by the goroutines of a concurrent traversal.
+A nil OwnerNames looks up the names without caching them.
+*/
+type OwnerNames struct {
+	mutex      sync.Mutex
+	userNames  map[string]string
+	groupNames map[string]string
+}
+
+func NewOwnerNames() *OwnerNames {
+	return &OwnerNames{userNames: make(map[string]string), groupNames: make(map[string]string)}
+}
+
+func (names *OwnerNames) userName(userId string) string {
+	lookupBlock := func(id string) (string, error) {
+		lookedUpUser, err := user.LookupId(id)
+		if err != nil {
+			return "", err
+		}
+		return lookedUpUser.Username, nil
+	}
+	if names == nil {
+		return lookupOrBlank(userId, lookupBlock)
+	}
+	return names.lookup(names.userNames, userId, lookupBlock)
+}
+
+func (names *OwnerNames) groupName(groupId string) string {
+	lookupBlock := func(id string) (string, error) {
+		group, err := user.LookupGroupId(id)
+		if err != nil {
+			return "", err
+		}
+		return group.Name, nil
+	}
+	if names == nil {
+		return lookupOrBlank(groupId, lookupBlock)
+	}
+	return names.lookup(names.groupNames, groupId, lookupBlock)
+}
+
+func (names *OwnerNames) lookup(cache map[string]string, id string, lookupBlock func(id string) (string, error)) string {
+	names.mutex.Lock()
+	defer names.mutex.Unlock()
+
+	if name, ok := cache[id]; ok {
+		return name
+	}
+	name := lookupOrBlank(id, lookupBlock)
+	cache[id] = name
+	return name
+}
+
+func lookupOrBlank(id string, lookupBlock func(id string) (string, error)) string {
+	name, err := lookupBlock(id)
+	if err != nil {
+		return ""
+	}
+	return name
+}
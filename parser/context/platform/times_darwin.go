@@ -12,11 +12,23 @@ import (
 type CreatedTime = time.Time
 type ModifiedTime = time.Time
 type AccessTime = time.Time
+type ChangeTime = time.Time
 
 func FileTimes(file fs.FileInfo) (CreatedTime, ModifiedTime, AccessTime) {
 	toTime := func(ts syscall.Timespec) time.Time {
 		return time.Unix(ts.Sec, ts.Nsec)
 	}
-	stat := file.Sys().(*syscall.Stat_t)
+	stat, ok := file.Sys().(*syscall.Stat_t)
+	if !ok {
+		return file.ModTime(), file.ModTime(), file.ModTime()
+	}
 	return toTime(stat.Ctimespec), toTime(stat.Mtimespec), toTime(stat.Atimespec)
 }
+
+func FileChangeTime(file fs.FileInfo) (ChangeTime, bool) {
+	stat, ok := file.Sys().(*syscall.Stat_t)
+	if !ok {
+		return time.Time{}, false
+	}
+	return time.Unix(stat.Ctimespec.Sec, stat.Ctimespec.Nsec), true
+}
@@ -12,11 +12,27 @@ import (
 type CreatedTime = time.Time
 type ModifiedTime = time.Time
 type AccessTime = time.Time
+type ChangeTime = time.Time
 
 func FileTimes(file fs.FileInfo) (CreatedTime, ModifiedTime, AccessTime) {
 	toTime := func(ts syscall.Timespec) time.Time {
 		return time.Unix(int64(ts.Sec), int64(ts.Nsec))
 	}
-	stat := file.Sys().(*syscall.Stat_t)
+	stat, ok := file.Sys().(*syscall.Stat_t)
+	if !ok {
+		return file.ModTime(), file.ModTime(), file.ModTime()
+	}
 	return toTime(stat.Ctim), toTime(stat.Mtim), toTime(stat.Atim)
 }
+
+/*
+FileChangeTime returns the time when the inode of the file was last changed, for example, by a change of the contents,
+the permissions or the owner. The created time on linux is also the change time, the birth time is read using statx.
+*/
+func FileChangeTime(file fs.FileInfo) (ChangeTime, bool) {
+	stat, ok := file.Sys().(*syscall.Stat_t)
+	if !ok {
+		return time.Time{}, false
+	}
+	return time.Unix(int64(stat.Ctim.Sec), int64(stat.Ctim.Nsec)), true
+}
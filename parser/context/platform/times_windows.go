@@ -12,12 +12,20 @@ import (
 type CreatedTime = time.Time
 type ModifiedTime = time.Time
 type AccessTime = time.Time
+type ChangeTime = time.Time
 
 func FileTimes(file fs.FileInfo) (CreatedTime, ModifiedTime, AccessTime) {
 	toTime := func(ft syscall.Filetime) time.Time {
 		return time.Unix(0, ft.Nanoseconds())
 	}
 
-	stat := file.Sys().(*syscall.Win32FileAttributeData)
+	stat, ok := file.Sys().(*syscall.Win32FileAttributeData)
+	if !ok {
+		return file.ModTime(), file.ModTime(), file.ModTime()
+	}
 	return toTime(stat.CreationTime), toTime(stat.LastWriteTime), toTime(stat.LastAccessTime)
 }
+
+func FileChangeTime(_ fs.FileInfo) (ChangeTime, bool) {
+	return time.Time{}, false
+}
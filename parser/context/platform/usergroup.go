@@ -5,7 +5,6 @@ package platform
 
 import (
 	"io/fs"
-	"os/user"
 	"strconv"
 	"syscall"
 )
@@ -15,17 +14,16 @@ type UserName = string
 type GroupId = string
 type GroupName = string
 
-func UserGroup(file fs.FileInfo) (UserId, UserName, GroupId, GroupName) {
-	stat := file.Sys().(*syscall.Stat_t)
-	userId := strconv.FormatUint(uint64(stat.Uid), 10)
-
-	lookedUpUser, err := user.LookupId(userId)
-	if err != nil {
+/*
+UserGroup returns the owner and the group of the file. The ids are returned even if they have no name, for example, for
+the files extracted from an archive or on a mounted disk, and the names are looked up once for each id using the names.
+*/
+func UserGroup(file fs.FileInfo, names *OwnerNames) (UserId, UserName, GroupId, GroupName) {
+	stat, ok := file.Sys().(*syscall.Stat_t)
+	if !ok {
 		return "", "", "", ""
 	}
-	group, err := user.LookupGroupId(lookedUpUser.Gid)
-	if err != nil {
-		return "", "", "", ""
-	}
-	return lookedUpUser.Uid, lookedUpUser.Username, group.Gid, group.Name
+	userId := strconv.FormatUint(uint64(stat.Uid), 10)
+	groupId := strconv.FormatUint(uint64(stat.Gid), 10)
+	return userId, names.userName(userId), groupId, names.groupName(groupId)
 }
@@ -0,0 +1,77 @@
+//go:build unit && !windows
+// +build unit,!windows
+
+package platform
+
+import (
+	"io/fs"
+	"os/user"
+	"syscall"
+	"testing"
+	"time"
+)
+
+type ownedFile struct {
+	stat *syscall.Stat_t
+}
+
+func (file ownedFile) Name() string       { return "owned.txt" }
+func (file ownedFile) Size() int64        { return 0 }
+func (file ownedFile) Mode() fs.FileMode  { return 0644 }
+func (file ownedFile) ModTime() time.Time { return time.Time{} }
+func (file ownedFile) IsDir() bool        { return false }
+func (file ownedFile) Sys() interface{}   { return file.stat }
+
+func TestUserGroupReturnsTheGroupOfTheFile(t *testing.T) {
+	root, err := user.LookupGroupId("0")
+	if err != nil {
+		t.Skipf("group 0 can not be looked up: %v", err)
+	}
+	userId, _, groupId, groupName := UserGroup(ownedFile{stat: &syscall.Stat_t{Uid: 4242424, Gid: 0}}, NewOwnerNames())
+
+	if userId != "4242424" {
+		t.Fatalf("Expected userId to be %v, received %v", "4242424", userId)
+	}
+	if groupId != "0" || groupName != root.Name {
+		t.Fatalf("Expected group to be %v %v, received %v %v", "0", root.Name, groupId, groupName)
+	}
+}
+
+func TestUserGroupKeepsTheIdsWithoutANameEntry(t *testing.T) {
+	userId, userName, groupId, groupName := UserGroup(ownedFile{stat: &syscall.Stat_t{Uid: 4242424, Gid: 4242425}}, nil)
+
+	if userId != "4242424" || groupId != "4242425" {
+		t.Fatalf("Expected the ids to be %v and %v, received %v and %v", "4242424", "4242425", userId, groupId)
+	}
+	if userName != "" || groupName != "" {
+		t.Fatalf("Expected the names to be blank, received %v and %v", userName, groupName)
+	}
+}
+
+func TestOwnerNamesCachesTheLookups(t *testing.T) {
+	names, lookups := NewOwnerNames(), 0
+	lookupBlock := func(id string) (string, error) {
+		lookups = lookups + 1
+		return "name-" + id, nil
+	}
+	for count := 0; count < 3; count++ {
+		if name := names.lookup(names.userNames, "1000", lookupBlock); name != "name-1000" {
+			t.Fatalf("Expected the name to be %v, received %v", "name-1000", name)
+		}
+	}
+	if lookups != 1 {
+		t.Fatalf("Expected the name to be looked up once, received %v lookups", lookups)
+	}
+}
+
+func TestOwnerNamesCachesTheIdsWithoutAName(t *testing.T) {
+	names, lookups := NewOwnerNames(), 0
+	lookupBlock := func(id string) (string, error) {
+		lookups = lookups + 1
+		return "", user.UnknownGroupIdError(id)
+	}
+	names.lookup(names.groupNames, "4242425", lookupBlock)
+	if name := names.lookup(names.groupNames, "4242425", lookupBlock); name != "" || lookups != 1 {
+		t.Fatalf("Expected a blank name looked up once, received %v after %v lookups", name, lookups)
+	}
+}
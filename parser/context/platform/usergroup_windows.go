@@ -12,6 +12,6 @@ type UserName = string
 type GroupId = string
 type GroupName = string
 
-func UserGroup(file fs.FileInfo) (UserId, UserName, GroupId, GroupName) {
+func UserGroup(file fs.FileInfo, names *OwnerNames) (UserId, UserName, GroupId, GroupName) {
 	return "", "", "", ""
 }
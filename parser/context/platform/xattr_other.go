@@ -0,0 +1,18 @@
+//go:build !linux && !darwin
+// +build !linux,!darwin
+
+package platform
+
+import (
+	"errors"
+)
+
+var errExtendedAttributesNotSupported = errors.New("extended attributes are not supported on this platform")
+
+func ExtendedAttributeNames(_ string) ([]string, error) {
+	return nil, errExtendedAttributesNotSupported
+}
+
+func ExtendedAttribute(_ string, _ string) ([]byte, error) {
+	return nil, errExtendedAttributesNotSupported
+}
@@ -0,0 +1,58 @@
+//go:build linux || darwin
+// +build linux darwin
+
+package platform
+
+import (
+	"golang.org/x/sys/unix"
+	"strings"
+)
+
+/*
+ExtendedAttributeNames lists the names of the extended attributes of a file, without following a symbolic link.
+*/
+func ExtendedAttributeNames(path string) ([]string, error) {
+	list, err := readGrowing(func(dest []byte) (int, error) {
+		return unix.Llistxattr(path, dest)
+	})
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, name := range strings.Split(string(list), "\x00") {
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+/*
+ExtendedAttribute reads the value of an extended attribute of a file, without following a symbolic link.
+*/
+func ExtendedAttribute(path string, name string) ([]byte, error) {
+	return readGrowing(func(dest []byte) (int, error) {
+		return unix.Lgetxattr(path, name, dest)
+	})
+}
+
+func readGrowing(read func(dest []byte) (int, error)) ([]byte, error) {
+	for {
+		size, err := read(nil)
+		if err != nil {
+			return nil, err
+		}
+		if size == 0 {
+			return nil, nil
+		}
+		dest := make([]byte, size)
+		size, err = read(dest)
+		if err == unix.ERANGE {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return dest[0:size], nil
+	}
+}
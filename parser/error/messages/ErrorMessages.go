@@ -10,18 +10,37 @@ const (
 	ErrorMessageMissingOrderByAttributes                  = "expected an attribute position after 'order by'. attribute positions start with 1"
 	ErrorMessageNonZeroPositivePositions                  = "expected non-zero & positive 'order by' positions"
 	ErrorMessageNonZeroPositivePositionsWithExistingError = "expected non-zero & positive 'order by' positions, %v"
+	ErrorMessageInvalidOrderByAttribute                   = "invalid order by clause, %v is neither an attribute position, nor an alias, nor an attribute, nor a function, nor an expression"
+	ErrorMessageInvalidOrderBy                            = "invalid order by clause, please check opening and closing parentheses for all the functions"
 	ErrorMessageOrderByPositionOutOfRange                 = "expected 'order by' position to be between %v and %v, both inclusive"
 	ErrorMessageMissingSource                             = "expected a source path after 'from`"
-	ErrorMessageInaccessibleSource                        = "expected directory path %v to exist. please check the path, also ensure that it is accessible"
+	ErrorMessageInaccessibleSource                        = "expected source path %v to exist. please check the path, also ensure that it is accessible"
 	ErrorMessageSourceNotADirectory                       = "expected source path to be a directory"
-	ErrorMessageInvalidKeywordAfterFrom                   = "expected either where or order by or limit clause after the source directory"
+	ErrorMessageInvalidSourcePattern                      = "expected a valid glob pattern in the source path %v"
+	ErrorMessageInvalidKeywordAfterFrom                   = "expected either where or group by or order by or limit clause after the source directory"
+	ErrorMessageMissingByAfterGroup                       = "expected 'by' after group"
+	ErrorMessageMissingCommaGroupBy                       = "expected a comma after 'group by' in attribute positions or expressions"
+	ErrorMessageMissingGroupByAttributes                  = "expected an attribute position or an expression after 'group by'. attribute positions start with 1"
+	ErrorMessageNonZeroPositiveGroupByPositions           = "expected non-zero & positive 'group by' positions"
+	ErrorMessageGroupByPositionOutOfRange                 = "expected 'group by' position to be between %v and %v, both inclusive"
+	ErrorMessageGroupByPositionOnAggregate                = "expected 'group by' position %v to refer to a projection without aggregate functions"
+	ErrorMessageAggregateFunctionInsideGroupBy            = "invalid group by clause, aggregate functions are not supported in the group by clause"
+	ErrorMessageInvalidGroupBy                            = "invalid group by clause, please check opening and closing parentheses for all the functions"
+	ErrorMessageInvalidGroupByAttribute                   = "invalid group by clause, %v is neither an attribute position, nor an attribute, nor a function"
+	ErrorMessageHavingWithoutGroupBy                      = "expected 'having' to be preceded by a 'group by' clause"
+	ErrorMessageExpectedExpressionInHaving                = "expected one expression in the having clause, or remove 'having' keyword"
+	ErrorMessageInvalidHaving                             = "invalid having clause, please check opening and closing parentheses for all the functions"
+	ErrorMessageInvalidHavingFunctionUsed                 = "invalid having clause, 'having' clause must be a single expression. please check all the functions supported in 'where' clause, the same functions are supported in 'having'.\n'having' can be followed by either an 'order by' or a 'limit' clause"
 	ErrorMessageMissingCommaProjection                    = "expected a comma in the projection list after a supported attribute or a function. please check the spellings, supported attributes and supported functions as well"
 	ErrorMessageOpeningParenthesesProjection              = "expected an opening parentheses in the projection list after '%v'"
 	ErrorMessageInvalidProjection                         = "invalid projection list, please check the opening and closing parentheses for all the functions"
+	ErrorMessageUnsupportedValueInProjection              = "expected a supported attribute or a function or an expression in the projection list, received %v. please check the spellings, supported attributes and supported functions as well"
+	ErrorMessageMissingAliasAfterAs                       = "expected an alias after 'as' in the projection list"
+	ErrorMessageAliasOnWildcard                           = "expected 'as' to follow an attribute or a function or an expression, an alias can not be given to '*'"
 	ErrorMessageExpectedExpressionInProjection            = "expected atleast one expression in the projection list. please check the supported attributes and functions"
 	ErrorMessageExpectedExpressionInWhere                 = "expected one expression in the where clause, or remove 'where' keyword"
 	ErrorMessageInvalidWhere                              = "invalid where clause, please check opening and closing parentheses for all the functions"
-	ErrorMessageInvalidWhereFunctionUsed                  = "invalid where clause, 'where' clause must be a single expression. please check all the functions supported in 'where' clause.\n'where' can be followed by either an 'order by' or a 'limit' clause"
+	ErrorMessageInvalidWhereFunctionUsed                  = "invalid where clause, 'where' clause must be a single expression. please check all the functions supported in 'where' clause.\n'where' can be followed by either a 'group by' or an 'order by' or a 'limit' clause"
 	ErrorMessageAggregateFunctionInsideWhere              = "invalid where clause, aggregate functions are not supported in the where clause"
 	ErrorMessageMissingParameterInScalarFunctions         = "expected %v parameter(s) in the function %v but did not receive the required parameter(s)"
 	ErrorMessageIncorrectValueType                        = "expected a %v value type but received %v"
@@ -34,6 +53,10 @@ const (
 	ErrorMessageUnsupportedDateTimeFormat                 = "expected a supported date/time format id. Use CLI to check the supported date/time format ids"
 	ErrorMessageCannotConvertToBoolean                    = "expected conversion of %v to boolean, but failed"
 	ErrorMessageUndefinedConversionFunction               = "expected conversion of %v to %v, but such a conversion is not supported"
+	ErrorMessageInvalidGitRevision                        = "expected a valid git revision in the source %v, %v"
+	ErrorMessageSourceOutsideRepository                   = "expected the path in the source %v to be inside the git repository %v"
+	ErrorMessageSymbolicLinkLoop                          = "file system loop detected, %v points to one of its parent directories"
+	ErrorMessageStreamingNotSupported                     = "expected a query without 'order by', 'group by', 'having' and aggregate functions for streaming the results"
 	ErrorMessageQueryAliasAlreadyExists                   = "expected a non-existing query alias. Query alias %v is already present in the file %v"
 	ErrorMessageQueryAliasAddPrefixWithExistingError      = "[Add query alias], %s"
 	ErrorMessageQueryAliasGetPrefixWithExistingError      = "[Get query alias], %s"
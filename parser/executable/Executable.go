@@ -0,0 +1,227 @@
+package executable
+
+import (
+	"bytes"
+	"debug/buildinfo"
+	"debug/elf"
+	"debug/macho"
+	"debug/pe"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	TypeElf   = "elf"
+	TypeMachO = "macho"
+	TypePe    = "pe"
+)
+
+var ErrNotAnExecutable = errors.New("not a supported executable")
+
+var (
+	elfMagic      = []byte("\x7fELF")
+	machOMagics   = [][]byte{{0xfe, 0xed, 0xfa, 0xce}, {0xfe, 0xed, 0xfa, 0xcf}, {0xce, 0xfa, 0xed, 0xfe}, {0xcf, 0xfa, 0xed, 0xfe}}
+	machOFatMagic = []byte{0xca, 0xfe, 0xba, 0xbe}
+	peMagic       = []byte("MZ")
+)
+
+var elfArchitectures = map[elf.Machine]string{
+	elf.EM_386:     "386",
+	elf.EM_X86_64:  "amd64",
+	elf.EM_ARM:     "arm",
+	elf.EM_AARCH64: "arm64",
+	elf.EM_RISCV:   "riscv64",
+	elf.EM_S390:    "s390x",
+}
+
+var machOArchitectures = map[macho.Cpu]string{
+	macho.Cpu386:   "386",
+	macho.CpuAmd64: "amd64",
+	macho.CpuArm:   "arm",
+	macho.CpuArm64: "arm64",
+	macho.CpuPpc:   "ppc",
+	macho.CpuPpc64: "ppc64",
+}
+
+var peArchitectures = map[uint16]string{
+	pe.IMAGE_FILE_MACHINE_I386:  "386",
+	pe.IMAGE_FILE_MACHINE_AMD64: "amd64",
+	pe.IMAGE_FILE_MACHINE_ARMNT: "arm",
+	pe.IMAGE_FILE_MACHINE_ARM64: "arm64",
+}
+
+/*
+Executable is the metadata of an ELF, a Mach-O or a PE binary. The architecture uses the names of GOARCH, and a
+universal Mach-O binary has the architectures of all its binaries separated by a comma, with the other fields from
+its first binary. The Go version and the module path are blank for a binary not built by Go.
+*/
+type Executable struct {
+	Type             string
+	Architecture     string
+	IsStripped       bool
+	IsStatic         bool
+	DynamicLibraries []string
+	GoVersion        string
+	GoModulePath     string
+}
+
+/*
+Read reads the headers of an ELF, a Mach-O or a PE binary, identified by its magic number.
+A binary is stripped if it does not have the symbol table, and static if it does not load any shared library.
+*/
+func Read(reader io.ReaderAt) (*Executable, error) {
+	header := make([]byte, 4)
+	if _, err := reader.ReadAt(header, 0); err != nil {
+		return nil, ErrNotAnExecutable
+	}
+	var executable *Executable
+	var err error
+	switch {
+	case bytes.Equal(header, elfMagic):
+		executable, err = readElf(reader)
+	case bytes.Equal(header, machOFatMagic):
+		executable, err = readMachOFat(reader)
+	case isMachO(header):
+		executable, err = readMachO(reader)
+	case bytes.HasPrefix(header, peMagic):
+		executable, err = readPe(reader)
+	default:
+		return nil, ErrNotAnExecutable
+	}
+	if err != nil {
+		return nil, ErrNotAnExecutable
+	}
+	if info, err := buildinfo.Read(reader); err == nil {
+		executable.GoVersion, executable.GoModulePath = info.GoVersion, info.Main.Path
+	}
+	return executable, nil
+}
+
+func readElf(reader io.ReaderAt) (*Executable, error) {
+	file, err := elf.NewFile(reader)
+	if err != nil {
+		return nil, err
+	}
+	libraries, _ := file.ImportedLibraries()
+	hasInterpreter := false
+	for _, program := range file.Progs {
+		if program.Type == elf.PT_INTERP {
+			hasInterpreter = true
+		}
+	}
+	return &Executable{
+		Type:             TypeElf,
+		Architecture:     elfArchitecture(file),
+		IsStripped:       file.Section(".symtab") == nil,
+		IsStatic:         !hasInterpreter && len(libraries) == 0,
+		DynamicLibraries: libraries,
+	}, nil
+}
+
+func elfArchitecture(file *elf.File) string {
+	switch file.Machine {
+	case elf.EM_PPC64:
+		if file.Data == elf.ELFDATA2LSB {
+			return "ppc64le"
+		}
+		return "ppc64"
+	case elf.EM_MIPS:
+		architecture := "mips"
+		if file.Class == elf.ELFCLASS64 {
+			architecture = "mips64"
+		}
+		if file.Data == elf.ELFDATA2LSB {
+			return architecture + "le"
+		}
+		return architecture
+	}
+	if architecture, ok := elfArchitectures[file.Machine]; ok {
+		return architecture
+	}
+	return strings.ToLower(strings.TrimPrefix(file.Machine.String(), "EM_"))
+}
+
+func isMachO(header []byte) bool {
+	for _, magic := range machOMagics {
+		if bytes.Equal(header, magic) {
+			return true
+		}
+	}
+	return false
+}
+
+func readMachO(reader io.ReaderAt) (*Executable, error) {
+	file, err := macho.NewFile(reader)
+	if err != nil {
+		return nil, err
+	}
+	return machOExecutable(file), nil
+}
+
+func readMachOFat(reader io.ReaderAt) (*Executable, error) {
+	file, err := macho.NewFatFile(reader)
+	if err != nil {
+		return nil, err
+	}
+	executable := machOExecutable(file.Arches[0].File)
+	architectures := make([]string, 0, len(file.Arches))
+	for _, arch := range file.Arches {
+		architectures = append(architectures, machOArchitecture(arch.Cpu))
+	}
+	executable.Architecture = strings.Join(architectures, ",")
+	return executable, nil
+}
+
+func machOExecutable(file *macho.File) *Executable {
+	libraries, _ := file.ImportedLibraries()
+	return &Executable{
+		Type:             TypeMachO,
+		Architecture:     machOArchitecture(file.Cpu),
+		IsStripped:       file.Symtab == nil || file.Dysymtab == nil || file.Dysymtab.Nlocalsym == 0,
+		IsStatic:         len(libraries) == 0,
+		DynamicLibraries: libraries,
+	}
+}
+
+func machOArchitecture(cpu macho.Cpu) string {
+	if architecture, ok := machOArchitectures[cpu]; ok {
+		return architecture
+	}
+	return strings.ToLower(strings.TrimPrefix(cpu.String(), "Cpu"))
+}
+
+/*
+readPe reads a PE binary. The libraries are the DLLs of the imported symbols, in the order of the import table.
+*/
+func readPe(reader io.ReaderAt) (*Executable, error) {
+	file, err := pe.NewFile(reader)
+	if err != nil {
+		return nil, err
+	}
+	symbols, _ := file.ImportedSymbols()
+	var libraries []string
+	seen := make(map[string]bool)
+	for _, symbol := range symbols {
+		index := strings.LastIndex(symbol, ":")
+		if index < 0 {
+			continue
+		}
+		if library := symbol[index+1:]; !seen[strings.ToLower(library)] {
+			seen[strings.ToLower(library)] = true
+			libraries = append(libraries, library)
+		}
+	}
+	architecture, ok := peArchitectures[file.Machine]
+	if !ok {
+		architecture = fmt.Sprintf("0x%04x", file.Machine)
+	}
+	return &Executable{
+		Type:             TypePe,
+		Architecture:     architecture,
+		IsStripped:       len(file.Symbols) == 0,
+		IsStatic:         len(libraries) == 0,
+		DynamicLibraries: libraries,
+	}, nil
+}
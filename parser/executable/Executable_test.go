@@ -0,0 +1,100 @@
+//go:build unit
+// +build unit
+
+package executable
+
+import (
+	"bytes"
+	"os"
+	"runtime"
+	"testing"
+)
+
+func readTestBinary(t *testing.T) *Executable {
+	path, err := os.Executable()
+	if err != nil {
+		t.Skipf("test binary not available: %v", err)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("expected no error while opening the test binary, received %v", err)
+	}
+	defer file.Close()
+
+	binary, err := Read(file)
+	if err != nil {
+		t.Fatalf("expected no error while reading the test binary, received %v", err)
+	}
+	return binary
+}
+
+func TestReadTheTypeOfTheTestBinary(t *testing.T) {
+	binary := readTestBinary(t)
+
+	expected := TypeElf
+	switch runtime.GOOS {
+	case "darwin", "ios":
+		expected = TypeMachO
+	case "windows":
+		expected = TypePe
+	}
+	if binary.Type != expected {
+		t.Fatalf("Expected type to be %v, received %v", expected, binary.Type)
+	}
+}
+
+func TestReadTheArchitectureOfTheTestBinary(t *testing.T) {
+	binary := readTestBinary(t)
+
+	if binary.Architecture != runtime.GOARCH {
+		t.Fatalf("Expected architecture to be %v, received %v", runtime.GOARCH, binary.Architecture)
+	}
+}
+
+func TestReadTheGoBuildInformationOfTheTestBinary(t *testing.T) {
+	binary := readTestBinary(t)
+
+	if binary.GoVersion != runtime.Version() {
+		t.Fatalf("Expected go version to be %v, received %v", runtime.Version(), binary.GoVersion)
+	}
+	if binary.GoModulePath != "goselect" {
+		t.Fatalf("Expected go module to be %v, received %v", "goselect", binary.GoModulePath)
+	}
+}
+
+func TestTheLibrariesOfAStaticBinaryAreEmpty(t *testing.T) {
+	binary := readTestBinary(t)
+
+	if binary.IsStatic && len(binary.DynamicLibraries) != 0 {
+		t.Fatalf("Expected a static binary to not have libraries, received %v", binary.DynamicLibraries)
+	}
+}
+
+func TestReadATextFile(t *testing.T) {
+	_, err := Read(bytes.NewReader([]byte("#!/bin/sh\necho hello\n")))
+	if err != ErrNotAnExecutable {
+		t.Fatalf("Expected error to be %v, received %v", ErrNotAnExecutable, err)
+	}
+}
+
+func TestReadAJavaClassFileWithTheMagicOfAUniversalBinary(t *testing.T) {
+	class := []byte{0xca, 0xfe, 0xba, 0xbe, 0x00, 0x00, 0x00, 0x34, 0x00, 0x0a, 0x0a, 0x00}
+	_, err := Read(bytes.NewReader(class))
+	if err != ErrNotAnExecutable {
+		t.Fatalf("Expected error to be %v, received %v", ErrNotAnExecutable, err)
+	}
+}
+
+func TestReadATruncatedElf(t *testing.T) {
+	_, err := Read(bytes.NewReader([]byte("\x7fELF\x02\x01")))
+	if err != ErrNotAnExecutable {
+		t.Fatalf("Expected error to be %v, received %v", ErrNotAnExecutable, err)
+	}
+}
+
+func TestReadAnEmptyFile(t *testing.T) {
+	_, err := Read(bytes.NewReader(nil))
+	if err != ErrNotAnExecutable {
+		t.Fatalf("Expected error to be %v, received %v", ErrNotAnExecutable, err)
+	}
+}
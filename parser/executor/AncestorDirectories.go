@@ -0,0 +1,69 @@
+package executor
+
+import (
+	"goselect/parser/context/platform"
+	"goselect/parser/filesystem"
+	"io/fs"
+	"path/filepath"
+)
+
+/*
+directoryIdentity identifies a directory by device and inode, or by its path with all the symbolic links resolved on
+the platforms that do not expose the inode, like windows, where a junction or a symbolic link can make a loop too.
+*/
+type directoryIdentity struct {
+	device        platform.Device
+	inode         platform.Inode
+	canonicalPath string
+	known         bool
+}
+
+func identityOf(fileSystem filesystem.FileSystem, path string, directory fs.FileInfo) directoryIdentity {
+	if device, inode, ok := platform.FileIdentity(directory); ok {
+		return directoryIdentity{device: device, inode: inode, known: true}
+	}
+	if !filesystem.IsOs(fileSystem) {
+		return directoryIdentity{}
+	}
+	return canonicalIdentityOf(path)
+}
+
+func canonicalIdentityOf(path string) directoryIdentity {
+	canonicalPath, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return directoryIdentity{}
+	}
+	if absolutePath, err := filepath.Abs(canonicalPath); err == nil {
+		canonicalPath = absolutePath
+	}
+	return directoryIdentity{canonicalPath: canonicalPath, known: true}
+}
+
+/*
+ancestorDirectories is the chain of the directories from the source directory to the directory being traversed.
+A directory that is already in the chain, typically reached through a symbolic link, would make the traversal loop
+forever. The chain is immutable so that it can be shared by the concurrent traversal.
+*/
+type ancestorDirectories struct {
+	identity directoryIdentity
+	parent   *ancestorDirectories
+}
+
+func (ancestors *ancestorDirectories) with(directory directoryIdentity) *ancestorDirectories {
+	if !directory.known {
+		return ancestors
+	}
+	return &ancestorDirectories{identity: directory, parent: ancestors}
+}
+
+func (ancestors *ancestorDirectories) contains(directory directoryIdentity) bool {
+	if !directory.known {
+		return false
+	}
+	for ancestor := ancestors; ancestor != nil; ancestor = ancestor.parent {
+		if ancestor.identity == directory {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,94 @@
+package executor
+
+import (
+	"goselect/parser/archive"
+	"goselect/parser/context"
+	"goselect/parser/source"
+	"io/fs"
+	"strings"
+)
+
+type archiveEntry struct {
+	fileAttributes *context.FileAttributes
+	depth          int
+}
+
+/*
+archiveEntries returns the attributes of the entries in an archive, which is treated as a virtual directory.
+The depth of an entry is the depth of the archive plus the number of segments in the path of the entry, and
+the entries deeper than the maximum depth or not matching the glob pattern of the root are left out.
+*/
+func (selectQueryExecutor SelectQueryExecutor) archiveEntries(
+	archivePath string,
+	relativePath string,
+	depth int,
+	root *source.Root,
+) ([]archiveEntry, error) {
+	entries, err := archive.ReadEntries(selectQueryExecutor.context.FileSystem(), archivePath)
+	if err != nil {
+		return nil, err
+	}
+	var archiveEntries []archiveEntry
+	for _, entry := range entries {
+		entryDepth := depth + strings.Count(entry.Path, "/") + 1
+		entryRelativePath := entry.Path
+		if relativePath != "" {
+			entryRelativePath = relativePath + "/" + entry.Path
+		}
+		if entryDepth > selectQueryExecutor.maxDepth || !root.Matches(entryRelativePath) {
+			continue
+		}
+		level := directoryLevel{root: root, depth: entryDepth}
+		fileAttributes := context.ToArchiveEntryAttributes(
+			archivePath,
+			entry.Path,
+			entry.Info,
+			entry.CompressedSize,
+			entry.IsEmpty,
+			selectQueryExecutor.context,
+		)
+		archiveEntries = append(archiveEntries, archiveEntry{
+			fileAttributes: level.describe(fileAttributes, false, selectQueryExecutor.context),
+			depth:          entryDepth,
+		})
+	}
+	return archiveEntries, nil
+}
+
+func (selectQueryExecutor SelectQueryExecutor) executeArchive(
+	root *source.Root,
+	maxLimit uint32,
+	rows rowCollector,
+	grouping *Grouping,
+) error {
+	archiveEntries, err := selectQueryExecutor.archiveEntries(root.Path, "", 0, root)
+	if err != nil {
+		return err
+	}
+	return selectQueryExecutor.addArchiveEntries(archiveEntries, maxLimit, rows, grouping)
+}
+
+func (selectQueryExecutor SelectQueryExecutor) addArchiveEntries(
+	archiveEntries []archiveEntry,
+	maxLimit uint32,
+	rows rowCollector,
+	grouping *Grouping,
+) error {
+	for _, archiveEntry := range archiveEntries {
+		if selectQueryExecutor.haveCollectedEnough(rows, maxLimit) {
+			return nil
+		}
+		if err := selectQueryExecutor.addIfChosen(archiveEntry.fileAttributes, archiveEntry.depth, rows, grouping); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (selectQueryExecutor SelectQueryExecutor) shouldTraverseArchive(file fs.FileInfo, level directoryLevel) bool {
+	return selectQueryExecutor.options.traverseArchives &&
+		file.Mode().IsRegular() &&
+		level.depth < selectQueryExecutor.maxDepth &&
+		archive.IsArchive(file.Name()) &&
+		level.canMatchBelow(file.Name())
+}
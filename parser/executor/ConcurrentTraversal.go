@@ -0,0 +1,357 @@
+package executor
+
+import (
+	"goselect/parser/context"
+	"goselect/parser/expression"
+	"goselect/parser/source"
+	"os"
+	"sync"
+	"sync/atomic"
+)
+
+/*
+maxBufferedEntries is the number of the entries that the workers can read ahead of the rows that are added, after which
+the workers wait for the rows to be added before reading another directory.
+*/
+var maxBufferedEntries = 16 * 1024
+
+type ConcurrentTraversal struct {
+	executor        SelectQueryExecutor
+	grouping        *Grouping
+	maxLimit        uint32
+	tasks           chan *directoryTask
+	pendingTasks    sync.WaitGroup
+	chosenEntries   uint32
+	stopped         int32
+	firstError      error
+	errorLock       sync.Mutex
+	bufferedEntries int
+	bufferLock      sync.Mutex
+	bufferReleased  *sync.Cond
+	bufferFull      chan struct{}
+}
+
+type directoryTask struct {
+	directory string
+	entries   []os.DirEntry
+	level     directoryLevel
+	result    *traversedDirectory
+}
+
+type traversedDirectory struct {
+	entries []*traversedEntry
+	size    int
+	done    chan struct{}
+}
+
+type traversedEntry struct {
+	child          *traversedDirectory
+	fileAttributes *context.FileAttributes
+	values         []context.Value
+	fullyEvaluated []bool
+	expressions    []*expression.Expression
+}
+
+func newConcurrentTraversal(executor SelectQueryExecutor, grouping *Grouping, maxLimit uint32) *ConcurrentTraversal {
+	traversal := &ConcurrentTraversal{
+		executor:   executor,
+		grouping:   grouping,
+		maxLimit:   maxLimit,
+		tasks:      make(chan *directoryTask, executor.options.Parallelism()),
+		bufferFull: make(chan struct{}, 1),
+	}
+	traversal.bufferReleased = sync.NewCond(&traversal.bufferLock)
+	return traversal
+}
+
+func newTraversedDirectory() *traversedDirectory {
+	return &traversedDirectory{done: make(chan struct{})}
+}
+
+/*
+The directories are read and the entries are evaluated against the 'where' clause by a bounded pool of workers.
+The results are kept in the shape of the directory tree, and are added to the rows on the calling goroutine in the
+same order as a sequential traversal, as soon as a directory and all the directories before it are visited.
+This keeps the aggregate function state correct and the ordering of the rows identical to a sequential run,
+streams the rows while the traversal is running, and releases the entries once they are added.
+*/
+func (traversal *ConcurrentTraversal) execute(root *source.Root, rows rowCollector) error {
+	entries, err := traversal.executor.readDirectory(root.Directory)
+	if err != nil {
+		return err
+	}
+	level, err := traversal.executor.rootLevel(root)
+	if err != nil {
+		return err
+	}
+	var workers sync.WaitGroup
+	for worker := 0; worker < traversal.executor.options.Parallelism(); worker++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for {
+				traversal.waitForBuffer()
+				task, ok := <-traversal.tasks
+				if !ok {
+					return
+				}
+				traversal.visit(task)
+			}
+		}()
+	}
+
+	result := newTraversedDirectory()
+	traversal.pendingTasks.Add(1)
+	traversal.submit(&directoryTask{directory: root.Directory, entries: entries, level: level, result: result})
+	_, err = traversal.addTo(rows, result)
+
+	traversal.stop()
+	traversal.pendingTasks.Wait()
+	close(traversal.tasks)
+	workers.Wait()
+
+	if traversalError := traversal.err(); traversalError != nil {
+		return traversalError
+	}
+	return err
+}
+
+func (traversal *ConcurrentTraversal) submit(task *directoryTask) {
+	select {
+	case traversal.tasks <- task:
+	default:
+		traversal.visit(task)
+	}
+}
+
+func (traversal *ConcurrentTraversal) visit(task *directoryTask) {
+	defer traversal.pendingTasks.Done()
+	defer close(task.result.done)
+	if task.level.depth > traversal.executor.maxDepth {
+		return
+	}
+	task.result.size = len(task.entries)
+	traversal.bufferLock.Lock()
+	traversal.bufferedEntries = traversal.bufferedEntries + task.result.size
+	traversal.bufferLock.Unlock()
+
+	for _, entry := range task.entries {
+		if traversal.isStopped() {
+			return
+		}
+		ignored := task.level.isIgnored(entry.Name(), entry.IsDir())
+		if ignored && traversal.executor.options.ShouldSkipIgnoredFiles() {
+			continue
+		}
+		file, err := entry.Info()
+		if err != nil {
+			if err := traversal.executor.tolerate(traversal.executor.childDirectoryName(task.directory, entry), err); err != nil {
+				traversal.stopWith(err)
+				return
+			}
+			if traversal.executor.options.ShouldCollectErrors() && task.level.matches(entry.Name()) {
+				traversed := &traversedEntry{}
+				task.result.entries = append(task.result.entries, traversed)
+				fileAttributes := context.ToFileAttributesWithError(task.directory, entry.Name(), err, traversal.executor.context)
+				if err := traversal.chooseAndEvaluate(traversed, task.level.describe(fileAttributes, ignored, traversal.executor.context), task.level.depth); err != nil {
+					traversal.stopWith(err)
+					return
+				}
+			}
+			continue
+		}
+		traversed := &traversedEntry{}
+		task.result.entries = append(task.result.entries, traversed)
+
+		newPath := traversal.executor.childDirectoryName(task.directory, entry)
+		traversable, readError := traversal.executor.directoryToTraverse(newPath, file, task.level)
+		if traversable != nil {
+			childEntries, err := traversal.executor.readDirectory(newPath)
+			if err != nil {
+				if err := traversal.executor.tolerate(newPath, err); err != nil {
+					traversal.stopWith(err)
+					return
+				}
+				readError = err
+			} else {
+				traversed.child = newTraversedDirectory()
+				traversal.pendingTasks.Add(1)
+				traversal.submit(&directoryTask{
+					directory: newPath,
+					entries:   childEntries,
+					level:     task.level.child(entry.Name(), traversable),
+					result:    traversed.child,
+				})
+			}
+		} else if traversal.executor.shouldTraverseArchive(file, task.level) {
+			archiveEntries, err := traversal.executor.archiveEntries(newPath, task.level.relativePath(entry.Name()), task.level.depth, task.level.root)
+			if err != nil {
+				if err := traversal.executor.tolerate(newPath, err); err != nil {
+					traversal.stopWith(err)
+					return
+				}
+				readError = err
+			} else if err := traversal.visitArchive(traversed, archiveEntries); err != nil {
+				traversal.stopWith(err)
+				return
+			}
+		}
+		if !task.level.matches(entry.Name()) {
+			continue
+		}
+		fileAttributes := context.ToFileAttributes(task.directory, file, traversal.executor.context).
+			WithError(readError, traversal.executor.context)
+		if err := traversal.chooseAndEvaluate(traversed, task.level.describe(fileAttributes, ignored, traversal.executor.context), task.level.depth); err != nil {
+			traversal.stopWith(err)
+			return
+		}
+	}
+}
+
+func (traversal *ConcurrentTraversal) visitArchive(traversed *traversedEntry, archiveEntries []archiveEntry) error {
+	traversed.child = newTraversedDirectory()
+	defer close(traversed.child.done)
+	for _, archiveEntry := range archiveEntries {
+		if traversal.isStopped() {
+			return nil
+		}
+		child := &traversedEntry{}
+		traversed.child.entries = append(traversed.child.entries, child)
+		if err := traversal.chooseAndEvaluate(child, archiveEntry.fileAttributes, archiveEntry.depth); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (traversal *ConcurrentTraversal) chooseAndEvaluate(traversed *traversedEntry, fileAttributes *context.FileAttributes, depth int) error {
+	shouldChoose, err := traversal.executor.shouldChoose(fileAttributes, depth)
+	if err != nil || !shouldChoose {
+		return err
+	}
+	if err := traversal.evaluate(traversed, fileAttributes); err != nil {
+		return err
+	}
+	if traversal.executor.canStopEarly() && atomic.AddUint32(&traversal.chosenEntries, 1) >= traversal.maxLimit {
+		atomic.StoreInt32(&traversal.stopped, 1)
+	}
+	return nil
+}
+
+func (traversal *ConcurrentTraversal) evaluate(traversed *traversedEntry, fileAttributes *context.FileAttributes) error {
+	traversed.fileAttributes = fileAttributes
+	if !traversal.grouping.isStateless() {
+		return nil
+	}
+	values, fullyEvaluated, expressions, err := traversal.grouping.projections.EvaluateWith(
+		fileAttributes,
+		traversal.grouping.functions,
+	)
+	if err != nil {
+		return err
+	}
+	traversed.values, traversed.fullyEvaluated, traversed.expressions = values, fullyEvaluated, expressions
+	return nil
+}
+
+func (traversal *ConcurrentTraversal) addTo(rows rowCollector, directory *traversedDirectory) (bool, error) {
+	traversal.waitFor(directory)
+	if err := traversal.err(); err != nil {
+		return false, err
+	}
+	defer traversal.release(directory)
+	for index, traversed := range directory.entries {
+		directory.entries[index] = nil
+		if traversed.child != nil {
+			if collectedEnough, err := traversal.addTo(rows, traversed.child); err != nil || collectedEnough {
+				return collectedEnough, err
+			}
+		}
+		if traversal.executor.haveCollectedEnough(rows, traversal.maxLimit) {
+			return true, nil
+		}
+		if traversed.fileAttributes == nil ||
+			!traversal.executor.emitted.add(traversed.fileAttributes.Get(context.AttributeAbsolutePath).GetAsString()) {
+			continue
+		}
+		if traversed.values != nil {
+			rows.addRow(traversed.values, traversed.fullyEvaluated, traversed.expressions)
+		} else if err := traversal.grouping.addTo(rows, traversed.fileAttributes); err != nil {
+			return false, err
+		}
+	}
+	return false, nil
+}
+
+/*
+waitFor waits until the directory is visited. Once the workers wait for the buffered entries to be added, the waiting
+goroutine visits the pending directories itself, since the buffer is released only after the directory is visited.
+*/
+func (traversal *ConcurrentTraversal) waitFor(directory *traversedDirectory) {
+	for !traversal.isBufferFull() {
+		select {
+		case <-directory.done:
+			return
+		case <-traversal.bufferFull:
+		}
+	}
+	for {
+		select {
+		case <-directory.done:
+			return
+		case task := <-traversal.tasks:
+			traversal.visit(task)
+		}
+	}
+}
+
+func (traversal *ConcurrentTraversal) waitForBuffer() {
+	traversal.bufferLock.Lock()
+	defer traversal.bufferLock.Unlock()
+	for traversal.bufferedEntries >= maxBufferedEntries && !traversal.isStopped() {
+		select {
+		case traversal.bufferFull <- struct{}{}:
+		default:
+		}
+		traversal.bufferReleased.Wait()
+	}
+}
+
+func (traversal *ConcurrentTraversal) isBufferFull() bool {
+	traversal.bufferLock.Lock()
+	defer traversal.bufferLock.Unlock()
+	return traversal.bufferedEntries >= maxBufferedEntries
+}
+
+func (traversal *ConcurrentTraversal) release(directory *traversedDirectory) {
+	traversal.bufferLock.Lock()
+	defer traversal.bufferLock.Unlock()
+	traversal.bufferedEntries = traversal.bufferedEntries - directory.size
+	traversal.bufferReleased.Broadcast()
+}
+
+func (traversal *ConcurrentTraversal) stopWith(err error) {
+	traversal.errorLock.Lock()
+	if traversal.firstError == nil {
+		traversal.firstError = err
+	}
+	traversal.errorLock.Unlock()
+	traversal.stop()
+}
+
+func (traversal *ConcurrentTraversal) stop() {
+	atomic.StoreInt32(&traversal.stopped, 1)
+	traversal.bufferLock.Lock()
+	traversal.bufferReleased.Broadcast()
+	traversal.bufferLock.Unlock()
+}
+
+func (traversal *ConcurrentTraversal) err() error {
+	traversal.errorLock.Lock()
+	defer traversal.errorLock.Unlock()
+	return traversal.firstError
+}
+
+func (traversal *ConcurrentTraversal) isStopped() bool {
+	return atomic.LoadInt32(&traversal.stopped) == 1
+}
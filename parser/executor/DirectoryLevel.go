@@ -0,0 +1,80 @@
+package executor
+
+import (
+	"goselect/parser/context"
+	"goselect/parser/ignore"
+	"goselect/parser/source"
+)
+
+/*
+directoryLevel is the state of the traversal for a directory: the root in the 'from' clause being walked,
+the path of the directory relative to the directory of the root, its depth relative to the directory of the root,
+its ancestors for detecting the loops and the ignore rules that apply to its entries.
+The ignore rules are nil if the ignore files are not read.
+*/
+type directoryLevel struct {
+	root              *source.Root
+	relativeDirectory string
+	depth             int
+	ancestors         *ancestorDirectories
+	ignoreRules       *ignore.Rules
+}
+
+func (selectQueryExecutor SelectQueryExecutor) rootLevel(root *source.Root) (directoryLevel, error) {
+	level := directoryLevel{root: root, depth: 1}
+	fileSystem := selectQueryExecutor.context.FileSystem()
+	if directory, err := fileSystem.Stat(root.Directory); err == nil {
+		level.ancestors = level.ancestors.with(identityOf(fileSystem, root.Directory, directory))
+	}
+	if selectQueryExecutor.options.ShouldReadIgnoreFiles() {
+		ignoreRules, err := ignore.NewRules(fileSystem, root.Directory)
+		if err != nil {
+			return level, err
+		}
+		level.ignoreRules = ignoreRules
+	}
+	return level, nil
+}
+
+func (level directoryLevel) child(name string, directory *directoryIdentity) directoryLevel {
+	child := directoryLevel{
+		root:              level.root,
+		relativeDirectory: level.relativePath(name),
+		depth:             level.depth + 1,
+		ancestors:         level.ancestors.with(*directory),
+	}
+	if level.ignoreRules != nil {
+		child.ignoreRules = level.ignoreRules.ForDirectory(name)
+	}
+	return child
+}
+
+func (level directoryLevel) matches(name string) bool {
+	return level.root.Matches(level.relativePath(name))
+}
+
+func (level directoryLevel) canMatchBelow(name string) bool {
+	return level.root.CanMatchBelow(level.relativePath(name))
+}
+
+func (level directoryLevel) relativePath(name string) string {
+	if level.relativeDirectory == "" {
+		return name
+	}
+	return level.relativeDirectory + "/" + name
+}
+
+func (level directoryLevel) describe(
+	fileAttributes *context.FileAttributes,
+	ignored bool,
+	ctx *context.ParsingApplicationContext,
+) *context.FileAttributes {
+	return fileAttributes.
+		WithDepth(level.depth, ctx).
+		WithIgnored(ignored, ctx).
+		WithRoot(level.root.String(), ctx)
+}
+
+func (level directoryLevel) isIgnored(name string, isDirectory bool) bool {
+	return level.ignoreRules != nil && level.ignoreRules.IsIgnored(name, isDirectory)
+}
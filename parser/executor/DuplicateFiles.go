@@ -0,0 +1,64 @@
+package executor
+
+import (
+	"goselect/parser/context"
+)
+
+/*
+duplicateFiles collects the chosen files when the duplicates are queried, and returns only the files that have the same
+contents as at least one other file, grouped together by their contents.
+The files are first grouped by their size, so a file with a unique size is never hashed, and the files with the same size
+are then compared using the sha256 attribute, which is cached and reused if it is also projected.
+Only the regular non-empty files are compared, the directories, the links and the empty files are never duplicates.
+*/
+type duplicateFiles struct {
+	sizes  []int64
+	bySize map[int64][]*context.FileAttributes
+}
+
+func newDuplicateFiles() *duplicateFiles {
+	return &duplicateFiles{bySize: make(map[int64][]*context.FileAttributes)}
+}
+
+func (duplicates *duplicateFiles) add(fileAttributes *context.FileAttributes) {
+	if isFile, _ := fileAttributes.Get(context.AttributeNameIsFile).GetBoolean(); !isFile {
+		return
+	}
+	size, err := fileAttributes.Get(context.AttributeSize).GetNumericAsFloat64()
+	if err != nil || size == 0 {
+		return
+	}
+	key := int64(size)
+	if _, ok := duplicates.bySize[key]; !ok {
+		duplicates.sizes = append(duplicates.sizes, key)
+	}
+	duplicates.bySize[key] = append(duplicates.bySize[key], fileAttributes)
+}
+
+func (duplicates *duplicateFiles) all() []*context.FileAttributes {
+	var all []*context.FileAttributes
+	for _, size := range duplicates.sizes {
+		sameSize := duplicates.bySize[size]
+		if len(sameSize) < 2 {
+			continue
+		}
+		var hashes []string
+		byHash := make(map[string][]*context.FileAttributes)
+		for _, fileAttributes := range sameSize {
+			hash := fileAttributes.Get(context.AttributeSha256).GetAsString()
+			if hash == "" {
+				continue
+			}
+			if _, ok := byHash[hash]; !ok {
+				hashes = append(hashes, hash)
+			}
+			byHash[hash] = append(byHash[hash], fileAttributes)
+		}
+		for _, hash := range hashes {
+			if len(byHash[hash]) > 1 {
+				all = append(all, byHash[hash]...)
+			}
+		}
+	}
+	return all
+}
@@ -0,0 +1,78 @@
+//go:build unit
+// +build unit
+
+package executor
+
+import (
+	"goselect/parser/context"
+	"goselect/parser/filesystem"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+)
+
+type openCountingFS struct {
+	fstest.MapFS
+	opened map[string]int
+}
+
+func (countingFS openCountingFS) Open(name string) (fs.File, error) {
+	countingFS.opened[name] = countingFS.opened[name] + 1
+	return countingFS.MapFS.Open(name)
+}
+
+func TestDuplicateFilesDoesNotHashTheFilesWithAUniqueSize(t *testing.T) {
+	countingFS := openCountingFS{
+		MapFS: fstest.MapFS{
+			"logo.png":      {Data: []byte("logo")},
+			"logo-copy.png": {Data: []byte("logo")},
+			"banner.png":    {Data: []byte("banner")},
+		},
+		opened: make(map[string]int),
+	}
+	fileSystem := filesystem.FromFS(countingFS)
+	applicationContext := context.NewContext(context.NewFunctions(), context.NewAttributes()).WithFileSystem(fileSystem)
+
+	duplicates := newDuplicateFiles()
+	for _, name := range []string{"logo.png", "banner.png", "logo-copy.png"} {
+		file, err := fileSystem.Stat(name)
+		if err != nil {
+			t.Fatalf("error is %v", err)
+		}
+		duplicates.add(context.ToFileAttributes(".", file, applicationContext))
+	}
+	for name := range countingFS.opened {
+		delete(countingFS.opened, name)
+	}
+
+	all := duplicates.all()
+	if len(all) != 2 {
+		t.Fatalf("Expected 2 duplicate files, received %v", len(all))
+	}
+	if countingFS.opened["banner.png"] != 0 {
+		t.Fatalf("Expected the file with a unique size to not be hashed, it was opened %v times", countingFS.opened["banner.png"])
+	}
+	if countingFS.opened["logo.png"] != 1 || countingFS.opened["logo-copy.png"] != 1 {
+		t.Fatalf("Expected the files with the same size to be hashed once, received %v", countingFS.opened)
+	}
+}
+
+func TestDuplicateFilesIgnoresTheDirectoriesAndTheEmptyFiles(t *testing.T) {
+	fileSystem := filesystem.FromFS(fstest.MapFS{
+		"a/empty.txt": {Data: []byte("")},
+		"b/empty.txt": {Data: []byte("")},
+	})
+	applicationContext := context.NewContext(context.NewFunctions(), context.NewAttributes()).WithFileSystem(fileSystem)
+
+	duplicates := newDuplicateFiles()
+	for _, name := range []string{"a", "b", "a/empty.txt", "b/empty.txt"} {
+		file, err := fileSystem.Stat(name)
+		if err != nil {
+			t.Fatalf("error is %v", err)
+		}
+		duplicates.add(context.ToFileAttributes(".", file, applicationContext))
+	}
+	if all := duplicates.all(); len(all) != 0 {
+		t.Fatalf("Expected no duplicate files, received %v", len(all))
+	}
+}
@@ -0,0 +1,29 @@
+package executor
+
+/*
+emittedPaths keeps the absolute paths of the rows added from all the roots in the 'from' clause, so that a file
+reachable from more than one root, for example, 'from ./src, ./src/parser', is added only once, for the first root.
+The paths are not kept for a single root, which can not produce a file twice.
+The rows are added on a single goroutine, even for the concurrent traversal, so emittedPaths is not guarded.
+*/
+type emittedPaths struct {
+	paths map[string]bool
+}
+
+func newEmittedPaths(roots int) *emittedPaths {
+	if roots < 2 {
+		return nil
+	}
+	return &emittedPaths{paths: make(map[string]bool)}
+}
+
+func (emitted *emittedPaths) add(path string) bool {
+	if emitted == nil {
+		return true
+	}
+	if emitted.paths[path] {
+		return false
+	}
+	emitted.paths[path] = true
+	return true
+}
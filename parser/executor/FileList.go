@@ -0,0 +1,72 @@
+package executor
+
+import (
+	"bufio"
+	"bytes"
+	"goselect/parser/source"
+	"path/filepath"
+	"strings"
+)
+
+/*
+executeFileList evaluates the files listed in the file list of the context, like the output of 'git diff --name-only'
+or 'fd -0', instead of walking a directory. Each path is treated like a file in the 'from' clause, so a directory
+in the list is returned without its entries, and a path that can not be read is handled by the error policy.
+The directory of a path is kept as listed, so a bare name like 'a.txt' has the path 'a.txt', not './a.txt'.
+The list is read while the rows are collected, so that the rows can be streamed as the paths arrive.
+*/
+func (selectQueryExecutor SelectQueryExecutor) executeFileList(
+	root *source.Root,
+	maxLimit uint32,
+	rows rowCollector,
+	grouping *Grouping,
+) error {
+	scanner := bufio.NewScanner(selectQueryExecutor.context.FileList())
+	scanner.Split((&pathSplitter{}).split)
+	for scanner.Scan() {
+		if selectQueryExecutor.haveCollectedEnough(rows, maxLimit) {
+			return nil
+		}
+		path := strings.TrimSuffix(scanner.Text(), "\r")
+		if len(path) > 1 {
+			path = strings.TrimRight(path, "/"+pathSeparator)
+		}
+		if path == "" {
+			continue
+		}
+		directory := path[0 : len(path)-len(filepath.Base(path))]
+		if err := selectQueryExecutor.executeFileAt(path, directory, root, rows, grouping); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+/*
+pathSplitter splits a list of paths separated by NUL or by newline. The separator is NUL if the first chunk of the
+list with a separator contains a NUL, so that the paths with a newline in the name can be listed with 'find -print0'.
+*/
+type pathSplitter struct {
+	separator byte
+	decided   bool
+}
+
+func (splitter *pathSplitter) split(data []byte, atEOF bool) (int, []byte, error) {
+	if !splitter.decided {
+		switch {
+		case bytes.IndexByte(data, 0) >= 0:
+			splitter.separator, splitter.decided = 0, true
+		case bytes.IndexByte(data, '\n') >= 0 || atEOF:
+			splitter.separator, splitter.decided = '\n', true
+		default:
+			return 0, nil, nil
+		}
+	}
+	if index := bytes.IndexByte(data, splitter.separator); index >= 0 {
+		return index + 1, data[0:index], nil
+	}
+	if atEOF && len(data) > 0 {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
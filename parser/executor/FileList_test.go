@@ -0,0 +1,161 @@
+//go:build unit
+// +build unit
+
+package executor
+
+import (
+	"bufio"
+	"goselect/parser"
+	"goselect/parser/context"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func executeQueryWithFileList(t *testing.T, query string, fileList io.Reader, options *Options) (*EvaluatingRows, *SelectQueryExecutor, error) {
+	newContext := context.NewContext(context.NewFunctions(), context.NewAttributes()).
+		WithFileSystem(mapFileSystem()).
+		WithFileList(fileList)
+	aParser, err := parser.NewParser(query, newContext)
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	selectQuery, err := aParser.Parse()
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	queryExecutor := NewSelectQueryExecutor(selectQuery, newContext, options)
+	rows, err := queryExecutor.Execute()
+	return rows, queryExecutor, err
+}
+
+func splitPaths(reader io.Reader) []string {
+	scanner := bufio.NewScanner(reader)
+	scanner.Split((&pathSplitter{}).split)
+	var paths []string
+	for scanner.Scan() {
+		paths = append(paths, scanner.Text())
+	}
+	return paths
+}
+
+func TestSplitPathsSeparatedByNewline(t *testing.T) {
+	paths := splitPaths(strings.NewReader("main.go\ndocs/README.md\n"))
+	if len(paths) != 2 || paths[0] != "main.go" || paths[1] != "docs/README.md" {
+		t.Fatalf("Expected paths to be [main.go docs/README.md], received %v", paths)
+	}
+}
+
+func TestSplitPathsSeparatedByNUL(t *testing.T) {
+	paths := splitPaths(strings.NewReader("main.go\x00a\nb.md\x00"))
+	if len(paths) != 2 || paths[0] != "main.go" || paths[1] != "a\nb.md" {
+		t.Fatalf("Expected paths to be [main.go a\\nb.md], received %q", paths)
+	}
+}
+
+func TestSplitPathsReadOneByteAtATime(t *testing.T) {
+	paths := splitPaths(iotest.OneByteReader(strings.NewReader("main.go\x00app.log")))
+	if len(paths) != 2 || paths[0] != "main.go" || paths[1] != "app.log" {
+		t.Fatalf("Expected paths to be [main.go app.log], received %q", paths)
+	}
+}
+
+func TestSplitASinglePathWithoutASeparator(t *testing.T) {
+	paths := splitPaths(strings.NewReader("main.go"))
+	if len(paths) != 1 || paths[0] != "main.go" {
+		t.Fatalf("Expected paths to be [main.go], received %v", paths)
+	}
+}
+
+func TestExecuteOnAFileList(t *testing.T) {
+	fileList := strings.NewReader("main.go\r\ndocs/guide/\n\ndocs/README.md\n")
+	rows, _, err := executeQueryWithFileList(t, "select path, name, isdir, depth, root from stdin", fileList, NewDefaultOptions())
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	expected := [][]context.Value{
+		{context.StringValue("main.go"), context.StringValue("main.go"), context.BooleanValue(false), context.IntValue(0), context.StringValue("stdin")},
+		{context.StringValue("docs/guide"), context.StringValue("guide"), context.BooleanValue(true), context.IntValue(0), context.StringValue("stdin")},
+		{context.StringValue("docs/README.md"), context.StringValue("README.md"), context.BooleanValue(false), context.IntValue(0), context.StringValue("stdin")},
+	}
+	AssertMatch(t, expected, rows)
+}
+
+func TestExecuteOnAFileListWithWhereOrderAndLimit(t *testing.T) {
+	fileList := strings.NewReader("main.go\x00app.log\x00docs/guide/intro.md\x00docs\x00")
+	rows, _, err := executeQueryWithFileList(t, "select name, size from stdin where isdir = false order by size desc limit 2", fileList, NewDefaultOptions())
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	expected := [][]context.Value{
+		{context.StringValue("main.go"), context.Int64Value(13)},
+		{context.StringValue("app.log"), context.Int64Value(8)},
+	}
+	AssertMatch(t, expected, rows)
+}
+
+func TestExecuteOnAFileListAndADirectoryReturnsAFileOnce(t *testing.T) {
+	fileList := strings.NewReader("docs/README.md\nmain.go\n")
+	rows, _, err := executeQueryWithFileList(t, "select root, path from stdin, docs", fileList, NewDefaultOptions())
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	expected := [][]context.Value{
+		{context.StringValue("stdin"), context.StringValue("docs/README.md")},
+		{context.StringValue("stdin"), context.StringValue("main.go")},
+		{context.StringValue("docs"), context.StringValue("docs/guide/intro.md")},
+		{context.StringValue("docs"), context.StringValue("docs/guide")},
+	}
+	AssertMatch(t, expected, rows)
+}
+
+func TestExecuteOnAFileListWithAMissingFile(t *testing.T) {
+	_, _, err := executeQueryWithFileList(t, "select name from stdin", strings.NewReader("main.go\ndeleted.go\n"), NewDefaultOptions())
+	if err == nil {
+		t.Fatalf("Expected an error for a missing file in the file list with the fail fast error policy")
+	}
+}
+
+func TestExecuteOnAFileListWithAMissingFileAndSkipErrorPolicy(t *testing.T) {
+	rows, queryExecutor, err := executeQueryWithFileList(
+		t,
+		"select name from stdin",
+		strings.NewReader("main.go\ndeleted.go\n"),
+		NewDefaultOptions().WithErrorPolicy(ErrorPolicySkip),
+	)
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	expected := [][]context.Value{
+		{context.StringValue("main.go")},
+	}
+	AssertMatch(t, expected, rows)
+	if skipped := queryExecutor.SkippedEntries(); len(skipped) != 1 || skipped[0].Path != "deleted.go" {
+		t.Fatalf("Expected deleted.go to be skipped, received %v", skipped)
+	}
+}
+
+func TestExecuteOnAFileListWithAMissingBareNameAndCollectErrorPolicy(t *testing.T) {
+	rows, _, err := executeQueryWithFileList(
+		t,
+		"select path, name from stdin",
+		strings.NewReader("deleted.go\n"),
+		NewDefaultOptions().WithErrorPolicy(ErrorPolicyCollect),
+	)
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	expected := [][]context.Value{
+		{context.StringValue("deleted.go"), context.StringValue("deleted.go")},
+	}
+	AssertMatch(t, expected, rows)
+}
+
+func TestExecuteOnAnEmptyFileList(t *testing.T) {
+	rows, _, err := executeQueryWithFileList(t, "select name from stdin", strings.NewReader(""), NewDefaultOptions())
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	AssertMatch(t, [][]context.Value{}, rows)
+}
@@ -0,0 +1,136 @@
+package executor
+
+import (
+	"goselect/parser/context"
+	"goselect/parser/group"
+	"goselect/parser/having"
+	"goselect/parser/projection"
+)
+
+type Grouping struct {
+	group       *group.Group
+	having      *having.Having
+	projections *projection.Projections
+	functions   *context.AllFunctions
+	groups      map[string]*groupState
+	duplicates  *duplicateFiles
+}
+
+type groupState struct {
+	projections    *projection.Projections
+	having         *having.Having
+	fileAttributes *context.FileAttributes
+	row            *EvaluatingRow
+}
+
+func newGrouping(
+	group *group.Group,
+	having *having.Having,
+	projections *projection.Projections,
+	functions *context.AllFunctions,
+) *Grouping {
+	return &Grouping{
+		group:       group,
+		having:      having,
+		projections: projections,
+		functions:   functions,
+		groups:      make(map[string]*groupState),
+	}
+}
+
+func (grouping *Grouping) collectDuplicates() *Grouping {
+	grouping.duplicates = newDuplicateFiles()
+	return grouping
+}
+
+func (grouping *Grouping) addTo(rows rowCollector, fileAttributes *context.FileAttributes) error {
+	if grouping.duplicates != nil {
+		grouping.duplicates.add(fileAttributes)
+		return nil
+	}
+	return grouping.add(rows, fileAttributes)
+}
+
+/*
+addDuplicates adds the collected files that are duplicates, after all the roots are traversed.
+*/
+func (grouping *Grouping) addDuplicates(rows rowCollector) error {
+	if grouping.duplicates == nil {
+		return nil
+	}
+	for _, fileAttributes := range grouping.duplicates.all() {
+		if rows.isClosed() {
+			return nil
+		}
+		if err := grouping.add(rows, fileAttributes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (grouping *Grouping) add(rows rowCollector, fileAttributes *context.FileAttributes) error {
+	if grouping.group == nil {
+		values, fullyEvaluated, expressions, err := grouping.projections.EvaluateWith(fileAttributes, grouping.functions)
+		if err != nil {
+			return err
+		}
+		rows.addRow(values, fullyEvaluated, expressions)
+		return nil
+	}
+	key, err := grouping.group.KeyFor(fileAttributes, grouping.functions)
+	if err != nil {
+		return err
+	}
+	state, ok := grouping.groups[key]
+	if ok {
+		if _, _, _, err := state.projections.EvaluateWith(fileAttributes, grouping.functions); err != nil {
+			return err
+		}
+		return state.evaluateHaving(fileAttributes, grouping.functions)
+	}
+	state = &groupState{
+		projections:    grouping.projections.CloneWithInitialState(grouping.functions),
+		fileAttributes: fileAttributes,
+	}
+	if grouping.having != nil {
+		state.having = grouping.having.CloneWithInitialState(grouping.functions)
+	}
+	grouping.groups[key] = state
+
+	values, fullyEvaluated, expressions, err := state.projections.EvaluateWith(fileAttributes, grouping.functions)
+	if err != nil {
+		return err
+	}
+	state.row = rows.addRow(values, fullyEvaluated, expressions)
+	return state.evaluateHaving(fileAttributes, grouping.functions)
+}
+
+func (grouping *Grouping) isStateless() bool {
+	return grouping.group == nil && grouping.projections.AggregationCount() == 0 && grouping.duplicates == nil
+}
+
+func (grouping *Grouping) filter(rows *EvaluatingRows) error {
+	if grouping.having == nil {
+		return nil
+	}
+	rejected := make(map[*EvaluatingRow]bool)
+	for _, state := range grouping.groups {
+		passesHaving, err := state.having.FullyEvaluateWith(state.fileAttributes, grouping.functions)
+		if err != nil {
+			return err
+		}
+		if !passesHaving {
+			rejected[state.row] = true
+		}
+	}
+	rows.removeRows(rejected)
+	return nil
+}
+
+func (state *groupState) evaluateHaving(fileAttributes *context.FileAttributes, functions *context.AllFunctions) error {
+	if state.having == nil {
+		return nil
+	}
+	return state.having.EvaluateWith(fileAttributes, functions)
+}
@@ -4,13 +4,37 @@ import (
 	"strings"
 )
 
+type ErrorPolicy int
+
+const (
+	ErrorPolicyFailFast ErrorPolicy = iota
+	ErrorPolicySkip
+	ErrorPolicyCollect
+)
+
+type IgnorePolicy int
+
+const (
+	IgnorePolicyNone IgnorePolicy = iota
+	IgnorePolicySkip
+	IgnorePolicyMark
+)
+
 type Options struct {
 	traverseNestedDirectories    bool
+	followSymbolicLinks          bool
+	traverseArchives             bool
 	directoriesToIgnoreTraversal map[string]bool
+	parallelism                  int
+	errorPolicy                  ErrorPolicy
+	ignorePolicy                 IgnorePolicy
+	minDepth                     int
+	maxDepth                     int
+	duplicates                   bool
 }
 
 func NewDefaultOptions() *Options {
-	return &Options{traverseNestedDirectories: true}
+	return &Options{traverseNestedDirectories: true, parallelism: 1, errorPolicy: ErrorPolicyFailFast}
 }
 
 func (options *Options) EnableNestedTraversal() *Options {
@@ -23,6 +47,26 @@ func (options *Options) DisableNestedTraversal() *Options {
 	return options
 }
 
+func (options *Options) EnableSymbolicLinkTraversal() *Options {
+	options.followSymbolicLinks = true
+	return options
+}
+
+func (options *Options) DisableSymbolicLinkTraversal() *Options {
+	options.followSymbolicLinks = false
+	return options
+}
+
+func (options *Options) EnableArchiveTraversal() *Options {
+	options.traverseArchives = true
+	return options
+}
+
+func (options *Options) DisableArchiveTraversal() *Options {
+	options.traverseArchives = false
+	return options
+}
+
 func (options *Options) DirectoriesToIgnoreTraversal(names []string) *Options {
 	directoriesToIgnore := make(map[string]bool)
 	for _, directory := range names {
@@ -32,6 +76,68 @@ func (options *Options) DirectoriesToIgnoreTraversal(names []string) *Options {
 	return options
 }
 
+func (options *Options) WithParallelism(parallelism int) *Options {
+	if parallelism < 1 {
+		parallelism = 1
+	}
+	options.parallelism = parallelism
+	return options
+}
+
+func (options Options) Parallelism() int {
+	return options.parallelism
+}
+
+func (options *Options) WithMinDepth(minDepth int) *Options {
+	options.minDepth = minDepth
+	return options
+}
+
+func (options *Options) WithMaxDepth(maxDepth int) *Options {
+	options.maxDepth = maxDepth
+	return options
+}
+
+func (options *Options) EnableDuplicates() *Options {
+	options.duplicates = true
+	return options
+}
+
+func (options *Options) DisableDuplicates() *Options {
+	options.duplicates = false
+	return options
+}
+
+func (options Options) ShouldFindDuplicates() bool {
+	return options.duplicates
+}
+
+func (options *Options) WithErrorPolicy(errorPolicy ErrorPolicy) *Options {
+	options.errorPolicy = errorPolicy
+	return options
+}
+
+func (options Options) IsFailFast() bool {
+	return options.errorPolicy == ErrorPolicyFailFast
+}
+
+func (options Options) ShouldCollectErrors() bool {
+	return options.errorPolicy == ErrorPolicyCollect
+}
+
+func (options *Options) WithIgnorePolicy(ignorePolicy IgnorePolicy) *Options {
+	options.ignorePolicy = ignorePolicy
+	return options
+}
+
+func (options Options) ShouldReadIgnoreFiles() bool {
+	return options.ignorePolicy != IgnorePolicyNone
+}
+
+func (options Options) ShouldSkipIgnoredFiles() bool {
+	return options.ignorePolicy == IgnorePolicySkip
+}
+
 func (options Options) IsDirectoryTraversalIgnored(name string) bool {
 	return options.directoriesToIgnoreTraversal[strings.ToLower(name)]
 }
@@ -17,7 +17,7 @@ func newOrdering(order *order.Order) *Ordering {
 func (ordering *Ordering) doOrder(rows *EvaluatingRows) {
 	if ordering.order != nil {
 		sort.SliceStable(rows.rows, func(i, j int) bool {
-			return ordering.isOrdered(rows.AtIndex(i).AllAttributes(), rows.AtIndex(j).AllAttributes())
+			return ordering.isOrdered(rows.AtIndex(i).allAttributesIncludingHidden(), rows.AtIndex(j).allAttributesIncludingHidden())
 		})
 	}
 }
@@ -26,7 +26,7 @@ func (ordering Ordering) isOrdered(first, second []context.Value) bool {
 		firstAttributeValue := first[orderingAttributeRef.ProjectionPosition-1]
 		secondAttributeValue := second[orderingAttributeRef.ProjectionPosition-1]
 
-		comparisonResult := firstAttributeValue.CompareTo(secondAttributeValue)
+		comparisonResult := compareForOrdering(firstAttributeValue, secondAttributeValue)
 		if comparisonResult == 0 {
 			continue
 		}
@@ -34,8 +34,25 @@ func (ordering Ordering) isOrdered(first, second []context.Value) bool {
 		if ordering.order.IsAscendingAt(index) {
 			return comparisonResult < 0
 		} else {
-			return comparisonResult >= 0
+			return comparisonResult > 0
 		}
 	}
 	return false
 }
+
+/*
+compareForOrdering orders a blank date time, like the exif_datetime of an image without EXIF or the birthtime on a file
+system without it, before all the date times, so that the blank values are at the beginning in an ascending order and
+at the end in a descending order. All the other values, including the blank strings, are compared using CompareTo.
+*/
+func compareForOrdering(first, second context.Value) int {
+	switch {
+	case first.IsUndefined() && second.IsUndefined():
+		return context.CompareToEqual
+	case first.IsUndefined() && second.IsDateTime():
+		return context.CompareToLessThan
+	case first.IsDateTime() && second.IsUndefined():
+		return context.CompareToGreaterThan
+	}
+	return first.CompareTo(second)
+}
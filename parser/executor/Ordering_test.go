@@ -7,17 +7,29 @@ import (
 	"goselect/parser/context"
 	"goselect/parser/expression"
 	"goselect/parser/order"
+	"goselect/parser/projection"
 	"goselect/parser/tokenizer"
+	"strings"
 	"testing"
+	"time"
 )
 
+func projectionsWithCount(count int) *projection.Projections {
+	attributes := []string{"name", "size"}
+	projections, _ := projection.NewProjections(
+		tokenizer.NewTokenizer(strings.Join(attributes[0:count], ",")).Tokenize().Iterator(),
+		context.NewContext(context.NewFunctions(), context.NewAttributes()),
+	)
+	return projections
+}
+
 func TestAscendingOrderWithASingleColumn(t *testing.T) {
 	tokens := tokenizer.NewEmptyTokens()
 	tokens.Add(tokenizer.NewToken(tokenizer.Order, "order"))
 	tokens.Add(tokenizer.NewToken(tokenizer.By, "by"))
 	tokens.Add(tokenizer.NewToken(tokenizer.RawString, "1"))
 
-	anOrder, _ := order.NewOrder(tokens.Iterator(), 1)
+	anOrder, _ := order.NewOrder(tokens.Iterator(), projectionsWithCount(1), context.NewContext(context.NewFunctions(), context.NewAttributes()))
 
 	newContext := context.NewContext(context.NewFunctions(), context.NewAttributes())
 	rows := emptyRows(newContext.AllFunctions(), 2)
@@ -43,7 +55,7 @@ func TestAscendingOrderWith2Columns(t *testing.T) {
 	tokens.Add(tokenizer.NewToken(tokenizer.Comma, ","))
 	tokens.Add(tokenizer.NewToken(tokenizer.RawString, "2"))
 
-	anOrder, _ := order.NewOrder(tokens.Iterator(), 2)
+	anOrder, _ := order.NewOrder(tokens.Iterator(), projectionsWithCount(2), context.NewContext(context.NewFunctions(), context.NewAttributes()))
 
 	newContext := context.NewContext(context.NewFunctions(), context.NewAttributes())
 	rows := emptyRows(newContext.AllFunctions(), 3)
@@ -70,7 +82,7 @@ func TestDescendingOrderWithASingleColumn(t *testing.T) {
 	tokens.Add(tokenizer.NewToken(tokenizer.RawString, "1"))
 	tokens.Add(tokenizer.NewToken(tokenizer.RawString, "desc"))
 
-	anOrder, _ := order.NewOrder(tokens.Iterator(), 1)
+	anOrder, _ := order.NewOrder(tokens.Iterator(), projectionsWithCount(1), context.NewContext(context.NewFunctions(), context.NewAttributes()))
 
 	newContext := context.NewContext(context.NewFunctions(), context.NewAttributes())
 	rows := emptyRows(newContext.AllFunctions(), 2)
@@ -98,7 +110,7 @@ func TestDescendingOrderWith2Columns(t *testing.T) {
 	tokens.Add(tokenizer.NewToken(tokenizer.RawString, "2"))
 	tokens.Add(tokenizer.NewToken(tokenizer.RawString, "desc"))
 
-	anOrder, _ := order.NewOrder(tokens.Iterator(), 2)
+	anOrder, _ := order.NewOrder(tokens.Iterator(), projectionsWithCount(2), context.NewContext(context.NewFunctions(), context.NewAttributes()))
 
 	newContext := context.NewContext(context.NewFunctions(), context.NewAttributes())
 	rows := emptyRows(newContext.AllFunctions(), 3)
@@ -128,7 +140,7 @@ func TestMixOrderWith2Columns(t *testing.T) {
 	tokens.Add(tokenizer.NewToken(tokenizer.RawString, "2"))
 	tokens.Add(tokenizer.NewToken(tokenizer.RawString, "desc"))
 
-	anOrder, _ := order.NewOrder(tokens.Iterator(), 2)
+	anOrder, _ := order.NewOrder(tokens.Iterator(), projectionsWithCount(2), context.NewContext(context.NewFunctions(), context.NewAttributes()))
 
 	newContext := context.NewContext(context.NewFunctions(), context.NewAttributes())
 	rows := emptyRows(newContext.AllFunctions(), 3)
@@ -147,3 +159,75 @@ func TestMixOrderWith2Columns(t *testing.T) {
 
 	AssertMatch(t, expected, rows)
 }
+
+func TestOrderWithBlankValues(t *testing.T) {
+	tokens := tokenizer.NewEmptyTokens()
+	tokens.Add(tokenizer.NewToken(tokenizer.Order, "order"))
+	tokens.Add(tokenizer.NewToken(tokenizer.By, "by"))
+	tokens.Add(tokenizer.NewToken(tokenizer.RawString, "2"))
+	tokens.Add(tokenizer.NewToken(tokenizer.RawString, "desc"))
+	tokens.Add(tokenizer.NewToken(tokenizer.Comma, ","))
+	tokens.Add(tokenizer.NewToken(tokenizer.RawString, "1"))
+
+	anOrder, _ := order.NewOrder(tokens.Iterator(), projectionsWithCount(2), context.NewContext(context.NewFunctions(), context.NewAttributes()))
+
+	newContext := context.NewContext(context.NewFunctions(), context.NewAttributes())
+	rows := emptyRows(newContext.AllFunctions(), 4)
+	rows.addRow([]context.Value{context.StringValue("fileC"), context.EmptyValue}, []bool{true, true}, []*expression.Expression{})
+	rows.addRow([]context.Value{context.StringValue("fileB"), context.DateTimeValue(time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC))}, []bool{true, true}, []*expression.Expression{})
+	rows.addRow([]context.Value{context.StringValue("fileA"), context.EmptyValue}, []bool{true, true}, []*expression.Expression{})
+	rows.addRow([]context.Value{context.StringValue("fileD"), context.DateTimeValue(time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC))}, []bool{true, true}, []*expression.Expression{})
+
+	ordering := newOrdering(anOrder)
+	ordering.doOrder(rows)
+
+	expected := []string{"fileD", "fileB", "fileA", "fileC"}
+	for index, name := range expected {
+		if actual := rows.AtIndex(index).allAttributesIncludingHidden()[0].GetAsString(); actual != name {
+			t.Fatalf("Expected row %v to be %v, received %v", index, name, actual)
+		}
+	}
+}
+
+func TestOrderWithBlankStringValues(t *testing.T) {
+	tokens := tokenizer.NewEmptyTokens()
+	tokens.Add(tokenizer.NewToken(tokenizer.Order, "order"))
+	tokens.Add(tokenizer.NewToken(tokenizer.By, "by"))
+	tokens.Add(tokenizer.NewToken(tokenizer.RawString, "2"))
+	tokens.Add(tokenizer.NewToken(tokenizer.RawString, "desc"))
+	tokens.Add(tokenizer.NewToken(tokenizer.Comma, ","))
+	tokens.Add(tokenizer.NewToken(tokenizer.RawString, "1"))
+
+	anOrder, _ := order.NewOrder(tokens.Iterator(), projectionsWithCount(2), context.NewContext(context.NewFunctions(), context.NewAttributes()))
+
+	newContext := context.NewContext(context.NewFunctions(), context.NewAttributes())
+	rows := emptyRows(newContext.AllFunctions(), 4)
+	rows.addRow([]context.Value{context.StringValue("fileC"), context.StringValue("")}, []bool{true, true}, []*expression.Expression{})
+	rows.addRow([]context.Value{context.StringValue("fileB"), context.StringValue("canon")}, []bool{true, true}, []*expression.Expression{})
+	rows.addRow([]context.Value{context.StringValue("fileA"), context.StringValue("")}, []bool{true, true}, []*expression.Expression{})
+	rows.addRow([]context.Value{context.StringValue("fileD"), context.StringValue("nikon")}, []bool{true, true}, []*expression.Expression{})
+
+	ordering := newOrdering(anOrder)
+	ordering.doOrder(rows)
+
+	expected := []string{"fileD", "fileB", "fileA", "fileC"}
+	for index, name := range expected {
+		if actual := rows.AtIndex(index).allAttributesIncludingHidden()[0].GetAsString(); actual != name {
+			t.Fatalf("Expected row %v to be %v, received %v", index, name, actual)
+		}
+	}
+}
+
+func TestCompareForOrderingIsCompareToForTheValuesOtherThanTheBlankDateTimes(t *testing.T) {
+	pairs := [][]context.Value{
+		{context.StringValue(""), context.StringValue("canon")},
+		{context.StringValue("canon"), context.StringValue("")},
+		{context.EmptyValue, context.StringValue("canon")},
+		{context.IntValue(10), context.EmptyValue},
+	}
+	for _, pair := range pairs {
+		if actual, expected := compareForOrdering(pair[0], pair[1]), pair[0].CompareTo(pair[1]); actual != expected {
+			t.Fatalf("Expected the comparison of %v and %v to be %v, received %v", pair[0], pair[1], expected, actual)
+		}
+	}
+}
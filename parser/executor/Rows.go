@@ -5,10 +5,27 @@ import (
 	"goselect/parser/expression"
 )
 
+type Rows interface {
+	RowIterator() RowIterator
+	Count() uint32
+}
+
+type RowIterator interface {
+	HasNext() bool
+	Next() *EvaluatingRow
+}
+
+type rowCollector interface {
+	addRow(attributeValues []context.Value, fullyEvaluated []bool, expressions []*expression.Expression) *EvaluatingRow
+	collectedCount() uint32
+	isClosed() bool
+}
+
 type EvaluatingRows struct {
-	rows      []*EvaluatingRow
-	functions *context.AllFunctions
-	limit     uint32
+	rows             []*EvaluatingRow
+	functions        *context.AllFunctions
+	limit            uint32
+	hiddenAttributes int
 }
 
 type RowsIterator struct {
@@ -18,27 +35,55 @@ type RowsIterator struct {
 }
 
 type EvaluatingRow struct {
-	attributeValues []context.Value
-	fullyEvaluated  []bool
-	expressions     []*expression.Expression
-	functions       *context.AllFunctions
+	attributeValues  []context.Value
+	fullyEvaluated   []bool
+	expressions      []*expression.Expression
+	functions        *context.AllFunctions
+	hiddenAttributes int
 }
 
 func emptyRows(functions *context.AllFunctions, limit uint32) *EvaluatingRows {
-	return &EvaluatingRows{functions: functions, limit: limit}
+	return emptyRowsWithHiddenAttributes(functions, limit, 0)
+}
+
+func emptyRowsWithHiddenAttributes(functions *context.AllFunctions, limit uint32, hiddenAttributes int) *EvaluatingRows {
+	return &EvaluatingRows{functions: functions, limit: limit, hiddenAttributes: hiddenAttributes}
 }
 
 func (rows *EvaluatingRows) addRow(attributeValues []context.Value, fullyEvaluated []bool, expressions []*expression.Expression) *EvaluatingRow {
-	row := &EvaluatingRow{
-		attributeValues: attributeValues,
-		fullyEvaluated:  fullyEvaluated,
-		expressions:     expressions,
-		functions:       rows.functions,
-	}
+	row := rows.newRow(attributeValues, fullyEvaluated, expressions)
 	rows.rows = append(rows.rows, row)
 	return row
 }
 
+func (rows *EvaluatingRows) newRow(attributeValues []context.Value, fullyEvaluated []bool, expressions []*expression.Expression) *EvaluatingRow {
+	return &EvaluatingRow{
+		attributeValues:  attributeValues,
+		fullyEvaluated:   fullyEvaluated,
+		expressions:      expressions,
+		functions:        rows.functions,
+		hiddenAttributes: rows.hiddenAttributes,
+	}
+}
+
+func (rows *EvaluatingRows) removeRows(rejected map[*EvaluatingRow]bool) {
+	var retained []*EvaluatingRow
+	for _, row := range rows.rows {
+		if !rejected[row] {
+			retained = append(retained, row)
+		}
+	}
+	rows.rows = retained
+}
+
+func (rows *EvaluatingRows) collectedCount() uint32 {
+	return uint32(len(rows.rows))
+}
+
+func (rows *EvaluatingRows) isClosed() bool {
+	return false
+}
+
 func (rows EvaluatingRows) Count() uint32 {
 	minOf := func(a, b uint32) uint32 {
 		if a < b {
@@ -49,7 +94,7 @@ func (rows EvaluatingRows) Count() uint32 {
 	return minOf(uint32(len(rows.rows)), rows.limit)
 }
 
-func (rows *EvaluatingRows) RowIterator() *RowsIterator {
+func (rows *EvaluatingRows) RowIterator() RowIterator {
 	return &RowsIterator{currentIndex: 0, limit: rows.limit, rows: rows.rows}
 }
 
@@ -72,6 +117,11 @@ func (rowsIterator *RowsIterator) Next() *EvaluatingRow {
 }
 
 func (row *EvaluatingRow) AllAttributes() []context.Value {
+	values := row.allAttributesIncludingHidden()
+	return values[0 : len(values)-row.hiddenAttributes]
+}
+
+func (row *EvaluatingRow) allAttributesIncludingHidden() []context.Value {
 	var values []context.Value
 	for index, attributeValue := range row.attributeValues {
 		if row.fullyEvaluated[index] {
@@ -90,5 +140,5 @@ func (row *EvaluatingRow) AllAttributes() []context.Value {
 }
 
 func (row EvaluatingRow) TotalAttributes() int {
-	return len(row.attributeValues)
+	return len(row.attributeValues) - row.hiddenAttributes
 }
@@ -150,3 +150,18 @@ func TestEvaluatingRowFullyEvaluationOfAnAttribute(t *testing.T) {
 		)
 	}
 }
+
+func TestEvaluatingRowAllAttributesWithoutHiddenAttributes(t *testing.T) {
+	rows := emptyRowsWithHiddenAttributes(context.NewFunctions(), 1, 1)
+	rows.addRow([]context.Value{context.StringValue("someValue"), context.Int64Value(10)}, []bool{true, true}, []*expression.Expression{})
+
+	attributes := rows.AtIndex(0).AllAttributes()
+	expected := []context.Value{context.StringValue("someValue")}
+
+	if !reflect.DeepEqual(expected, attributes) {
+		t.Fatalf("Expected attributes to be %v, received %v", expected, attributes)
+	}
+	if rows.AtIndex(0).TotalAttributes() != 1 {
+		t.Fatalf("Expected total attributes to be %v, received %v", 1, rows.AtIndex(0).TotalAttributes())
+	}
+}
@@ -1,100 +1,350 @@
 package executor
 
 import (
+	"errors"
+	"fmt"
 	"goselect/parser"
+	"goselect/parser/archive"
 	"goselect/parser/context"
+	"goselect/parser/error/messages"
+	"goselect/parser/filesystem"
+	"goselect/parser/source"
 	"io/fs"
 	"math"
 	"os"
+	"path/filepath"
 	"strings"
 )
 
 const pathSeparator = string(os.PathSeparator)
 
+var readDirectoryFunc = func(fileSystem filesystem.FileSystem, directory string) ([]os.DirEntry, error) {
+	return fileSystem.ReadDir(directory)
+}
+
+func resetReadDirectory() {
+	readDirectoryFunc = func(fileSystem filesystem.FileSystem, directory string) ([]os.DirEntry, error) {
+		return fileSystem.ReadDir(directory)
+	}
+}
+
 type SelectQueryExecutor struct {
-	options *Options
-	query   *parser.SelectQuery
-	context *context.ParsingApplicationContext
+	options  *Options
+	query    *parser.SelectQuery
+	context  *context.ParsingApplicationContext
+	skipped  *skippedEntries
+	emitted  *emittedPaths
+	maxDepth int
 }
 
 func NewSelectQueryExecutor(query *parser.SelectQuery, context *context.ParsingApplicationContext, options *Options) *SelectQueryExecutor {
 	return &SelectQueryExecutor{
-		query:   query,
-		context: context,
-		options: options,
+		query:    query,
+		context:  context,
+		options:  options,
+		skipped:  &skippedEntries{},
+		emitted:  newEmittedPaths(len(query.Source.Roots)),
+		maxDepth: maxDepthOf(query, context, options),
+	}
+}
+
+/*
+maxDepthOf returns the smaller of the maximum depth in the options and the upper bound of 'depth' in the 'where' clause,
+so that the directories deeper than the bound are not traversed at all.
+*/
+func maxDepthOf(query *parser.SelectQuery, applicationContext *context.ParsingApplicationContext, options *Options) int {
+	maxDepth := math.MaxInt32
+	if options.maxDepth > 0 {
+		maxDepth = options.maxDepth
+	}
+	if query.Where == nil {
+		return maxDepth
 	}
+	if bound, ok := query.Where.UpperBoundOf(context.AttributeDepth, applicationContext.AllFunctions()); ok && bound < int64(maxDepth) {
+		maxDepth = int(bound)
+	}
+	return maxDepth
 }
 
 func (selectQueryExecutor *SelectQueryExecutor) Execute() (*EvaluatingRows, error) {
-	source := selectQueryExecutor.query.Source
+	rows, grouping, err := selectQueryExecutor.executeFrom(selectQueryExecutor.maxLimit())
+	if err != nil {
+		return nil, err
+	}
+	if err := grouping.filter(rows); err != nil {
+		return nil, err
+	}
+	newOrdering(selectQueryExecutor.query.Order).doOrder(rows)
+	return rows, nil
+}
 
+func (selectQueryExecutor *SelectQueryExecutor) IsStreamable() bool {
+	return !selectQueryExecutor.query.IsGroupDefined() && selectQueryExecutor.canStopEarly() &&
+		!selectQueryExecutor.options.ShouldFindDuplicates()
+}
+
+func (selectQueryExecutor *SelectQueryExecutor) ExecuteStreaming() (*StreamingRows, error) {
+	if !selectQueryExecutor.IsStreamable() {
+		return nil, errors.New(messages.ErrorMessageStreamingNotSupported)
+	}
+	rows := newStreamingRows(selectQueryExecutor.context.AllFunctions())
+	grouping := newGrouping(
+		nil,
+		nil,
+		selectQueryExecutor.query.Projections,
+		selectQueryExecutor.context.AllFunctions(),
+	)
+	go func() {
+		rows.finish(selectQueryExecutor.executeInto(selectQueryExecutor.maxLimit(), rows, grouping))
+	}()
+	return rows, nil
+}
+
+func (selectQueryExecutor SelectQueryExecutor) maxLimit() uint32 {
 	var limit uint32 = math.MaxInt32
-	if selectQueryExecutor.query.Projections.HasAllAggregates() {
+	if selectQueryExecutor.query.Projections.HasAllAggregates() && !selectQueryExecutor.query.IsGroupDefined() {
 		limit = 1
 	} else {
 		if selectQueryExecutor.query.IsLimitDefined() {
 			limit = selectQueryExecutor.query.Limit.Limit
 		}
 	}
-	rows, err := selectQueryExecutor.executeFrom(source.Directory, limit)
-	if err != nil {
-		return nil, err
+	return limit
+}
+
+func (selectQueryExecutor SelectQueryExecutor) executeFrom(maxLimit uint32) (*EvaluatingRows, *Grouping, error) {
+	rows := emptyRowsWithHiddenAttributes(
+		selectQueryExecutor.context.AllFunctions(),
+		maxLimit,
+		selectQueryExecutor.query.Projections.HiddenCount(),
+	)
+	grouping := newGrouping(
+		selectQueryExecutor.query.Group,
+		selectQueryExecutor.query.Having,
+		selectQueryExecutor.query.Projections,
+		selectQueryExecutor.context.AllFunctions(),
+	)
+	if selectQueryExecutor.options.ShouldFindDuplicates() {
+		grouping.collectDuplicates()
 	}
-	newOrdering(selectQueryExecutor.query.Order).doOrder(rows)
-	return rows, nil
+	if selectQueryExecutor.canSelectTopK() {
+		topKRows := newTopKRows(rows, newOrdering(selectQueryExecutor.query.Order), maxLimit)
+		if err := selectQueryExecutor.executeInto(maxLimit, topKRows, grouping); err != nil {
+			return nil, nil, err
+		}
+		topKRows.finish()
+		return rows, grouping, nil
+	}
+	if err := selectQueryExecutor.executeInto(maxLimit, rows, grouping); err != nil {
+		return nil, nil, err
+	}
+	return rows, grouping, nil
 }
 
-func (selectQueryExecutor SelectQueryExecutor) executeFrom(directory string, maxLimit uint32) (*EvaluatingRows, error) {
-	rows := emptyRows(selectQueryExecutor.context.AllFunctions(), maxLimit)
-	if err := selectQueryExecutor.execute(directory, maxLimit, rows); err != nil {
-		return nil, err
+func (selectQueryExecutor SelectQueryExecutor) executeInto(maxLimit uint32, rows rowCollector, grouping *Grouping) error {
+	for _, root := range selectQueryExecutor.query.Source.Roots {
+		if selectQueryExecutor.haveCollectedEnough(rows, maxLimit) {
+			break
+		}
+		if err := selectQueryExecutor.executeRoot(root, maxLimit, rows, grouping); err != nil {
+			return err
+		}
 	}
-	return rows, nil
+	return grouping.addDuplicates(rows)
+}
+
+func (selectQueryExecutor SelectQueryExecutor) executeRoot(root *source.Root, maxLimit uint32, rows rowCollector, grouping *Grouping) error {
+	if root.FileSystem != nil {
+		selectQueryExecutor.context = selectQueryExecutor.context.ForFileSystem(root.FileSystem)
+	}
+	if root.IsFileList() {
+		return selectQueryExecutor.executeFileList(root, maxLimit, rows, grouping)
+	}
+	if root.IsFile() && archive.IsArchive(root.Path) {
+		return selectQueryExecutor.executeArchive(root, maxLimit, rows, grouping)
+	}
+	if root.IsFile() {
+		return selectQueryExecutor.executeFile(root, rows, grouping)
+	}
+	if selectQueryExecutor.options.Parallelism() > 1 {
+		return newConcurrentTraversal(selectQueryExecutor, grouping, maxLimit).execute(root, rows)
+	}
+	return selectQueryExecutor.execute(root, maxLimit, rows, grouping)
 }
 
-func (selectQueryExecutor SelectQueryExecutor) execute(directory string, maxLimit uint32, rows *EvaluatingRows) error {
-	entries, err := os.ReadDir(directory)
+func (selectQueryExecutor SelectQueryExecutor) executeFile(root *source.Root, rows rowCollector, grouping *Grouping) error {
+	return selectQueryExecutor.executeFileAt(root.Path, root.Directory, root, rows, grouping)
+}
+
+func (selectQueryExecutor SelectQueryExecutor) executeFileAt(
+	path string,
+	directory string,
+	root *source.Root,
+	rows rowCollector,
+	grouping *Grouping,
+) error {
+	level := directoryLevel{depth: 0, root: root}
+	file, err := selectQueryExecutor.context.FileSystem().Lstat(path)
+	if err != nil {
+		if err := selectQueryExecutor.tolerate(path, err); err != nil {
+			return err
+		}
+		if !selectQueryExecutor.options.ShouldCollectErrors() {
+			return nil
+		}
+		fileAttributes := context.ToFileAttributesWithError(directory, filepath.Base(path), err, selectQueryExecutor.context)
+		return selectQueryExecutor.addIfChosen(level.describe(fileAttributes, false, selectQueryExecutor.context), level.depth, rows, grouping)
+	}
+	fileAttributes := context.ToFileAttributes(directory, file, selectQueryExecutor.context)
+	return selectQueryExecutor.addIfChosen(level.describe(fileAttributes, false, selectQueryExecutor.context), level.depth, rows, grouping)
+}
+
+func (selectQueryExecutor *SelectQueryExecutor) SkippedEntries() []SkippedEntry {
+	return selectQueryExecutor.skipped.all()
+}
+
+func (selectQueryExecutor SelectQueryExecutor) execute(root *source.Root, maxLimit uint32, rows rowCollector, grouping *Grouping) error {
+	entries, err := selectQueryExecutor.readDirectory(root.Directory)
 	if err != nil {
 		return err
 	}
+	level, err := selectQueryExecutor.rootLevel(root)
+	if err != nil {
+		return err
+	}
+	return selectQueryExecutor.executeEntries(root.Directory, entries, level, maxLimit, rows, grouping)
+}
+
+func (selectQueryExecutor SelectQueryExecutor) executeEntries(
+	directory string,
+	entries []os.DirEntry,
+	level directoryLevel,
+	maxLimit uint32,
+	rows rowCollector,
+	grouping *Grouping,
+) error {
+	if level.depth > selectQueryExecutor.maxDepth {
+		return nil
+	}
 	for _, entry := range entries {
+		ignored := level.isIgnored(entry.Name(), entry.IsDir())
+		if ignored && selectQueryExecutor.options.ShouldSkipIgnoredFiles() {
+			continue
+		}
 		file, err := entry.Info()
 		if err != nil {
-			return err
+			if err := selectQueryExecutor.tolerate(selectQueryExecutor.childDirectoryName(directory, entry), err); err != nil {
+				return err
+			}
+			if selectQueryExecutor.options.ShouldCollectErrors() && level.matches(entry.Name()) {
+				if selectQueryExecutor.haveCollectedEnough(rows, maxLimit) {
+					return nil
+				}
+				fileAttributes := context.ToFileAttributesWithError(directory, entry.Name(), err, selectQueryExecutor.context)
+				if err := selectQueryExecutor.addIfChosen(level.describe(fileAttributes, ignored, selectQueryExecutor.context), level.depth, rows, grouping); err != nil {
+					return err
+				}
+			}
+			continue
 		}
-		if selectQueryExecutor.shouldTraverseDirectory(file) {
-			newPath := selectQueryExecutor.childDirectoryName(directory, entry)
-			if err := selectQueryExecutor.execute(newPath, maxLimit, rows); err != nil {
+		newPath := selectQueryExecutor.childDirectoryName(directory, entry)
+		traversable, readError := selectQueryExecutor.directoryToTraverse(newPath, file, level)
+		if traversable != nil {
+			childEntries, err := selectQueryExecutor.readDirectory(newPath)
+			if err != nil {
+				if err := selectQueryExecutor.tolerate(newPath, err); err != nil {
+					return err
+				}
+				readError = err
+			} else if err := selectQueryExecutor.executeEntries(newPath, childEntries, level.child(entry.Name(), traversable), maxLimit, rows, grouping); err != nil {
+				return err
+			}
+		} else if selectQueryExecutor.shouldTraverseArchive(file, level) {
+			archiveEntries, err := selectQueryExecutor.archiveEntries(newPath, level.relativePath(entry.Name()), level.depth, level.root)
+			if err != nil {
+				if err := selectQueryExecutor.tolerate(newPath, err); err != nil {
+					return err
+				}
+				readError = err
+			} else if err := selectQueryExecutor.addArchiveEntries(archiveEntries, maxLimit, rows, grouping); err != nil {
 				return err
 			}
 		}
 		if selectQueryExecutor.haveCollectedEnough(rows, maxLimit) {
 			return nil
 		}
-		fileAttributes := context.ToFileAttributes(directory, file, selectQueryExecutor.context)
-		shouldChoose, err := selectQueryExecutor.shouldChoose(fileAttributes)
-		if err != nil {
-			return err
+		if !level.matches(entry.Name()) {
+			continue
 		}
-		if shouldChoose {
-			values, fullyEvaluated, expressions, err := selectQueryExecutor.query.Projections.EvaluateWith(
-				fileAttributes,
-				selectQueryExecutor.context.AllFunctions(),
-			)
-			if err != nil {
-				return err
-			}
-			rows.addRow(values, fullyEvaluated, expressions)
+		fileAttributes := context.ToFileAttributes(directory, file, selectQueryExecutor.context).
+			WithError(readError, selectQueryExecutor.context)
+		if err := selectQueryExecutor.addIfChosen(level.describe(fileAttributes, ignored, selectQueryExecutor.context), level.depth, rows, grouping); err != nil {
+			return err
 		}
 	}
 	return nil
 }
 
-func (selectQueryExecutor SelectQueryExecutor) shouldTraverseDirectory(file fs.FileInfo) bool {
-	return file.IsDir() &&
-		selectQueryExecutor.options.traverseNestedDirectories &&
-		!selectQueryExecutor.options.IsDirectoryTraversalIgnored(file.Name())
+func (selectQueryExecutor SelectQueryExecutor) addIfChosen(
+	fileAttributes *context.FileAttributes,
+	depth int,
+	rows rowCollector,
+	grouping *Grouping,
+) error {
+	shouldChoose, err := selectQueryExecutor.shouldChoose(fileAttributes, depth)
+	if err != nil {
+		return err
+	}
+	if shouldChoose && selectQueryExecutor.emitted.add(fileAttributes.Get(context.AttributeAbsolutePath).GetAsString()) {
+		return grouping.addTo(rows, fileAttributes)
+	}
+	return nil
+}
+
+func (selectQueryExecutor SelectQueryExecutor) tolerate(path string, err error) error {
+	if selectQueryExecutor.options.IsFailFast() {
+		return err
+	}
+	selectQueryExecutor.skipped.add(path, err)
+	return nil
+}
+
+/*
+directoryToTraverse returns the directory to be traversed for an entry, which is either a directory, or a symbolic link
+to a directory if the symbolic links are followed. Broken links are not traversed. A directory that is one of its own
+ancestors is not traversed, and the loop is returned as an error to be attached to the entry.
+*/
+func (selectQueryExecutor SelectQueryExecutor) directoryToTraverse(
+	path string,
+	file fs.FileInfo,
+	level directoryLevel,
+) (*directoryIdentity, error) {
+	if level.depth >= selectQueryExecutor.maxDepth ||
+		!selectQueryExecutor.options.traverseNestedDirectories ||
+		selectQueryExecutor.options.IsDirectoryTraversalIgnored(file.Name()) ||
+		!level.canMatchBelow(file.Name()) {
+		return nil, nil
+	}
+	directory := file
+	if file.Mode()&os.ModeSymlink == os.ModeSymlink && selectQueryExecutor.options.followSymbolicLinks {
+		target, err := selectQueryExecutor.context.FileSystem().Stat(path)
+		if err != nil {
+			return nil, nil
+		}
+		directory = target
+	}
+	if !directory.IsDir() {
+		return nil, nil
+	}
+	identity := identityOf(selectQueryExecutor.context.FileSystem(), path, directory)
+	if level.ancestors.contains(identity) {
+		return nil, fmt.Errorf(messages.ErrorMessageSymbolicLinkLoop, path)
+	}
+	return &identity, nil
+}
 
+func (selectQueryExecutor SelectQueryExecutor) readDirectory(directory string) ([]os.DirEntry, error) {
+	return readDirectoryFunc(selectQueryExecutor.context.FileSystem(), directory)
 }
 
 func (selectQueryExecutor SelectQueryExecutor) childDirectoryName(directory string, entry os.DirEntry) string {
@@ -105,13 +355,27 @@ func (selectQueryExecutor SelectQueryExecutor) childDirectoryName(directory stri
 	return newPath
 }
 
-func (selectQueryExecutor SelectQueryExecutor) haveCollectedEnough(rows *EvaluatingRows, maxLimit uint32) bool {
-	return rows.Count() >= maxLimit &&
-		!selectQueryExecutor.query.IsOrderDefined() &&
+func (selectQueryExecutor SelectQueryExecutor) haveCollectedEnough(rows rowCollector, maxLimit uint32) bool {
+	return rows.isClosed() || (rows.collectedCount() >= maxLimit && selectQueryExecutor.canStopEarly())
+}
+
+func (selectQueryExecutor SelectQueryExecutor) canSelectTopK() bool {
+	return selectQueryExecutor.query.IsOrderDefined() &&
+		selectQueryExecutor.query.IsLimitDefined() &&
+		!selectQueryExecutor.query.IsGroupDefined() &&
+		selectQueryExecutor.query.Projections.AggregationCount() == 0
+}
+
+func (selectQueryExecutor SelectQueryExecutor) canStopEarly() bool {
+	return !selectQueryExecutor.query.IsOrderDefined() &&
+		!selectQueryExecutor.query.IsHavingDefined() &&
 		selectQueryExecutor.query.Projections.AggregationCount() == 0
 }
 
-func (selectQueryExecutor SelectQueryExecutor) shouldChoose(fileAttributes *context.FileAttributes) (bool, error) {
+func (selectQueryExecutor SelectQueryExecutor) shouldChoose(fileAttributes *context.FileAttributes, depth int) (bool, error) {
+	if depth < selectQueryExecutor.options.minDepth {
+		return false, nil
+	}
 	if passesWhere, err := selectQueryExecutor.query.Where.EvaluateWith(fileAttributes, selectQueryExecutor.context.AllFunctions()); err != nil {
 		return false, err
 	} else if passesWhere {
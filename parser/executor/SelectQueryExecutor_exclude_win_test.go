@@ -0,0 +1,102 @@
+//go:build unit && !windows
+// +build unit,!windows
+
+package executor
+
+import (
+	"goselect/parser/context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+/*
+symbolicLinkFarm creates the following tree:
+real/a.log
+real/loop -> ..
+linked -> real
+broken -> missing
+*/
+func symbolicLinkFarm(t *testing.T) string {
+	directory := t.TempDir()
+	if err := os.Mkdir(directory+"/real", 0755); err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	if err := os.WriteFile(directory+"/real/a.log", []byte("log"), 0644); err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	for link, target := range map[string]string{"real/loop": "..", "linked": "real", "broken": "missing"} {
+		if err := os.Symlink(target, directory+"/"+link); err != nil {
+			t.Fatalf("error is %v", err)
+		}
+	}
+	return directory
+}
+
+func TestExecuteWithoutFollowingSymbolicLinks(t *testing.T) {
+	directory := symbolicLinkFarm(t)
+
+	rows, _, err := executeQuery(t, "select path, issymlink, linktarget, isbrokenlink from "+directory+" order by 1", NewDefaultOptions())
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	expected := [][]context.Value{
+		{context.StringValue(directory + "/broken"), context.BooleanValue(true), context.StringValue("missing"), context.BooleanValue(true)},
+		{context.StringValue(directory + "/linked"), context.BooleanValue(true), context.StringValue("real"), context.BooleanValue(false)},
+		{context.StringValue(directory + "/real"), context.BooleanValue(false), context.StringValue(""), context.BooleanValue(false)},
+		{context.StringValue(directory + "/real/a.log"), context.BooleanValue(false), context.StringValue(""), context.BooleanValue(false)},
+		{context.StringValue(directory + "/real/loop"), context.BooleanValue(true), context.StringValue(".."), context.BooleanValue(false)},
+	}
+	AssertMatch(t, expected, rows)
+}
+
+func TestExecuteFollowingSymbolicLinks(t *testing.T) {
+	directory := symbolicLinkFarm(t)
+
+	rows, _, err := executeQuery(t, "select path, error from "+directory+" order by 1", NewDefaultOptions().EnableSymbolicLinkTraversal())
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	expected := [][]context.Value{
+		{context.StringValue(directory + "/broken"), context.StringValue("")},
+		{context.StringValue(directory + "/linked"), context.StringValue("")},
+		{context.StringValue(directory + "/linked/a.log"), context.StringValue("")},
+		{context.StringValue(directory + "/linked/loop"), context.StringValue("file system loop detected, " + directory + "/linked/loop points to one of its parent directories")},
+		{context.StringValue(directory + "/real"), context.StringValue("")},
+		{context.StringValue(directory + "/real/a.log"), context.StringValue("")},
+		{context.StringValue(directory + "/real/loop"), context.StringValue("file system loop detected, " + directory + "/real/loop points to one of its parent directories")},
+	}
+	AssertMatch(t, expected, rows)
+}
+
+func TestExecuteFollowingSymbolicLinksWithParallelism(t *testing.T) {
+	directory := symbolicLinkFarm(t)
+
+	rows, _, err := executeQuery(t, "select path, resolvedpath from "+directory+" where like(path, .*a.log) order by 1", NewDefaultOptions().EnableSymbolicLinkTraversal().WithParallelism(3))
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	resolvedDirectory, _ := filepath.EvalSymlinks(directory)
+	expected := [][]context.Value{
+		{context.StringValue(directory + "/linked/a.log"), context.StringValue(resolvedDirectory + "/real/a.log")},
+		{context.StringValue(directory + "/real/a.log"), context.StringValue(resolvedDirectory + "/real/a.log")},
+	}
+	AssertMatch(t, expected, rows)
+}
+
+func TestAncestorDirectoriesIdentifiedByCanonicalPathContainAParentReachedThroughASymbolicLink(t *testing.T) {
+	directory := symbolicLinkFarm(t)
+
+	var ancestors *ancestorDirectories
+	ancestors = ancestors.with(canonicalIdentityOf(directory)).with(canonicalIdentityOf(directory + "/real"))
+
+	if !ancestors.contains(canonicalIdentityOf(directory + "/real/loop")) {
+		t.Fatalf("Expected the ancestors to contain %v", directory+"/real/loop")
+	}
+	if !ancestors.contains(canonicalIdentityOf(directory + "/linked")) {
+		t.Fatalf("Expected the ancestors to contain %v", directory+"/linked")
+	}
+	if ancestors.contains(canonicalIdentityOf(directory + "/broken")) {
+		t.Fatalf("Expected the ancestors to not contain the broken link %v", directory+"/broken")
+	}
+}
@@ -0,0 +1,879 @@
+//go:build unit
+// +build unit
+
+package executor
+
+import (
+	"archive/zip"
+	"errors"
+	"goselect/parser"
+	"goselect/parser/context"
+	"goselect/parser/filesystem"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func failReadingDirectory(name string) {
+	readDirectoryFunc = func(fileSystem filesystem.FileSystem, directory string) ([]os.DirEntry, error) {
+		if strings.HasSuffix(directory, name) {
+			return nil, errors.New("permission denied")
+		}
+		return fileSystem.ReadDir(directory)
+	}
+}
+
+type deletedDirEntry struct {
+	name string
+}
+
+func (entry deletedDirEntry) Name() string               { return entry.name }
+func (entry deletedDirEntry) IsDir() bool                { return false }
+func (entry deletedDirEntry) Type() fs.FileMode          { return 0 }
+func (entry deletedDirEntry) Info() (fs.FileInfo, error) { return nil, errors.New("no such file") }
+
+func addDeletedEntry(name string) {
+	readDirectoryFunc = func(fileSystem filesystem.FileSystem, directory string) ([]os.DirEntry, error) {
+		entries, err := fileSystem.ReadDir(directory)
+		return append(entries, deletedDirEntry{name: name}), err
+	}
+}
+
+func executeQuery(t *testing.T, query string, options *Options) (*EvaluatingRows, *SelectQueryExecutor, error) {
+	return executeQueryOn(t, query, filesystem.Os(), options)
+}
+
+func executeQueryOn(t *testing.T, query string, fileSystem filesystem.FileSystem, options *Options) (*EvaluatingRows, *SelectQueryExecutor, error) {
+	newContext := context.NewContext(context.NewFunctions(), context.NewAttributes()).WithFileSystem(fileSystem)
+	aParser, err := parser.NewParser(query, newContext)
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	selectQuery, err := aParser.Parse()
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	queryExecutor := NewSelectQueryExecutor(selectQuery, newContext, options)
+	rows, err := queryExecutor.Execute()
+	return rows, queryExecutor, err
+}
+
+func TestExecuteWithFailFastErrorPolicy(t *testing.T) {
+	failReadingDirectory("multi")
+	defer resetReadDirectory()
+
+	_, _, err := executeQuery(t, "select name from ../test/resources/TestResultsWithProjections", NewDefaultOptions())
+	if err == nil {
+		t.Fatalf("Expected an error while reading a directory with fail fast error policy")
+	}
+}
+
+func TestExecuteWithSkipErrorPolicy(t *testing.T) {
+	failReadingDirectory("multi")
+	defer resetReadDirectory()
+
+	rows, queryExecutor, err := executeQuery(
+		t,
+		"select name, error from ../test/resources/TestResultsWithProjections where eq(name, multi)",
+		NewDefaultOptions().WithErrorPolicy(ErrorPolicySkip),
+	)
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	expected := [][]context.Value{
+		{context.StringValue("multi"), context.StringValue("permission denied")},
+	}
+	AssertMatch(t, expected, rows)
+
+	skippedEntries := queryExecutor.SkippedEntries()
+	if len(skippedEntries) != 1 || !strings.HasSuffix(skippedEntries[0].Path, "multi") {
+		t.Fatalf("Expected the directory multi to be skipped, received %v", skippedEntries)
+	}
+}
+
+func TestExecuteWithSkipErrorPolicyAndParallelism(t *testing.T) {
+	failReadingDirectory("multi")
+	defer resetReadDirectory()
+
+	rows, queryExecutor, err := executeQuery(
+		t,
+		"select name from ../test/resources/TestResultsWithProjections where like(error, .+) order by 1",
+		NewDefaultOptions().WithErrorPolicy(ErrorPolicySkip).WithParallelism(4),
+	)
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	expected := [][]context.Value{
+		{context.StringValue("multi")},
+	}
+	AssertMatch(t, expected, rows)
+
+	if len(queryExecutor.SkippedEntries()) != 1 {
+		t.Fatalf("Expected 1 skipped entry, received %v", queryExecutor.SkippedEntries())
+	}
+}
+
+func TestExecuteWithAnErrorPolicyWhenTheSourceDirectoryCanNotBeRead(t *testing.T) {
+	failReadingDirectory("TestResultsWithProjections")
+	defer resetReadDirectory()
+
+	_, _, err := executeQuery(t, "select name from ../test/resources/TestResultsWithProjections", NewDefaultOptions().WithErrorPolicy(ErrorPolicyCollect))
+	if err == nil {
+		t.Fatalf("Expected an error while reading the source directory")
+	}
+}
+
+func TestExecuteWithoutAnErrorHasABlankErrorAttribute(t *testing.T) {
+	rows, _, err := executeQuery(t, "select error from ../test/resources/TestResultsWithProjections/single", NewDefaultOptions())
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	expected := [][]context.Value{
+		{context.StringValue("")},
+	}
+	AssertMatch(t, expected, rows)
+}
+
+func TestExecuteWithSkipErrorPolicyOnADeletedEntry(t *testing.T) {
+	addDeletedEntry("deleted.log")
+	defer resetReadDirectory()
+
+	rows, queryExecutor, err := executeQuery(
+		t,
+		"select name from ../test/resources/TestResultsWithProjections/single",
+		NewDefaultOptions().WithErrorPolicy(ErrorPolicySkip),
+	)
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	expected := [][]context.Value{
+		{context.StringValue("TestResultsWithProjections_A.txt")},
+	}
+	AssertMatch(t, expected, rows)
+
+	if len(queryExecutor.SkippedEntries()) != 1 {
+		t.Fatalf("Expected 1 skipped entry, received %v", queryExecutor.SkippedEntries())
+	}
+}
+
+func TestExecuteWithCollectErrorPolicyOnADeletedEntry(t *testing.T) {
+	addDeletedEntry("deleted.log")
+	defer resetReadDirectory()
+
+	rows, _, err := executeQuery(
+		t,
+		"select name, error from ../test/resources/TestResultsWithProjections/single",
+		NewDefaultOptions().WithErrorPolicy(ErrorPolicyCollect),
+	)
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	expected := [][]context.Value{
+		{context.StringValue("TestResultsWithProjections_A.txt"), context.StringValue("")},
+		{context.StringValue("deleted.log"), context.StringValue("no such file")},
+	}
+	AssertMatch(t, expected, rows)
+}
+
+func TestExecuteWithCollectErrorPolicyAndParallelismOnADeletedEntry(t *testing.T) {
+	addDeletedEntry("deleted.log")
+	defer resetReadDirectory()
+
+	rows, _, err := executeQuery(
+		t,
+		"select name, error from ../test/resources/TestResultsWithProjections/single",
+		NewDefaultOptions().WithErrorPolicy(ErrorPolicyCollect).WithParallelism(2),
+	)
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	expected := [][]context.Value{
+		{context.StringValue("TestResultsWithProjections_A.txt"), context.StringValue("")},
+		{context.StringValue("deleted.log"), context.StringValue("no such file")},
+	}
+	AssertMatch(t, expected, rows)
+}
+
+func recordReadDirectories(readDirectories *[]string) {
+	readDirectoryFunc = func(fileSystem filesystem.FileSystem, directory string) ([]os.DirEntry, error) {
+		*readDirectories = append(*readDirectories, directory)
+		return fileSystem.ReadDir(directory)
+	}
+}
+
+func TestExecuteWithDepth(t *testing.T) {
+	rows, _, err := executeQuery(
+		t,
+		"select name, depth from ../test/resources/TestResultsWithProjections where or(eq(name, single), eq(name, TestResultsWithProjections_A.txt))",
+		NewDefaultOptions(),
+	)
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	expected := [][]context.Value{
+		{context.StringValue("TestResultsWithProjections_A.txt"), context.IntValue(2)},
+		{context.StringValue("single"), context.IntValue(1)},
+	}
+	AssertMatch(t, expected, rows)
+}
+
+func TestExecuteWithMaxDepth(t *testing.T) {
+	var readDirectories []string
+	recordReadDirectories(&readDirectories)
+	defer resetReadDirectory()
+
+	rows, _, err := executeQuery(
+		t,
+		"select name from ../test/resources/TestResultsWithProjections order by 1",
+		NewDefaultOptions().WithMaxDepth(1),
+	)
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	expected := [][]context.Value{
+		{context.StringValue("empty")},
+		{context.StringValue("hidden")},
+		{context.StringValue("multi")},
+		{context.StringValue("single")},
+	}
+	AssertMatch(t, expected, rows)
+	if len(readDirectories) != 1 {
+		t.Fatalf("Expected only the source directory to be read, received %v", readDirectories)
+	}
+}
+
+func TestExecuteWithMinDepth(t *testing.T) {
+	rows, _, err := executeQuery(
+		t,
+		"select name from ../test/resources/TestResultsWithProjections where eq(isdir, true)",
+		NewDefaultOptions().WithMinDepth(2),
+	)
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	if rows.Count() != 0 {
+		t.Fatalf("Expected no directories below the depth 1, received %v", rows.Count())
+	}
+}
+
+func TestExecuteWithMinAndMaxDepthAndParallelism(t *testing.T) {
+	rows, _, err := executeQuery(
+		t,
+		"select name, depth from ../test/resources/TestResultsWithProjections where like(name, .*.txt) order by 1",
+		NewDefaultOptions().WithMinDepth(2).WithMaxDepth(2).WithParallelism(3),
+	)
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	expected := [][]context.Value{
+		{context.StringValue("TestResultsWithProjections_A.txt"), context.IntValue(2)},
+		{context.StringValue("TestResultsWithProjections_C.txt"), context.IntValue(2)},
+		{context.StringValue("TestResultsWithProjections_D.txt"), context.IntValue(2)},
+	}
+	AssertMatch(t, expected, rows)
+}
+
+func TestExecuteWithDepthInWherePrunesTheTraversal(t *testing.T) {
+	var readDirectories []string
+	recordReadDirectories(&readDirectories)
+	defer resetReadDirectory()
+
+	rows, _, err := executeQuery(
+		t,
+		"select name from ../test/resources/TestResultsWithProjections where and(le(depth, 1), eq(isdir, true)) order by 1",
+		NewDefaultOptions(),
+	)
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	expected := [][]context.Value{
+		{context.StringValue("empty")},
+		{context.StringValue("hidden")},
+		{context.StringValue("multi")},
+		{context.StringValue("single")},
+	}
+	AssertMatch(t, expected, rows)
+	if len(readDirectories) != 1 {
+		t.Fatalf("Expected only the source directory to be read, received %v", readDirectories)
+	}
+}
+
+/*
+repositoryWithIgnoreFiles creates the following tree:
+.git/info/exclude (secrets)
+.gitignore (*.log, !keep.log, build/)
+build/output.bin
+keep.log
+app.log
+main.go
+secrets
+*/
+func repositoryWithIgnoreFiles(t *testing.T) string {
+	directory := t.TempDir()
+	files := map[string]string{
+		".git/info/exclude": "secrets\n",
+		".gitignore":        "*.log\n!keep.log\nbuild/\n",
+		"build/output.bin":  "",
+		"keep.log":          "",
+		"app.log":           "",
+		"main.go":           "",
+		"secrets":           "",
+	}
+	for name, content := range files {
+		path := directory + "/" + name
+		if err := os.MkdirAll(path[0:strings.LastIndex(path, "/")], 0755); err != nil {
+			t.Fatalf("error is %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("error is %v", err)
+		}
+	}
+	return directory
+}
+
+func TestExecuteWithIgnorePolicySkip(t *testing.T) {
+	directory := repositoryWithIgnoreFiles(t)
+
+	rows, _, err := executeQuery(t, "select name from "+directory+" order by 1", NewDefaultOptions().WithIgnorePolicy(IgnorePolicySkip))
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	expected := [][]context.Value{
+		{context.StringValue(".gitignore")},
+		{context.StringValue("keep.log")},
+		{context.StringValue("main.go")},
+	}
+	AssertMatch(t, expected, rows)
+}
+
+func TestExecuteWithIgnorePolicySkipAndParallelism(t *testing.T) {
+	directory := repositoryWithIgnoreFiles(t)
+
+	rows, _, err := executeQuery(t, "select name from "+directory+" order by 1", NewDefaultOptions().WithIgnorePolicy(IgnorePolicySkip).WithParallelism(3))
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	expected := [][]context.Value{
+		{context.StringValue(".gitignore")},
+		{context.StringValue("keep.log")},
+		{context.StringValue("main.go")},
+	}
+	AssertMatch(t, expected, rows)
+}
+
+func TestExecuteWithIgnorePolicyMark(t *testing.T) {
+	directory := repositoryWithIgnoreFiles(t)
+
+	rows, _, err := executeQuery(
+		t,
+		"select name from "+directory+" where and(eq(isignored, true), ne(name, .git)) order by 1",
+		NewDefaultOptions().WithIgnorePolicy(IgnorePolicyMark).DirectoriesToIgnoreTraversal([]string{".git"}),
+	)
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	expected := [][]context.Value{
+		{context.StringValue("app.log")},
+		{context.StringValue("build")},
+		{context.StringValue("output.bin")},
+		{context.StringValue("secrets")},
+	}
+	AssertMatch(t, expected, rows)
+}
+
+func TestExecuteWithoutAnIgnorePolicy(t *testing.T) {
+	directory := repositoryWithIgnoreFiles(t)
+
+	rows, _, err := executeQuery(t, "select name from "+directory+" where eq(isignored, true)", NewDefaultOptions())
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	if rows.Count() != 0 {
+		t.Fatalf("Expected no ignored files without an ignore policy, received %v", rows.Count())
+	}
+}
+
+/*
+directoryWithArchive creates the following tree:
+notes.txt
+release.zip (docs/README.md, bin/run.sh)
+*/
+func directoryWithArchive(t *testing.T) string {
+	directory := t.TempDir()
+	if err := os.WriteFile(directory+"/notes.txt", []byte("notes"), 0644); err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	file, err := os.Create(directory + "/release.zip")
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	defer file.Close()
+
+	writer := zip.NewWriter(file)
+	for _, name := range []string{"docs/README.md", "bin/run.sh"} {
+		entry, err := writer.Create(name)
+		if err != nil {
+			t.Fatalf("error is %v", err)
+		}
+		_, _ = entry.Write([]byte(name))
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	return directory
+}
+
+func TestExecuteWithAnArchiveAsTheSource(t *testing.T) {
+	directory := directoryWithArchive(t)
+
+	rows, _, err := executeQuery(t, "select name, size, depth from "+directory+"/release.zip", NewDefaultOptions())
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	expected := [][]context.Value{
+		{context.StringValue("README.md"), context.Int64Value(14), context.IntValue(2)},
+		{context.StringValue("run.sh"), context.Int64Value(10), context.IntValue(2)},
+	}
+	AssertMatch(t, expected, rows)
+}
+
+func TestExecuteWithArchiveTraversal(t *testing.T) {
+	directory := directoryWithArchive(t)
+
+	rows, _, err := executeQuery(t, "select name, depth from "+directory+" order by 1", NewDefaultOptions().EnableArchiveTraversal())
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	expected := [][]context.Value{
+		{context.StringValue("README.md"), context.IntValue(3)},
+		{context.StringValue("notes.txt"), context.IntValue(1)},
+		{context.StringValue("release.zip"), context.IntValue(1)},
+		{context.StringValue("run.sh"), context.IntValue(3)},
+	}
+	AssertMatch(t, expected, rows)
+}
+
+func TestExecuteWithArchiveTraversalAndParallelism(t *testing.T) {
+	directory := directoryWithArchive(t)
+
+	rows, _, err := executeQuery(t, "select name from "+directory+" order by 1", NewDefaultOptions().EnableArchiveTraversal().WithParallelism(3))
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	expected := [][]context.Value{
+		{context.StringValue("README.md")},
+		{context.StringValue("notes.txt")},
+		{context.StringValue("release.zip")},
+		{context.StringValue("run.sh")},
+	}
+	AssertMatch(t, expected, rows)
+}
+
+func TestExecuteWithArchiveTraversalAndMaxDepth(t *testing.T) {
+	directory := directoryWithArchive(t)
+
+	rows, _, err := executeQuery(t, "select name from "+directory+" order by 1", NewDefaultOptions().EnableArchiveTraversal().WithMaxDepth(1))
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	expected := [][]context.Value{
+		{context.StringValue("notes.txt")},
+		{context.StringValue("release.zip")},
+	}
+	AssertMatch(t, expected, rows)
+}
+
+func TestExecuteWithoutArchiveTraversal(t *testing.T) {
+	directory := directoryWithArchive(t)
+
+	rows, _, err := executeQuery(t, "select name from "+directory+" order by 1", NewDefaultOptions())
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	expected := [][]context.Value{
+		{context.StringValue("notes.txt")},
+		{context.StringValue("release.zip")},
+	}
+	AssertMatch(t, expected, rows)
+}
+
+/*
+mapFileSystem creates the following tree in memory:
+.gitignore (*.log)
+main.go
+app.log
+docs/README.md
+docs/guide/intro.md
+*/
+func mapFileSystem() filesystem.FileSystem {
+	return filesystem.FromFS(fstest.MapFS{
+		".gitignore":          {Data: []byte("*.log\n")},
+		"main.go":             {Data: []byte("package main\n"), Mode: 0644},
+		"app.log":             {Data: []byte("started\n"), Mode: 0644},
+		"docs/README.md":      {Data: []byte("readme\n"), Mode: 0644},
+		"docs/guide/intro.md": {Data: []byte("intro\n"), Mode: 0600},
+	})
+}
+
+func TestExecuteOnAnFSFileSystem(t *testing.T) {
+	rows, _, err := executeQueryOn(
+		t,
+		"select name, size, depth, permission, mimetype, username from . where eq(isdir, false) order by 1",
+		mapFileSystem(),
+		NewDefaultOptions(),
+	)
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	text := context.StringValue("text/plain; charset=utf-8")
+	expected := [][]context.Value{
+		{context.StringValue(".gitignore"), context.Int64Value(6), context.IntValue(1), context.StringValue("----------"), text, context.StringValue("")},
+		{context.StringValue("README.md"), context.Int64Value(7), context.IntValue(2), context.StringValue("-rw-r--r--"), text, context.StringValue("")},
+		{context.StringValue("app.log"), context.Int64Value(8), context.IntValue(1), context.StringValue("-rw-r--r--"), text, context.StringValue("")},
+		{context.StringValue("intro.md"), context.Int64Value(6), context.IntValue(3), context.StringValue("-rw-------"), text, context.StringValue("")},
+		{context.StringValue("main.go"), context.Int64Value(13), context.IntValue(1), context.StringValue("-rw-r--r--"), text, context.StringValue("")},
+	}
+	AssertMatch(t, expected, rows)
+}
+
+func TestExecuteOnAnFSFileSystemWithParallelism(t *testing.T) {
+	rows, _, err := executeQueryOn(t, "select absolutepath from . order by 1", mapFileSystem(), NewDefaultOptions().WithParallelism(3))
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	expected := [][]context.Value{
+		{context.StringValue(".gitignore")},
+		{context.StringValue("app.log")},
+		{context.StringValue("docs")},
+		{context.StringValue("docs/README.md")},
+		{context.StringValue("docs/guide")},
+		{context.StringValue("docs/guide/intro.md")},
+		{context.StringValue("main.go")},
+	}
+	AssertMatch(t, expected, rows)
+}
+
+func TestExecuteOnAnFSFileSystemWithAFileAndAGlobPattern(t *testing.T) {
+	rows, _, err := executeQueryOn(t, "select name from ./main.go, docs/**/*.md order by 1", mapFileSystem(), NewDefaultOptions())
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	expected := [][]context.Value{
+		{context.StringValue("README.md")},
+		{context.StringValue("intro.md")},
+		{context.StringValue("main.go")},
+	}
+	AssertMatch(t, expected, rows)
+}
+
+func TestExecuteOnAnFSFileSystemWithIgnorePolicySkip(t *testing.T) {
+	rows, _, err := executeQueryOn(t, "select name from . where eq(isdir, false) order by 1", mapFileSystem(), NewDefaultOptions().WithIgnorePolicy(IgnorePolicySkip))
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	expected := [][]context.Value{
+		{context.StringValue(".gitignore")},
+		{context.StringValue("README.md")},
+		{context.StringValue("intro.md")},
+		{context.StringValue("main.go")},
+	}
+	AssertMatch(t, expected, rows)
+}
+
+func TestExecuteOnAnFSFileSystemWithANonExistingSource(t *testing.T) {
+	newContext := context.NewContext(context.NewFunctions(), context.NewAttributes()).WithFileSystem(mapFileSystem())
+	aParser, err := parser.NewParser("select name from ./src", newContext)
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	if _, err := aParser.Parse(); err == nil {
+		t.Fatalf("Expected an error while parsing a query with a source that does not exist in the file system")
+	}
+}
+
+/*
+gitRepository creates a repository with two commits, the first by 'first' adds README.md and src/main.go, and the
+second by 'second' changes src/main.go and adds src/util.go. It returns the directory and the hashes of the commits.
+*/
+func gitRepository(t *testing.T) (string, []string) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	directory := t.TempDir()
+	runGit := func(author string, arguments ...string) string {
+		command := exec.Command("git", append([]string{"-c", "user.name=" + author, "-c", "user.email=" + author + "@example.com", "-c", "commit.gpgsign=false"}, arguments...)...)
+		command.Dir = directory
+		output, err := command.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v failed with %v, %v", arguments, err, string(output))
+		}
+		return strings.TrimSpace(string(output))
+	}
+	commit := func(author string, files map[string]string) string {
+		for name, content := range files {
+			if err := os.MkdirAll(filepath.Dir(filepath.Join(directory, name)), 0755); err != nil {
+				t.Fatalf("error is %v", err)
+			}
+			if err := os.WriteFile(filepath.Join(directory, name), []byte(content), 0644); err != nil {
+				t.Fatalf("error is %v", err)
+			}
+		}
+		runGit(author, "add", "-A")
+		runGit(author, "commit", "-q", "-m", author)
+		return runGit(author, "rev-parse", "HEAD")
+	}
+	runGit("first", "init", "-q")
+	first := commit("first", map[string]string{"README.md": "# readme\n", "src/main.go": "package main\n"})
+	second := commit("second", map[string]string{"src/main.go": "package main\n\nfunc main() {}\n", "src/util.go": "package main\n"})
+	return directory, []string{first, second}
+}
+
+func TestExecuteAtAGitRevision(t *testing.T) {
+	directory, commits := gitRepository(t)
+	rows, _, err := executeQuery(t, "select path, size, gitmode, lastauthor, lastcommit from git:HEAD:"+directory+" where eq(isdir, false) order by 1", NewDefaultOptions())
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	expected := [][]context.Value{
+		{context.StringValue("./README.md"), context.Int64Value(9), context.StringValue("100644"), context.StringValue("first"), context.StringValue(commits[0])},
+		{context.StringValue("./src/main.go"), context.Int64Value(29), context.StringValue("100644"), context.StringValue("second"), context.StringValue(commits[1])},
+		{context.StringValue("./src/util.go"), context.Int64Value(13), context.StringValue("100644"), context.StringValue("second"), context.StringValue(commits[1])},
+	}
+	AssertMatch(t, expected, rows)
+}
+
+func TestExecuteAtAnOlderGitRevisionWithParallelism(t *testing.T) {
+	directory, commits := gitRepository(t)
+	rows, _, err := executeQuery(t, "select path, size, lastcommit from git:HEAD~1:"+directory+"/src order by 1", NewDefaultOptions().WithParallelism(2))
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	expected := [][]context.Value{
+		{context.StringValue("src/main.go"), context.Int64Value(13), context.StringValue(commits[0])},
+	}
+	AssertMatch(t, expected, rows)
+}
+
+func TestExecuteAtTheSameGitRevisionFromOverlappingRoots(t *testing.T) {
+	directory, _ := gitRepository(t)
+	rows, _, err := executeQuery(t, "select path from git:HEAD:"+directory+"/src, git:HEAD:"+directory+"/**/*.go order by 1", NewDefaultOptions())
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	expected := [][]context.Value{
+		{context.StringValue("src/main.go")},
+		{context.StringValue("src/util.go")},
+	}
+	AssertMatch(t, expected, rows)
+}
+
+func TestExecuteWithContentSearchFunctions(t *testing.T) {
+	rows, _, err := executeQueryOn(
+		t,
+		"select path, countmatches(contents, e), firstmatch(contents, 're.*') from . where grep(contents, '^\\w+$') order by 1",
+		mapFileSystem(),
+		NewDefaultOptions(),
+	)
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	expected := [][]context.Value{
+		{context.StringValue("./app.log"), context.IntValue(1), context.StringValue("")},
+		{context.StringValue("./docs/README.md"), context.IntValue(2), context.StringValue("readme")},
+		{context.StringValue("./docs/guide/intro.md"), context.IntValue(0), context.StringValue("")},
+	}
+	AssertMatch(t, expected, rows)
+}
+
+func TestExecuteWithAContentSearchFunctionOnAFunctionOfTheContents(t *testing.T) {
+	rows, _, err := executeQueryOn(
+		t,
+		"select path from . where grep(upper(contents), '^README') order by 1",
+		mapFileSystem(),
+		NewDefaultOptions(),
+	)
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	expected := [][]context.Value{
+		{context.StringValue("./docs/README.md")},
+	}
+	AssertMatch(t, expected, rows)
+}
+
+func TestExecuteWithTextStatistics(t *testing.T) {
+	rows, _, err := executeQueryOn(
+		t,
+		"select ext, sum(lines), sum(words), sum(chars), max(maxlinelength) from . where eq(isdir, false) group by ext order by 1",
+		mapFileSystem(),
+		NewDefaultOptions(),
+	)
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	expected := [][]context.Value{
+		{context.StringValue(""), context.Float64Value(1), context.Float64Value(1), context.Float64Value(6), context.Int64Value(5)},
+		{context.StringValue(".go"), context.Float64Value(1), context.Float64Value(2), context.Float64Value(13), context.Int64Value(12)},
+		{context.StringValue(".log"), context.Float64Value(1), context.Float64Value(1), context.Float64Value(8), context.Int64Value(7)},
+		{context.StringValue(".md"), context.Float64Value(2), context.Float64Value(2), context.Float64Value(13), context.Int64Value(6)},
+	}
+	AssertMatch(t, expected, rows)
+}
+
+func duplicatesFileSystem() filesystem.FileSystem {
+	return filesystem.FromFS(fstest.MapFS{
+		"a/logo.png":      {Data: []byte("logo"), Mode: 0644},
+		"b/logo-copy.png": {Data: []byte("logo"), Mode: 0644},
+		"b/icon.png":      {Data: []byte("icon"), Mode: 0644},
+		"c/logo.png":      {Data: []byte("logo"), Mode: 0644},
+		"c/readme.md":     {Data: []byte("readme"), Mode: 0644},
+		"c/notes.md":      {Data: []byte("readme"), Mode: 0644},
+		"empty.txt":       {Data: []byte(""), Mode: 0644},
+		"other-empty.txt": {Data: []byte(""), Mode: 0644},
+	})
+}
+
+func TestExecuteWithDuplicates(t *testing.T) {
+	rows, _, err := executeQueryOn(
+		t,
+		"select path, xxhash from . order by 2, 1",
+		duplicatesFileSystem(),
+		NewDefaultOptions().EnableDuplicates(),
+	)
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	logo, readme := context.StringValue("24ac2a88650f73f5"), context.StringValue("6e52cb0f1668cc22")
+	expected := [][]context.Value{
+		{context.StringValue("./a/logo.png"), logo},
+		{context.StringValue("./b/logo-copy.png"), logo},
+		{context.StringValue("./c/logo.png"), logo},
+		{context.StringValue("./c/notes.md"), readme},
+		{context.StringValue("./c/readme.md"), readme},
+	}
+	AssertMatch(t, expected, rows)
+}
+
+func TestExecuteWithDuplicatesGroupedByHash(t *testing.T) {
+	rows, _, err := executeQueryOn(
+		t,
+		"select count(), min(path) from . group by sha256 order by 1 desc",
+		duplicatesFileSystem(),
+		NewDefaultOptions().EnableDuplicates().WithParallelism(4),
+	)
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	expected := [][]context.Value{
+		{context.IntValue(3), context.StringValue("./a/logo.png")},
+		{context.IntValue(2), context.StringValue("./c/notes.md")},
+	}
+	AssertMatch(t, expected, rows)
+}
+
+func TestExecuteWithDuplicatesAndLimit(t *testing.T) {
+	rows, _, err := executeQueryOn(
+		t,
+		"select name from . where eq(ext, .png) limit 1",
+		duplicatesFileSystem(),
+		NewDefaultOptions().EnableDuplicates(),
+	)
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	expected := [][]context.Value{
+		{context.StringValue("logo.png")},
+	}
+	AssertMatch(t, expected, rows)
+}
+
+func TestExecuteStreamingWithParallelismAddsTheRowsBeforeTheTraversalEnds(t *testing.T) {
+	directory := t.TempDir()
+	for _, path := range []string{"a/first.txt", "b/c/last.txt"} {
+		if err := os.MkdirAll(filepath.Join(directory, filepath.Dir(path)), 0755); err != nil {
+			t.Fatalf("error is %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(directory, path), []byte("content"), 0644); err != nil {
+			t.Fatalf("error is %v", err)
+		}
+	}
+	blocked := make(chan struct{})
+	readDirectoryFunc = func(fileSystem filesystem.FileSystem, directory string) ([]os.DirEntry, error) {
+		if filepath.Base(directory) == "c" {
+			<-blocked
+		}
+		return fileSystem.ReadDir(directory)
+	}
+	defer resetReadDirectory()
+
+	newContext := context.NewContext(context.NewFunctions(), context.NewAttributes())
+	aParser, err := parser.NewParser("select name from "+directory, newContext)
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	selectQuery, err := aParser.Parse()
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	rows, err := NewSelectQueryExecutor(selectQuery, newContext, NewDefaultOptions().WithParallelism(3)).ExecuteStreaming()
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	iterator := rows.RowIterator()
+
+	var names []string
+	for len(names) < 2 && iterator.HasNext() {
+		names = append(names, iterator.Next().AllAttributes()[0].GetAsString())
+	}
+	close(blocked)
+	for iterator.HasNext() {
+		names = append(names, iterator.Next().AllAttributes()[0].GetAsString())
+	}
+	expected := []string{"first.txt", "a", "last.txt", "c", "b"}
+
+	if !reflect.DeepEqual(expected, names) {
+		t.Fatalf("Expected names to be %v, received %v", expected, names)
+	}
+}
+
+func TestExecuteWithParallelismWhenTheBufferedEntriesExceedTheLimit(t *testing.T) {
+	directory := t.TempDir()
+	var expected []string
+	for _, child := range []string{"a", "b", "c", "d"} {
+		for _, grandChild := range []string{"e", "f", "g"} {
+			if err := os.MkdirAll(filepath.Join(directory, child, grandChild), 0755); err != nil {
+				t.Fatalf("error is %v", err)
+			}
+			for _, name := range []string{"1.txt", "2.txt", "3.txt"} {
+				if err := os.WriteFile(filepath.Join(directory, child, grandChild, name), []byte("content"), 0644); err != nil {
+					t.Fatalf("error is %v", err)
+				}
+				expected = append(expected, filepath.Join(child, grandChild, name))
+			}
+		}
+	}
+	maxBufferedEntries = 2
+	defer func() {
+		maxBufferedEntries = 16 * 1024
+	}()
+
+	rows, _, err := executeQuery(t, "select path from "+directory+" where like(name, txt)", NewDefaultOptions().WithParallelism(2))
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	var received []string
+	iterator := rows.RowIterator()
+	for iterator.HasNext() {
+		path, _ := filepath.Rel(directory, iterator.Next().AllAttributes()[0].GetAsString())
+		received = append(received, path)
+	}
+
+	if !reflect.DeepEqual(expected, received) {
+		t.Fatalf("Expected paths to be %v, received %v", expected, received)
+	}
+}
@@ -0,0 +1,32 @@
+//go:build unit && windows
+// +build unit,windows
+
+package executor
+
+import (
+	"goselect/parser/context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExecuteFollowingSymbolicLinksDetectsALoopOnWindows(t *testing.T) {
+	directory := t.TempDir()
+	if err := os.Mkdir(filepath.Join(directory, "real"), 0755); err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	if err := os.Symlink(directory, filepath.Join(directory, "real", "loop")); err != nil {
+		t.Skipf("symbolic links can not be created: %v", err)
+	}
+
+	rows, _, err := executeQuery(t, "select path, error from "+directory+" order by 1", NewDefaultOptions().EnableSymbolicLinkTraversal())
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	loop := filepath.Join(directory, "real", "loop")
+	expected := [][]context.Value{
+		{context.StringValue(filepath.Join(directory, "real")), context.StringValue("")},
+		{context.StringValue(loop), context.StringValue("file system loop detected, " + loop + " points to one of its parent directories")},
+	}
+	AssertMatch(t, expected, rows)
+}
@@ -0,0 +1,35 @@
+package executor
+
+import (
+	"sort"
+	"sync"
+)
+
+type SkippedEntry struct {
+	Path string
+	Err  error
+}
+
+type skippedEntries struct {
+	entries []SkippedEntry
+	lock    sync.Mutex
+}
+
+func (skipped *skippedEntries) add(path string, err error) {
+	skipped.lock.Lock()
+	defer skipped.lock.Unlock()
+
+	skipped.entries = append(skipped.entries, SkippedEntry{Path: path, Err: err})
+}
+
+func (skipped *skippedEntries) all() []SkippedEntry {
+	skipped.lock.Lock()
+	defer skipped.lock.Unlock()
+
+	entries := make([]SkippedEntry, len(skipped.entries))
+	copy(entries, skipped.entries)
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Path < entries[j].Path
+	})
+	return entries
+}
@@ -0,0 +1,107 @@
+package executor
+
+import (
+	"goselect/parser/context"
+	"goselect/parser/expression"
+	"sync"
+)
+
+const streamingBufferSize = 128
+
+type StreamingRows struct {
+	rows      chan *EvaluatingRow
+	closed    chan struct{}
+	closeOnce sync.Once
+	functions *context.AllFunctions
+	collected uint32
+	received  uint32
+	err       error
+}
+
+type StreamingRowsIterator struct {
+	streamingRows *StreamingRows
+	next          *EvaluatingRow
+}
+
+func newStreamingRows(functions *context.AllFunctions) *StreamingRows {
+	return &StreamingRows{
+		rows:      make(chan *EvaluatingRow, streamingBufferSize),
+		closed:    make(chan struct{}),
+		functions: functions,
+	}
+}
+
+func (rows *StreamingRows) addRow(attributeValues []context.Value, fullyEvaluated []bool, expressions []*expression.Expression) *EvaluatingRow {
+	row := &EvaluatingRow{
+		attributeValues: attributeValues,
+		fullyEvaluated:  fullyEvaluated,
+		expressions:     expressions,
+		functions:       rows.functions,
+	}
+	select {
+	case rows.rows <- row:
+		rows.collected = rows.collected + 1
+	case <-rows.closed:
+	}
+	return row
+}
+
+func (rows *StreamingRows) collectedCount() uint32 {
+	return rows.collected
+}
+
+func (rows *StreamingRows) isClosed() bool {
+	select {
+	case <-rows.closed:
+		return true
+	default:
+		return false
+	}
+}
+
+func (rows *StreamingRows) finish(err error) {
+	rows.err = err
+	close(rows.rows)
+}
+
+func (rows *StreamingRows) RowIterator() RowIterator {
+	return &StreamingRowsIterator{streamingRows: rows}
+}
+
+func (rows *StreamingRows) Count() uint32 {
+	return rows.received
+}
+
+func (rows *StreamingRows) Err() error {
+	return rows.err
+}
+
+func (rows *StreamingRows) Close() {
+	rows.closeOnce.Do(func() {
+		close(rows.closed)
+	})
+	for range rows.rows {
+	}
+}
+
+func (iterator *StreamingRowsIterator) HasNext() bool {
+	if iterator.next != nil {
+		return true
+	}
+	row, ok := <-iterator.streamingRows.rows
+	if !ok {
+		return false
+	}
+	iterator.next = row
+	return true
+}
+
+func (iterator *StreamingRowsIterator) Next() *EvaluatingRow {
+	if !iterator.HasNext() {
+		return nil
+	}
+	row := iterator.next
+	iterator.next = nil
+	iterator.streamingRows.received = iterator.streamingRows.received + 1
+	return row
+}
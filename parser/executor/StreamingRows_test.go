@@ -0,0 +1,71 @@
+//go:build unit
+// +build unit
+
+package executor
+
+import (
+	"errors"
+	"goselect/parser/context"
+	"goselect/parser/expression"
+	"reflect"
+	"testing"
+)
+
+func TestStreamingRowsIterator(t *testing.T) {
+	rows := newStreamingRows(context.NewFunctions())
+	go func() {
+		rows.addRow([]context.Value{context.StringValue("first")}, []bool{true}, []*expression.Expression{})
+		rows.addRow([]context.Value{context.StringValue("second")}, []bool{true}, []*expression.Expression{})
+		rows.finish(nil)
+	}()
+
+	var attributes []context.Value
+	iterator := rows.RowIterator()
+	for iterator.HasNext() {
+		attributes = append(attributes, iterator.Next().AllAttributes()...)
+	}
+	expected := []context.Value{context.StringValue("first"), context.StringValue("second")}
+
+	if !reflect.DeepEqual(expected, attributes) {
+		t.Fatalf("Expected attributes to be %v, received %v", expected, attributes)
+	}
+	if rows.Count() != 2 {
+		t.Fatalf("Expected count to be %v, received %v", 2, rows.Count())
+	}
+}
+
+func TestStreamingRowsWithAnError(t *testing.T) {
+	rows := newStreamingRows(context.NewFunctions())
+	go func() {
+		rows.finish(errors.New("test error"))
+	}()
+
+	iterator := rows.RowIterator()
+	for iterator.HasNext() {
+		iterator.Next()
+	}
+	if rows.Err() == nil {
+		t.Fatalf("Expected an error after iterating the streaming rows but received none")
+	}
+}
+
+func TestStreamingRowsClose(t *testing.T) {
+	rows := newStreamingRows(context.NewFunctions())
+	go func() {
+		for !rows.isClosed() {
+			rows.addRow([]context.Value{context.StringValue("value")}, []bool{true}, []*expression.Expression{})
+		}
+		rows.finish(nil)
+	}()
+
+	iterator := rows.RowIterator()
+	iterator.Next()
+	rows.Close()
+
+	if !rows.isClosed() {
+		t.Fatalf("Expected streaming rows to be closed")
+	}
+	if rows.Count() != 1 {
+		t.Fatalf("Expected count to be %v, received %v", 1, rows.Count())
+	}
+}
@@ -0,0 +1,106 @@
+package executor
+
+import (
+	"container/heap"
+	"goselect/parser/context"
+	"goselect/parser/expression"
+	"sort"
+)
+
+type TopKRows struct {
+	rows      *EvaluatingRows
+	ordering  *Ordering
+	capacity  uint32
+	sequences []uint64
+	sequence  uint64
+}
+
+func newTopKRows(rows *EvaluatingRows, ordering *Ordering, capacity uint32) *TopKRows {
+	return &TopKRows{
+		rows:     rows,
+		ordering: ordering,
+		capacity: capacity,
+	}
+}
+
+/*
+TopKRows keeps the first K rows as per the ordering in a heap, with the row that would be ordered last at the top.
+A new row replaces the top only if it would be ordered before it. The rows that compare equal are ordered by their
+arrival, so the result is the same as sorting all the rows (stable) and taking the first K.
+*/
+func (topKRows *TopKRows) addRow(attributeValues []context.Value, fullyEvaluated []bool, expressions []*expression.Expression) *EvaluatingRow {
+	row := topKRows.rows.newRow(attributeValues, fullyEvaluated, expressions)
+	topKRows.sequence = topKRows.sequence + 1
+
+	if topKRows.capacity == 0 {
+		return row
+	}
+	if uint32(topKRows.Len()) < topKRows.capacity {
+		heap.Push(topKRows, sequencedRow{row: row, sequence: topKRows.sequence})
+		return row
+	}
+	if topKRows.ordering.isOrdered(row.allAttributesIncludingHidden(), topKRows.rows.rows[0].allAttributesIncludingHidden()) {
+		topKRows.rows.rows[0], topKRows.sequences[0] = row, topKRows.sequence
+		heap.Fix(topKRows, 0)
+	}
+	return row
+}
+
+func (topKRows *TopKRows) collectedCount() uint32 {
+	return uint32(topKRows.Len())
+}
+
+func (topKRows *TopKRows) isClosed() bool {
+	return false
+}
+
+func (topKRows *TopKRows) finish() {
+	sort.Sort(bySequence{topKRows})
+	topKRows.sequences = nil
+}
+
+type sequencedRow struct {
+	row      *EvaluatingRow
+	sequence uint64
+}
+
+func (topKRows *TopKRows) Len() int {
+	return len(topKRows.rows.rows)
+}
+
+func (topKRows *TopKRows) Less(i, j int) bool {
+	first, second := topKRows.rows.rows[i].allAttributesIncludingHidden(), topKRows.rows.rows[j].allAttributesIncludingHidden()
+	if topKRows.ordering.isOrdered(second, first) {
+		return true
+	}
+	if topKRows.ordering.isOrdered(first, second) {
+		return false
+	}
+	return topKRows.sequences[i] > topKRows.sequences[j]
+}
+
+func (topKRows *TopKRows) Swap(i, j int) {
+	topKRows.rows.rows[i], topKRows.rows.rows[j] = topKRows.rows.rows[j], topKRows.rows.rows[i]
+	topKRows.sequences[i], topKRows.sequences[j] = topKRows.sequences[j], topKRows.sequences[i]
+}
+
+func (topKRows *TopKRows) Push(element interface{}) {
+	sequenced := element.(sequencedRow)
+	topKRows.rows.rows = append(topKRows.rows.rows, sequenced.row)
+	topKRows.sequences = append(topKRows.sequences, sequenced.sequence)
+}
+
+func (topKRows *TopKRows) Pop() interface{} {
+	last := topKRows.Len() - 1
+	sequenced := sequencedRow{row: topKRows.rows.rows[last], sequence: topKRows.sequences[last]}
+	topKRows.rows.rows, topKRows.sequences = topKRows.rows.rows[0:last], topKRows.sequences[0:last]
+	return sequenced
+}
+
+type bySequence struct {
+	*TopKRows
+}
+
+func (rows bySequence) Less(i, j int) bool {
+	return rows.sequences[i] < rows.sequences[j]
+}
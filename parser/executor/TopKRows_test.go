@@ -0,0 +1,78 @@
+//go:build unit
+// +build unit
+
+package executor
+
+import (
+	"goselect/parser/context"
+	"goselect/parser/expression"
+	"goselect/parser/order"
+	"goselect/parser/tokenizer"
+	"testing"
+)
+
+func orderByWithDirection(position string, direction string) *order.Order {
+	tokens := tokenizer.NewEmptyTokens()
+	tokens.Add(tokenizer.NewToken(tokenizer.Order, "order"))
+	tokens.Add(tokenizer.NewToken(tokenizer.By, "by"))
+	tokens.Add(tokenizer.NewToken(tokenizer.RawString, position))
+	tokens.Add(tokenizer.NewToken(tokenizer.RawString, direction))
+
+	anOrder, _ := order.NewOrder(tokens.Iterator(), projectionsWithCount(2), context.NewContext(context.NewFunctions(), context.NewAttributes()))
+	return anOrder
+}
+
+func TestTopKRowsInDescendingOrder(t *testing.T) {
+	newContext := context.NewContext(context.NewFunctions(), context.NewAttributes())
+	rows := emptyRows(newContext.AllFunctions(), 2)
+	ordering := newOrdering(orderByWithDirection("2", "desc"))
+
+	topKRows := newTopKRows(rows, ordering, 2)
+	topKRows.addRow([]context.Value{context.StringValue("fileA"), context.Int64Value(10)}, []bool{true, true}, []*expression.Expression{})
+	topKRows.addRow([]context.Value{context.StringValue("fileB"), context.Int64Value(30)}, []bool{true, true}, []*expression.Expression{})
+	topKRows.addRow([]context.Value{context.StringValue("fileC"), context.Int64Value(5)}, []bool{true, true}, []*expression.Expression{})
+	topKRows.addRow([]context.Value{context.StringValue("fileD"), context.Int64Value(20)}, []bool{true, true}, []*expression.Expression{})
+	topKRows.finish()
+	ordering.doOrder(rows)
+
+	expected := [][]context.Value{
+		{context.StringValue("fileB"), context.Int64Value(30)},
+		{context.StringValue("fileD"), context.Int64Value(20)},
+	}
+	AssertMatch(t, expected, rows)
+}
+
+func TestTopKRowsInAscendingOrderRetainsTheArrivalOrderForEqualRows(t *testing.T) {
+	newContext := context.NewContext(context.NewFunctions(), context.NewAttributes())
+	rows := emptyRows(newContext.AllFunctions(), 3)
+	ordering := newOrdering(orderByWithDirection("2", "asc"))
+
+	topKRows := newTopKRows(rows, ordering, 3)
+	topKRows.addRow([]context.Value{context.StringValue("fileA"), context.Int64Value(10)}, []bool{true, true}, []*expression.Expression{})
+	topKRows.addRow([]context.Value{context.StringValue("fileB"), context.Int64Value(5)}, []bool{true, true}, []*expression.Expression{})
+	topKRows.addRow([]context.Value{context.StringValue("fileC"), context.Int64Value(10)}, []bool{true, true}, []*expression.Expression{})
+	topKRows.addRow([]context.Value{context.StringValue("fileD"), context.Int64Value(10)}, []bool{true, true}, []*expression.Expression{})
+	topKRows.addRow([]context.Value{context.StringValue("fileE"), context.Int64Value(1)}, []bool{true, true}, []*expression.Expression{})
+	topKRows.finish()
+	ordering.doOrder(rows)
+
+	expected := [][]context.Value{
+		{context.StringValue("fileE"), context.Int64Value(1)},
+		{context.StringValue("fileB"), context.Int64Value(5)},
+		{context.StringValue("fileA"), context.Int64Value(10)},
+	}
+	AssertMatch(t, expected, rows)
+}
+
+func TestTopKRowsWithZeroCapacity(t *testing.T) {
+	newContext := context.NewContext(context.NewFunctions(), context.NewAttributes())
+	rows := emptyRows(newContext.AllFunctions(), 0)
+
+	topKRows := newTopKRows(rows, newOrdering(orderByWithDirection("2", "asc")), 0)
+	topKRows.addRow([]context.Value{context.StringValue("fileA"), context.Int64Value(10)}, []bool{true, true}, []*expression.Expression{})
+	topKRows.finish()
+
+	if topKRows.collectedCount() != 0 {
+		t.Fatalf("Expected no rows to be collected, received %v", topKRows.collectedCount())
+	}
+}
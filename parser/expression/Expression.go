@@ -2,6 +2,8 @@ package expression
 
 import (
 	"goselect/parser/context"
+	"math"
+	"strings"
 )
 
 type Expressions struct {
@@ -23,11 +25,16 @@ type Expression struct {
 	function  *FunctionInstance
 }
 
+/*
+FunctionInstance is a function in an expression. A content-search function like grep, with the 'contents' attribute as
+the first parameter, searches the contents of the file line by line instead of the value of the attribute.
+*/
 type FunctionInstance struct {
-	name        string
-	args        []*Expression
-	state       *context.FunctionState
-	isAggregate bool
+	name                string
+	args                []*Expression
+	state               *context.FunctionState
+	isAggregate         bool
+	searchesTheContents bool
 }
 
 func FunctionInstanceWith(name string, args []*Expression, state *context.FunctionState, isAggregate bool) *FunctionInstance {
@@ -113,6 +120,14 @@ func (expressions Expressions) DisplayableAttributes() []string {
 	return attributes
 }
 
+func (expressions Expressions) CloneWithInitialState(functions *context.AllFunctions) Expressions {
+	var clones []*Expression
+	for _, expression := range expressions.Expressions {
+		clones = append(clones, expression.cloneWithInitialState(functions))
+	}
+	return Expressions{Expressions: clones}
+}
+
 func (expressions Expressions) AggregationCount() int {
 	count := 0
 	for _, expression := range expressions.Expressions {
@@ -164,7 +179,7 @@ func (expression Expression) Evaluate(
 
 	var values []context.Value
 	isAtleastOneExpressionAnAggregateFunction := false
-	for _, arg := range expression.function.args {
+	for _, arg := range expression.function.parameters() {
 		v, err, isAggregate := arg.Evaluate(fileAttributes, functions)
 		if err != nil {
 			return context.EmptyValue, err, isAggregate
@@ -185,7 +200,7 @@ func (expression Expression) Evaluate(
 		return state.Initial, err, true
 	}
 	if !isAnAggregateFunction && !isAtleastOneExpressionAnAggregateFunction {
-		v, err := functions.Execute(expression.function.name, values...)
+		v, err := expression.function.execute(fileAttributes, functions, values)
 		return v, err, isAtleastOneExpressionAnAggregateFunction
 	}
 	return context.EmptyValue, nil, isAtleastOneExpressionAnAggregateFunction
@@ -199,7 +214,7 @@ func (expression *Expression) FullyEvaluate(functions *context.AllFunctions) (co
 		}
 		var values []context.Value
 		for _, arg := range expression.function.args {
-			if arg.isAFunction() && functions.IsAnAggregateFunction(arg.function.name) {
+			if arg.isAFunction() && (arg.HasAnAggregate() || !arg.hasAnAttribute()) {
 				v, err := execute(arg)
 				if err != nil {
 					return context.EmptyValue, err
@@ -219,19 +234,167 @@ func (expression *Expression) FullyEvaluate(functions *context.AllFunctions) (co
 	return execute(expression)
 }
 
+func (expression *Expression) FullyEvaluateWith(
+	fileAttributes *context.FileAttributes,
+	functions *context.AllFunctions,
+) (context.Value, error) {
+
+	if !expression.isAFunction() {
+		return expression.getNonFunctionValue(fileAttributes), nil
+	}
+	if functions.IsAnAggregateFunction(expression.function.name) {
+		return expression.FullyEvaluate(functions)
+	}
+	var values []context.Value
+	for _, arg := range expression.function.parameters() {
+		v, err := arg.FullyEvaluateWith(fileAttributes, functions)
+		if err != nil {
+			return context.EmptyValue, err
+		}
+		values = append(values, v)
+	}
+	return expression.function.execute(fileAttributes, functions, values)
+}
+
 func (expression Expression) HasAnAggregate() bool {
-	isAnyArgumentAnAggregate := func(fn *FunctionInstance) bool {
-		if fn != nil {
-			for _, arg := range fn.args {
-				if arg.isAFunction() && arg.function.isAggregate {
-					return true
-				}
+	if !expression.isAFunction() {
+		return false
+	}
+	if expression.function.isAggregate {
+		return true
+	}
+	for _, arg := range expression.function.args {
+		if arg.HasAnAggregate() {
+			return true
+		}
+	}
+	return false
+}
+
+func (expression Expression) hasAnAttribute() bool {
+	if expression.eType == TypeAttribute {
+		return true
+	}
+	if expression.isAFunction() {
+		for _, arg := range expression.function.args {
+			if arg.hasAnAttribute() {
+				return true
 			}
-			return false
 		}
-		return false
 	}
-	return (expression.isAFunction() && expression.function.isAggregate) || isAnyArgumentAnAggregate(expression.function)
+	return false
+}
+
+func (expression Expression) cloneWithInitialState(functions *context.AllFunctions) *Expression {
+	if !expression.isAFunction() {
+		return &Expression{eType: expression.eType, value: expression.value, attribute: expression.attribute}
+	}
+	var args []*Expression
+	for _, arg := range expression.function.args {
+		args = append(args, arg.cloneWithInitialState(functions))
+	}
+	var state *context.FunctionState
+	if expression.function.isAggregate {
+		state = functions.InitialState(expression.function.name)
+	}
+	clone := FunctionInstanceWith(expression.function.name, args, state, expression.function.isAggregate)
+	clone.searchesTheContents = expression.function.searchesTheContents
+	return WithFunctionInstance(clone)
+}
+
+/*
+UpperBoundOf returns the largest value of the attribute that can satisfy the expression, if the expression bounds it.
+The bound is derived from the comparisons of the attribute with a numeric literal, that are either the expression
+itself or are combined using 'and'. For example, and(le(depth, 2), eq(ext, .log)) bounds depth to 2.
+*/
+func (expression Expression) UpperBoundOf(attribute string, functions *context.AllFunctions) (int64, bool) {
+	if !expression.isAFunction() {
+		return 0, false
+	}
+	name, args := expression.function.name, expression.function.args
+	if functions.IsAnAliasOf(name, context.FunctionNameAnd) {
+		var bound int64
+		var isBounded bool
+		for _, arg := range args {
+			if argBound, ok := arg.UpperBoundOf(attribute, functions); ok && (!isBounded || argBound < bound) {
+				bound, isBounded = argBound, true
+			}
+		}
+		return bound, isBounded
+	}
+	if len(args) != 2 {
+		return 0, false
+	}
+	if args[0].isTheAttribute(attribute) {
+		if literal, ok := args[1].numericLiteral(); ok {
+			switch {
+			case functions.IsAnAliasOf(name, context.FunctionNameLessThanEqual), functions.IsAnAliasOf(name, context.FunctionNameEqual):
+				return int64(math.Floor(literal)), true
+			case functions.IsAnAliasOf(name, context.FunctionNameLessThan):
+				return int64(math.Ceil(literal)) - 1, true
+			}
+		}
+	}
+	if args[1].isTheAttribute(attribute) {
+		if literal, ok := args[0].numericLiteral(); ok {
+			switch {
+			case functions.IsAnAliasOf(name, context.FunctionNameGreaterThanEqual), functions.IsAnAliasOf(name, context.FunctionNameEqual):
+				return int64(math.Floor(literal)), true
+			case functions.IsAnAliasOf(name, context.FunctionNameGreaterThan):
+				return int64(math.Ceil(literal)) - 1, true
+			}
+		}
+	}
+	return 0, false
+}
+
+func (expression Expression) isTheAttribute(attribute string) bool {
+	return expression.eType == TypeAttribute && strings.EqualFold(expression.attribute, attribute)
+}
+
+func (expression Expression) numericLiteral() (float64, bool) {
+	if expression.eType != TypeValue {
+		return 0, false
+	}
+	literal, err := expression.value.GetNumericAsFloat64()
+	if err != nil {
+		return 0, false
+	}
+	return literal, true
+}
+
+func (expression Expression) IsAFunctionWithTag(ctx *context.ParsingApplicationContext, tag string) bool {
+	return expression.isAFunction() && ctx.FunctionContainsATag(expression.function.name, tag)
+}
+
+func (expression Expression) IsABooleanAttribute(ctx *context.ParsingApplicationContext) bool {
+	return expression.eType == TypeAttribute && ctx.IsABooleanAttribute(expression.attribute)
+}
+
+func (expression Expression) IsAValue() bool {
+	return expression.eType == TypeValue
+}
+
+/*
+parameters returns the parameters that are evaluated before executing the function, which are all the parameters
+except the 'contents' attribute of a content-search function, that is read from the file by the function.
+*/
+func (fn *FunctionInstance) parameters() []*Expression {
+	if fn.searchesTheContents {
+		return fn.args[1:]
+	}
+	return fn.args
+}
+
+func (fn *FunctionInstance) execute(
+	fileAttributes *context.FileAttributes,
+	functions *context.AllFunctions,
+	values []context.Value,
+) (context.Value, error) {
+	if fn.searchesTheContents {
+		return functions.ExecuteOnContents(fn.name, fileAttributes, values...)
+	}
+	return functions.ExecuteOn(fn.name, fileAttributes, values...)
 }
 
 func (expression Expression) isAFunction() bool {
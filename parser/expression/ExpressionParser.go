@@ -0,0 +1,217 @@
+package expression
+
+import (
+	"errors"
+	"fmt"
+	"goselect/parser/context"
+	"goselect/parser/error/messages"
+	"goselect/parser/tokenizer"
+	"strings"
+)
+
+const (
+	precedenceOr         = 1
+	precedenceAnd        = 2
+	precedenceNot        = 3
+	precedenceComparison = 4
+	precedenceAdditive   = 5
+	precedenceMultiply   = 6
+)
+
+type binaryOperator struct {
+	function   string
+	precedence int
+}
+
+var binaryOperators = map[string]binaryOperator{
+	"or":   {function: "or", precedence: precedenceOr},
+	"and":  {function: "and", precedence: precedenceAnd},
+	"=":    {function: "eq", precedence: precedenceComparison},
+	"!=":   {function: "ne", precedence: precedenceComparison},
+	"<":    {function: "lt", precedence: precedenceComparison},
+	"<=":   {function: "le", precedence: precedenceComparison},
+	">":    {function: "gt", precedence: precedenceComparison},
+	">=":   {function: "ge", precedence: precedenceComparison},
+	"like": {function: "like", precedence: precedenceComparison},
+	"+":    {function: "add", precedence: precedenceAdditive},
+	"-":    {function: "sub", precedence: precedenceAdditive},
+	"*":    {function: "mul", precedence: precedenceMultiply},
+	"/":    {function: "div", precedence: precedenceMultiply},
+}
+
+type ExpressionParser struct {
+	tokenIterator            *tokenizer.TokenIterator
+	ctx                      *context.ParsingApplicationContext
+	terminals                []string
+	invalidExpressionMessage string
+	aggregateFunctionMessage string
+}
+
+/*
+expression: disjunction
+disjunction: conjunction [or conjunction]*
+conjunction: negation [and negation]*
+negation: not negation | comparison
+comparison: additive [(= | != | < | <= | > | >= | like) additive]*
+additive: multiplicative [(+ | -) multiplicative]*
+multiplicative: primary [(* | /) primary]*
+primary: (expression) | function(expression, expression ..) | attribute | literal
+
+operators are lowered onto the existing functions, for example: size > 1024 becomes gt(size, 1024)
+arithmetic operators must be separated by a space, for example: size * 2 (size*2 is a single literal)
+*/
+func NewExpressionParser(
+	tokenIterator *tokenizer.TokenIterator,
+	ctx *context.ParsingApplicationContext,
+	terminals []string,
+	invalidExpressionMessage string,
+	aggregateFunctionMessage string,
+) *ExpressionParser {
+	return &ExpressionParser{
+		tokenIterator:            tokenIterator,
+		ctx:                      ctx,
+		terminals:                terminals,
+		invalidExpressionMessage: invalidExpressionMessage,
+		aggregateFunctionMessage: aggregateFunctionMessage,
+	}
+}
+
+func (parser *ExpressionParser) Parse() (*Expression, error) {
+	return parser.expression(precedenceOr)
+}
+
+func (parser *ExpressionParser) IsAtTerminal() bool {
+	if !parser.tokenIterator.HasNext() {
+		return true
+	}
+	token := parser.tokenIterator.Peek()
+	for _, terminal := range parser.terminals {
+		if token.Equals(terminal) {
+			return true
+		}
+	}
+	return false
+}
+
+func (parser *ExpressionParser) expression(minimumPrecedence int) (*Expression, error) {
+	left, err := parser.unary()
+	if err != nil {
+		return nil, err
+	}
+	for !parser.IsAtTerminal() {
+		operator, ok := parser.binaryOperatorAhead()
+		if !ok || operator.precedence < minimumPrecedence {
+			break
+		}
+		parser.tokenIterator.Next()
+		right, err := parser.expression(operator.precedence + 1)
+		if err != nil {
+			return nil, err
+		}
+		left = WithFunctionInstance(FunctionInstanceWith(operator.function, []*Expression{left, right}, nil, false))
+	}
+	return left, nil
+}
+
+func (parser *ExpressionParser) unary() (*Expression, error) {
+	if parser.IsAtTerminal() {
+		return nil, errors.New(parser.invalidExpressionMessage)
+	}
+	if parser.isAnUnaryNotAhead() {
+		parser.tokenIterator.Next()
+		operand, err := parser.expression(precedenceNot)
+		if err != nil {
+			return nil, err
+		}
+		return WithFunctionInstance(FunctionInstanceWith("not", []*Expression{operand}, nil, false)), nil
+	}
+	return parser.primary()
+}
+
+func (parser *ExpressionParser) primary() (*Expression, error) {
+	token := parser.tokenIterator.Next()
+	switch {
+	case token.Equals("("):
+		anExpression, err := parser.expression(precedenceOr)
+		if err != nil {
+			return nil, err
+		}
+		if parser.IsAtTerminal() || !parser.tokenIterator.Peek().Equals(")") {
+			return nil, errors.New(parser.invalidExpressionMessage)
+		}
+		parser.tokenIterator.Next()
+		return anExpression, nil
+	case parser.ctx.IsASupportedAttribute(token.TokenValue):
+		return WithAttribute(token.TokenValue), nil
+	case parser.ctx.IsASupportedFunction(token.TokenValue):
+		fn, err := parser.function(token)
+		if err != nil {
+			return nil, err
+		}
+		return WithFunctionInstance(fn), nil
+	default:
+		value, err := context.ToValue(token)
+		if err != nil {
+			value = context.StringValue(token.TokenValue)
+		}
+		return WithValue(value), nil
+	}
+}
+
+func (parser *ExpressionParser) function(functionNameToken tokenizer.Token) (*FunctionInstance, error) {
+	if parser.IsAtTerminal() || !parser.tokenIterator.Peek().Equals("(") {
+		return nil, fmt.Errorf(messages.ErrorMessageOpeningParenthesesProjection, functionNameToken.TokenValue)
+	}
+	isAggregate := parser.ctx.IsAnAggregateFunction(functionNameToken.TokenValue)
+	if isAggregate && len(parser.aggregateFunctionMessage) > 0 {
+		return nil, errors.New(parser.aggregateFunctionMessage)
+	}
+	parser.tokenIterator.Next()
+
+	var functionArgs []*Expression
+	for {
+		if parser.IsAtTerminal() {
+			return nil, errors.New(parser.invalidExpressionMessage)
+		}
+		token := parser.tokenIterator.Peek()
+		if token.Equals(")") {
+			parser.tokenIterator.Next()
+			break
+		}
+		if token.Equals(",") {
+			parser.tokenIterator.Next()
+			continue
+		}
+		arg, err := parser.expression(precedenceOr)
+		if err != nil {
+			return nil, err
+		}
+		functionArgs = append(functionArgs, arg)
+	}
+
+	var state *context.FunctionState
+	if isAggregate {
+		state = parser.ctx.InitialState(functionNameToken.TokenValue)
+	}
+	fn := FunctionInstanceWith(functionNameToken.TokenValue, functionArgs, state, isAggregate)
+	fn.searchesTheContents = len(functionArgs) > 0 && functionArgs[0].eType == TypeAttribute &&
+		parser.ctx.SearchesTheContentsOf(functionNameToken.TokenValue, functionArgs[0].attribute)
+	return fn, nil
+}
+
+func (parser *ExpressionParser) binaryOperatorAhead() (binaryOperator, bool) {
+	token := parser.tokenIterator.Peek()
+	if !token.IsAnOperator() && token.TokenType != tokenizer.RawString {
+		return binaryOperator{}, false
+	}
+	operator, ok := binaryOperators[strings.ToLower(token.TokenValue)]
+	return operator, ok
+}
+
+func (parser *ExpressionParser) isAnUnaryNotAhead() bool {
+	if !parser.tokenIterator.Peek().Equals("not") {
+		return false
+	}
+	next, ok := parser.tokenIterator.PeekAt(1)
+	return !ok || !next.Equals("(")
+}
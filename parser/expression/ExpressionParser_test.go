@@ -0,0 +1,192 @@
+//go:build unit
+// +build unit
+
+package expression
+
+import (
+	"goselect/parser/context"
+	"goselect/parser/tokenizer"
+	"reflect"
+	"testing"
+)
+
+func parse(rawExpression string) (*Expression, error) {
+	ctx := context.NewContext(context.NewFunctions(), context.NewAttributes())
+	iterator := tokenizer.NewTokenizer(rawExpression).Tokenize().Iterator()
+	return NewExpressionParser(iterator, ctx, []string{"from"}, "invalid", "").Parse()
+}
+
+func displayOf(anExpression *Expression) []string {
+	return Expressions{Expressions: []*Expression{anExpression}}.DisplayableAttributes()
+}
+
+func TestParsesAnAttribute(t *testing.T) {
+	anExpression, _ := parse("name")
+	expected := []string{"name"}
+
+	if !reflect.DeepEqual(expected, displayOf(anExpression)) {
+		t.Fatalf("Expected expression to be %v, received %v", expected, displayOf(anExpression))
+	}
+}
+
+func TestParsesAFunctionWithoutCommasBetweenArguments(t *testing.T) {
+	anExpression, _ := parse("parseSize(1 Kb)")
+	expected := []string{"parseSize(1,Kb)"}
+
+	if !reflect.DeepEqual(expected, displayOf(anExpression)) {
+		t.Fatalf("Expected expression to be %v, received %v", expected, displayOf(anExpression))
+	}
+}
+
+func TestParsesAContentSearchFunctionOnTheContents(t *testing.T) {
+	anExpression, _ := parse("grep(contents, TODO)")
+	if !anExpression.function.searchesTheContents {
+		t.Fatalf("Expected grep with the contents attribute to search the contents of the file")
+	}
+	expected := []string{"grep(contents,TODO)"}
+	if !reflect.DeepEqual(expected, displayOf(anExpression)) {
+		t.Fatalf("Expected expression to be %v, received %v", expected, displayOf(anExpression))
+	}
+}
+
+func TestParsesAContentSearchFunctionOnAValue(t *testing.T) {
+	anExpression, _ := parse("grep(lower(contents), todo)")
+	if anExpression.function.searchesTheContents {
+		t.Fatalf("Expected grep with a function of the contents attribute to search the value of the function")
+	}
+}
+
+func TestParsesAnArithmeticExpressionWithPrecedence(t *testing.T) {
+	anExpression, _ := parse("size + 2 * 3")
+	expected := []string{"add(size,mul(2,3))"}
+
+	if !reflect.DeepEqual(expected, displayOf(anExpression)) {
+		t.Fatalf("Expected expression to be %v, received %v", expected, displayOf(anExpression))
+	}
+}
+
+func TestParsesAnArithmeticExpressionWithLeftAssociativity(t *testing.T) {
+	anExpression, _ := parse("10 - 2 - 3")
+	expected := []string{"sub(sub(10,2),3)"}
+
+	if !reflect.DeepEqual(expected, displayOf(anExpression)) {
+		t.Fatalf("Expected expression to be %v, received %v", expected, displayOf(anExpression))
+	}
+}
+
+func TestParsesAnArithmeticExpressionWithParentheses(t *testing.T) {
+	anExpression, _ := parse("(size + 2) * 3")
+	expected := []string{"mul(add(size,2),3)"}
+
+	if !reflect.DeepEqual(expected, displayOf(anExpression)) {
+		t.Fatalf("Expected expression to be %v, received %v", expected, displayOf(anExpression))
+	}
+}
+
+func TestParsesComparisonOperators(t *testing.T) {
+	expectedByExpression := map[string]string{
+		"size = 10":  "eq(size,10)",
+		"size != 10": "ne(size,10)",
+		"size <> 10": "ne(size,10)",
+		"size < 10":  "lt(size,10)",
+		"size <= 10": "le(size,10)",
+		"size > 10":  "gt(size,10)",
+		"size >= 10": "ge(size,10)",
+		"size>=10":   "ge(size,10)",
+	}
+	for rawExpression, expected := range expectedByExpression {
+		anExpression, _ := parse(rawExpression)
+		if !reflect.DeepEqual([]string{expected}, displayOf(anExpression)) {
+			t.Fatalf("Expected expression %v to be %v, received %v", rawExpression, expected, displayOf(anExpression))
+		}
+	}
+}
+
+func TestParsesLogicalOperatorsWithPrecedence(t *testing.T) {
+	anExpression, _ := parse("isdir or size > 10 and not ext = .log")
+	expected := []string{"or(isdir,and(gt(size,10),not(eq(ext,.log))))"}
+
+	if !reflect.DeepEqual(expected, displayOf(anExpression)) {
+		t.Fatalf("Expected expression to be %v, received %v", expected, displayOf(anExpression))
+	}
+}
+
+func TestParsesLike(t *testing.T) {
+	anExpression, _ := parse("name like '.*log' and like(ext, log)")
+	expected := []string{"and(like(name,.*log),like(ext,log))"}
+
+	if !reflect.DeepEqual(expected, displayOf(anExpression)) {
+		t.Fatalf("Expected expression to be %v, received %v", expected, displayOf(anExpression))
+	}
+}
+
+func TestParsesOperatorsInsideFunctionArguments(t *testing.T) {
+	anExpression, _ := parse("fmtsize(size * 2), 3")
+	expected := []string{"fmtsize(mul(size,2))"}
+
+	if !reflect.DeepEqual(expected, displayOf(anExpression)) {
+		t.Fatalf("Expected expression to be %v, received %v", expected, displayOf(anExpression))
+	}
+}
+
+func TestParsesAnOperatorAsALiteralInsideFunctionArguments(t *testing.T) {
+	anExpression, _ := parse("concat(name, -, ext)")
+	expected := []string{"concat(name,-,ext)"}
+
+	if !reflect.DeepEqual(expected, displayOf(anExpression)) {
+		t.Fatalf("Expected expression to be %v, received %v", expected, displayOf(anExpression))
+	}
+}
+
+func TestParsesAnAggregateFunctionWithInitialState(t *testing.T) {
+	anExpression, _ := parse("sum(size) / count()")
+
+	if !anExpression.HasAnAggregate() {
+		t.Fatalf("Expected expression to contain an aggregate function")
+	}
+	if anExpression.function.args[0].function.state == nil {
+		t.Fatalf("Expected aggregate function to have an initial state")
+	}
+}
+
+func TestParsesUptoTheTerminal(t *testing.T) {
+	ctx := context.NewContext(context.NewFunctions(), context.NewAttributes())
+	iterator := tokenizer.NewTokenizer("size * 2 from .").Tokenize().Iterator()
+	parser := NewExpressionParser(iterator, ctx, []string{"from"}, "invalid", "")
+
+	_, _ = parser.Parse()
+	if !parser.IsAtTerminal() {
+		t.Fatalf("Expected parser to stop at the terminal, received %v", iterator.Peek().TokenValue)
+	}
+}
+
+func TestThrowsAnErrorGivenAnAggregateFunctionWhenNotAllowed(t *testing.T) {
+	ctx := context.NewContext(context.NewFunctions(), context.NewAttributes())
+	iterator := tokenizer.NewTokenizer("count() > 1").Tokenize().Iterator()
+
+	_, err := NewExpressionParser(iterator, ctx, nil, "invalid", "aggregate").Parse()
+	if err == nil || err.Error() != "aggregate" {
+		t.Fatalf("Expected an aggregate error, received %v", err)
+	}
+}
+
+func TestThrowsAnErrorGivenMissingClosingParentheses(t *testing.T) {
+	_, err := parse("(size + 2 from .")
+	if err == nil {
+		t.Fatalf("Expected an error given missing closing parentheses")
+	}
+}
+
+func TestThrowsAnErrorGivenAMissingOperand(t *testing.T) {
+	_, err := parse("size > from .")
+	if err == nil {
+		t.Fatalf("Expected an error given a missing operand")
+	}
+}
+
+func TestThrowsAnErrorGivenAFunctionWithoutOpeningParentheses(t *testing.T) {
+	_, err := parse("lower + 1")
+	if err == nil {
+		t.Fatalf("Expected an error given a function without opening parentheses")
+	}
+}
@@ -695,6 +695,74 @@ func TestExpressionAtAnInvalidIndex(t *testing.T) {
 	}
 }
 
+func TestExpressionsCloneWithInitialStateDoesNotShareTheAggregateState(t *testing.T) {
+	functions := context.NewFunctions()
+	expression := WithFunctionInstance(&FunctionInstance{
+		name:        "Count",
+		args:        []*Expression{},
+		state:       functions.InitialState("Count"),
+		isAggregate: true,
+	})
+	expressions := Expressions{Expressions: []*Expression{expression}}
+	_, _, _, _ = expressions.EvaluateWith(nil, functions)
+	_, _, _, _ = expressions.EvaluateWith(nil, functions)
+
+	clone := expressions.CloneWithInitialState(functions)
+	_, _, _, _ = clone.EvaluateWith(nil, functions)
+
+	value, _ := clone.ExpressionAt(0).FullyEvaluate(functions)
+	if value.GetAsString() != "1" {
+		t.Fatalf("Expected the cloned expression to return %v, received %v", "1", value.GetAsString())
+	}
+	value, _ = expressions.ExpressionAt(0).FullyEvaluate(functions)
+	if value.GetAsString() != "2" {
+		t.Fatalf("Expected the original expression to return %v, received %v", "2", value.GetAsString())
+	}
+}
+
+func TestExpressionsCloneWithInitialStateRetainsTheDisplayableAttributes(t *testing.T) {
+	functions := context.NewFunctions()
+	expressions := Expressions{Expressions: []*Expression{
+		WithAttribute("name"),
+		WithFunctionInstance(&FunctionInstance{
+			name:        "sum",
+			args:        []*Expression{WithAttribute("size")},
+			state:       functions.InitialState("sum"),
+			isAggregate: true,
+		}),
+	}}
+	clone := expressions.CloneWithInitialState(functions)
+	expected := []string{"name", "sum(size)"}
+
+	if !reflect.DeepEqual(expected, clone.DisplayableAttributes()) {
+		t.Fatalf("Expected attributes to be %v, received %v", expected, clone.DisplayableAttributes())
+	}
+}
+
+func TestExpressionFullyEvaluateWithAnAggregateFunctionInsideAScalarFunction(t *testing.T) {
+	functions := context.NewFunctions()
+	expression := WithFunctionInstance(&FunctionInstance{
+		name: "gt",
+		args: []*Expression{
+			WithFunctionInstance(&FunctionInstance{
+				name:        "count",
+				args:        []*Expression{},
+				state:       functions.InitialState("count"),
+				isAggregate: true,
+			}),
+			WithValue(context.Int64Value(1)),
+		},
+	})
+	expressions := Expressions{Expressions: []*Expression{expression}}
+	_, _, _, _ = expressions.EvaluateWith(nil, functions)
+	_, _, _, _ = expressions.EvaluateWith(nil, functions)
+
+	value, _ := expression.FullyEvaluateWith(nil, functions)
+	if value.GetAsString() != "Y" {
+		t.Fatalf("Expected the expression to return %v, received %v", "Y", value.GetAsString())
+	}
+}
+
 func simulate2RowExecution(expressions Expressions, functions *context.AllFunctions) ([]*Expression, []*Expression) {
 	_, _, allExpressions1, _ := expressions.EvaluateWith(nil, functions)
 	_, _, allExpressions2, _ := expressions.EvaluateWith(nil, functions)
@@ -0,0 +1,60 @@
+package filesystem
+
+import (
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+type fsFileSystem struct {
+	fileSystem fs.FS
+}
+
+/*
+FromFS adapts an fs.FS to a FileSystem.
+A name is cleaned and made relative to the root of the fs.FS, so '.', './docs' and '/docs' are all valid names.
+fs.FS has no symbolic links, so Lstat is the same as Stat, ReadLink fails and EvalSymlinks returns the cleaned name.
+*/
+func FromFS(fileSystem fs.FS) FileSystem {
+	return fsFileSystem{fileSystem: fileSystem}
+}
+
+func (fsys fsFileSystem) Open(name string) (fs.File, error) {
+	return fsys.fileSystem.Open(pathOf(name))
+}
+
+func (fsys fsFileSystem) ReadDir(name string) ([]fs.DirEntry, error) {
+	return fs.ReadDir(fsys.fileSystem, pathOf(name))
+}
+
+func (fsys fsFileSystem) Stat(name string) (fs.FileInfo, error) {
+	return fs.Stat(fsys.fileSystem, pathOf(name))
+}
+
+func (fsys fsFileSystem) Lstat(name string) (fs.FileInfo, error) {
+	return fsys.Stat(name)
+}
+
+func (fsys fsFileSystem) ReadLink(name string) (string, error) {
+	return "", &fs.PathError{Op: "readlink", Path: name, Err: fs.ErrInvalid}
+}
+
+func (fsys fsFileSystem) EvalSymlinks(name string) (string, error) {
+	if _, err := fsys.Stat(name); err != nil {
+		return "", err
+	}
+	return pathOf(name), nil
+}
+
+func (fsys fsFileSystem) Abs(name string) (string, error) {
+	return pathOf(name), nil
+}
+
+func pathOf(name string) string {
+	cleaned := strings.TrimPrefix(path.Clean(filepath.ToSlash(name)), "/")
+	if cleaned == "" {
+		return "."
+	}
+	return cleaned
+}
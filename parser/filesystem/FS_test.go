@@ -0,0 +1,101 @@
+//go:build unit
+// +build unit
+
+package filesystem
+
+import (
+	"io"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+)
+
+var mapFS = fstest.MapFS{
+	"docs/README.md": {Data: []byte("readme")},
+}
+
+func TestStatWithDifferentFormsOfAName(t *testing.T) {
+	fileSystem := FromFS(mapFS)
+	for _, name := range []string{"docs/README.md", "./docs/README.md", "/docs/README.md", "docs/../docs/README.md"} {
+		file, err := fileSystem.Stat(name)
+		if err != nil {
+			t.Fatalf("error is %v for %v", err, name)
+		}
+		if file.Size() != 6 {
+			t.Fatalf("Expected size of %v to be %v, received %v", name, 6, file.Size())
+		}
+	}
+}
+
+func TestReadDirOfTheRoot(t *testing.T) {
+	fileSystem := FromFS(mapFS)
+	for _, name := range []string{".", "./", "/"} {
+		entries, err := fileSystem.ReadDir(name)
+		if err != nil {
+			t.Fatalf("error is %v for %v", err, name)
+		}
+		if len(entries) != 1 || entries[0].Name() != "docs" {
+			t.Fatalf("Expected the root to contain docs, received %v entries", len(entries))
+		}
+	}
+}
+
+func TestAbsIsTheCleanedName(t *testing.T) {
+	absolutePath, _ := FromFS(mapFS).Abs("./docs/README.md")
+	if absolutePath != "docs/README.md" {
+		t.Fatalf("Expected absolute path to be %v, received %v", "docs/README.md", absolutePath)
+	}
+}
+
+func TestSymbolicLinksAreNotSupported(t *testing.T) {
+	fileSystem := FromFS(mapFS)
+	if _, err := fileSystem.ReadLink("docs/README.md"); err == nil {
+		t.Fatalf("Expected an error while reading a link in an fs.FS")
+	}
+	resolvedPath, err := fileSystem.EvalSymlinks("./docs/README.md")
+	if err != nil || resolvedPath != "docs/README.md" {
+		t.Fatalf("Expected resolved path to be %v, received %v, %v", "docs/README.md", resolvedPath, err)
+	}
+	if _, err := fileSystem.EvalSymlinks("non-existent"); err == nil {
+		t.Fatalf("Expected an error while resolving a non-existent file")
+	}
+}
+
+func TestReadFile(t *testing.T) {
+	content, err := ReadFile(FromFS(mapFS), "./docs/README.md")
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	if string(content) != "readme" {
+		t.Fatalf("Expected content to be %v, received %v", "readme", string(content))
+	}
+}
+
+type sequentialFile struct {
+	file fs.File
+}
+
+func (file sequentialFile) Stat() (fs.FileInfo, error)      { return file.file.Stat() }
+func (file sequentialFile) Read(buffer []byte) (int, error) { return file.file.Read(buffer) }
+func (file sequentialFile) Close() error                    { return file.file.Close() }
+
+func TestHeaderReaderAtOfAFileThatCanNotBeReadAtAnOffset(t *testing.T) {
+	file, err := FromFS(mapFS).Open("docs/README.md")
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	readerAt, size, err := HeaderReaderAtOf(sequentialFile{file: file}, 4)
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	if size != 6 {
+		t.Fatalf("Expected size to be %v, received %v", 6, size)
+	}
+	header := make([]byte, 4)
+	if _, err := readerAt.ReadAt(header, 0); err != nil || string(header) != "read" {
+		t.Fatalf("Expected the header to be %v, received %v, %v", "read", string(header), err)
+	}
+	if _, err := readerAt.ReadAt(make([]byte, 2), 4); err != io.EOF {
+		t.Fatalf("Expected a read beyond the limit to return %v, received %v", io.EOF, err)
+	}
+}
@@ -0,0 +1,65 @@
+package filesystem
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+)
+
+/*
+FileSystem is the file system that the queries are executed against.
+The names are the paths as they appear in the queries. Os resolves them on the file system of the operating system,
+and FromFS resolves them relative to the root of an fs.FS, like embed.FS or fstest.MapFS.
+*/
+type FileSystem interface {
+	fs.FS
+	ReadDir(name string) ([]fs.DirEntry, error)
+	Stat(name string) (fs.FileInfo, error)
+	Lstat(name string) (fs.FileInfo, error)
+	ReadLink(name string) (string, error)
+	EvalSymlinks(name string) (string, error)
+	Abs(name string) (string, error)
+}
+
+func ReadFile(fileSystem FileSystem, name string) ([]byte, error) {
+	return fs.ReadFile(fileSystem, name)
+}
+
+/*
+ReaderAtOf returns a file as an io.ReaderAt along with its size, for the formats that are read at offsets, like zip.
+A file that can not be read at an offset, like a file of some fs.FS implementations, is read into memory.
+*/
+func ReaderAtOf(file fs.File) (io.ReaderAt, int64, error) {
+	info, err := file.Stat()
+	if err != nil {
+		return nil, 0, err
+	}
+	if readerAt, ok := file.(io.ReaderAt); ok {
+		return readerAt, info.Size(), nil
+	}
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return nil, 0, err
+	}
+	return bytes.NewReader(content), int64(len(content)), nil
+}
+
+/*
+HeaderReaderAtOf returns a file as an io.ReaderAt along with its size, like ReaderAtOf, for the formats whose metadata
+is in the headers, like the media files. A file that can not be read at an offset is read into memory only up to the
+limit, and a read beyond the limit fails like a read beyond the end of a truncated file.
+*/
+func HeaderReaderAtOf(file fs.File, limit int64) (io.ReaderAt, int64, error) {
+	info, err := file.Stat()
+	if err != nil {
+		return nil, 0, err
+	}
+	if readerAt, ok := file.(io.ReaderAt); ok {
+		return readerAt, info.Size(), nil
+	}
+	content, err := io.ReadAll(io.LimitReader(file, limit))
+	if err != nil {
+		return nil, 0, err
+	}
+	return bytes.NewReader(content), info.Size(), nil
+}
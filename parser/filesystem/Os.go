@@ -0,0 +1,54 @@
+package filesystem
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+type osFileSystem struct{}
+
+func Os() FileSystem {
+	return osFileSystem{}
+}
+
+/*
+IsOs returns true if the file system is the file system of the operating system, where the names are the paths on
+the disk, for example, to read the extended attributes of a file.
+*/
+func IsOs(fileSystem FileSystem) bool {
+	_, ok := fileSystem.(osFileSystem)
+	return ok
+}
+
+func (osFileSystem) Open(name string) (fs.File, error) {
+	return os.Open(name)
+}
+
+func (osFileSystem) ReadDir(name string) ([]fs.DirEntry, error) {
+	return os.ReadDir(name)
+}
+
+func (osFileSystem) Stat(name string) (fs.FileInfo, error) {
+	return os.Stat(name)
+}
+
+func (osFileSystem) Lstat(name string) (fs.FileInfo, error) {
+	return os.Lstat(name)
+}
+
+func (osFileSystem) ReadLink(name string) (string, error) {
+	return os.Readlink(name)
+}
+
+func (osFileSystem) EvalSymlinks(name string) (string, error) {
+	resolvedPath, err := filepath.EvalSymlinks(name)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Abs(resolvedPath)
+}
+
+func (osFileSystem) Abs(name string) (string, error) {
+	return filepath.Abs(name)
+}
@@ -0,0 +1,145 @@
+package git
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"goselect/parser/filesystem"
+	"path"
+	"path/filepath"
+)
+
+const (
+	indexSignature    = "DIRC"
+	indexHeaderLength = 12
+	indexEntryLength  = 62
+	indexExtendedFlag = 0x4000
+)
+
+/*
+Index is the set of the paths tracked in the index of a worktree, '.git/index', with the directories that contain them.
+The paths are relative to the worktree and separated by '/'.
+*/
+type Index struct {
+	files       map[string]bool
+	directories map[string]bool
+}
+
+/*
+ReadIndex reads the index of the worktree, in the version 2, 3 or 4 of the index format. The index of a linked
+worktree is read from the git directory that its '.git' file points to.
+*/
+func ReadIndex(fileSystem filesystem.FileSystem, worktree string) (*Index, error) {
+	gitPath := filepath.Join(worktree, gitDirectoryName)
+	file, err := fileSystem.Stat(gitPath)
+	if err != nil {
+		return nil, err
+	}
+	gitDirectory := gitPath
+	if !file.IsDir() {
+		if gitDirectory, err = readGitFile(fileSystem, worktree, gitPath); err != nil {
+			return nil, err
+		}
+	}
+	content, err := filesystem.ReadFile(fileSystem, filepath.Join(gitDirectory, "index"))
+	if err != nil {
+		return nil, err
+	}
+	return parseIndex(content)
+}
+
+func parseIndex(content []byte) (*Index, error) {
+	if len(content) < indexHeaderLength || string(content[0:4]) != indexSignature {
+		return nil, errors.New("invalid git index")
+	}
+	version := binary.BigEndian.Uint32(content[4:8])
+	if version < 2 || version > 4 {
+		return nil, fmt.Errorf("unsupported git index version %v", version)
+	}
+	count := binary.BigEndian.Uint32(content[8:12])
+	index := &Index{files: make(map[string]bool), directories: make(map[string]bool)}
+
+	offset, previousPath := indexHeaderLength, ""
+	for entry := uint32(0); entry < count; entry++ {
+		if offset+indexEntryLength > len(content) {
+			return nil, errors.New("truncated git index")
+		}
+		flags := binary.BigEndian.Uint16(content[offset+60 : offset+62])
+		pathOffset := offset + indexEntryLength
+		if version >= 3 && flags&indexExtendedFlag != 0 {
+			pathOffset = pathOffset + 2
+		}
+		var entryPath string
+		if version == 4 {
+			removed, length, ok := indexVariableInteger(content[min(pathOffset, len(content)):])
+			if !ok || int(removed) > len(previousPath) {
+				return nil, errors.New("invalid git index path")
+			}
+			pathOffset = pathOffset + length
+			end := bytes.IndexByte(content[min(pathOffset, len(content)):], 0)
+			if end < 0 {
+				return nil, errors.New("truncated git index")
+			}
+			entryPath = previousPath[:len(previousPath)-int(removed)] + string(content[pathOffset:pathOffset+end])
+			offset = pathOffset + end + 1
+		} else {
+			end := bytes.IndexByte(content[min(pathOffset, len(content)):], 0)
+			if end < 0 {
+				return nil, errors.New("truncated git index")
+			}
+			entryPath = string(content[pathOffset : pathOffset+end])
+			entryLength := pathOffset - offset + end
+			offset = offset + (entryLength+8)&^7
+		}
+		index.add(entryPath)
+		previousPath = entryPath
+	}
+	return index, nil
+}
+
+/*
+indexVariableInteger reads the variable length integer of the version 4 index, where each byte but the last has the
+high bit set, and 1 is added before every shift so that an integer has a single encoding.
+*/
+func indexVariableInteger(content []byte) (uint64, int, bool) {
+	var value uint64
+	for length, current := range content {
+		if length > 0 {
+			value = value + 1
+		}
+		value = value<<7 | uint64(current&0x7f)
+		if current&0x80 == 0 {
+			return value, length + 1, true
+		}
+		if length >= 9 {
+			break
+		}
+	}
+	return 0, 0, false
+}
+
+func (index *Index) add(filePath string) {
+	index.files[filePath] = true
+	for directory := path.Dir(filePath); directory != "." && !index.directories[directory]; directory = path.Dir(directory) {
+		index.directories[directory] = true
+	}
+}
+
+/*
+IsTracked returns true if the file is in the index, or for a directory, if any file below it is in the index or the
+directory is a submodule.
+*/
+func (index *Index) IsTracked(filePath string, isDirectory bool) bool {
+	if isDirectory && index.directories[filePath] {
+		return true
+	}
+	return index.files[filePath]
+}
+
+func min(one int, other int) int {
+	if one < other {
+		return one
+	}
+	return other
+}
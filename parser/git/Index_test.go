@@ -0,0 +1,63 @@
+//go:build unit
+// +build unit
+
+package git
+
+import (
+	"goselect/parser/filesystem"
+	"testing"
+)
+
+func readTestIndex(t *testing.T, directory string) *Index {
+	index, err := ReadIndex(filesystem.Os(), directory)
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	return index
+}
+
+func TestReadIndexWithTheTrackedFiles(t *testing.T) {
+	index := readTestIndex(t, newTestRepository(t))
+	for _, file := range []string{"README.md", "src/main.go", "bin/run.sh", "link"} {
+		if !index.IsTracked(file, false) {
+			t.Fatalf("Expected %v to be tracked", file)
+		}
+	}
+	if index.IsTracked("docs/guide.md", false) {
+		t.Fatalf("Expected docs/guide.md to not be tracked")
+	}
+}
+
+func TestReadIndexWithTheDirectoriesOfTheTrackedFiles(t *testing.T) {
+	index := readTestIndex(t, newTestRepository(t))
+	if !index.IsTracked("src", true) {
+		t.Fatalf("Expected src to be tracked")
+	}
+	if index.IsTracked("docs", true) {
+		t.Fatalf("Expected docs to not be tracked")
+	}
+	if index.IsTracked("src", false) {
+		t.Fatalf("Expected the file src to not be tracked")
+	}
+}
+
+func TestReadIndexInTheVersion4(t *testing.T) {
+	directory := newTestRepository(t)
+	runGit(t, directory, 0, "update-index", "--index-version", "4")
+
+	index := readTestIndex(t, directory)
+	for _, file := range []string{"README.md", "bin/run.sh", "link", "src/main.go"} {
+		if !index.IsTracked(file, false) {
+			t.Fatalf("Expected %v to be tracked", file)
+		}
+	}
+}
+
+func TestParseAnInvalidIndex(t *testing.T) {
+	if _, err := parseIndex([]byte("DIRC\x00\x00\x00\x02\x00\x00\x00\x01")); err == nil {
+		t.Fatalf("Expected an error for a truncated index")
+	}
+	if _, err := parseIndex([]byte("not an index")); err == nil {
+		t.Fatalf("Expected an error for an invalid index")
+	}
+}
@@ -0,0 +1,190 @@
+package git
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Hash [20]byte
+
+type objectType int
+
+const (
+	objectCommit         objectType = 1
+	objectTree           objectType = 2
+	objectBlob           objectType = 3
+	objectTag            objectType = 4
+	objectOffsetDelta    objectType = 6
+	objectReferenceDelta objectType = 7
+)
+
+const (
+	ModeTree       uint32 = 0o040000
+	ModeBlob       uint32 = 0o100644
+	ModeExecutable uint32 = 0o100755
+	ModeSymlink    uint32 = 0o120000
+	ModeSubmodule  uint32 = 0o160000
+)
+
+type Commit struct {
+	Hash    Hash
+	Tree    Hash
+	Parents []Hash
+	Author  string
+	Time    time.Time
+}
+
+type TreeEntry struct {
+	Name string
+	Mode uint32
+	Hash Hash
+}
+
+func (hash Hash) String() string {
+	return hex.EncodeToString(hash[:])
+}
+
+func hashOf(text string) (Hash, bool) {
+	var hash Hash
+	if len(text) != 2*len(hash) {
+		return hash, false
+	}
+	if _, err := hex.Decode(hash[:], []byte(text)); err != nil {
+		return hash, false
+	}
+	return hash, true
+}
+
+func (objectType objectType) String() string {
+	switch objectType {
+	case objectCommit:
+		return "commit"
+	case objectTree:
+		return "tree"
+	case objectBlob:
+		return "blob"
+	case objectTag:
+		return "tag"
+	}
+	return strconv.Itoa(int(objectType))
+}
+
+func objectTypeOf(name string) (objectType, error) {
+	switch name {
+	case "commit":
+		return objectCommit, nil
+	case "tree":
+		return objectTree, nil
+	case "blob":
+		return objectBlob, nil
+	case "tag":
+		return objectTag, nil
+	}
+	return 0, fmt.Errorf("unknown git object type %v", name)
+}
+
+/*
+parseCommit reads the tree, the parents, the author name and the committer time from the headers of a commit.
+The message of the commit is not read.
+*/
+func parseCommit(hash Hash, content []byte) (*Commit, error) {
+	commit := &Commit{Hash: hash}
+	for _, line := range strings.Split(string(headersOf(content)), "\n") {
+		key, value := splitHeader(line)
+		switch key {
+		case "tree":
+			tree, ok := hashOf(value)
+			if !ok {
+				return nil, fmt.Errorf("invalid tree in git commit %v", hash)
+			}
+			commit.Tree = tree
+		case "parent":
+			parent, ok := hashOf(value)
+			if !ok {
+				return nil, fmt.Errorf("invalid parent in git commit %v", hash)
+			}
+			commit.Parents = append(commit.Parents, parent)
+		case "author":
+			commit.Author, _ = parseSignature(value)
+		case "committer":
+			_, commit.Time = parseSignature(value)
+		}
+	}
+	return commit, nil
+}
+
+func parseTree(content []byte) ([]TreeEntry, error) {
+	var entries []TreeEntry
+	for len(content) > 0 {
+		space := bytes.IndexByte(content, ' ')
+		null := bytes.IndexByte(content, 0)
+		if space < 0 || null < space || len(content) < null+21 {
+			return nil, errors.New("invalid git tree")
+		}
+		mode, err := strconv.ParseUint(string(content[0:space]), 8, 32)
+		if err != nil {
+			return nil, errors.New("invalid mode in git tree")
+		}
+		entry := TreeEntry{Name: string(content[space+1 : null]), Mode: uint32(mode)}
+		copy(entry.Hash[:], content[null+1:null+21])
+		entries = append(entries, entry)
+		content = content[null+21:]
+	}
+	return entries, nil
+}
+
+/*
+parseTag returns the object that an annotated tag points to.
+*/
+func parseTag(hash Hash, content []byte) (Hash, error) {
+	for _, line := range strings.Split(string(headersOf(content)), "\n") {
+		if key, value := splitHeader(line); key == "object" {
+			if object, ok := hashOf(value); ok {
+				return object, nil
+			}
+		}
+	}
+	return Hash{}, fmt.Errorf("invalid git tag %v", hash)
+}
+
+func headersOf(content []byte) []byte {
+	if end := bytes.Index(content, []byte("\n\n")); end >= 0 {
+		return content[0:end]
+	}
+	return content
+}
+
+func splitHeader(line string) (string, string) {
+	if space := strings.IndexByte(line, ' '); space >= 0 {
+		return line[0:space], line[space+1:]
+	}
+	return line, ""
+}
+
+/*
+parseSignature reads a signature of the form 'Name <email> 1664000000 +0530' into the name and the time.
+*/
+func parseSignature(signature string) (string, time.Time) {
+	end := strings.LastIndexByte(signature, '>')
+	if end < 0 {
+		return strings.TrimSpace(signature), time.Time{}
+	}
+	name := signature[0:end]
+	if start := strings.LastIndexByte(name, '<'); start >= 0 {
+		name = name[0:start]
+	}
+	fields := strings.Fields(signature[end+1:])
+	if len(fields) < 1 {
+		return strings.TrimSpace(name), time.Time{}
+	}
+	seconds, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return strings.TrimSpace(name), time.Time{}
+	}
+	return strings.TrimSpace(name), time.Unix(seconds, 0)
+}
@@ -0,0 +1,223 @@
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+)
+
+const (
+	packIndexHeaderSize = 8
+	packFanoutSize      = 256 * 4
+)
+
+/*
+pack is a pack file along with its version 2 index.
+The index is kept in memory, and the objects are read from the pack file at their offsets.
+*/
+type pack struct {
+	index   []byte
+	data    io.ReaderAt
+	objects int
+}
+
+func newPack(index []byte, data io.ReaderAt) (*pack, error) {
+	if len(index) < packIndexHeaderSize+packFanoutSize ||
+		!bytes.Equal(index[0:4], []byte{0xff, 't', 'O', 'c'}) ||
+		binary.BigEndian.Uint32(index[4:8]) != 2 {
+		return nil, errors.New("unsupported git pack index, expected version 2")
+	}
+	objects := int(binary.BigEndian.Uint32(index[packIndexHeaderSize+packFanoutSize-4:]))
+	if len(index) < packIndexHeaderSize+packFanoutSize+objects*(20+4+4) {
+		return nil, errors.New("truncated git pack index")
+	}
+	return &pack{index: index, data: data, objects: objects}, nil
+}
+
+func (pack *pack) hashAt(position int) Hash {
+	var hash Hash
+	start := packIndexHeaderSize + packFanoutSize + position*20
+	copy(hash[:], pack.index[start:start+20])
+	return hash
+}
+
+func (pack *pack) bucketOf(firstByte byte) (int, int) {
+	fanout := func(index int) int {
+		start := packIndexHeaderSize + index*4
+		return int(binary.BigEndian.Uint32(pack.index[start : start+4]))
+	}
+	low := 0
+	if firstByte > 0 {
+		low = fanout(int(firstByte) - 1)
+	}
+	return low, fanout(int(firstByte))
+}
+
+func (pack *pack) find(hash Hash) (int64, bool) {
+	low, high := pack.bucketOf(hash[0])
+	position := low + sort.Search(high-low, func(index int) bool {
+		candidate := pack.hashAt(low + index)
+		return bytes.Compare(candidate[:], hash[:]) >= 0
+	})
+	if position >= high || pack.hashAt(position) != hash {
+		return 0, false
+	}
+	return pack.offsetAt(position), true
+}
+
+func (pack *pack) findPrefix(prefix string, matches map[Hash]bool) {
+	firstByte, err := hex.DecodeString(prefix[0:2])
+	if err != nil {
+		return
+	}
+	low, high := pack.bucketOf(firstByte[0])
+	for position := low; position < high; position++ {
+		if hash := pack.hashAt(position); strings.HasPrefix(hash.String(), prefix) {
+			matches[hash] = true
+		}
+	}
+}
+
+func (pack *pack) offsetAt(position int) int64 {
+	offsets := packIndexHeaderSize + packFanoutSize + pack.objects*(20+4)
+	offset := binary.BigEndian.Uint32(pack.index[offsets+position*4:])
+	if offset&0x80000000 == 0 {
+		return int64(offset)
+	}
+	largeOffsets := offsets + pack.objects*4
+	return int64(binary.BigEndian.Uint64(pack.index[largeOffsets+int(offset&0x7fffffff)*8:]))
+}
+
+type packedObject struct {
+	objectType objectType
+	size       int64
+	baseOffset int64
+	baseHash   Hash
+	reader     *bufio.Reader
+}
+
+/*
+readHeader reads the type and the size of the object at an offset, and the base of a delta object.
+The reader is positioned at the start of the compressed content.
+*/
+func (pack *pack) readHeader(offset int64) (*packedObject, error) {
+	reader := bufio.NewReader(io.NewSectionReader(pack.data, offset, math.MaxInt64-offset))
+	next, err := reader.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	object := &packedObject{objectType: objectType((next >> 4) & 7), size: int64(next & 15), reader: reader}
+	for shift := uint(4); next&0x80 != 0; shift = shift + 7 {
+		if next, err = reader.ReadByte(); err != nil {
+			return nil, err
+		}
+		object.size = object.size | int64(next&0x7f)<<shift
+	}
+	switch object.objectType {
+	case objectOffsetDelta:
+		if next, err = reader.ReadByte(); err != nil {
+			return nil, err
+		}
+		distance := int64(next & 0x7f)
+		for next&0x80 != 0 {
+			if next, err = reader.ReadByte(); err != nil {
+				return nil, err
+			}
+			distance = (distance+1)<<7 | int64(next&0x7f)
+		}
+		object.baseOffset = offset - distance
+	case objectReferenceDelta:
+		if _, err := io.ReadFull(reader, object.baseHash[:]); err != nil {
+			return nil, err
+		}
+	}
+	return object, nil
+}
+
+func (object *packedObject) content() ([]byte, error) {
+	decompressor, err := zlib.NewReader(object.reader)
+	if err != nil {
+		return nil, err
+	}
+	defer decompressor.Close()
+
+	content := make([]byte, object.size)
+	if _, err := io.ReadFull(decompressor, content); err != nil {
+		return nil, err
+	}
+	return content, nil
+}
+
+/*
+applyDelta builds an object from its base and a delta, which is a list of instructions that either copy a range of
+the base or insert new bytes.
+*/
+func applyDelta(base []byte, delta []byte) ([]byte, error) {
+	baseSize, delta := readDeltaSize(delta)
+	if baseSize != int64(len(base)) {
+		return nil, errors.New("git delta does not match the size of its base")
+	}
+	targetSize, delta := readDeltaSize(delta)
+	target := make([]byte, 0, targetSize)
+	for len(delta) > 0 {
+		instruction := delta[0]
+		delta = delta[1:]
+		if instruction&0x80 != 0 {
+			var offset, size int64
+			for bit := 0; bit < 7; bit++ {
+				if instruction&(1<<bit) == 0 {
+					continue
+				}
+				if len(delta) == 0 {
+					return nil, errors.New("truncated git delta")
+				}
+				if bit < 4 {
+					offset = offset | int64(delta[0])<<(8*bit)
+				} else {
+					size = size | int64(delta[0])<<(8*(bit-4))
+				}
+				delta = delta[1:]
+			}
+			if size == 0 {
+				size = 0x10000
+			}
+			if offset+size > int64(len(base)) {
+				return nil, errors.New("git delta copies outside of its base")
+			}
+			target = append(target, base[offset:offset+size]...)
+		} else if instruction != 0 {
+			if int(instruction) > len(delta) {
+				return nil, errors.New("truncated git delta")
+			}
+			target = append(target, delta[0:instruction]...)
+			delta = delta[instruction:]
+		} else {
+			return nil, errors.New("invalid git delta instruction")
+		}
+	}
+	if int64(len(target)) != targetSize {
+		return nil, fmt.Errorf("git delta produced %v bytes, expected %v", len(target), targetSize)
+	}
+	return target, nil
+}
+
+func readDeltaSize(delta []byte) (int64, []byte) {
+	var size int64
+	for shift := uint(0); len(delta) > 0; shift = shift + 7 {
+		next := delta[0]
+		delta = delta[1:]
+		size = size | int64(next&0x7f)<<shift
+		if next&0x80 == 0 {
+			break
+		}
+	}
+	return size, delta
+}
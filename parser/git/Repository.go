@@ -0,0 +1,371 @@
+package git
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"goselect/parser/filesystem"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	gitDirectoryName = ".git"
+	maxCachedBytes   = 64 * 1024 * 1024
+)
+
+/*
+Repository reads the objects and the references of a local git repository, from the loose objects and from the
+version 2 pack indexes, without a git installation.
+The trees, the commits and the bases of the deltas are cached, so that walking the history reads an object only once.
+A Repository can be used by the concurrent traversal.
+*/
+type Repository struct {
+	fileSystem      filesystem.FileSystem
+	worktree        string
+	gitDirectory    string
+	commonDirectory string
+	packs           []*pack
+	lock            sync.Mutex
+	cachedObjects   map[interface{}]*object
+	cachedBytes     int
+}
+
+/*
+ObjectNotFoundError is returned when an object is neither a loose object nor in a pack, like the parent of the first
+commit of a shallow clone.
+*/
+type ObjectNotFoundError struct {
+	Hash Hash
+}
+
+func (err *ObjectNotFoundError) Error() string {
+	return fmt.Sprintf("git object %v not found", err.Hash)
+}
+
+type object struct {
+	objectType objectType
+	content    []byte
+}
+
+type packOffset struct {
+	pack   *pack
+	offset int64
+}
+
+/*
+OpenRepository finds the repository that a path belongs to, by looking for '.git' in the path and its parents.
+'.git' can be a directory or, for a linked worktree or a submodule, a file with 'gitdir: <path>'.
+*/
+func OpenRepository(fileSystem filesystem.FileSystem, path string) (*Repository, error) {
+	absolutePath, err := fileSystem.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	for current := absolutePath; ; current = filepath.Dir(current) {
+		gitPath := filepath.Join(current, gitDirectoryName)
+		if file, err := fileSystem.Stat(gitPath); err == nil {
+			gitDirectory := gitPath
+			if !file.IsDir() {
+				if gitDirectory, err = readGitFile(fileSystem, current, gitPath); err != nil {
+					return nil, err
+				}
+			}
+			return newRepository(fileSystem, current, gitDirectory)
+		}
+		if filepath.Dir(current) == current {
+			return nil, fmt.Errorf("not a git repository: %v", path)
+		}
+	}
+}
+
+func readGitFile(fileSystem filesystem.FileSystem, worktree string, gitPath string) (string, error) {
+	content, err := filesystem.ReadFile(fileSystem, gitPath)
+	if err != nil {
+		return "", err
+	}
+	gitDirectory := strings.TrimSpace(strings.TrimPrefix(string(content), "gitdir:"))
+	if gitDirectory == strings.TrimSpace(string(content)) {
+		return "", fmt.Errorf("invalid git file %v", gitPath)
+	}
+	if !filepath.IsAbs(gitDirectory) {
+		gitDirectory = filepath.Join(worktree, gitDirectory)
+	}
+	return gitDirectory, nil
+}
+
+func newRepository(fileSystem filesystem.FileSystem, worktree string, gitDirectory string) (*Repository, error) {
+	repository := &Repository{
+		fileSystem:      fileSystem,
+		worktree:        worktree,
+		gitDirectory:    gitDirectory,
+		commonDirectory: gitDirectory,
+		cachedObjects:   make(map[interface{}]*object),
+	}
+	if content, err := filesystem.ReadFile(fileSystem, filepath.Join(gitDirectory, "commondir")); err == nil {
+		commonDirectory := strings.TrimSpace(string(content))
+		if !filepath.IsAbs(commonDirectory) {
+			commonDirectory = filepath.Join(gitDirectory, commonDirectory)
+		}
+		repository.commonDirectory = commonDirectory
+	}
+	packDirectory := filepath.Join(repository.commonDirectory, "objects", "pack")
+	entries, err := fileSystem.ReadDir(packDirectory)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if !strings.HasSuffix(entry.Name(), ".idx") {
+			continue
+		}
+		pack, err := repository.openPack(filepath.Join(packDirectory, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		repository.packs = append(repository.packs, pack)
+	}
+	return repository, nil
+}
+
+func (repository *Repository) openPack(indexPath string) (*pack, error) {
+	index, err := filesystem.ReadFile(repository.fileSystem, indexPath)
+	if err != nil {
+		return nil, err
+	}
+	file, err := repository.fileSystem.Open(strings.TrimSuffix(indexPath, ".idx") + ".pack")
+	if err != nil {
+		return nil, err
+	}
+	data, _, err := filesystem.ReaderAtOf(file)
+	if err != nil {
+		return nil, err
+	}
+	return newPack(index, data)
+}
+
+/*
+Worktree returns the directory that contains '.git', which the paths in a revision are relative to.
+*/
+func (repository *Repository) Worktree() string {
+	return repository.worktree
+}
+
+func (repository *Repository) Commit(hash Hash) (*Commit, error) {
+	read, err := repository.readObject(hash)
+	if err != nil {
+		return nil, err
+	}
+	switch read.objectType {
+	case objectCommit:
+		return parseCommit(hash, read.content)
+	case objectTag:
+		target, err := parseTag(hash, read.content)
+		if err != nil {
+			return nil, err
+		}
+		return repository.Commit(target)
+	}
+	return nil, fmt.Errorf("git object %v is a %v, expected a commit", hash, read.objectType)
+}
+
+func (repository *Repository) Tree(hash Hash) ([]TreeEntry, error) {
+	read, err := repository.readObject(hash)
+	if err != nil {
+		return nil, err
+	}
+	if read.objectType != objectTree {
+		return nil, fmt.Errorf("git object %v is a %v, expected a tree", hash, read.objectType)
+	}
+	return parseTree(read.content)
+}
+
+func (repository *Repository) Blob(hash Hash) ([]byte, error) {
+	read, err := repository.readObject(hash)
+	if err != nil {
+		return nil, err
+	}
+	if read.objectType != objectBlob {
+		return nil, fmt.Errorf("git object %v is a %v, expected a blob", hash, read.objectType)
+	}
+	return read.content, nil
+}
+
+/*
+BlobSize returns the size of a blob by reading only the header of a loose object or of a packed object,
+and the header of the delta for a deltified object.
+*/
+func (repository *Repository) BlobSize(hash Hash) (int64, error) {
+	if cached, ok := repository.cached(hash); ok {
+		return int64(len(cached.content)), nil
+	}
+	file, err := repository.fileSystem.Open(repository.loosePath(hash))
+	if err == nil {
+		defer file.Close()
+		decompressor, err := zlib.NewReader(file)
+		if err != nil {
+			return 0, err
+		}
+		defer decompressor.Close()
+		header := make([]byte, 32)
+		read, _ := io.ReadFull(decompressor, header)
+		_, size, _, err := parseLooseHeader(header[0:read])
+		return size, err
+	}
+	for _, pack := range repository.packs {
+		offset, ok := pack.find(hash)
+		if !ok {
+			continue
+		}
+		packed, err := pack.readHeader(offset)
+		if err != nil {
+			return 0, err
+		}
+		if packed.objectType != objectOffsetDelta && packed.objectType != objectReferenceDelta {
+			return packed.size, nil
+		}
+		decompressor, err := zlib.NewReader(packed.reader)
+		if err != nil {
+			return 0, err
+		}
+		defer decompressor.Close()
+		header := make([]byte, 20)
+		read, _ := io.ReadFull(decompressor, header)
+		_, remaining := readDeltaSize(header[0:read])
+		size, _ := readDeltaSize(remaining)
+		return size, nil
+	}
+	return 0, &ObjectNotFoundError{Hash: hash}
+}
+
+func (repository *Repository) readObject(hash Hash) (*object, error) {
+	if cached, ok := repository.cached(hash); ok {
+		return cached, nil
+	}
+	loaded, err := repository.readLooseObject(hash)
+	if errors.Is(err, fs.ErrNotExist) {
+		loaded, err = repository.readPackedObjectWith(hash)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if loaded.objectType != objectBlob {
+		repository.remember(hash, loaded)
+	}
+	return loaded, nil
+}
+
+func (repository *Repository) readPackedObjectWith(hash Hash) (*object, error) {
+	for _, pack := range repository.packs {
+		if offset, ok := pack.find(hash); ok {
+			return repository.readPackedObject(pack, offset)
+		}
+	}
+	return nil, &ObjectNotFoundError{Hash: hash}
+}
+
+func (repository *Repository) readLooseObject(hash Hash) (*object, error) {
+	file, err := repository.fileSystem.Open(repository.loosePath(hash))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	decompressor, err := zlib.NewReader(file)
+	if err != nil {
+		return nil, err
+	}
+	defer decompressor.Close()
+	content, err := io.ReadAll(decompressor)
+	if err != nil {
+		return nil, err
+	}
+	objectType, size, headerLength, err := parseLooseHeader(content)
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(content)-headerLength) != size {
+		return nil, fmt.Errorf("git object %v has an invalid size", hash)
+	}
+	return &object{objectType: objectType, content: content[headerLength:]}, nil
+}
+
+func (repository *Repository) readPackedObject(pack *pack, offset int64) (*object, error) {
+	key := packOffset{pack: pack, offset: offset}
+	if cached, ok := repository.cached(key); ok {
+		return cached, nil
+	}
+	packed, err := pack.readHeader(offset)
+	if err != nil {
+		return nil, err
+	}
+	content, err := packed.content()
+	if err != nil {
+		return nil, err
+	}
+	var base *object
+	switch packed.objectType {
+	case objectOffsetDelta:
+		base, err = repository.readPackedObject(pack, packed.baseOffset)
+	case objectReferenceDelta:
+		base, err = repository.readObject(packed.baseHash)
+	default:
+		return &object{objectType: packed.objectType, content: content}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if content, err = applyDelta(base.content, content); err != nil {
+		return nil, err
+	}
+	resolved := &object{objectType: base.objectType, content: content}
+	repository.remember(key, resolved)
+	return resolved, nil
+}
+
+func (repository *Repository) loosePath(hash Hash) string {
+	hexHash := hash.String()
+	return filepath.Join(repository.commonDirectory, "objects", hexHash[0:2], hexHash[2:])
+}
+
+func (repository *Repository) cached(key interface{}) (*object, bool) {
+	repository.lock.Lock()
+	defer repository.lock.Unlock()
+	cached, ok := repository.cachedObjects[key]
+	return cached, ok
+}
+
+func (repository *Repository) remember(key interface{}, cached *object) {
+	repository.lock.Lock()
+	defer repository.lock.Unlock()
+	if repository.cachedBytes+len(cached.content) > maxCachedBytes {
+		repository.cachedObjects = make(map[interface{}]*object)
+		repository.cachedBytes = 0
+	}
+	repository.cachedObjects[key] = cached
+	repository.cachedBytes = repository.cachedBytes + len(cached.content)
+}
+
+/*
+parseLooseHeader reads the header of a loose object, '<type> <size>\0', and returns the length of the header.
+*/
+func parseLooseHeader(content []byte) (objectType, int64, int, error) {
+	null := bytes.IndexByte(content, 0)
+	space := bytes.IndexByte(content, ' ')
+	if null < 0 || space < 0 || space > null {
+		return 0, 0, 0, errors.New("invalid git object header")
+	}
+	objectType, err := objectTypeOf(string(content[0:space]))
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	size, err := strconv.ParseInt(string(content[space+1:null]), 10, 64)
+	if err != nil {
+		return 0, 0, 0, errors.New("invalid git object header")
+	}
+	return objectType, size, null + 1, nil
+}
@@ -0,0 +1,153 @@
+//go:build unit
+// +build unit
+
+package git
+
+import (
+	"goselect/parser/filesystem"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+/*
+newRepository creates a repository with the following history on 'main', and an annotated tag 'v1' on the first commit:
+1. README.md, src/main.go
+2. src/main.go changed, bin/run.sh (executable) and docs/guide.md added
+3. docs/guide.md removed, link (a symbolic link to README.md) added
+*/
+func newTestRepository(t *testing.T) string {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	directory := t.TempDir()
+	commit := func(message string, timestamp int64, files map[string]string) {
+		for name, content := range files {
+			path := filepath.Join(directory, name)
+			if content == "" {
+				runGit(t, directory, timestamp, "rm", "-q", name)
+				continue
+			}
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				t.Fatalf("error is %v", err)
+			}
+			if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+				t.Fatalf("error is %v", err)
+			}
+		}
+		runGit(t, directory, timestamp, "add", "-A")
+		runGit(t, directory, timestamp, "commit", "-q", "-m", message)
+	}
+	runGit(t, directory, 0, "init", "-q", "-b", "main")
+	commit("first", 1664000000, map[string]string{"README.md": "# readme\n", "src/main.go": "package main\n"})
+	runGit(t, directory, 1664000000, "tag", "-a", "v1", "-m", "version 1")
+
+	if err := os.MkdirAll(filepath.Join(directory, "bin"), 0755); err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(directory, "bin", "run.sh"), []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	commit("second", 1664100000, map[string]string{"src/main.go": "package main\n\nfunc main() {}\n", "docs/guide.md": "guide\n"})
+
+	if err := os.Symlink("README.md", filepath.Join(directory, "link")); err != nil {
+		t.Skip("symbolic links are not supported")
+	}
+	commit("third", 1664200000, map[string]string{"docs/guide.md": ""})
+	return directory
+}
+
+func runGit(t *testing.T, directory string, timestamp int64, arguments ...string) string {
+	command := exec.Command("git", append([]string{"-c", "commit.gpgsign=false", "-c", "tag.gpgsign=false"}, arguments...)...)
+	command.Dir = directory
+	date := time.Unix(timestamp, 0).UTC().Format(time.RFC3339)
+	command.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=author", "GIT_AUTHOR_EMAIL=author@example.com", "GIT_AUTHOR_DATE="+date,
+		"GIT_COMMITTER_NAME=committer", "GIT_COMMITTER_EMAIL=committer@example.com", "GIT_COMMITTER_DATE="+date,
+	)
+	output, err := command.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v failed with %v, %v", arguments, err, string(output))
+	}
+	return strings.TrimSpace(string(output))
+}
+
+func openTestRepository(t *testing.T, directory string) *Repository {
+	repository, err := OpenRepository(filesystem.Os(), filepath.Join(directory, "src"))
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	return repository
+}
+
+func TestOpenRepositoryFromADirectoryInsideTheWorktree(t *testing.T) {
+	directory := newTestRepository(t)
+	repository := openTestRepository(t, directory)
+
+	if repository.Worktree() != directory {
+		t.Fatalf("Expected worktree to be %v, received %v", directory, repository.Worktree())
+	}
+}
+
+func TestOpenRepositoryOutsideARepository(t *testing.T) {
+	if _, err := OpenRepository(filesystem.Os(), t.TempDir()); err == nil {
+		t.Fatalf("Expected an error while opening a directory that is not in a git repository")
+	}
+}
+
+func TestResolveRevisions(t *testing.T) {
+	directory := newTestRepository(t)
+	for _, packed := range []bool{false, true} {
+		if packed {
+			runGit(t, directory, 0, "gc", "-q")
+		}
+		repository := openTestRepository(t, directory)
+		head := runGit(t, directory, 0, "rev-parse", "HEAD")
+		first := runGit(t, directory, 0, "rev-parse", "HEAD~2")
+		second := runGit(t, directory, 0, "rev-parse", "HEAD~1")
+
+		revisions := map[string]string{
+			"HEAD":            head,
+			"main":            head,
+			"refs/heads/main": head,
+			"HEAD~1":          second,
+			"HEAD^":           second,
+			"HEAD~2":          first,
+			"HEAD^^":          first,
+			"main~1^1":        first,
+			"HEAD^0":          head,
+			"v1":              first,
+			"tags/v1":         first,
+			head[0:7]:         head,
+			second:            second,
+		}
+		for revision, expected := range revisions {
+			commit, err := repository.ResolveRevision(revision)
+			if err != nil {
+				t.Fatalf("error is %v while resolving %v, packed %v", err, revision, packed)
+			}
+			if commit.Hash.String() != expected {
+				t.Fatalf("Expected %v to resolve to %v, received %v, packed %v", revision, expected, commit.Hash, packed)
+			}
+		}
+		for _, revision := range []string{"HEAD~3", "HEAD^2", "unknown", "abc", "~1"} {
+			if _, err := repository.ResolveRevision(revision); err == nil {
+				t.Fatalf("Expected an error while resolving %v, packed %v", revision, packed)
+			}
+		}
+	}
+}
+
+func TestCommitAttributes(t *testing.T) {
+	directory := newTestRepository(t)
+	commit, err := openTestRepository(t, directory).ResolveRevision("HEAD~1")
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	if commit.Author != "author" || !commit.Time.Equal(time.Unix(1664100000, 0)) || len(commit.Parents) != 1 {
+		t.Fatalf("Expected author, time and parents to be %v, %v, %v, received %v, %v, %v", "author", time.Unix(1664100000, 0), 1, commit.Author, commit.Time, len(commit.Parents))
+	}
+}
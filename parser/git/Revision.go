@@ -0,0 +1,161 @@
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"goselect/parser/filesystem"
+	"io/fs"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const maxSymbolicReferences = 10
+
+/*
+ResolveRevision resolves a revision to a commit, following the rules of 'git rev-parse' for the common forms:
+1. a reference, looked up as is, then in 'refs/', 'refs/tags/', 'refs/heads/' and 'refs/remotes/', for example, 'HEAD',
+'main', 'v1.2' or 'origin/main', from the loose references and from 'packed-refs',
+2. a complete or an abbreviated commit hash with at least 4 characters,
+3. followed by any number of '~<n>' for the n-th first parent and '^<n>' for the n-th parent,
+for example, 'HEAD~10', 'v1.2^2' or 'main~2^'. A missing n is 1, and '^0' is the commit itself.
+An annotated tag is peeled to the commit it points to.
+*/
+func (repository *Repository) ResolveRevision(revision string) (*Commit, error) {
+	end := strings.IndexAny(revision, "~^")
+	if end < 0 {
+		end = len(revision)
+	}
+	hash, err := repository.resolveName(revision[0:end])
+	if err != nil {
+		return nil, err
+	}
+	commit, err := repository.Commit(hash)
+	if err != nil {
+		return nil, err
+	}
+	for suffix := revision[end:]; suffix != ""; {
+		operator := suffix[0]
+		digits := 0
+		for digits+1 < len(suffix) && suffix[digits+1] >= '0' && suffix[digits+1] <= '9' {
+			digits++
+		}
+		count := 1
+		if digits > 0 {
+			if count, err = strconv.Atoi(suffix[1 : digits+1]); err != nil {
+				return nil, fmt.Errorf("invalid git revision %v", revision)
+			}
+		}
+		suffix = suffix[digits+1:]
+		if operator == '~' {
+			for ; count > 0; count-- {
+				if commit, err = repository.parentOf(commit, 1, revision); err != nil {
+					return nil, err
+				}
+			}
+		} else if count > 0 {
+			if commit, err = repository.parentOf(commit, count, revision); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return commit, nil
+}
+
+func (repository *Repository) parentOf(commit *Commit, index int, revision string) (*Commit, error) {
+	if index > len(commit.Parents) {
+		return nil, fmt.Errorf("git revision %v does not exist, commit %v has %v parents", revision, commit.Hash, len(commit.Parents))
+	}
+	return repository.Commit(commit.Parents[index-1])
+}
+
+func (repository *Repository) resolveName(name string) (Hash, error) {
+	if name == "" {
+		return Hash{}, fmt.Errorf("invalid git revision %v", name)
+	}
+	if hash, ok := hashOf(name); ok {
+		return hash, nil
+	}
+	for _, candidate := range []string{name, "refs/" + name, "refs/tags/" + name, "refs/heads/" + name, "refs/remotes/" + name, "refs/remotes/" + name + "/HEAD"} {
+		if hash, ok, err := repository.resolveReference(candidate, 0); err != nil || ok {
+			return hash, err
+		}
+	}
+	return repository.resolveAbbreviatedHash(name)
+}
+
+/*
+resolveReference reads a reference from its loose file, in the git directory and then in the common directory of
+a linked worktree, or from 'packed-refs', and follows a symbolic reference like 'ref: refs/heads/main'.
+*/
+func (repository *Repository) resolveReference(name string, depth int) (Hash, bool, error) {
+	if depth > maxSymbolicReferences {
+		return Hash{}, false, fmt.Errorf("too many levels of symbolic git references for %v", name)
+	}
+	for _, directory := range []string{repository.gitDirectory, repository.commonDirectory} {
+		content, err := filesystem.ReadFile(repository.fileSystem, filepath.Join(directory, filepath.FromSlash(name)))
+		if err != nil {
+			continue
+		}
+		value := strings.TrimSpace(string(content))
+		if strings.HasPrefix(value, "ref:") {
+			return repository.resolveReference(strings.TrimSpace(strings.TrimPrefix(value, "ref:")), depth+1)
+		}
+		if hash, ok := hashOf(value); ok {
+			return hash, true, nil
+		}
+	}
+	return repository.resolvePackedReference(name)
+}
+
+func (repository *Repository) resolvePackedReference(name string) (Hash, bool, error) {
+	content, err := filesystem.ReadFile(repository.fileSystem, filepath.Join(repository.commonDirectory, "packed-refs"))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return Hash{}, false, nil
+		}
+		return Hash{}, false, err
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "#") || strings.HasPrefix(line, "^") {
+			continue
+		}
+		if hashText, reference := splitHeader(line); reference == name {
+			if hash, ok := hashOf(hashText); ok {
+				return hash, true, nil
+			}
+		}
+	}
+	return Hash{}, false, nil
+}
+
+func (repository *Repository) resolveAbbreviatedHash(prefix string) (Hash, error) {
+	prefix = strings.ToLower(prefix)
+	if len(prefix) < 4 || strings.Trim(prefix, "0123456789abcdef") != "" {
+		return Hash{}, fmt.Errorf("unknown git revision %v", prefix)
+	}
+	matches := make(map[Hash]bool)
+	if entries, err := repository.fileSystem.ReadDir(filepath.Join(repository.commonDirectory, "objects", prefix[0:2])); err == nil {
+		for _, entry := range entries {
+			if hash, ok := hashOf(prefix[0:2] + entry.Name()); ok && strings.HasPrefix(hash.String(), prefix) {
+				matches[hash] = true
+			}
+		}
+	}
+	for _, pack := range repository.packs {
+		pack.findPrefix(prefix, matches)
+	}
+	switch len(matches) {
+	case 0:
+		return Hash{}, fmt.Errorf("unknown git revision %v", prefix)
+	case 1:
+		for hash := range matches {
+			return hash, nil
+		}
+	}
+	return Hash{}, fmt.Errorf("ambiguous git revision %v", prefix)
+}
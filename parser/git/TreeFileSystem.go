@@ -0,0 +1,426 @@
+package git
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+/*
+TreeFileSystem is a read-only FileSystem over the tree of a commit, so that a query can run against a revision
+without checking it out. The names are relative to the root of the repository, and the absolute path of a name is
+'git:<commit hash>:<name>', which is unique across the revisions and is accepted as a name as well.
+The modified time of every file is the time of the commit, and the last commit that changed a path is available
+through LastCommit.
+*/
+type TreeFileSystem struct {
+	repository      *Repository
+	commit          *Commit
+	absolutePrefix  string
+	lastCommitsLock sync.Mutex
+	lastCommits     map[string]*lastCommits
+}
+
+/*
+lastCommits are the last commits of the paths below a prefix, found with a single walk of the history.
+*/
+type lastCommits struct {
+	once    sync.Once
+	commits map[string]*Commit
+	err     error
+}
+
+/*
+ObjectInfo is returned by Sys() of the fs.FileInfo of a TreeFileSystem.
+*/
+type ObjectInfo struct {
+	Hash Hash
+	Mode uint32
+}
+
+type treeFileInfo struct {
+	name    string
+	entry   TreeEntry
+	size    int64
+	modTime time.Time
+}
+
+type treeDirEntry struct {
+	fileSystem *TreeFileSystem
+	entry      TreeEntry
+}
+
+type blobFile struct {
+	*bytes.Reader
+	info fs.FileInfo
+}
+
+func NewTreeFileSystem(repository *Repository, commit *Commit) *TreeFileSystem {
+	return &TreeFileSystem{
+		repository:     repository,
+		commit:         commit,
+		absolutePrefix: "git:" + commit.Hash.String() + ":",
+		lastCommits:    make(map[string]*lastCommits),
+	}
+}
+
+/*
+AddQueriedPath limits the walk of the history for LastCommit to the paths below the queried paths, instead of the
+whole tree. A path that is not below a queried path is looked up in a walk of the whole tree.
+*/
+func (fileSystem *TreeFileSystem) AddQueriedPath(name string) {
+	fileSystem.lastCommitsLock.Lock()
+	defer fileSystem.lastCommitsLock.Unlock()
+	prefix := fileSystem.cleanPath(name)
+	if _, ok := fileSystem.lastCommits[prefix]; !ok {
+		fileSystem.lastCommits[prefix] = &lastCommits{}
+	}
+}
+
+func (fileSystem *TreeFileSystem) Commit() *Commit {
+	return fileSystem.commit
+}
+
+func (fileSystem *TreeFileSystem) Open(name string) (fs.File, error) {
+	entry, err := fileSystem.entryOf("open", name)
+	if err != nil {
+		return nil, err
+	}
+	if entry.Mode == ModeTree || entry.Mode == ModeSubmodule {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: errors.New("is a directory")}
+	}
+	content, err := fileSystem.repository.Blob(entry.Hash)
+	if err != nil {
+		return nil, err
+	}
+	info := fileSystem.infoOf(entry, int64(len(content)))
+	return &blobFile{Reader: bytes.NewReader(content), info: info}, nil
+}
+
+func (fileSystem *TreeFileSystem) ReadDir(name string) ([]fs.DirEntry, error) {
+	entry, err := fileSystem.entryOf("readdir", name)
+	if err != nil {
+		return nil, err
+	}
+	if entry.Mode != ModeTree {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: errors.New("not a directory")}
+	}
+	treeEntries, err := fileSystem.repository.Tree(entry.Hash)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]fs.DirEntry, 0, len(treeEntries))
+	for _, treeEntry := range treeEntries {
+		entries = append(entries, treeDirEntry{fileSystem: fileSystem, entry: treeEntry})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name() < entries[j].Name()
+	})
+	return entries, nil
+}
+
+func (fileSystem *TreeFileSystem) Stat(name string) (fs.FileInfo, error) {
+	entry, err := fileSystem.entryOf("stat", name)
+	if err != nil {
+		return nil, err
+	}
+	return fileSystem.statOf(entry)
+}
+
+func (fileSystem *TreeFileSystem) Lstat(name string) (fs.FileInfo, error) {
+	return fileSystem.Stat(name)
+}
+
+func (fileSystem *TreeFileSystem) ReadLink(name string) (string, error) {
+	entry, err := fileSystem.entryOf("readlink", name)
+	if err != nil {
+		return "", err
+	}
+	if entry.Mode != ModeSymlink {
+		return "", &fs.PathError{Op: "readlink", Path: name, Err: fs.ErrInvalid}
+	}
+	target, err := fileSystem.repository.Blob(entry.Hash)
+	return string(target), err
+}
+
+func (fileSystem *TreeFileSystem) EvalSymlinks(name string) (string, error) {
+	if _, err := fileSystem.entryOf("stat", name); err != nil {
+		return "", err
+	}
+	return fileSystem.Abs(name)
+}
+
+func (fileSystem *TreeFileSystem) Abs(name string) (string, error) {
+	return fileSystem.absolutePrefix + fileSystem.cleanPath(name), nil
+}
+
+/*
+LastCommit returns the last commit that changed a path, following the first parents from the commit of the file system.
+A change that was merged is attributed to the merge commit. The history is walked once for a queried path, on the
+first call, until the last commit of every path below it is found, or until the first commit or the boundary of a
+shallow clone.
+*/
+func (fileSystem *TreeFileSystem) LastCommit(name string) (*Commit, error) {
+	name = fileSystem.cleanPath(name)
+	if name == "." {
+		return fileSystem.commit, nil
+	}
+	prefix, scope := fileSystem.lastCommitsFor(name)
+	scope.once.Do(func() {
+		scope.commits, scope.err = fileSystem.findLastCommits(prefix)
+	})
+	if scope.err != nil {
+		return nil, scope.err
+	}
+	commit, ok := scope.commits[name]
+	if !ok {
+		return nil, &fs.PathError{Op: "lastcommit", Path: name, Err: fs.ErrNotExist}
+	}
+	return commit, nil
+}
+
+/*
+lastCommitsFor returns the longest queried path that contains the name, or the root of the tree.
+*/
+func (fileSystem *TreeFileSystem) lastCommitsFor(name string) (string, *lastCommits) {
+	fileSystem.lastCommitsLock.Lock()
+	defer fileSystem.lastCommitsLock.Unlock()
+	longest := "."
+	for prefix := range fileSystem.lastCommits {
+		if isBelow(name, prefix) && (longest == "." || len(prefix) > len(longest)) {
+			longest = prefix
+		}
+	}
+	if _, ok := fileSystem.lastCommits[longest]; !ok {
+		fileSystem.lastCommits[longest] = &lastCommits{}
+	}
+	return longest, fileSystem.lastCommits[longest]
+}
+
+func (fileSystem *TreeFileSystem) findLastCommits(prefix string) (map[string]*Commit, error) {
+	pending := make(map[string]bool)
+	if prefix == "." {
+		if err := fileSystem.collectPaths(fileSystem.commit.Tree, "", pending); err != nil {
+			return nil, err
+		}
+	} else {
+		entry, err := fileSystem.entryOf("lastcommit", prefix)
+		if err != nil {
+			return nil, err
+		}
+		pending[prefix] = true
+		if entry.Mode == ModeTree {
+			if err := fileSystem.collectPaths(entry.Hash, prefix+"/", pending); err != nil {
+				return nil, err
+			}
+		}
+	}
+	lastCommits := make(map[string]*Commit)
+	for commit := fileSystem.commit; len(pending) > 0; {
+		parent, err := fileSystem.firstParentOf(commit)
+		if err != nil {
+			return nil, err
+		}
+		if parent == nil {
+			for path := range pending {
+				lastCommits[path] = commit
+			}
+			break
+		}
+		err = fileSystem.diffTrees(commit.Tree, parent.Tree, "", prefix, func(path string) {
+			if pending[path] {
+				lastCommits[path] = commit
+				delete(pending, path)
+			}
+		})
+		if err != nil {
+			return nil, err
+		}
+		commit = parent
+	}
+	return lastCommits, nil
+}
+
+/*
+firstParentOf returns nil for the first commit, and for the boundary of a shallow clone, where the parent is not in
+the repository. Any other error reading the parent is returned.
+*/
+func (fileSystem *TreeFileSystem) firstParentOf(commit *Commit) (*Commit, error) {
+	if len(commit.Parents) == 0 {
+		return nil, nil
+	}
+	parent, err := fileSystem.repository.Commit(commit.Parents[0])
+	var notFound *ObjectNotFoundError
+	if errors.As(err, &notFound) && notFound.Hash == commit.Parents[0] {
+		return nil, nil
+	}
+	return parent, err
+}
+
+func (fileSystem *TreeFileSystem) collectPaths(tree Hash, prefix string, paths map[string]bool) error {
+	entries, err := fileSystem.repository.Tree(tree)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		paths[prefix+entry.Name] = true
+		if entry.Mode == ModeTree {
+			if err := fileSystem.collectPaths(entry.Hash, prefix+entry.Name+"/", paths); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+/*
+diffTrees visits the paths in a tree that are added or changed compared to the tree of the parent,
+and descends only into the directories that changed and that lead to or are below the queried path.
+A zero hash is an empty tree.
+*/
+func (fileSystem *TreeFileSystem) diffTrees(tree Hash, parentTree Hash, prefix string, queriedPath string, visit func(path string)) error {
+	if tree == parentTree {
+		return nil
+	}
+	entries, err := fileSystem.treeOrEmpty(tree)
+	if err != nil {
+		return err
+	}
+	parentEntries, err := fileSystem.treeOrEmpty(parentTree)
+	if err != nil {
+		return err
+	}
+	parentEntriesByName := make(map[string]TreeEntry, len(parentEntries))
+	for _, parentEntry := range parentEntries {
+		parentEntriesByName[parentEntry.Name] = parentEntry
+	}
+	for _, entry := range entries {
+		parentEntry, ok := parentEntriesByName[entry.Name]
+		if ok && parentEntry.Hash == entry.Hash && parentEntry.Mode == entry.Mode {
+			continue
+		}
+		if !isBelow(prefix+entry.Name, queriedPath) && !isBelow(queriedPath, prefix+entry.Name) {
+			continue
+		}
+		visit(prefix + entry.Name)
+		if entry.Mode == ModeTree {
+			parentSubtree := Hash{}
+			if ok && parentEntry.Mode == ModeTree {
+				parentSubtree = parentEntry.Hash
+			}
+			if err := fileSystem.diffTrees(entry.Hash, parentSubtree, prefix+entry.Name+"/", queriedPath, visit); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+/*
+isBelow returns true if the path is the directory or is below it, where '.' is the root of the tree.
+*/
+func isBelow(path string, directory string) bool {
+	return directory == "." || path == directory || strings.HasPrefix(path, directory+"/")
+}
+
+func (fileSystem *TreeFileSystem) treeOrEmpty(tree Hash) ([]TreeEntry, error) {
+	if tree == (Hash{}) {
+		return nil, nil
+	}
+	return fileSystem.repository.Tree(tree)
+}
+
+func (fileSystem *TreeFileSystem) entryOf(operation string, name string) (TreeEntry, error) {
+	entry := TreeEntry{Name: ".", Mode: ModeTree, Hash: fileSystem.commit.Tree}
+	cleaned := fileSystem.cleanPath(name)
+	if cleaned == "." {
+		return entry, nil
+	}
+	for _, segment := range strings.Split(cleaned, "/") {
+		if entry.Mode != ModeTree {
+			return TreeEntry{}, &fs.PathError{Op: operation, Path: name, Err: fs.ErrNotExist}
+		}
+		entries, err := fileSystem.repository.Tree(entry.Hash)
+		if err != nil {
+			return TreeEntry{}, err
+		}
+		found := false
+		for _, candidate := range entries {
+			if candidate.Name == segment {
+				entry, found = candidate, true
+				break
+			}
+		}
+		if !found {
+			return TreeEntry{}, &fs.PathError{Op: operation, Path: name, Err: fs.ErrNotExist}
+		}
+	}
+	return entry, nil
+}
+
+func (fileSystem *TreeFileSystem) statOf(entry TreeEntry) (fs.FileInfo, error) {
+	var size int64
+	if entry.Mode != ModeTree && entry.Mode != ModeSubmodule {
+		blobSize, err := fileSystem.repository.BlobSize(entry.Hash)
+		if err != nil {
+			return nil, err
+		}
+		size = blobSize
+	}
+	return fileSystem.infoOf(entry, size), nil
+}
+
+func (fileSystem *TreeFileSystem) infoOf(entry TreeEntry, size int64) fs.FileInfo {
+	return &treeFileInfo{name: entry.Name, entry: entry, size: size, modTime: fileSystem.commit.Time}
+}
+
+func (info *treeFileInfo) Name() string       { return info.name }
+func (info *treeFileInfo) Size() int64        { return info.size }
+func (info *treeFileInfo) Mode() fs.FileMode  { return fileModeOf(info.entry.Mode) }
+func (info *treeFileInfo) ModTime() time.Time { return info.modTime }
+func (info *treeFileInfo) IsDir() bool        { return info.entry.Mode == ModeTree }
+func (info *treeFileInfo) Sys() interface{} {
+	return &ObjectInfo{Hash: info.entry.Hash, Mode: info.entry.Mode}
+}
+
+func (entry treeDirEntry) Name() string      { return entry.entry.Name }
+func (entry treeDirEntry) IsDir() bool       { return entry.entry.Mode == ModeTree }
+func (entry treeDirEntry) Type() fs.FileMode { return fileModeOf(entry.entry.Mode).Type() }
+func (entry treeDirEntry) Info() (fs.FileInfo, error) {
+	return entry.fileSystem.statOf(entry.entry)
+}
+
+func (file *blobFile) Stat() (fs.FileInfo, error) { return file.info, nil }
+func (file *blobFile) Close() error               { return nil }
+
+/*
+fileModeOf maps the mode of a tree entry to a file mode. git stores only the executable bit of a file,
+so a file is 0644 or 0755, and a submodule, which has no tree in the repository, is an irregular file.
+*/
+func fileModeOf(mode uint32) fs.FileMode {
+	switch mode {
+	case ModeTree:
+		return fs.ModeDir | 0755
+	case ModeExecutable:
+		return 0755
+	case ModeSymlink:
+		return fs.ModeSymlink | 0777
+	case ModeSubmodule:
+		return fs.ModeIrregular
+	}
+	return 0644
+}
+
+func (fileSystem *TreeFileSystem) cleanPath(name string) string {
+	cleaned := strings.TrimPrefix(path.Clean(filepath.ToSlash(strings.TrimPrefix(name, fileSystem.absolutePrefix))), "/")
+	if cleaned == "" {
+		return "."
+	}
+	return cleaned
+}
@@ -0,0 +1,177 @@
+//go:build unit
+// +build unit
+
+package git
+
+import (
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+func treeFileSystemAt(t *testing.T, directory string, revision string) *TreeFileSystem {
+	repository := openTestRepository(t, directory)
+	commit, err := repository.ResolveRevision(revision)
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	return NewTreeFileSystem(repository, commit)
+}
+
+func TestReadDirOfATree(t *testing.T) {
+	directory := newTestRepository(t)
+	for _, packed := range []bool{false, true} {
+		if packed {
+			runGit(t, directory, 0, "gc", "-q")
+		}
+		fileSystem := treeFileSystemAt(t, directory, "HEAD")
+		entries, err := fileSystem.ReadDir(".")
+		if err != nil {
+			t.Fatalf("error is %v", err)
+		}
+		var names []string
+		for _, entry := range entries {
+			names = append(names, entry.Name())
+		}
+		if !sort.StringsAreSorted(names) || len(names) != 4 || names[0] != "README.md" || names[1] != "bin" || names[2] != "link" || names[3] != "src" {
+			t.Fatalf("Expected entries to be [README.md bin link src], received %v, packed %v", names, packed)
+		}
+	}
+}
+
+func TestStatOfTheEntries(t *testing.T) {
+	directory := newTestRepository(t)
+	fileSystem := treeFileSystemAt(t, directory, "HEAD")
+
+	expected := []struct {
+		name    string
+		size    int64
+		mode    fs.FileMode
+		gitMode uint32
+	}{
+		{name: "./src/main.go", size: 29, mode: 0644, gitMode: ModeBlob},
+		{name: "bin/run.sh", size: 10, mode: 0755, gitMode: ModeExecutable},
+		{name: "link", size: 9, mode: fs.ModeSymlink | 0777, gitMode: ModeSymlink},
+		{name: "src", size: 0, mode: fs.ModeDir | 0755, gitMode: ModeTree},
+	}
+	for _, entry := range expected {
+		file, err := fileSystem.Stat(entry.name)
+		if err != nil {
+			t.Fatalf("error is %v", err)
+		}
+		object := file.Sys().(*ObjectInfo)
+		if file.Size() != entry.size || file.Mode() != entry.mode || object.Mode != entry.gitMode {
+			t.Fatalf("Expected %v to have size %v, mode %v and git mode %o, received %v, %v, %o", entry.name, entry.size, entry.mode, entry.gitMode, file.Size(), file.Mode(), object.Mode)
+		}
+	}
+	if _, err := fileSystem.Stat("docs/guide.md"); err == nil {
+		t.Fatalf("Expected an error for a file removed in the revision")
+	}
+}
+
+func TestOpenAndReadLinkAtAnOlderRevision(t *testing.T) {
+	directory := newTestRepository(t)
+	fileSystem := treeFileSystemAt(t, directory, "v1")
+
+	file, err := fileSystem.Open("src/main.go")
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	content, _ := io.ReadAll(file)
+	if string(content) != "package main\n" {
+		t.Fatalf("Expected content at v1 to be %q, received %q", "package main\n", string(content))
+	}
+	if _, err := fileSystem.Stat("bin"); err == nil {
+		t.Fatalf("Expected bin to not exist at v1")
+	}
+
+	target, err := treeFileSystemAt(t, directory, "HEAD").ReadLink("link")
+	if err != nil || target != "README.md" {
+		t.Fatalf("Expected link target to be %v, received %v, %v", "README.md", target, err)
+	}
+}
+
+func TestAbsolutePathIncludesTheCommit(t *testing.T) {
+	directory := newTestRepository(t)
+	fileSystem := treeFileSystemAt(t, directory, "HEAD")
+
+	absolutePath, _ := fileSystem.Abs("./src/main.go")
+	expected := "git:" + fileSystem.Commit().Hash.String() + ":src/main.go"
+	if absolutePath != expected {
+		t.Fatalf("Expected absolute path to be %v, received %v", expected, absolutePath)
+	}
+	if _, err := fileSystem.Stat(absolutePath); err != nil {
+		t.Fatalf("Expected the absolute path to be accepted as a name, received %v", err)
+	}
+}
+
+func TestLastCommit(t *testing.T) {
+	directory := newTestRepository(t)
+	for _, packed := range []bool{false, true} {
+		if packed {
+			runGit(t, directory, 0, "gc", "-q")
+		}
+		fileSystem := treeFileSystemAt(t, directory, "HEAD")
+		for _, name := range []string{"README.md", "src", "src/main.go", "bin/run.sh", "link"} {
+			expected := runGit(t, directory, 0, "log", "--first-parent", "-1", "--format=%H", "HEAD", "--", name)
+			commit, err := fileSystem.LastCommit(name)
+			if err != nil {
+				t.Fatalf("error is %v", err)
+			}
+			if commit.Hash.String() != expected {
+				t.Fatalf("Expected last commit of %v to be %v, received %v, packed %v", name, expected, commit.Hash, packed)
+			}
+		}
+	}
+}
+
+func TestLastCommitBelowAQueriedPath(t *testing.T) {
+	directory := newTestRepository(t)
+	fileSystem := treeFileSystemAt(t, directory, "HEAD")
+	fileSystem.AddQueriedPath("src")
+	for _, name := range []string{"src", "src/main.go", "README.md"} {
+		expected := runGit(t, directory, 0, "log", "--first-parent", "-1", "--format=%H", "HEAD", "--", name)
+		commit, err := fileSystem.LastCommit(name)
+		if err != nil {
+			t.Fatalf("error is %v", err)
+		}
+		if commit.Hash.String() != expected {
+			t.Fatalf("Expected last commit of %v to be %v, received %v", name, expected, commit.Hash)
+		}
+	}
+}
+
+func TestLastCommitAtTheBoundaryOfAShallowClone(t *testing.T) {
+	directory := newTestRepository(t)
+	clone := filepath.Join(t.TempDir(), "clone")
+	runGit(t, directory, 0, "clone", "-q", "--depth", "2", "file://"+directory, clone)
+
+	fileSystem := treeFileSystemAt(t, clone, "HEAD")
+	commit, err := fileSystem.LastCommit("README.md")
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	expected := runGit(t, clone, 0, "rev-parse", "HEAD~1")
+	if commit.Hash.String() != expected {
+		t.Fatalf("Expected last commit of README.md to be the boundary %v, received %v", expected, commit.Hash)
+	}
+}
+
+func TestLastCommitWithAnUnreadableParent(t *testing.T) {
+	directory := newTestRepository(t)
+	parent := runGit(t, directory, 0, "rev-parse", "HEAD~1")
+	objectPath := filepath.Join(directory, ".git", "objects", parent[0:2], parent[2:])
+	if err := os.Chmod(objectPath, 0644); err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	if err := os.WriteFile(objectPath, []byte("corrupted"), 0644); err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	fileSystem := treeFileSystemAt(t, directory, "HEAD")
+	if _, err := fileSystem.LastCommit("README.md"); err == nil {
+		t.Fatalf("Expected an error reading the parent commit")
+	}
+}
@@ -0,0 +1,128 @@
+package group
+
+import (
+	"errors"
+	"fmt"
+	"goselect/parser/context"
+	"goselect/parser/error/messages"
+	"goselect/parser/expression"
+	"goselect/parser/projection"
+	"goselect/parser/tokenizer"
+	"strconv"
+	"strings"
+)
+
+type Group struct {
+	expressions expression.Expressions
+}
+
+/*
+group by:    positions Or attributes Or functions
+positions:   1, 2 (refer to the projections that do not contain any aggregate function)
+attributes:  ext, uname etc
+functions:   lower(ext), substr(name, 0, 3) etc (aggregate functions are not allowed)
+expressions: size / 1024, ext = .log etc (aggregate functions are not allowed)
+*/
+func NewGroup(
+	iterator *tokenizer.TokenIterator,
+	projections *projection.Projections,
+	ctx *context.ParsingApplicationContext,
+) (*Group, error) {
+	if !iterator.HasNext() {
+		return nil, nil
+	}
+	if iterator.HasNext() && !iterator.Peek().Equals("group") {
+		return nil, nil
+	}
+	iterator.Next()
+	if !iterator.HasNext() || !iterator.Peek().Equals("by") {
+		return nil, errors.New(messages.ErrorMessageMissingByAfterGroup)
+	}
+	iterator.Next()
+
+	var expressions []*expression.Expression
+	var expectComma bool
+
+	parser := expression.NewExpressionParser(
+		iterator,
+		ctx,
+		[]string{"having", "order", "limit"},
+		messages.ErrorMessageInvalidGroupBy,
+		messages.ErrorMessageAggregateFunctionInsideGroupBy,
+	)
+	for !parser.IsAtTerminal() {
+		token := iterator.Peek()
+		switch {
+		case expectComma:
+			if !token.Equals(",") {
+				return nil, errors.New(messages.ErrorMessageMissingCommaGroupBy)
+			}
+			iterator.Next()
+			expectComma = false
+		case token.TokenType == tokenizer.Numeric && isAPosition(iterator):
+			iterator.Next()
+			anExpression, err := projectionAt(token, projections)
+			if err != nil {
+				return nil, err
+			}
+			expressions = append(expressions, anExpression)
+			expectComma = true
+		default:
+			anExpression, err := parser.Parse()
+			if err != nil {
+				return nil, err
+			}
+			if anExpression.IsAValue() {
+				return nil, fmt.Errorf(messages.ErrorMessageInvalidGroupByAttribute, token.TokenValue)
+			}
+			expressions = append(expressions, anExpression)
+			expectComma = true
+		}
+	}
+	if len(expressions) == 0 {
+		return nil, errors.New(messages.ErrorMessageMissingGroupByAttributes)
+	}
+	return &Group{expressions: expression.Expressions{Expressions: expressions}}, nil
+}
+
+func (group Group) DisplayableAttributes() []string {
+	return group.expressions.DisplayableAttributes()
+}
+
+/*
+KeyFor returns the key of the group of a file, made of the typed keys of the values of the group by expressions.
+*/
+func (group Group) KeyFor(fileAttributes *context.FileAttributes, functions *context.AllFunctions) (string, error) {
+	var key strings.Builder
+	for _, anExpression := range group.expressions.Expressions {
+		value, err, _ := anExpression.Evaluate(fileAttributes, functions)
+		if err != nil {
+			return "", err
+		}
+		key.WriteString(value.Key())
+	}
+	return key.String(), nil
+}
+
+func isAPosition(iterator *tokenizer.TokenIterator) bool {
+	next, ok := iterator.PeekAt(1)
+	return !ok || next.Equals(",") || next.Equals("having") || next.Equals("order") || next.Equals("limit")
+}
+
+func projectionAt(token tokenizer.Token, projections *projection.Projections) (*expression.Expression, error) {
+	projectionPosition, err := strconv.Atoi(token.TokenValue)
+	if err != nil {
+		return nil, fmt.Errorf(messages.ErrorMessageInvalidGroupByAttribute, token.TokenValue)
+	}
+	if projectionPosition <= 0 {
+		return nil, errors.New(messages.ErrorMessageNonZeroPositiveGroupByPositions)
+	}
+	if projectionPosition > projections.Count() {
+		return nil, fmt.Errorf(messages.ErrorMessageGroupByPositionOutOfRange, 1, projections.Count())
+	}
+	anExpression := projections.ExpressionAt(projectionPosition - 1)
+	if anExpression.HasAnAggregate() {
+		return nil, fmt.Errorf(messages.ErrorMessageGroupByPositionOnAggregate, projectionPosition)
+	}
+	return anExpression, nil
+}
@@ -0,0 +1,186 @@
+//go:build unit
+// +build unit
+
+package group
+
+import (
+	"goselect/parser/context"
+	"goselect/parser/projection"
+	"goselect/parser/tokenizer"
+	"reflect"
+	"testing"
+)
+
+func newProjections(rawProjections string, ctx *context.ParsingApplicationContext) *projection.Projections {
+	projections, _ := projection.NewProjections(tokenizer.NewTokenizer(rawProjections).Tokenize().Iterator(), ctx)
+	return projections
+}
+
+func TestGroupWithoutAnyGroupByClause(t *testing.T) {
+	ctx := context.NewContext(context.NewFunctions(), context.NewAttributes())
+	tokens := tokenizer.NewEmptyTokens()
+
+	group, _ := NewGroup(tokens.Iterator(), newProjections("name", ctx), ctx)
+	if group != nil {
+		t.Fatalf("Expected group to be nil but was not")
+	}
+}
+
+func TestGroupWithAKeywordOtherThanGroup(t *testing.T) {
+	ctx := context.NewContext(context.NewFunctions(), context.NewAttributes())
+	tokens := tokenizer.NewEmptyTokens()
+	tokens.Add(tokenizer.NewToken(tokenizer.Order, "order"))
+
+	group, _ := NewGroup(tokens.Iterator(), newProjections("name", ctx), ctx)
+	if group != nil {
+		t.Fatalf("Expected group to be nil but was not")
+	}
+}
+
+func TestGroupByWithMissingBy(t *testing.T) {
+	ctx := context.NewContext(context.NewFunctions(), context.NewAttributes())
+	tokens := tokenizer.NewEmptyTokens()
+	tokens.Add(tokenizer.NewToken(tokenizer.Group, "group"))
+
+	_, err := NewGroup(tokens.Iterator(), newProjections("name", ctx), ctx)
+	if err == nil {
+		t.Fatalf("Expected an error given group keyword without by")
+	}
+}
+
+func TestGroupByWithoutAttributes(t *testing.T) {
+	ctx := context.NewContext(context.NewFunctions(), context.NewAttributes())
+	tokens := tokenizer.NewEmptyTokens()
+	tokens.Add(tokenizer.NewToken(tokenizer.Group, "group"))
+	tokens.Add(tokenizer.NewToken(tokenizer.By, "by"))
+	tokens.Add(tokenizer.NewToken(tokenizer.Order, "order"))
+
+	_, err := NewGroup(tokens.Iterator(), newProjections("name", ctx), ctx)
+	if err == nil {
+		t.Fatalf("Expected an error given group by without any attributes")
+	}
+}
+
+func TestGroupByWithMissingComma(t *testing.T) {
+	ctx := context.NewContext(context.NewFunctions(), context.NewAttributes())
+	tokens := tokenizer.NewEmptyTokens()
+	tokens.Add(tokenizer.NewToken(tokenizer.Group, "group"))
+	tokens.Add(tokenizer.NewToken(tokenizer.By, "by"))
+	tokens.Add(tokenizer.NewToken(tokenizer.RawString, "ext"))
+	tokens.Add(tokenizer.NewToken(tokenizer.RawString, "name"))
+
+	_, err := NewGroup(tokens.Iterator(), newProjections("name", ctx), ctx)
+	if err == nil {
+		t.Fatalf("Expected an error given group by with missing comma")
+	}
+}
+
+func TestGroupByWithAnUnknownAttribute(t *testing.T) {
+	ctx := context.NewContext(context.NewFunctions(), context.NewAttributes())
+	tokens := tokenizer.NewEmptyTokens()
+	tokens.Add(tokenizer.NewToken(tokenizer.Group, "group"))
+	tokens.Add(tokenizer.NewToken(tokenizer.By, "by"))
+	tokens.Add(tokenizer.NewToken(tokenizer.RawString, "unknown"))
+
+	_, err := NewGroup(tokens.Iterator(), newProjections("name", ctx), ctx)
+	if err == nil {
+		t.Fatalf("Expected an error given group by with an unknown attribute")
+	}
+}
+
+func TestGroupByWithAZeroPosition(t *testing.T) {
+	ctx := context.NewContext(context.NewFunctions(), context.NewAttributes())
+	tokens := tokenizer.NewEmptyTokens()
+	tokens.Add(tokenizer.NewToken(tokenizer.Group, "group"))
+	tokens.Add(tokenizer.NewToken(tokenizer.By, "by"))
+	tokens.Add(tokenizer.NewToken(tokenizer.Numeric, "0"))
+
+	_, err := NewGroup(tokens.Iterator(), newProjections("name", ctx), ctx)
+	if err == nil {
+		t.Fatalf("Expected an error given group by with zero as the position")
+	}
+}
+
+func TestGroupByWithAPositionOutOfRange(t *testing.T) {
+	ctx := context.NewContext(context.NewFunctions(), context.NewAttributes())
+	tokens := tokenizer.NewEmptyTokens()
+	tokens.Add(tokenizer.NewToken(tokenizer.Group, "group"))
+	tokens.Add(tokenizer.NewToken(tokenizer.By, "by"))
+	tokens.Add(tokenizer.NewToken(tokenizer.Numeric, "2"))
+
+	_, err := NewGroup(tokens.Iterator(), newProjections("name", ctx), ctx)
+	if err == nil {
+		t.Fatalf("Expected an error given group by with a position out of range")
+	}
+}
+
+func TestGroupByWithAPositionReferringToAnAggregate(t *testing.T) {
+	ctx := context.NewContext(context.NewFunctions(), context.NewAttributes())
+	tokens := tokenizer.NewEmptyTokens()
+	tokens.Add(tokenizer.NewToken(tokenizer.Group, "group"))
+	tokens.Add(tokenizer.NewToken(tokenizer.By, "by"))
+	tokens.Add(tokenizer.NewToken(tokenizer.Numeric, "2"))
+
+	_, err := NewGroup(tokens.Iterator(), newProjections("ext, count()", ctx), ctx)
+	if err == nil {
+		t.Fatalf("Expected an error given group by with a position referring to an aggregate function")
+	}
+}
+
+func TestGroupByWithAnAggregateFunction(t *testing.T) {
+	ctx := context.NewContext(context.NewFunctions(), context.NewAttributes())
+	tokens := tokenizer.NewTokenizer("group by count()").Tokenize()
+
+	_, err := NewGroup(tokens.Iterator(), newProjections("name", ctx), ctx)
+	if err == nil {
+		t.Fatalf("Expected an error given group by with an aggregate function")
+	}
+}
+
+func TestGroupByWithAnAggregateFunctionInsideAScalarFunction(t *testing.T) {
+	ctx := context.NewContext(context.NewFunctions(), context.NewAttributes())
+	tokens := tokenizer.NewTokenizer("group by lower(count())").Tokenize()
+
+	_, err := NewGroup(tokens.Iterator(), newProjections("name", ctx), ctx)
+	if err == nil {
+		t.Fatalf("Expected an error given group by with an aggregate function inside a scalar function")
+	}
+}
+
+func TestGroupByWithAFunctionWithoutClosingParentheses(t *testing.T) {
+	ctx := context.NewContext(context.NewFunctions(), context.NewAttributes())
+	tokens := tokenizer.NewTokenizer("group by lower(ext order by 1").Tokenize()
+
+	_, err := NewGroup(tokens.Iterator(), newProjections("name", ctx), ctx)
+	if err == nil {
+		t.Fatalf("Expected an error given group by with a function without closing parentheses")
+	}
+}
+
+func TestGroupByWithAPosition(t *testing.T) {
+	ctx := context.NewContext(context.NewFunctions(), context.NewAttributes())
+	tokens := tokenizer.NewTokenizer("group by 1").Tokenize()
+
+	group, _ := NewGroup(tokens.Iterator(), newProjections("lower(ext), count()", ctx), ctx)
+	expected := []string{"lower(ext)"}
+
+	if !reflect.DeepEqual(expected, group.DisplayableAttributes()) {
+		t.Fatalf("Expected group by attributes to be %v, received %v", expected, group.DisplayableAttributes())
+	}
+}
+
+func TestGroupByWithAttributeAndFunction(t *testing.T) {
+	ctx := context.NewContext(context.NewFunctions(), context.NewAttributes())
+	tokens := tokenizer.NewTokenizer("group by ext, lower(isdir) order by 1").Tokenize()
+	iterator := tokens.Iterator()
+
+	group, _ := NewGroup(iterator, newProjections("count()", ctx), ctx)
+	expected := []string{"ext", "lower(isdir)"}
+
+	if !reflect.DeepEqual(expected, group.DisplayableAttributes()) {
+		t.Fatalf("Expected group by attributes to be %v, received %v", expected, group.DisplayableAttributes())
+	}
+	if !iterator.Peek().Equals("order") {
+		t.Fatalf("Expected the next token to be %v, received %v", "order", iterator.Peek().TokenValue)
+	}
+}
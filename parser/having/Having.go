@@ -0,0 +1,85 @@
+package having
+
+import (
+	"errors"
+	"goselect/parser/context"
+	"goselect/parser/error/messages"
+	"goselect/parser/expression"
+	"goselect/parser/tokenizer"
+)
+
+type Having struct {
+	expressions expression.Expressions
+}
+
+/*
+having:      a single function supported in the 'where' clause Or an expression
+functions:   gt(count(), 10), eq(ext, .log), and(gt(sum(size), 1024), lt(count(), 5)) etc (aggregate functions are allowed)
+expressions: count() > 10, sum(size) > 1024 and count() < 5 etc (aggregate functions are allowed)
+*/
+func NewHaving(
+	tokenIterator *tokenizer.TokenIterator,
+	isGroupDefined bool,
+	ctx *context.ParsingApplicationContext,
+) (*Having, error) {
+	if !tokenIterator.HasNext() {
+		return nil, nil
+	}
+	if tokenIterator.HasNext() && !tokenIterator.Peek().Equals("having") {
+		return nil, nil
+	}
+	if !isGroupDefined {
+		return nil, errors.New(messages.ErrorMessageHavingWithoutGroupBy)
+	}
+	tokenIterator.Next()
+
+	var expressions []*expression.Expression
+	parser := expression.NewExpressionParser(
+		tokenIterator,
+		ctx,
+		[]string{"order", "limit"},
+		messages.ErrorMessageInvalidHaving,
+		"",
+	)
+	if !parser.IsAtTerminal() {
+		anExpression, err := parser.Parse()
+		if err != nil {
+			return nil, err
+		}
+		if !anExpression.IsAFunctionWithTag(ctx, "where") || !parser.IsAtTerminal() {
+			return nil, errors.New(messages.ErrorMessageInvalidHavingFunctionUsed)
+		}
+		expressions = append(expressions, anExpression)
+	}
+	if len(expressions) == 0 {
+		return nil, errors.New(messages.ErrorMessageExpectedExpressionInHaving)
+	}
+	return &Having{expressions: expression.Expressions{Expressions: expressions}}, nil
+}
+
+func (having Having) Display() string {
+	if attributes := having.expressions.DisplayableAttributes(); len(attributes) >= 1 {
+		return attributes[0]
+	}
+	return ""
+}
+
+func (having Having) CloneWithInitialState(functions *context.AllFunctions) *Having {
+	return &Having{expressions: having.expressions.CloneWithInitialState(functions)}
+}
+
+func (having Having) EvaluateWith(fileAttributes *context.FileAttributes, functions *context.AllFunctions) error {
+	_, _, _, err := having.expressions.EvaluateWith(fileAttributes, functions)
+	return err
+}
+
+func (having Having) FullyEvaluateWith(fileAttributes *context.FileAttributes, functions *context.AllFunctions) (bool, error) {
+	if expr := having.expressions.ExpressionAt(0); expr != nil {
+		value, err := expr.FullyEvaluateWith(fileAttributes, functions)
+		if err != nil {
+			return false, err
+		}
+		return value.GetBoolean()
+	}
+	return true, nil
+}
@@ -0,0 +1,108 @@
+//go:build unit
+// +build unit
+
+package having
+
+import (
+	"goselect/parser/context"
+	"goselect/parser/tokenizer"
+	"testing"
+)
+
+func TestHavingWithoutAnyHavingClause(t *testing.T) {
+	tokens := tokenizer.NewEmptyTokens()
+
+	having, _ := NewHaving(tokens.Iterator(), true, context.NewContext(context.NewFunctions(), context.NewAttributes()))
+	if having != nil {
+		t.Fatalf("Expected having to be nil but was not")
+	}
+}
+
+func TestHavingWithAKeywordOtherThanHaving(t *testing.T) {
+	tokens := tokenizer.NewEmptyTokens()
+	tokens.Add(tokenizer.NewToken(tokenizer.Order, "order"))
+
+	having, _ := NewHaving(tokens.Iterator(), true, context.NewContext(context.NewFunctions(), context.NewAttributes()))
+	if having != nil {
+		t.Fatalf("Expected having to be nil but was not")
+	}
+}
+
+func TestHavingWithoutGroupBy(t *testing.T) {
+	tokens := tokenizer.NewTokenizer("having gt(count(), 1)").Tokenize()
+
+	_, err := NewHaving(tokens.Iterator(), false, context.NewContext(context.NewFunctions(), context.NewAttributes()))
+	if err == nil {
+		t.Fatalf("Expected an error given having without group by")
+	}
+}
+
+func TestHavingWithoutAnExpression(t *testing.T) {
+	tokens := tokenizer.NewTokenizer("having order by 1").Tokenize()
+
+	_, err := NewHaving(tokens.Iterator(), true, context.NewContext(context.NewFunctions(), context.NewAttributes()))
+	if err == nil {
+		t.Fatalf("Expected an error given having without an expression")
+	}
+}
+
+func TestHavingWithAFunctionNotSupportedInWhere(t *testing.T) {
+	tokens := tokenizer.NewTokenizer("having count()").Tokenize()
+
+	_, err := NewHaving(tokens.Iterator(), true, context.NewContext(context.NewFunctions(), context.NewAttributes()))
+	if err == nil {
+		t.Fatalf("Expected an error given having with a function not supported in where")
+	}
+}
+
+func TestHavingWithMoreThanOneExpression(t *testing.T) {
+	tokens := tokenizer.NewTokenizer("having gt(count(), 1) eq(count(), 2)").Tokenize()
+
+	_, err := NewHaving(tokens.Iterator(), true, context.NewContext(context.NewFunctions(), context.NewAttributes()))
+	if err == nil {
+		t.Fatalf("Expected an error given having with more than one expression")
+	}
+}
+
+func TestHavingWithAFunctionWithoutClosingParentheses(t *testing.T) {
+	tokens := tokenizer.NewTokenizer("having gt(count(), 1 order by 1").Tokenize()
+
+	_, err := NewHaving(tokens.Iterator(), true, context.NewContext(context.NewFunctions(), context.NewAttributes()))
+	if err == nil {
+		t.Fatalf("Expected an error given having with a function without closing parentheses")
+	}
+}
+
+func TestHavingWithAnAggregateFunction(t *testing.T) {
+	tokens := tokenizer.NewTokenizer("having gt(count(), 1) order by 1").Tokenize()
+	iterator := tokens.Iterator()
+
+	having, _ := NewHaving(iterator, true, context.NewContext(context.NewFunctions(), context.NewAttributes()))
+	expected := "gt(count(),1)"
+
+	if expected != having.Display() {
+		t.Fatalf("Expected having clause to be %v, received %v", expected, having.Display())
+	}
+	if !iterator.Peek().Equals("order") {
+		t.Fatalf("Expected the next token to be %v, received %v", "order", iterator.Peek().TokenValue)
+	}
+}
+
+func TestHavingEvaluationAfterAggregation(t *testing.T) {
+	ctx := context.NewContext(context.NewFunctions(), context.NewAttributes())
+	tokens := tokenizer.NewTokenizer("having gt(count(), 1)").Tokenize()
+
+	having, _ := NewHaving(tokens.Iterator(), true, ctx)
+	_ = having.EvaluateWith(nil, ctx.AllFunctions())
+
+	passes, _ := having.FullyEvaluateWith(nil, ctx.AllFunctions())
+	if passes {
+		t.Fatalf("Expected having to be false after a single evaluation but was true")
+	}
+
+	_ = having.EvaluateWith(nil, ctx.AllFunctions())
+	passes, _ = having.FullyEvaluateWith(nil, ctx.AllFunctions())
+	if !passes {
+		t.Fatalf("Expected having to be true after two evaluations but was false")
+	}
+}
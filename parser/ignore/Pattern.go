@@ -0,0 +1,108 @@
+package ignore
+
+import (
+	"regexp"
+	"strings"
+)
+
+type pattern struct {
+	expression    *regexp.Regexp
+	negated       bool
+	directoryOnly bool
+}
+
+/*
+newPattern compiles a line of an ignore file, following the gitignore format:
+1. blank lines and lines starting with '#' are skipped, '\#' and '\!' escape the first character,
+2. trailing spaces are removed unless escaped with '\',
+3. '!' negates the pattern and re-includes a file excluded by an earlier pattern,
+4. a trailing '/' matches only directories,
+5. a pattern with a '/' at the beginning or in the middle is relative to the directory of the ignore file,
+otherwise it matches the name at any level below that directory,
+6. '*' matches anything except '/', '?' matches any one character except '/', '[a-z]' matches a character range,
+7. two consecutive asterisks match in all the directories when followed by a '/' at the beginning of the pattern,
+match everything inside when preceded by a '/' at the end of the pattern and match zero or more directories
+when surrounded by '/' in the middle of the pattern.
+*/
+func newPattern(line string) (*pattern, bool) {
+	line = strings.TrimRight(line, "\r")
+	line = trimTrailingSpaces(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return nil, false
+	}
+	negated := false
+	if strings.HasPrefix(line, "!") {
+		negated = true
+		line = line[1:]
+	} else if strings.HasPrefix(line, "\\!") || strings.HasPrefix(line, "\\#") {
+		line = line[1:]
+	}
+	directoryOnly := false
+	if strings.HasSuffix(line, "/") {
+		directoryOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	if line == "" {
+		return nil, false
+	}
+	anchored := strings.Contains(line, "/")
+	line = strings.TrimPrefix(line, "/")
+	if !anchored && !strings.HasPrefix(line, "**") {
+		line = "**/" + line
+	}
+	expression, err := regexp.Compile("^" + toRegularExpression(line) + "$")
+	if err != nil {
+		return nil, false
+	}
+	return &pattern{expression: expression, negated: negated, directoryOnly: directoryOnly}, true
+}
+
+func (pattern *pattern) matches(relativePath string, isDirectory bool) bool {
+	if pattern.directoryOnly && !isDirectory {
+		return false
+	}
+	return pattern.expression.MatchString(relativePath)
+}
+
+func trimTrailingSpaces(line string) string {
+	for strings.HasSuffix(line, " ") && !strings.HasSuffix(line, "\\ ") {
+		line = line[0 : len(line)-1]
+	}
+	return line
+}
+
+func toRegularExpression(glob string) string {
+	var builder strings.Builder
+	for index := 0; index < len(glob); index++ {
+		character := glob[index]
+		switch {
+		case strings.HasPrefix(glob[index:], "**/") && (index == 0 || glob[index-1] == '/'):
+			builder.WriteString("(.*/)?")
+			index = index + 2
+		case glob[index:] == "**" && index > 0 && glob[index-1] == '/':
+			builder.WriteString(".*")
+			index = index + 1
+		case character == '*':
+			builder.WriteString("[^/]*")
+		case character == '?':
+			builder.WriteString("[^/]")
+		case character == '[':
+			if end := strings.IndexByte(glob[index+1:], ']'); end >= 0 {
+				class := glob[index+1 : index+1+end]
+				if strings.HasPrefix(class, "!") {
+					class = "^" + class[1:]
+				}
+				builder.WriteString("[" + strings.ReplaceAll(class, "\\", "\\\\") + "]")
+				index = index + 1 + end
+			} else {
+				builder.WriteString("\\[")
+			}
+		case character == '\\' && index+1 < len(glob):
+			index = index + 1
+			builder.WriteString(regexp.QuoteMeta(string(glob[index])))
+		default:
+			builder.WriteString(regexp.QuoteMeta(string(character)))
+		}
+	}
+	return builder.String()
+}
@@ -0,0 +1,82 @@
+//go:build unit
+// +build unit
+
+package ignore
+
+import "testing"
+
+func TestPatternSkipsBlankLinesAndComments(t *testing.T) {
+	for _, line := range []string{"", "   ", "# comment", "/"} {
+		if _, ok := newPattern(line); ok {
+			t.Fatalf("Expected %v to not be a pattern", line)
+		}
+	}
+}
+
+func TestPatternMatchesTheNameAtAnyLevel(t *testing.T) {
+	assertMatches(t, "*.log", "a.log", false, true)
+	assertMatches(t, "*.log", "logs/nested/a.log", false, true)
+	assertMatches(t, "*.log", "a.txt", false, false)
+}
+
+func TestAnchoredPatternMatchesRelativeToTheIgnoreFile(t *testing.T) {
+	assertMatches(t, "/build", "build", true, true)
+	assertMatches(t, "/build", "src/build", true, false)
+	assertMatches(t, "doc/*.txt", "doc/notes.txt", false, true)
+	assertMatches(t, "doc/*.txt", "doc/server/notes.txt", false, false)
+}
+
+func TestDirectoryOnlyPattern(t *testing.T) {
+	assertMatches(t, "target/", "target", true, true)
+	assertMatches(t, "target/", "target", false, false)
+	assertMatches(t, "target/", "module/target", true, true)
+}
+
+func TestPatternWithLeadingDoubleAsterisk(t *testing.T) {
+	assertMatches(t, "**/foo/bar", "foo/bar", false, true)
+	assertMatches(t, "**/foo/bar", "a/b/foo/bar", false, true)
+}
+
+func TestPatternWithTrailingDoubleAsterisk(t *testing.T) {
+	assertMatches(t, "abc/**", "abc/x/y.txt", false, true)
+	assertMatches(t, "abc/**", "abc", true, false)
+}
+
+func TestPatternWithDoubleAsteriskInTheMiddle(t *testing.T) {
+	assertMatches(t, "a/**/b", "a/b", false, true)
+	assertMatches(t, "a/**/b", "a/x/y/b", false, true)
+	assertMatches(t, "a/**/b", "x/a/b", false, false)
+}
+
+func TestPatternWithQuestionMarkAndCharacterClass(t *testing.T) {
+	assertMatches(t, "file?.[ch]", "file1.c", false, true)
+	assertMatches(t, "file?.[ch]", "file1.o", false, false)
+	assertMatches(t, "file[!0-9].c", "filea.c", false, true)
+	assertMatches(t, "file[!0-9].c", "file1.c", false, false)
+}
+
+func TestPatternWithEscapedCharacters(t *testing.T) {
+	assertMatches(t, "\\#notes", "#notes", false, true)
+	assertMatches(t, "\\!important", "!important", false, true)
+	assertMatches(t, "trailing\\ ", "trailing ", false, true)
+}
+
+func TestNegatedPattern(t *testing.T) {
+	pattern, _ := newPattern("!keep.log")
+	if !pattern.negated {
+		t.Fatalf("Expected pattern to be negated")
+	}
+	if !pattern.matches("keep.log", false) {
+		t.Fatalf("Expected negated pattern to match keep.log")
+	}
+}
+
+func assertMatches(t *testing.T, line string, relativePath string, isDirectory bool, expected bool) {
+	pattern, ok := newPattern(line)
+	if !ok {
+		t.Fatalf("Expected %v to be a pattern", line)
+	}
+	if matches := pattern.matches(relativePath, isDirectory); matches != expected {
+		t.Fatalf("Expected pattern %v matching %v to be %v, received %v", line, relativePath, expected, matches)
+	}
+}
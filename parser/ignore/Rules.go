@@ -0,0 +1,149 @@
+package ignore
+
+import (
+	"goselect/parser/filesystem"
+	"goselect/parser/git"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const gitDirectory = ".git"
+
+var ignoreFileNames = []string{".gitignore", ".ignore"}
+
+/*
+Rules are the ignore patterns that apply to a directory, read from the '.gitignore' and '.ignore' files of the
+directory and of all its parents up to the root of the git repository, and from '.git/info/exclude' of the repository.
+Rules form an immutable chain from a directory to its parent, so the same rules can be shared while traversing the
+directories concurrently.
+The patterns of a deeper directory take precedence over the patterns of its parents, the patterns of '.ignore' take
+precedence over the patterns of '.gitignore' in the same directory, and within a file, a later pattern takes
+precedence over an earlier one.
+Like git, a file can not be re-included if its parent directory is ignored, and the '.git' directory is always ignored.
+Like 'git ls-files --others --cached', a file that is tracked in the index of the repository is never ignored, and
+neither is a directory with a tracked file below it, even if they match a pattern.
+*/
+type Rules struct {
+	fileSystem     filesystem.FileSystem
+	directory      string
+	patterns       []*pattern
+	ignored        bool
+	parent         *Rules
+	repositoryRoot string
+	index          *git.Index
+}
+
+func NewRules(fileSystem filesystem.FileSystem, directory string) (*Rules, error) {
+	absolutePath, err := fileSystem.Abs(directory)
+	if err != nil {
+		return nil, err
+	}
+	repositoryRoot, isInRepository := repositoryRootOf(fileSystem, absolutePath)
+	if !isInRepository {
+		return rulesFor(fileSystem, absolutePath, nil, nil), nil
+	}
+	rules := rulesFor(
+		fileSystem,
+		repositoryRoot,
+		readPatterns(fileSystem, filepath.Join(repositoryRoot, gitDirectory, "info", "exclude")),
+		nil,
+	)
+	rules.repositoryRoot = repositoryRoot
+	if index, err := git.ReadIndex(fileSystem, repositoryRoot); err == nil {
+		rules.index = index
+	}
+	relativePath, err := filepath.Rel(repositoryRoot, absolutePath)
+	if err != nil || relativePath == "." {
+		return rules, nil
+	}
+	for _, name := range strings.Split(relativePath, string(os.PathSeparator)) {
+		rules = rules.ForDirectory(name)
+	}
+	return rules, nil
+}
+
+func (rules *Rules) ForDirectory(name string) *Rules {
+	child := rulesFor(rules.fileSystem, filepath.Join(rules.directory, name), nil, rules)
+	child.repositoryRoot, child.index = rules.repositoryRoot, rules.index
+	return child
+}
+
+func (rules *Rules) IsIgnored(name string, isDirectory bool) bool {
+	if name == gitDirectory {
+		return true
+	}
+	return rules.matchesAPattern(name, isDirectory) && !rules.isTracked(name, isDirectory)
+}
+
+func (rules *Rules) isTracked(name string, isDirectory bool) bool {
+	if rules.index == nil {
+		return false
+	}
+	relativePath, err := filepath.Rel(rules.repositoryRoot, filepath.Join(rules.directory, name))
+	if err != nil {
+		return false
+	}
+	return rules.index.IsTracked(filepath.ToSlash(relativePath), isDirectory)
+}
+
+/*
+matchesAPattern returns true if the entry or one of its parent directories matches an ignore pattern, regardless of
+the index.
+*/
+func (rules *Rules) matchesAPattern(name string, isDirectory bool) bool {
+	if rules.ignored || name == gitDirectory {
+		return true
+	}
+	path := filepath.Join(rules.directory, name)
+	for current := rules; current != nil; current = current.parent {
+		relativePath, err := filepath.Rel(current.directory, path)
+		if err != nil {
+			continue
+		}
+		relativePath = filepath.ToSlash(relativePath)
+		for index := len(current.patterns) - 1; index >= 0; index-- {
+			if current.patterns[index].matches(relativePath, isDirectory) {
+				return !current.patterns[index].negated
+			}
+		}
+	}
+	return false
+}
+
+func rulesFor(fileSystem filesystem.FileSystem, directory string, excludedPatterns []*pattern, parent *Rules) *Rules {
+	patterns := excludedPatterns
+	for _, fileName := range ignoreFileNames {
+		patterns = append(patterns, readPatterns(fileSystem, filepath.Join(directory, fileName))...)
+	}
+	ignored := false
+	if parent != nil {
+		ignored = parent.matchesAPattern(filepath.Base(directory), true)
+	}
+	return &Rules{fileSystem: fileSystem, directory: directory, patterns: patterns, ignored: ignored, parent: parent}
+}
+
+func readPatterns(fileSystem filesystem.FileSystem, filePath string) []*pattern {
+	content, err := filesystem.ReadFile(fileSystem, filePath)
+	if err != nil {
+		return nil
+	}
+	var patterns []*pattern
+	for _, line := range strings.Split(string(content), "\n") {
+		if pattern, ok := newPattern(line); ok {
+			patterns = append(patterns, pattern)
+		}
+	}
+	return patterns
+}
+
+func repositoryRootOf(fileSystem filesystem.FileSystem, directory string) (string, bool) {
+	for current := directory; ; current = filepath.Dir(current) {
+		if _, err := fileSystem.Stat(filepath.Join(current, gitDirectory)); err == nil {
+			return current, true
+		}
+		if filepath.Dir(current) == current {
+			return "", false
+		}
+	}
+}
@@ -0,0 +1,155 @@
+//go:build unit
+// +build unit
+
+package ignore
+
+import (
+	"goselect/parser/filesystem"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func repository(t *testing.T, files map[string]string) string {
+	directory := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(directory, ".git", "info"), 0755); err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	for name, content := range files {
+		path := filepath.Join(directory, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("error is %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("error is %v", err)
+		}
+	}
+	return directory
+}
+
+func newRules(t *testing.T, directory string) *Rules {
+	rules, err := NewRules(filesystem.Os(), directory)
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	return rules
+}
+
+func TestIgnoresTheGitDirectory(t *testing.T) {
+	rules := newRules(t, repository(t, nil))
+	if !rules.IsIgnored(".git", true) {
+		t.Fatalf("Expected .git to be ignored")
+	}
+}
+
+func TestIgnoresUsingGitIgnore(t *testing.T) {
+	rules := newRules(t, repository(t, map[string]string{".gitignore": "*.log\n"}))
+	if !rules.IsIgnored("a.log", false) {
+		t.Fatalf("Expected a.log to be ignored")
+	}
+	if rules.IsIgnored("a.txt", false) {
+		t.Fatalf("Expected a.txt to not be ignored")
+	}
+}
+
+func TestIgnoresUsingInfoExclude(t *testing.T) {
+	rules := newRules(t, repository(t, map[string]string{".git/info/exclude": "secrets\n"}))
+	if !rules.IsIgnored("secrets", false) {
+		t.Fatalf("Expected secrets to be ignored")
+	}
+}
+
+func TestNegationReIncludesAFile(t *testing.T) {
+	rules := newRules(t, repository(t, map[string]string{".gitignore": "*.log\n!keep.log\n"}))
+	if rules.IsIgnored("keep.log", false) {
+		t.Fatalf("Expected keep.log to not be ignored")
+	}
+}
+
+func TestIgnoreFileTakesPrecedenceOverGitIgnore(t *testing.T) {
+	rules := newRules(t, repository(t, map[string]string{".gitignore": "*.log\n", ".ignore": "!a.log\n"}))
+	if rules.IsIgnored("a.log", false) {
+		t.Fatalf("Expected a.log to not be ignored")
+	}
+}
+
+func TestNestedGitIgnoreTakesPrecedence(t *testing.T) {
+	directory := repository(t, map[string]string{".gitignore": "*.log\n", "logs/.gitignore": "!a.log\n"})
+	rules := newRules(t, directory).ForDirectory("logs")
+	if rules.IsIgnored("a.log", false) {
+		t.Fatalf("Expected logs/a.log to not be ignored")
+	}
+	if !rules.IsIgnored("b.log", false) {
+		t.Fatalf("Expected logs/b.log to be ignored")
+	}
+}
+
+func TestAnchoredPatternInANestedGitIgnore(t *testing.T) {
+	directory := repository(t, map[string]string{"src/.gitignore": "/generated\n"})
+	rules := newRules(t, directory)
+	if rules.IsIgnored("generated", true) {
+		t.Fatalf("Expected generated in the root to not be ignored")
+	}
+	if !rules.ForDirectory("src").IsIgnored("generated", true) {
+		t.Fatalf("Expected src/generated to be ignored")
+	}
+}
+
+func TestAFileCanNotBeReIncludedIfItsDirectoryIsIgnored(t *testing.T) {
+	directory := repository(t, map[string]string{".gitignore": "build/\n!build/keep.txt\n"})
+	rules := newRules(t, directory).ForDirectory("build")
+	if !rules.IsIgnored("keep.txt", false) {
+		t.Fatalf("Expected build/keep.txt to be ignored")
+	}
+}
+
+func TestRulesForASubdirectoryOfTheRepository(t *testing.T) {
+	directory := repository(t, map[string]string{".gitignore": "*.log\n", "src/.gitignore": "*.tmp\n"})
+	rules := newRules(t, filepath.Join(directory, "src"))
+	if !rules.IsIgnored("a.log", false) || !rules.IsIgnored("a.tmp", false) {
+		t.Fatalf("Expected the patterns of the repository root and the source directory to apply")
+	}
+}
+
+func gitRepository(t *testing.T, files map[string]string, trackedFiles ...string) string {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	directory := repository(t, files)
+	if err := os.RemoveAll(filepath.Join(directory, ".git")); err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	for _, arguments := range [][]string{{"init", "-q"}, append([]string{"add", "-f", "--"}, trackedFiles...)} {
+		command := exec.Command("git", arguments...)
+		command.Dir = directory
+		if output, err := command.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed with %v, %v", arguments, err, string(output))
+		}
+	}
+	return directory
+}
+
+func TestATrackedFileIsNotIgnoredEvenIfItMatchesAPattern(t *testing.T) {
+	rules := newRules(t, gitRepository(t, map[string]string{".gitignore": "*.log\n", "kept.log": "", "other.log": ""}, "kept.log"))
+	if rules.IsIgnored("kept.log", false) {
+		t.Fatalf("Expected the tracked kept.log to not be ignored")
+	}
+	if !rules.IsIgnored("other.log", false) {
+		t.Fatalf("Expected the untracked other.log to be ignored")
+	}
+}
+
+func TestAnIgnoredDirectoryWithATrackedFileIsNotIgnored(t *testing.T) {
+	rules := newRules(t, gitRepository(t, map[string]string{".gitignore": "build/\n", "build/kept.txt": "", "build/other.txt": ""}, "build/kept.txt"))
+	if rules.IsIgnored("build", true) {
+		t.Fatalf("Expected build with a tracked file to not be ignored")
+	}
+	build := rules.ForDirectory("build")
+	if build.IsIgnored("kept.txt", false) {
+		t.Fatalf("Expected the tracked build/kept.txt to not be ignored")
+	}
+	if !build.IsIgnored("other.txt", false) {
+		t.Fatalf("Expected the untracked build/other.txt to be ignored")
+	}
+}
@@ -0,0 +1,90 @@
+package media
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math"
+)
+
+const (
+	FormatMp4      = "mp4"
+	FormatMov      = "mov"
+	FormatMp3      = "mp3"
+	FormatWav      = "wav"
+	FormatFlac     = "flac"
+	FormatMatroska = "matroska"
+	FormatWebm     = "webm"
+)
+
+var ErrNotAudioVideo = errors.New("not a supported audio or video")
+
+/*
+AudioVideo is the metadata of an audio or a video, read from the headers of its container.
+Duration is in seconds, and BitRate is the average bit rate of the whole file in bits per second.
+The sample rate, the channels and the audio codec are of the first audio track, and the dimensions and the video
+codec are of the first video track. The codec names are the short names used by ffmpeg, like h264, hevc, aac and mp3.
+*/
+type AudioVideo struct {
+	Format     string
+	Duration   float64
+	BitRate    int64
+	SampleRate int64
+	Channels   int64
+	Width      int64
+	Height     int64
+	VideoCodec string
+	AudioCodec string
+}
+
+/*
+ReadAudioVideo reads the metadata of an mp4, a mov, an mp3, a wav, a flac, a matroska or a webm file from its headers,
+without reading the media data. An mp3 without a Xing or a VBRI header is assumed to have a constant bit rate,
+unless its ID3 tag has the length.
+*/
+func ReadAudioVideo(reader io.ReaderAt, size int64) (*AudioVideo, error) {
+	header, err := readAt(reader, 0, 12)
+	if err != nil {
+		return nil, ErrNotAudioVideo
+	}
+	var audioVideo *AudioVideo
+	switch {
+	case isIsoMediaAtom(string(header[4:8])):
+		audioVideo, err = readIsoMedia(reader, size)
+	case string(header[0:4]) == "RIFF" && string(header[8:12]) == "WAVE":
+		audioVideo, err = readWav(reader)
+	case bytes.Equal(header[0:4], ebmlHeaderId):
+		audioVideo, err = readMatroska(reader, size)
+	case string(header[0:4]) == "fLaC":
+		audioVideo, err = readFlac(reader, 0)
+	case string(header[0:3]) == "ID3":
+		audioVideo, err = readAfterId3(reader, size)
+	case isMpegAudioFrame(header):
+		audioVideo, err = readMp3(reader, size, 0, 0)
+	default:
+		return nil, ErrNotAudioVideo
+	}
+	if err != nil {
+		return nil, err
+	}
+	audioVideo.Duration = math.Round(audioVideo.Duration*1000) / 1000
+	if audioVideo.BitRate == 0 && audioVideo.Duration > 0 {
+		audioVideo.BitRate = int64(math.Round(float64(size*8) / audioVideo.Duration))
+	}
+	return audioVideo, nil
+}
+
+func readAfterId3(reader io.ReaderAt, size int64) (*AudioVideo, error) {
+	tag, err := readId3(reader)
+	if err != nil {
+		return nil, err
+	}
+	header, err := readAt(reader, tag.size, 4)
+	if err != nil {
+		return nil, err
+	}
+	if string(header) == "fLaC" {
+		return readFlac(reader, tag.size)
+	}
+	return readMp3(reader, size, tag.size, tag.length)
+}
@@ -0,0 +1,361 @@
+//go:build unit
+// +build unit
+
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func atom(kind string, contents ...[]byte) []byte {
+	body := bytes.Join(contents, nil)
+	result := make([]byte, 8, 8+len(body))
+	binary.BigEndian.PutUint32(result[0:4], uint32(8+len(body)))
+	copy(result[4:8], kind)
+	return append(result, body...)
+}
+
+func movieHeader(timeScale uint32, duration uint32) []byte {
+	header := make([]byte, 100)
+	binary.BigEndian.PutUint32(header[12:16], timeScale)
+	binary.BigEndian.PutUint32(header[16:20], duration)
+	return atom("mvhd", header)
+}
+
+func track(handler string, sampleEntry []byte) []byte {
+	handlerReference := make([]byte, 25)
+	copy(handlerReference[8:12], handler)
+	sampleDescription := append([]byte{0, 0, 0, 0, 0, 0, 0, 1}, sampleEntry...)
+	return atom("trak", atom("mdia", atom("hdlr", handlerReference), atom("minf", atom("stbl", atom("stsd", sampleDescription)))))
+}
+
+func videoSampleEntry(fourcc string, width uint16, height uint16) []byte {
+	entry := make([]byte, 78)
+	binary.BigEndian.PutUint16(entry[24:26], width)
+	binary.BigEndian.PutUint16(entry[26:28], height)
+	return atom(fourcc, entry)
+}
+
+func audioSampleEntry(fourcc string, channels uint16, sampleRate uint16) []byte {
+	entry := make([]byte, 28)
+	binary.BigEndian.PutUint16(entry[16:18], channels)
+	binary.BigEndian.PutUint16(entry[24:26], sampleRate)
+	return atom(fourcc, entry)
+}
+
+func mp4(brand string) []byte {
+	return bytes.Join([][]byte{
+		atom("ftyp", []byte(brand), make([]byte, 4)),
+		atom("mdat", make([]byte, 5000)),
+		atom("moov",
+			movieHeader(1000, 12500),
+			track("vide", videoSampleEntry("avc1", 1920, 1080)),
+			track("soun", audioSampleEntry("mp4a", 2, 48000)),
+		),
+	}, nil)
+}
+
+func ebml(id uint32, contents ...[]byte) []byte {
+	var result bytes.Buffer
+	idBytes := make([]byte, 4)
+	binary.BigEndian.PutUint32(idBytes, id)
+	result.Write(bytes.TrimLeft(idBytes, "\x00"))
+	body := bytes.Join(contents, nil)
+	size := make([]byte, 8)
+	binary.BigEndian.PutUint64(size, uint64(len(body)))
+	size[0] = 0x01
+	result.Write(size)
+	result.Write(body)
+	return result.Bytes()
+}
+
+func bigEndian64(value uint64) []byte {
+	result := make([]byte, 8)
+	binary.BigEndian.PutUint64(result, value)
+	return result
+}
+
+func bigEndian32(value uint32) []byte {
+	result := make([]byte, 4)
+	binary.BigEndian.PutUint32(result, value)
+	return result
+}
+
+func littleEndian32(value uint32) []byte {
+	result := make([]byte, 4)
+	binary.LittleEndian.PutUint32(result, value)
+	return result
+}
+
+func ebmlUnsigned(value uint64) []byte {
+	return bytes.TrimLeft(bigEndian64(value), "\x00")
+}
+
+func ebmlFloat64(value float64) []byte {
+	return bigEndian64(math.Float64bits(value))
+}
+
+func webm(segmentSize []byte) []byte {
+	header := ebml(0x1a45dfa3, ebml(ebmlDocType, []byte("webm")))
+	segment := bytes.Join([][]byte{
+		ebml(matroskaInfo,
+			ebml(matroskaTimecode, ebmlUnsigned(1000000)),
+			ebml(matroskaDuration, ebmlFloat64(8500)),
+		),
+		ebml(matroskaTracks,
+			ebml(matroskaTrackEntry,
+				ebml(matroskaTrackType, ebmlUnsigned(matroskaTrackTypeVideo)),
+				ebml(matroskaCodecId, []byte("V_VP9")),
+				ebml(matroskaVideo, ebml(matroskaPixelWidth, ebmlUnsigned(1280)), ebml(matroskaPixelHeight, ebmlUnsigned(720))),
+			),
+			ebml(matroskaTrackEntry,
+				ebml(matroskaTrackType, ebmlUnsigned(matroskaTrackTypeAudio)),
+				ebml(matroskaCodecId, []byte("A_OPUS")),
+				ebml(matroskaAudio, ebml(matroskaSamplingRate, ebmlFloat64(48000)), ebml(matroskaChannels, ebmlUnsigned(2))),
+			),
+		),
+		ebml(0x1f43b675, make([]byte, 2000)),
+	}, nil)
+	if segmentSize == nil {
+		return append(header, ebml(matroskaSegment, segment)...)
+	}
+	return bytes.Join([][]byte{header, {0x18, 0x53, 0x80, 0x67}, segmentSize, segment}, nil)
+}
+
+func wav(formatTag uint16, channels uint16, sampleRate uint32, bitsPerSample uint16, dataSize int) []byte {
+	format := make([]byte, 16)
+	binary.LittleEndian.PutUint16(format[0:2], formatTag)
+	binary.LittleEndian.PutUint16(format[2:4], channels)
+	binary.LittleEndian.PutUint32(format[4:8], sampleRate)
+	binary.LittleEndian.PutUint32(format[8:12], sampleRate*uint32(channels)*uint32(bitsPerSample/8))
+	binary.LittleEndian.PutUint16(format[12:14], channels*bitsPerSample/8)
+	binary.LittleEndian.PutUint16(format[14:16], bitsPerSample)
+
+	var body bytes.Buffer
+	body.WriteString("WAVE")
+	for _, chunk := range []struct {
+		kind     string
+		contents []byte
+	}{{"LIST", []byte("INFOISFT")}, {"fmt ", format}, {"data", make([]byte, dataSize)}} {
+		body.WriteString(chunk.kind)
+		_ = binary.Write(&body, binary.LittleEndian, uint32(len(chunk.contents)))
+		body.Write(chunk.contents)
+	}
+	return append(append([]byte("RIFF"), littleEndian32(uint32(body.Len()))...), body.Bytes()...)
+}
+
+func flac(sampleRate uint64, channels uint64, totalSamples uint64) []byte {
+	streamInfo := make([]byte, 34)
+	binary.BigEndian.PutUint64(streamInfo[10:18], sampleRate<<44|(channels-1)<<41|15<<36|totalSamples)
+	return bytes.Join([][]byte{[]byte("fLaC"), {0x80, 0x00, 0x00, 0x22}, streamInfo, make([]byte, 1000)}, nil)
+}
+
+/*
+mp3Frames returns the frames of an MPEG 1 layer III mono stream at 128 kbps and 44.1 kHz, 417 bytes each.
+*/
+func mp3Frames(count int, firstFrame func(frame []byte)) []byte {
+	var frames bytes.Buffer
+	for index := 0; index < count; index++ {
+		frame := make([]byte, 417)
+		copy(frame, []byte{0xff, 0xfb, 0x90, 0xc4})
+		if index == 0 && firstFrame != nil {
+			firstFrame(frame)
+		}
+		frames.Write(frame)
+	}
+	return frames.Bytes()
+}
+
+func id3WithLength(milliseconds string) []byte {
+	frame := append([]byte("TLEN"), bigEndian32(uint32(len(milliseconds)+1))...)
+	frame = append(append(append(frame, 0, 0), 0), milliseconds...)
+	padding := make([]byte, 20)
+	size := len(frame) + len(padding)
+	header := []byte{'I', 'D', '3', 3, 0, 0, 0, 0, byte(size >> 7), byte(size & 0x7f)}
+	return bytes.Join([][]byte{header, frame, padding}, nil)
+}
+
+func readAudioVideo(t *testing.T, content []byte) *AudioVideo {
+	audioVideo, err := ReadAudioVideo(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		t.Fatalf("Expected no error while reading the audio or video, received %v", err)
+	}
+	return audioVideo
+}
+
+func assertAudioVideo(t *testing.T, actual *AudioVideo, expected *AudioVideo) {
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("Expected audio or video to be %+v, received %+v", expected, actual)
+	}
+}
+
+func TestReadMp4(t *testing.T) {
+	content := mp4("isom")
+	assertAudioVideo(t, readAudioVideo(t, content), &AudioVideo{
+		Format:     FormatMp4,
+		Duration:   12.5,
+		BitRate:    int64(math.Round(float64(len(content)*8) / 12.5)),
+		SampleRate: 48000,
+		Channels:   2,
+		Width:      1920,
+		Height:     1080,
+		VideoCodec: "h264",
+		AudioCodec: "aac",
+	})
+}
+
+func TestReadMov(t *testing.T) {
+	if format := readAudioVideo(t, mp4("qt  ")).Format; format != FormatMov {
+		t.Fatalf("Expected format to be %v, received %v", FormatMov, format)
+	}
+}
+
+func TestReadMp4TruncatedAfterTheMovie(t *testing.T) {
+	content := append(mp4("isom"), atom("mdat", make([]byte, 100))...)
+	if duration := readAudioVideo(t, content[0:len(content)-50]).Duration; duration != 12.5 {
+		t.Fatalf("Expected duration to be %v, received %v", 12.5, duration)
+	}
+}
+
+func TestReadMp4WithoutAMovie(t *testing.T) {
+	content := atom("ftyp", []byte("isom"), make([]byte, 4))
+	if _, err := ReadAudioVideo(bytes.NewReader(content), int64(len(content))); err == nil {
+		t.Fatalf("Expected an error while reading an mp4 without a movie atom")
+	}
+}
+
+func TestReadWebm(t *testing.T) {
+	content := webm(nil)
+	assertAudioVideo(t, readAudioVideo(t, content), &AudioVideo{
+		Format:     FormatWebm,
+		Duration:   8.5,
+		BitRate:    int64(math.Round(float64(len(content)*8) / 8.5)),
+		SampleRate: 48000,
+		Channels:   2,
+		Width:      1280,
+		Height:     720,
+		VideoCodec: "vp9",
+		AudioCodec: "opus",
+	})
+}
+
+func TestReadWebmWithASegmentOfUnknownSize(t *testing.T) {
+	audioVideo := readAudioVideo(t, webm([]byte{0x01, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}))
+	if audioVideo.Duration != 8.5 || audioVideo.VideoCodec != "vp9" || audioVideo.AudioCodec != "opus" {
+		t.Fatalf("Expected a webm of 8.5 seconds with vp9 and opus, received %+v", audioVideo)
+	}
+}
+
+func TestReadWav(t *testing.T) {
+	assertAudioVideo(t, readAudioVideo(t, wav(waveFormatPcm, 2, 44100, 16, 44100*4*3)), &AudioVideo{
+		Format:     FormatWav,
+		Duration:   3,
+		BitRate:    1411200,
+		SampleRate: 44100,
+		Channels:   2,
+		AudioCodec: "pcm_s16le",
+	})
+}
+
+func TestReadWavWithFloatSamples(t *testing.T) {
+	if codec := readAudioVideo(t, wav(waveFormatFloat, 1, 48000, 32, 4800)).AudioCodec; codec != "pcm_f32le" {
+		t.Fatalf("Expected audio codec to be %v, received %v", "pcm_f32le", codec)
+	}
+}
+
+func TestReadFlac(t *testing.T) {
+	content := flac(48000, 2, 48000*90)
+	assertAudioVideo(t, readAudioVideo(t, content), &AudioVideo{
+		Format:     FormatFlac,
+		Duration:   90,
+		BitRate:    int64(math.Round(float64(len(content)*8) / 90)),
+		SampleRate: 48000,
+		Channels:   2,
+		AudioCodec: "flac",
+	})
+}
+
+func TestReadFlacAfterAnId3Tag(t *testing.T) {
+	if duration := readAudioVideo(t, append(id3WithLength("1000"), flac(44100, 2, 44100*2)...)).Duration; duration != 2 {
+		t.Fatalf("Expected duration to be %v, received %v", 2, duration)
+	}
+}
+
+func TestReadMp3WithAConstantBitRate(t *testing.T) {
+	assertAudioVideo(t, readAudioVideo(t, mp3Frames(300, nil)), &AudioVideo{
+		Format:     FormatMp3,
+		Duration:   7.819,
+		BitRate:    128000,
+		SampleRate: 44100,
+		Channels:   1,
+		AudioCodec: "mp3",
+	})
+}
+
+func TestReadMp3WithAXingHeader(t *testing.T) {
+	content := mp3Frames(10, func(frame []byte) {
+		copy(frame[4+17:], "Xing")
+		binary.BigEndian.PutUint32(frame[4+17+4:], 0x01)
+		binary.BigEndian.PutUint32(frame[4+17+8:], 1000)
+	})
+	if duration := readAudioVideo(t, content).Duration; duration != 26.122 {
+		t.Fatalf("Expected duration to be %v, received %v", 26.122, duration)
+	}
+}
+
+func TestReadMp3WithTheLengthInTheId3Tag(t *testing.T) {
+	audioVideo := readAudioVideo(t, append(id3WithLength("185250"), mp3Frames(20, nil)...))
+	if audioVideo.Format != FormatMp3 || audioVideo.Duration != 185.25 {
+		t.Fatalf("Expected an mp3 of %v seconds, received %+v", 185.25, audioVideo)
+	}
+}
+
+func TestReadAudioVideoOfANonMediaFile(t *testing.T) {
+	content := []byte("just a text file, not a media file")
+	if _, err := ReadAudioVideo(bytes.NewReader(content), int64(len(content))); err != ErrNotAudioVideo {
+		t.Fatalf("Expected error %v, received %v", ErrNotAudioVideo, err)
+	}
+}
+
+func TestReadWebmTruncatedInsideAnElementHeader(t *testing.T) {
+	content := []byte("\x1aE\xdf\xa3\x84B\x8200000")
+	if _, err := ReadAudioVideo(bytes.NewReader(content), int64(len(content))); err == nil {
+		t.Fatalf("Expected an error while reading a webm truncated inside an element header")
+	}
+}
+
+func mediaSamples() [][]byte {
+	return [][]byte{
+		mp4("isom"),
+		webm(nil),
+		webm([]byte{0x01, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}),
+		wav(waveFormatPcm, 2, 44100, 16, 64),
+		flac(44100, 2, 44100*2),
+		append(id3WithLength("1000"), flac(44100, 2, 44100*2)...),
+		mp3Frames(3, nil),
+		append(id3WithLength("185250"), mp3Frames(2, nil)...),
+	}
+}
+
+func TestReadTruncatedAudioVideo(t *testing.T) {
+	for _, sample := range mediaSamples() {
+		for length := 0; length < len(sample); length++ {
+			truncated := sample[0:length]
+			_, _ = ReadAudioVideo(bytes.NewReader(truncated), int64(len(truncated)))
+		}
+	}
+}
+
+func FuzzReadAudioVideo(f *testing.F) {
+	for _, sample := range mediaSamples() {
+		f.Add(sample)
+	}
+	f.Add([]byte("\x1aE\xdf\xa3\x84B\x8200000"))
+	f.Fuzz(func(t *testing.T, content []byte) {
+		_, _ = ReadAudioVideo(bytes.NewReader(content), int64(len(content)))
+	})
+}
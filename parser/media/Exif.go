@@ -0,0 +1,107 @@
+package media
+
+import (
+	"bytes"
+	"io"
+	"time"
+)
+
+const (
+	tagMake               = 0x010f
+	tagModel              = 0x0110
+	tagOrientation        = 0x0112
+	tagDateTime           = 0x0132
+	tagExifIfd            = 0x8769
+	tagGpsIfd             = 0x8825
+	tagDateTimeOriginal   = 0x9003
+	tagOffsetTime         = 0x9010
+	tagOffsetTimeOriginal = 0x9011
+	tagGpsLatitude        = 0x0002
+	tagGpsLongitude       = 0x0004
+
+	exifDateTimeLayout = "2006:01:02 15:04:05"
+	exifOffsetLayout   = "-07:00"
+)
+
+var exifHeader = []byte("Exif\x00\x00")
+
+/*
+Exif are the EXIF fields of an image.
+DateTime is the time the photo was taken, read from DateTimeOriginal and falling back to DateTime of the image,
+in the local time zone unless the EXIF block has the offset of the time. DateTime is zero if the image does not have it.
+Orientation is from 1 to 8 as defined by EXIF, and 0 if the image does not have it.
+HasGps is true if the GPS block of the image has both the latitude and the longitude.
+*/
+type Exif struct {
+	DateTime    time.Time
+	Make        string
+	Model       string
+	Orientation int
+	HasGps      bool
+}
+
+/*
+readExif reads the EXIF block stored in a segment or a chunk of the image, starting at the offset with the given length.
+Some of the encoders prefix the block with the EXIF header of a JPEG segment, the prefix is skipped.
+*/
+func readExif(reader io.ReaderAt, offset int64, length int64) (Exif, bool) {
+	if prefix, err := readAt(reader, offset, len(exifHeader)); err == nil && bytes.Equal(prefix, exifHeader) {
+		offset, length = offset+int64(len(exifHeader)), length-int64(len(exifHeader))
+	}
+	structure, err := newTiff(io.NewSectionReader(reader, offset, length))
+	if err != nil {
+		return Exif{}, false
+	}
+	return structure.exif()
+}
+
+func (tiff *tiff) exif() (Exif, bool) {
+	ifd0, err := tiff.readIfd(tiff.ifd0)
+	if err != nil {
+		return Exif{}, false
+	}
+	exif := Exif{
+		Make:     tiff.string(ifd0, tagMake),
+		Model:    tiff.string(ifd0, tagModel),
+		DateTime: parseExifDateTime(tiff.string(ifd0, tagDateTime), ""),
+	}
+	if orientation, ok := tiff.uint(ifd0, tagOrientation); ok {
+		exif.Orientation = int(orientation)
+	}
+	if offset, ok := tiff.uint(ifd0, tagExifIfd); ok {
+		if exifIfd, err := tiff.readIfd(offset); err == nil {
+			if dateTime := parseExifDateTime(
+				tiff.string(exifIfd, tagDateTimeOriginal),
+				tiff.string(exifIfd, tagOffsetTimeOriginal),
+			); !dateTime.IsZero() {
+				exif.DateTime = dateTime
+			} else if !exif.DateTime.IsZero() {
+				exif.DateTime = parseExifDateTime(tiff.string(ifd0, tagDateTime), tiff.string(exifIfd, tagOffsetTime))
+			}
+		}
+	}
+	if offset, ok := tiff.uint(ifd0, tagGpsIfd); ok {
+		if gpsIfd, err := tiff.readIfd(offset); err == nil {
+			_, hasLatitude := gpsIfd[tagGpsLatitude]
+			_, hasLongitude := gpsIfd[tagGpsLongitude]
+			exif.HasGps = hasLatitude && hasLongitude
+		}
+	}
+	return exif, true
+}
+
+func parseExifDateTime(dateTime string, offset string) time.Time {
+	if dateTime == "" {
+		return time.Time{}
+	}
+	location := time.Local
+	if zone, err := time.Parse(exifOffsetLayout, offset); err == nil {
+		_, seconds := zone.Zone()
+		location = time.FixedZone(offset, seconds)
+	}
+	parsed, err := time.ParseInLocation(exifDateTimeLayout, dateTime, location)
+	if err != nil {
+		return time.Time{}
+	}
+	return parsed
+}
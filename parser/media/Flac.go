@@ -0,0 +1,34 @@
+package media
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+const flacStreamInfo = 0
+
+/*
+readFlac reads the STREAMINFO block, the first metadata block after the "fLaC" marker, which has the sample rate,
+the channels and the total number of samples of the stream.
+*/
+func readFlac(reader io.ReaderAt, start int64) (*AudioVideo, error) {
+	block, err := readAt(reader, start+4, 4+18)
+	if err != nil {
+		return nil, err
+	}
+	if block[0]&0x7f != flacStreamInfo {
+		return nil, errors.New("flac without a stream info block")
+	}
+	streamInfo := block[4:]
+	packed := binary.BigEndian.Uint64(streamInfo[10:18])
+	sampleRate := int64(packed >> 44)
+	channels := int64((packed>>41)&0x07) + 1
+	totalSamples := int64(packed & 0xfffffffff)
+
+	audioVideo := &AudioVideo{Format: FormatFlac, SampleRate: sampleRate, Channels: channels, AudioCodec: "flac"}
+	if sampleRate > 0 {
+		audioVideo.Duration = float64(totalSamples) / float64(sampleRate)
+	}
+	return audioVideo, nil
+}
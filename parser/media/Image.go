@@ -0,0 +1,244 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+const (
+	FormatJpeg = "jpeg"
+	FormatPng  = "png"
+	FormatGif  = "gif"
+	FormatBmp  = "bmp"
+	FormatWebp = "webp"
+	FormatTiff = "tiff"
+
+	tagImageWidth  = 0x0100
+	tagImageLength = 0x0101
+)
+
+var ErrNotAnImage = errors.New("not a supported image")
+
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+type Image struct {
+	Format string
+	Width  int64
+	Height int64
+	Exif   Exif
+}
+
+/*
+ReadImage reads the format, the dimensions and the EXIF fields of a jpeg, png, gif, bmp, webp or a tiff image from
+its headers, without decoding the pixels. The dimensions are the stored dimensions, before applying the EXIF
+orientation. A jpeg is read up to its frame header, and a png up to its first image data chunk.
+*/
+func ReadImage(reader io.ReaderAt) (*Image, error) {
+	header, err := readAt(reader, 0, 12)
+	if err != nil {
+		return nil, ErrNotAnImage
+	}
+	switch {
+	case header[0] == 0xff && header[1] == 0xd8:
+		return readJpeg(reader)
+	case bytes.HasPrefix(header, pngSignature):
+		return readPng(reader)
+	case string(header[0:6]) == "GIF87a" || string(header[0:6]) == "GIF89a":
+		return readGif(reader)
+	case string(header[0:2]) == "BM":
+		return readBmp(reader)
+	case string(header[0:4]) == "RIFF" && string(header[8:12]) == "WEBP":
+		return readWebp(reader)
+	case isTiff(header):
+		return readTiff(reader)
+	}
+	return nil, ErrNotAnImage
+}
+
+func readJpeg(reader io.ReaderAt) (*Image, error) {
+	image := &Image{Format: FormatJpeg}
+	for offset := int64(2); ; {
+		marker, err := readAt(reader, offset, 4)
+		if err != nil {
+			return nil, err
+		}
+		if marker[0] != 0xff {
+			return nil, errors.New("invalid jpeg marker")
+		}
+		kind := marker[1]
+		switch {
+		case kind == 0xff:
+			offset = offset + 1
+			continue
+		case kind == 0x01 || (kind >= 0xd0 && kind <= 0xd7):
+			offset = offset + 2
+			continue
+		case kind == 0xd9 || kind == 0xda:
+			return nil, errors.New("jpeg without a frame header")
+		}
+		length := int64(binary.BigEndian.Uint16(marker[2:4]))
+		if length < 2 {
+			return nil, errors.New("invalid jpeg segment length")
+		}
+		switch {
+		case kind == 0xe1:
+			if exif, ok := readExif(reader, offset+4, length-2); ok {
+				image.Exif = exif
+			}
+		case isStartOfFrame(kind):
+			frame, err := readAt(reader, offset+4, 5)
+			if err != nil {
+				return nil, err
+			}
+			image.Height = int64(binary.BigEndian.Uint16(frame[1:3]))
+			image.Width = int64(binary.BigEndian.Uint16(frame[3:5]))
+			return image, nil
+		}
+		offset = offset + 2 + length
+	}
+}
+
+func isStartOfFrame(marker byte) bool {
+	return marker >= 0xc0 && marker <= 0xcf && marker != 0xc4 && marker != 0xc8 && marker != 0xcc
+}
+
+func readPng(reader io.ReaderAt) (*Image, error) {
+	image := &Image{Format: FormatPng}
+	for offset, isFirst := int64(len(pngSignature)), true; ; isFirst = false {
+		chunk, err := readAt(reader, offset, 8)
+		if err != nil {
+			return nil, err
+		}
+		length, kind := int64(binary.BigEndian.Uint32(chunk[0:4])), string(chunk[4:8])
+		switch {
+		case isFirst && kind != "IHDR":
+			return nil, errors.New("png without a header chunk")
+		case kind == "IHDR":
+			dimensions, err := readAt(reader, offset+8, 8)
+			if err != nil {
+				return nil, err
+			}
+			image.Width = int64(binary.BigEndian.Uint32(dimensions[0:4]))
+			image.Height = int64(binary.BigEndian.Uint32(dimensions[4:8]))
+		case kind == "eXIf":
+			if exif, ok := readExif(reader, offset+8, length); ok {
+				image.Exif = exif
+			}
+		case kind == "IDAT" || kind == "IEND":
+			return image, nil
+		}
+		offset = offset + 12 + length
+	}
+}
+
+func readGif(reader io.ReaderAt) (*Image, error) {
+	screen, err := readAt(reader, 6, 4)
+	if err != nil {
+		return nil, err
+	}
+	return &Image{
+		Format: FormatGif,
+		Width:  int64(binary.LittleEndian.Uint16(screen[0:2])),
+		Height: int64(binary.LittleEndian.Uint16(screen[2:4])),
+	}, nil
+}
+
+func readBmp(reader io.ReaderAt) (*Image, error) {
+	header, err := readAt(reader, 14, 12)
+	if err != nil {
+		return nil, err
+	}
+	if binary.LittleEndian.Uint32(header[0:4]) == 12 {
+		return &Image{
+			Format: FormatBmp,
+			Width:  int64(binary.LittleEndian.Uint16(header[4:6])),
+			Height: int64(binary.LittleEndian.Uint16(header[6:8])),
+		}, nil
+	}
+	height := int64(int32(binary.LittleEndian.Uint32(header[8:12])))
+	if height < 0 {
+		height = -height
+	}
+	return &Image{
+		Format: FormatBmp,
+		Width:  int64(int32(binary.LittleEndian.Uint32(header[4:8]))),
+		Height: height,
+	}, nil
+}
+
+/*
+readWebp reads the chunks of a webp image. The canvas size of the extended format takes precedence over the size of
+the lossy or the lossless bitstream, and the EXIF chunk of the extended format is stored after the bitstream.
+*/
+func readWebp(reader io.ReaderAt) (*Image, error) {
+	image := &Image{Format: FormatWebp}
+	hasDimensions := false
+	for offset := int64(12); ; {
+		chunk, err := readAt(reader, offset, 8)
+		if err != nil {
+			if hasDimensions {
+				return image, nil
+			}
+			return nil, err
+		}
+		length := int64(binary.LittleEndian.Uint32(chunk[4:8]))
+		switch string(chunk[0:4]) {
+		case "VP8X":
+			canvas, err := readAt(reader, offset+8, 10)
+			if err != nil {
+				return nil, err
+			}
+			image.Width, image.Height = 1+int64(uint24(canvas[4:7])), 1+int64(uint24(canvas[7:10]))
+			hasDimensions = true
+		case "VP8 ":
+			frame, err := readAt(reader, offset+8, 10)
+			if err != nil {
+				return nil, err
+			}
+			if !hasDimensions && bytes.Equal(frame[3:6], []byte{0x9d, 0x01, 0x2a}) {
+				image.Width = int64(binary.LittleEndian.Uint16(frame[6:8]) & 0x3fff)
+				image.Height = int64(binary.LittleEndian.Uint16(frame[8:10]) & 0x3fff)
+				hasDimensions = true
+			}
+		case "VP8L":
+			frame, err := readAt(reader, offset+8, 5)
+			if err != nil {
+				return nil, err
+			}
+			if !hasDimensions && frame[0] == 0x2f {
+				bits := binary.LittleEndian.Uint32(frame[1:5])
+				image.Width, image.Height = 1+int64(bits&0x3fff), 1+int64((bits>>14)&0x3fff)
+				hasDimensions = true
+			}
+		case "EXIF":
+			if exif, ok := readExif(reader, offset+8, length); ok {
+				image.Exif = exif
+			}
+		}
+		offset = offset + 8 + length + length%2
+	}
+}
+
+func readTiff(reader io.ReaderAt) (*Image, error) {
+	structure, err := newTiff(reader)
+	if err != nil {
+		return nil, err
+	}
+	ifd0, err := structure.readIfd(structure.ifd0)
+	if err != nil {
+		return nil, err
+	}
+	width, _ := structure.uint(ifd0, tagImageWidth)
+	height, _ := structure.uint(ifd0, tagImageLength)
+	image := &Image{Format: FormatTiff, Width: int64(width), Height: int64(height)}
+	if exif, ok := structure.exif(); ok {
+		image.Exif = exif
+	}
+	return image, nil
+}
+
+func uint24(value []byte) uint32 {
+	return uint32(value[0]) | uint32(value[1])<<8 | uint32(value[2])<<16
+}
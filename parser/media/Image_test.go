@@ -0,0 +1,292 @@
+//go:build unit
+// +build unit
+
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"testing"
+	"time"
+)
+
+type tiffEntry struct {
+	tag      uint16
+	kind     uint16
+	count    uint32
+	data     []byte
+	pointsTo int
+}
+
+/*
+buildTiff lays out the directories one after the other after the header, followed by the values that do not fit in
+an entry. An entry with pointsTo greater than 0 holds the offset of that directory.
+*/
+func buildTiff(order binary.ByteOrder, directories ...[]tiffEntry) []byte {
+	offsets := make([]uint32, len(directories))
+	offset := uint32(8)
+	for index, directory := range directories {
+		offsets[index] = offset
+		offset = offset + 2 + uint32(len(directory))*12 + 4
+	}
+	var values []byte
+	var result bytes.Buffer
+	if order == binary.LittleEndian {
+		result.WriteString("II")
+	} else {
+		result.WriteString("MM")
+	}
+	_ = binary.Write(&result, order, uint16(42))
+	_ = binary.Write(&result, order, offsets[0])
+	for _, directory := range directories {
+		_ = binary.Write(&result, order, uint16(len(directory)))
+		for _, entry := range directory {
+			_ = binary.Write(&result, order, entry.tag)
+			_ = binary.Write(&result, order, entry.kind)
+			_ = binary.Write(&result, order, entry.count)
+			value := make([]byte, 4)
+			switch {
+			case entry.pointsTo > 0:
+				order.PutUint32(value, offsets[entry.pointsTo])
+			case len(entry.data) > 4:
+				order.PutUint32(value, offset+uint32(len(values)))
+				values = append(values, entry.data...)
+			default:
+				copy(value, entry.data)
+			}
+			result.Write(value)
+		}
+		_ = binary.Write(&result, order, uint32(0))
+	}
+	result.Write(values)
+	return result.Bytes()
+}
+
+func asciiEntry(tag uint16, value string) tiffEntry {
+	return tiffEntry{tag: tag, kind: tiffTypeAscii, count: uint32(len(value) + 1), data: append([]byte(value), 0)}
+}
+
+func shortEntry(order binary.ByteOrder, tag uint16, value uint16) tiffEntry {
+	data := make([]byte, 2)
+	order.PutUint16(data, value)
+	return tiffEntry{tag: tag, kind: tiffTypeShort, count: 1, data: data}
+}
+
+func longEntry(order binary.ByteOrder, tag uint16, value uint32) tiffEntry {
+	data := make([]byte, 4)
+	order.PutUint32(data, value)
+	return tiffEntry{tag: tag, kind: tiffTypeLong, count: 1, data: data}
+}
+
+func pointerEntry(tag uint16, directory int) tiffEntry {
+	return tiffEntry{tag: tag, kind: tiffTypeLong, count: 1, pointsTo: directory}
+}
+
+func cameraExif(order binary.ByteOrder, extraEntries ...tiffEntry) []byte {
+	return buildTiff(
+		order,
+		append([]tiffEntry{
+			asciiEntry(tagMake, "Canon"),
+			asciiEntry(tagModel, "Canon EOS R5"),
+			shortEntry(order, tagOrientation, 6),
+			asciiEntry(tagDateTime, "2022:08:01 10:00:00"),
+			pointerEntry(tagExifIfd, 1),
+			pointerEntry(tagGpsIfd, 2),
+		}, extraEntries...),
+		[]tiffEntry{
+			asciiEntry(tagDateTimeOriginal, "2022:07:30 18:45:12"),
+			asciiEntry(tagOffsetTimeOriginal, "+05:30"),
+		},
+		[]tiffEntry{
+			{tag: tagGpsLatitude, kind: 5, count: 3, data: make([]byte, 24)},
+			{tag: tagGpsLongitude, kind: 5, count: 3, data: make([]byte, 24)},
+		},
+	)
+}
+
+func expectedCameraExif() Exif {
+	return Exif{
+		DateTime:    time.Date(2022, 7, 30, 18, 45, 12, 0, time.FixedZone("+05:30", 5*60*60+30*60)),
+		Make:        "Canon",
+		Model:       "Canon EOS R5",
+		Orientation: 6,
+		HasGps:      true,
+	}
+}
+
+func encodedImage(encode func(buffer *bytes.Buffer, image image.Image) error) []byte {
+	var buffer bytes.Buffer
+	if err := encode(&buffer, image.NewRGBA(image.Rect(0, 0, 64, 48))); err != nil {
+		panic(err)
+	}
+	return buffer.Bytes()
+}
+
+func jpegWithExif(exif []byte) []byte {
+	encoded := encodedImage(func(buffer *bytes.Buffer, image image.Image) error {
+		return jpeg.Encode(buffer, image, nil)
+	})
+	segment := append(append([]byte{}, exifHeader...), exif...)
+	var result bytes.Buffer
+	result.Write(encoded[0:2])
+	result.Write([]byte{0xff, 0xe1})
+	_ = binary.Write(&result, binary.BigEndian, uint16(len(segment)+2))
+	result.Write(segment)
+	result.Write(encoded[2:])
+	return result.Bytes()
+}
+
+func pngWithExif(exif []byte) []byte {
+	encoded := encodedImage(func(buffer *bytes.Buffer, image image.Image) error {
+		return png.Encode(buffer, image)
+	})
+	headerEnd := len(pngSignature) + 8 + 13 + 4
+	var result bytes.Buffer
+	result.Write(encoded[0:headerEnd])
+	_ = binary.Write(&result, binary.BigEndian, uint32(len(exif)))
+	chunk := append([]byte("eXIf"), exif...)
+	result.Write(chunk)
+	_ = binary.Write(&result, binary.BigEndian, crc32.ChecksumIEEE(chunk))
+	result.Write(encoded[headerEnd:])
+	return result.Bytes()
+}
+
+func riff(chunks ...[]byte) []byte {
+	var body bytes.Buffer
+	body.WriteString("WEBP")
+	for _, chunk := range chunks {
+		body.Write(chunk[0:4])
+		_ = binary.Write(&body, binary.LittleEndian, uint32(len(chunk)-4))
+		body.Write(chunk[4:])
+		if len(chunk)%2 == 1 {
+			body.WriteByte(0)
+		}
+	}
+	var result bytes.Buffer
+	result.WriteString("RIFF")
+	_ = binary.Write(&result, binary.LittleEndian, uint32(body.Len()))
+	result.Write(body.Bytes())
+	return result.Bytes()
+}
+
+func readImage(t *testing.T, content []byte) *Image {
+	image, err := ReadImage(bytes.NewReader(content))
+	if err != nil {
+		t.Fatalf("Expected no error while reading the image, received %v", err)
+	}
+	return image
+}
+
+func assertImage(t *testing.T, image *Image, format string, width int64, height int64) {
+	if image.Format != format || image.Width != width || image.Height != height {
+		t.Fatalf("Expected image to be %v %vx%v, received %v %vx%v", format, width, height, image.Format, image.Width, image.Height)
+	}
+}
+
+func assertExif(t *testing.T, actual Exif, expected Exif) {
+	if !actual.DateTime.Equal(expected.DateTime) ||
+		actual.Make != expected.Make ||
+		actual.Model != expected.Model ||
+		actual.Orientation != expected.Orientation ||
+		actual.HasGps != expected.HasGps {
+		t.Fatalf("Expected exif to be %+v, received %+v", expected, actual)
+	}
+}
+
+func TestReadJpegWithExif(t *testing.T) {
+	image := readImage(t, jpegWithExif(cameraExif(binary.LittleEndian)))
+	assertImage(t, image, FormatJpeg, 64, 48)
+	assertExif(t, image.Exif, expectedCameraExif())
+}
+
+func TestReadJpegWithBigEndianExif(t *testing.T) {
+	image := readImage(t, jpegWithExif(cameraExif(binary.BigEndian)))
+	assertImage(t, image, FormatJpeg, 64, 48)
+	assertExif(t, image.Exif, expectedCameraExif())
+}
+
+func TestReadJpegWithoutExif(t *testing.T) {
+	image := readImage(t, encodedImage(func(buffer *bytes.Buffer, image image.Image) error {
+		return jpeg.Encode(buffer, image, nil)
+	}))
+	assertImage(t, image, FormatJpeg, 64, 48)
+	assertExif(t, image.Exif, Exif{})
+}
+
+func TestReadExifWithoutTheOriginalDateTimeAndGps(t *testing.T) {
+	order := binary.LittleEndian
+	exif := buildTiff(order, []tiffEntry{asciiEntry(tagModel, "Pixel 6"), asciiEntry(tagDateTime, "2021:01:02 03:04:05"), pointerEntry(tagGpsIfd, 1)}, []tiffEntry{shortEntry(order, 0x0000, 2)})
+	image := readImage(t, jpegWithExif(exif))
+	assertExif(t, image.Exif, Exif{Model: "Pixel 6", DateTime: time.Date(2021, 1, 2, 3, 4, 5, 0, time.Local)})
+}
+
+func TestReadPngWithExif(t *testing.T) {
+	image := readImage(t, pngWithExif(cameraExif(binary.BigEndian)))
+	assertImage(t, image, FormatPng, 64, 48)
+	assertExif(t, image.Exif, expectedCameraExif())
+}
+
+func TestReadGif(t *testing.T) {
+	image := readImage(t, encodedImage(func(buffer *bytes.Buffer, image image.Image) error {
+		return gif.Encode(buffer, image, nil)
+	}))
+	assertImage(t, image, FormatGif, 64, 48)
+}
+
+func TestReadBmp(t *testing.T) {
+	header := make([]byte, 54)
+	copy(header, "BM")
+	binary.LittleEndian.PutUint32(header[14:18], 40)
+	binary.LittleEndian.PutUint32(header[18:22], 640)
+	binary.LittleEndian.PutUint32(header[22:26], uint32(0xffffffff-480+1))
+	assertImage(t, readImage(t, header), FormatBmp, 640, 480)
+}
+
+func TestReadLossyWebp(t *testing.T) {
+	frame := []byte{'V', 'P', '8', ' ', 0, 0, 0, 0x9d, 0x01, 0x2a, 0x80, 0x02, 0xe0, 0x01}
+	assertImage(t, readImage(t, riff(frame)), FormatWebp, 640, 480)
+}
+
+func TestReadLosslessWebp(t *testing.T) {
+	bits := uint32(640-1) | uint32(480-1)<<14
+	frame := []byte{'V', 'P', '8', 'L', 0x2f, byte(bits), byte(bits >> 8), byte(bits >> 16), byte(bits >> 24)}
+	assertImage(t, readImage(t, riff(frame)), FormatWebp, 640, 480)
+}
+
+func TestReadExtendedWebpWithExif(t *testing.T) {
+	canvas := []byte{'V', 'P', '8', 'X', 0x08, 0, 0, 0, 0x7f, 0x07, 0, 0x37, 0x04, 0}
+	frame := []byte{'V', 'P', '8', 'L', 0x2f, 0, 0, 0, 0}
+	exif := append([]byte("EXIF"), cameraExif(binary.LittleEndian)...)
+	image := readImage(t, riff(canvas, frame, exif))
+	assertImage(t, image, FormatWebp, 1920, 1080)
+	assertExif(t, image.Exif, expectedCameraExif())
+}
+
+func TestReadTiff(t *testing.T) {
+	order := binary.BigEndian
+	content := buildTiff(
+		order,
+		[]tiffEntry{longEntry(order, tagImageWidth, 6000), shortEntry(order, tagImageLength, 4000), asciiEntry(tagMake, "NIKON"), shortEntry(order, tagOrientation, 1)},
+	)
+	image := readImage(t, content)
+	assertImage(t, image, FormatTiff, 6000, 4000)
+	assertExif(t, image.Exif, Exif{Make: "NIKON", Orientation: 1})
+}
+
+func TestReadImageOfANonImage(t *testing.T) {
+	if _, err := ReadImage(bytes.NewReader([]byte("just a text file"))); err != ErrNotAnImage {
+		t.Fatalf("Expected error %v, received %v", ErrNotAnImage, err)
+	}
+}
+
+func TestReadTruncatedJpeg(t *testing.T) {
+	content := jpegWithExif(cameraExif(binary.LittleEndian))
+	if _, err := ReadImage(bytes.NewReader(content[0:40])); err == nil {
+		t.Fatalf("Expected an error while reading a truncated jpeg")
+	}
+}
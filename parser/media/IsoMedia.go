@@ -0,0 +1,211 @@
+package media
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"strings"
+)
+
+var isoMediaCodecs = map[string]string{
+	"avc1": "h264",
+	"avc3": "h264",
+	"hvc1": "hevc",
+	"hev1": "hevc",
+	"av01": "av1",
+	"vp08": "vp8",
+	"vp09": "vp9",
+	"mp4v": "mpeg4",
+	"jpeg": "mjpeg",
+	"apch": "prores",
+	"apcn": "prores",
+	"apcs": "prores",
+	"apco": "prores",
+	"ap4h": "prores",
+	"mp4a": "aac",
+	".mp3": "mp3",
+	"ac-3": "ac3",
+	"ec-3": "eac3",
+	"Opus": "opus",
+	"fLaC": "flac",
+	"alac": "alac",
+	"sowt": "pcm_s16le",
+	"twos": "pcm_s16be",
+	"ulaw": "pcm_mulaw",
+	"alaw": "pcm_alaw",
+}
+
+func isIsoMediaAtom(kind string) bool {
+	switch kind {
+	case "ftyp", "moov", "mdat", "wide", "free":
+		return true
+	}
+	return false
+}
+
+/*
+readIsoMedia reads the atoms of an mp4 or a mov file, the movie header for the duration and the sample description of
+the first video and the first audio track. The media data is skipped using the size of its atom, and a file truncated
+after the movie atom is still read.
+*/
+func readIsoMedia(reader io.ReaderAt, size int64) (*AudioVideo, error) {
+	audioVideo := &AudioVideo{Format: FormatMp4}
+	hasMovie := false
+	err := forEachAtom(reader, 0, size, func(kind string, start int64, end int64) error {
+		switch kind {
+		case "ftyp":
+			brand, err := readAt(reader, start, 4)
+			if err != nil {
+				return err
+			}
+			if string(brand) == "qt  " {
+				audioVideo.Format = FormatMov
+			}
+		case "moov":
+			hasMovie = true
+			return readMovie(reader, start, end, audioVideo)
+		}
+		return nil
+	})
+	if !hasMovie {
+		if err != nil {
+			return nil, err
+		}
+		return nil, errors.New("mp4 without a movie atom")
+	}
+	return audioVideo, nil
+}
+
+func readMovie(reader io.ReaderAt, start int64, end int64, audioVideo *AudioVideo) error {
+	return forEachAtom(reader, start, end, func(kind string, start int64, end int64) error {
+		switch kind {
+		case "mvhd":
+			header, err := readAt(reader, start, 32)
+			if err != nil {
+				return err
+			}
+			var timeScale uint32
+			var duration uint64
+			if header[0] == 1 {
+				timeScale, duration = binary.BigEndian.Uint32(header[20:24]), binary.BigEndian.Uint64(header[24:32])
+			} else {
+				timeScale, duration = binary.BigEndian.Uint32(header[12:16]), uint64(binary.BigEndian.Uint32(header[16:20]))
+			}
+			if timeScale > 0 && duration != math.MaxUint32 && duration != math.MaxUint64 {
+				audioVideo.Duration = float64(duration) / float64(timeScale)
+			}
+		case "trak":
+			return readTrack(reader, start, end, audioVideo)
+		}
+		return nil
+	})
+}
+
+func readTrack(reader io.ReaderAt, start int64, end int64, audioVideo *AudioVideo) error {
+	handler, sampleDescription := "", int64(-1)
+	err := forEachAtomIn(reader, start, end, []string{"mdia"}, func(kind string, start int64, end int64) error {
+		switch kind {
+		case "hdlr":
+			header, err := readAt(reader, start, 12)
+			if err != nil {
+				return err
+			}
+			handler = string(header[8:12])
+		case "minf":
+			return forEachAtomIn(reader, start, end, []string{"stbl"}, func(kind string, start int64, end int64) error {
+				if kind == "stsd" {
+					sampleDescription = start
+				}
+				return nil
+			})
+		}
+		return nil
+	})
+	if err != nil || sampleDescription < 0 {
+		return err
+	}
+	entry := sampleDescription + 8
+	switch {
+	case handler == "vide" && audioVideo.VideoCodec == "":
+		description, err := readAt(reader, entry, 36)
+		if err != nil {
+			return err
+		}
+		audioVideo.VideoCodec = isoMediaCodec(string(description[4:8]))
+		audioVideo.Width = int64(binary.BigEndian.Uint16(description[32:34]))
+		audioVideo.Height = int64(binary.BigEndian.Uint16(description[34:36]))
+	case handler == "soun" && audioVideo.AudioCodec == "":
+		description, err := readAt(reader, entry, 36)
+		if err != nil {
+			return err
+		}
+		audioVideo.AudioCodec = isoMediaCodec(string(description[4:8]))
+		if binary.BigEndian.Uint16(description[16:18]) == 2 {
+			extended, err := readAt(reader, entry+40, 12)
+			if err != nil {
+				return err
+			}
+			audioVideo.SampleRate = int64(math.Float64frombits(binary.BigEndian.Uint64(extended[0:8])))
+			audioVideo.Channels = int64(binary.BigEndian.Uint32(extended[8:12]))
+			return nil
+		}
+		audioVideo.Channels = int64(binary.BigEndian.Uint16(description[24:26]))
+		audioVideo.SampleRate = int64(binary.BigEndian.Uint16(description[32:34]))
+	}
+	return nil
+}
+
+func isoMediaCodec(fourcc string) string {
+	if codec, ok := isoMediaCodecs[fourcc]; ok {
+		return codec
+	}
+	return strings.ToLower(strings.TrimSpace(fourcc))
+}
+
+/*
+forEachAtomIn visits the atoms inside the atoms on the path, for example, the atoms inside 'mdia' of a 'trak'.
+*/
+func forEachAtomIn(reader io.ReaderAt, start int64, end int64, path []string, visit func(kind string, start int64, end int64) error) error {
+	if len(path) == 0 {
+		return forEachAtom(reader, start, end, visit)
+	}
+	return forEachAtom(reader, start, end, func(kind string, start int64, end int64) error {
+		if kind != path[0] {
+			return nil
+		}
+		return forEachAtomIn(reader, start, end, path[1:], visit)
+	})
+}
+
+/*
+forEachAtom visits the atoms between start and end with the range of their contents.
+An atom with a size of 1 has a 64-bit size after its type, and an atom with a size of 0 extends to the end.
+*/
+func forEachAtom(reader io.ReaderAt, start int64, end int64, visit func(kind string, start int64, end int64) error) error {
+	for offset := start; offset+8 <= end; {
+		header, err := readAt(reader, offset, 8)
+		if err != nil {
+			return err
+		}
+		atomSize, headerSize := int64(binary.BigEndian.Uint32(header[0:4])), int64(8)
+		switch atomSize {
+		case 0:
+			atomSize = end - offset
+		case 1:
+			largeSize, err := readAt(reader, offset+8, 8)
+			if err != nil {
+				return err
+			}
+			atomSize, headerSize = int64(binary.BigEndian.Uint64(largeSize)), 16
+		}
+		if atomSize < headerSize || offset+atomSize > end {
+			return errors.New("invalid mp4 atom size")
+		}
+		if err := visit(string(header[4:8]), offset+headerSize, offset+atomSize); err != nil {
+			return err
+		}
+		offset = offset + atomSize
+	}
+	return nil
+}
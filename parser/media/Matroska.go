@@ -0,0 +1,319 @@
+package media
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"strings"
+)
+
+const (
+	ebmlDocType          = 0x4282
+	matroskaSegment      = 0x18538067
+	matroskaInfo         = 0x1549a966
+	matroskaTimecode     = 0x2ad7b1
+	matroskaDuration     = 0x4489
+	matroskaTracks       = 0x1654ae6b
+	matroskaTrackEntry   = 0xae
+	matroskaTrackType    = 0x83
+	matroskaCodecId      = 0x86
+	matroskaVideo        = 0xe0
+	matroskaPixelWidth   = 0xb0
+	matroskaPixelHeight  = 0xba
+	matroskaAudio        = 0xe1
+	matroskaSamplingRate = 0xb5
+	matroskaChannels     = 0x9f
+
+	matroskaTrackTypeVideo = 1
+	matroskaTrackTypeAudio = 2
+
+	defaultMatroskaTimecodeScale = 1000000
+	maxEbmlElementLength         = 1024 * 1024
+)
+
+var ebmlHeaderId = []byte{0x1a, 0x45, 0xdf, 0xa3}
+
+var errStopReading = errors.New("stop reading")
+
+var matroskaCodecs = map[string]string{
+	"V_MPEG4/ISO/AVC":  "h264",
+	"V_MPEGH/ISO/HEVC": "hevc",
+	"V_VP8":            "vp8",
+	"V_VP9":            "vp9",
+	"V_AV1":            "av1",
+	"V_MPEG4/ISO/ASP":  "mpeg4",
+	"V_MPEG2":          "mpeg2video",
+	"V_MJPEG":          "mjpeg",
+	"V_PRORES":         "prores",
+	"A_AAC":            "aac",
+	"A_OPUS":           "opus",
+	"A_VORBIS":         "vorbis",
+	"A_FLAC":           "flac",
+	"A_AC3":            "ac3",
+	"A_EAC3":           "eac3",
+	"A_DTS":            "dts",
+	"A_MPEG/L3":        "mp3",
+	"A_MPEG/L2":        "mp2",
+	"A_PCM/INT/LIT":    "pcm_s16le",
+	"A_PCM/FLOAT/IEEE": "pcm_f32le",
+}
+
+var matroskaCodecPrefixes = map[string]string{"A_AAC/": "aac", "A_PCM/INT/": "pcm", "V_MS/": "msvideo"}
+
+type ebmlElement struct {
+	id    uint32
+	start int64
+	end   int64
+}
+
+type matroskaTrack struct {
+	kind       uint64
+	codec      string
+	width      uint64
+	height     uint64
+	sampleRate float64
+	channels   uint64
+}
+
+/*
+readMatroska reads the EBML header for the doc type, and the Info and the Tracks elements of the first segment.
+The clusters with the media data are skipped using their size, and the reading stops once both the elements are read.
+*/
+func readMatroska(reader io.ReaderAt, size int64) (*AudioVideo, error) {
+	header, err := readEbmlElement(reader, 0, size)
+	if err != nil {
+		return nil, err
+	}
+	audioVideo := &AudioVideo{Format: FormatMatroska}
+	err = forEachEbmlElement(reader, header.start, header.end, func(element ebmlElement) error {
+		if element.id == ebmlDocType {
+			if docType, err := ebmlString(reader, element); err == nil && docType == "webm" {
+				audioVideo.Format = FormatWebm
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	segment, err := readEbmlElement(reader, header.end, size)
+	if err != nil {
+		return nil, err
+	}
+	if segment.id != matroskaSegment {
+		return nil, errors.New("matroska without a segment")
+	}
+	hasInfo, hasTracks := false, false
+	err = forEachEbmlElement(reader, segment.start, segment.end, func(element ebmlElement) error {
+		switch element.id {
+		case matroskaInfo:
+			hasInfo = true
+			return readMatroskaInfo(reader, element, audioVideo)
+		case matroskaTracks:
+			hasTracks = true
+			return forEachEbmlElement(reader, element.start, element.end, func(entry ebmlElement) error {
+				if entry.id != matroskaTrackEntry {
+					return nil
+				}
+				return readMatroskaTrack(reader, entry, audioVideo)
+			})
+		}
+		if hasInfo && hasTracks {
+			return errStopReading
+		}
+		return nil
+	})
+	if err != nil && err != errStopReading && !hasInfo {
+		return nil, err
+	}
+	return audioVideo, nil
+}
+
+func readMatroskaInfo(reader io.ReaderAt, info ebmlElement, audioVideo *AudioVideo) error {
+	timecodeScale, duration := uint64(defaultMatroskaTimecodeScale), 0.0
+	err := forEachEbmlElement(reader, info.start, info.end, func(element ebmlElement) error {
+		var err error
+		switch element.id {
+		case matroskaTimecode:
+			timecodeScale, err = ebmlUint(reader, element)
+		case matroskaDuration:
+			duration, err = ebmlFloat(reader, element)
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	audioVideo.Duration = duration * float64(timecodeScale) / 1e9
+	return nil
+}
+
+func readMatroskaTrack(reader io.ReaderAt, entry ebmlElement, audioVideo *AudioVideo) error {
+	track := matroskaTrack{}
+	err := forEachEbmlElement(reader, entry.start, entry.end, func(element ebmlElement) error {
+		var err error
+		switch element.id {
+		case matroskaTrackType:
+			track.kind, err = ebmlUint(reader, element)
+		case matroskaCodecId:
+			track.codec, err = ebmlString(reader, element)
+		case matroskaVideo:
+			err = forEachEbmlElement(reader, element.start, element.end, func(video ebmlElement) error {
+				var err error
+				switch video.id {
+				case matroskaPixelWidth:
+					track.width, err = ebmlUint(reader, video)
+				case matroskaPixelHeight:
+					track.height, err = ebmlUint(reader, video)
+				}
+				return err
+			})
+		case matroskaAudio:
+			track.channels = 1
+			err = forEachEbmlElement(reader, element.start, element.end, func(audio ebmlElement) error {
+				var err error
+				switch audio.id {
+				case matroskaSamplingRate:
+					track.sampleRate, err = ebmlFloat(reader, audio)
+				case matroskaChannels:
+					track.channels, err = ebmlUint(reader, audio)
+				}
+				return err
+			})
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	switch {
+	case track.kind == matroskaTrackTypeVideo && audioVideo.VideoCodec == "":
+		audioVideo.VideoCodec = matroskaCodec(track.codec)
+		audioVideo.Width, audioVideo.Height = int64(track.width), int64(track.height)
+	case track.kind == matroskaTrackTypeAudio && audioVideo.AudioCodec == "":
+		audioVideo.AudioCodec = matroskaCodec(track.codec)
+		audioVideo.SampleRate, audioVideo.Channels = int64(track.sampleRate), int64(track.channels)
+	}
+	return nil
+}
+
+func matroskaCodec(codecId string) string {
+	if codec, ok := matroskaCodecs[codecId]; ok {
+		return codec
+	}
+	for prefix, codec := range matroskaCodecPrefixes {
+		if strings.HasPrefix(codecId, prefix) {
+			return codec
+		}
+	}
+	return strings.ToLower(codecId)
+}
+
+/*
+forEachEbmlElement visits the elements between start and end. An element of unknown size, like the cluster of a live
+stream, extends to the end, since its end can not be found without reading the media data.
+*/
+func forEachEbmlElement(reader io.ReaderAt, start int64, end int64, visit func(element ebmlElement) error) error {
+	for offset := start; offset < end; {
+		element, err := readEbmlElement(reader, offset, end)
+		if err != nil {
+			return err
+		}
+		if err := visit(element); err != nil {
+			return err
+		}
+		offset = element.end
+	}
+	return nil
+}
+
+func readEbmlElement(reader io.ReaderAt, offset int64, end int64) (ebmlElement, error) {
+	header := readAvailable(reader, offset, 12)
+	id, idLength, ok := ebmlVariableInteger(header, 4, false)
+	if !ok {
+		return ebmlElement{}, errors.New("invalid ebml element id")
+	}
+	size, sizeLength, ok := ebmlVariableInteger(header[idLength:], 8, true)
+	if !ok {
+		return ebmlElement{}, errors.New("invalid ebml element size")
+	}
+	start := offset + int64(idLength+sizeLength)
+	if start > end {
+		return ebmlElement{}, errors.New("truncated ebml element")
+	}
+	if size == 1<<(7*uint(sizeLength))-1 || start+int64(size) > end || int64(size) < 0 {
+		return ebmlElement{id: uint32(id), start: start, end: end}, nil
+	}
+	return ebmlElement{id: uint32(id), start: start, end: start + int64(size)}, nil
+}
+
+/*
+ebmlVariableInteger reads an EBML variable length integer, where the number of leading zero bits of the first byte is
+the number of the bytes that follow. The marker bit is kept for an element id and removed for a size.
+*/
+func ebmlVariableInteger(value []byte, maxLength int, removeMarker bool) (uint64, int, bool) {
+	if len(value) == 0 || value[0] == 0 {
+		return 0, 0, false
+	}
+	length := 1
+	for mask := byte(0x80); value[0]&mask == 0; mask = mask >> 1 {
+		length++
+	}
+	if length > maxLength || length > len(value) {
+		return 0, 0, false
+	}
+	result := uint64(value[0])
+	if removeMarker {
+		result = result & uint64(0xff>>length)
+	}
+	for index := 1; index < length; index++ {
+		result = result<<8 | uint64(value[index])
+	}
+	return result, length, true
+}
+
+func ebmlContents(reader io.ReaderAt, element ebmlElement) ([]byte, error) {
+	length := element.end - element.start
+	if length < 0 {
+		return nil, errors.New("invalid ebml element length")
+	}
+	if length > maxEbmlElementLength {
+		return nil, errors.New("ebml element too large")
+	}
+	return readAt(reader, element.start, int(length))
+}
+
+func ebmlUint(reader io.ReaderAt, element ebmlElement) (uint64, error) {
+	contents, err := ebmlContents(reader, element)
+	if err != nil || len(contents) > 8 {
+		return 0, errors.New("invalid ebml unsigned integer")
+	}
+	var result uint64
+	for _, value := range contents {
+		result = result<<8 | uint64(value)
+	}
+	return result, nil
+}
+
+func ebmlFloat(reader io.ReaderAt, element ebmlElement) (float64, error) {
+	contents, err := ebmlContents(reader, element)
+	if err != nil {
+		return 0, err
+	}
+	switch len(contents) {
+	case 4:
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(contents))), nil
+	case 8:
+		return math.Float64frombits(binary.BigEndian.Uint64(contents)), nil
+	}
+	return 0, errors.New("invalid ebml float")
+}
+
+func ebmlString(reader io.ReaderAt, element ebmlElement) (string, error) {
+	contents, err := ebmlContents(reader, element)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(contents), "\x00"), nil
+}
@@ -0,0 +1,233 @@
+package media
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	mpegVersion1  = 3
+	mpegVersion2  = 2
+	mpegVersion25 = 0
+
+	mpegLayer1 = 3
+	mpegLayer2 = 2
+	mpegLayer3 = 1
+
+	mpegChannelModeMono = 3
+
+	maxMp3SyncSearchLength = 64 * 1024
+	id3v1Length            = 128
+	maxId3ReadLength       = 1024 * 1024
+)
+
+var mpegBitRates = map[bool]map[int][]int64{
+	true: {
+		mpegLayer1: {0, 32, 64, 96, 128, 160, 192, 224, 256, 288, 320, 352, 384, 416, 448},
+		mpegLayer2: {0, 32, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320, 384},
+		mpegLayer3: {0, 32, 40, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320},
+	},
+	false: {
+		mpegLayer1: {0, 32, 48, 56, 64, 80, 96, 112, 128, 144, 160, 176, 192, 224, 256},
+		mpegLayer2: {0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160},
+		mpegLayer3: {0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160},
+	},
+}
+
+var mpegSampleRates = map[int][]int64{
+	mpegVersion1:  {44100, 48000, 32000},
+	mpegVersion2:  {22050, 24000, 16000},
+	mpegVersion25: {11025, 12000, 8000},
+}
+
+var mpegCodecs = map[int]string{mpegLayer1: "mp1", mpegLayer2: "mp2", mpegLayer3: "mp3"}
+
+type mpegFrame struct {
+	version         int
+	layer           int
+	bitRate         int64
+	sampleRate      int64
+	channels        int64
+	length          int64
+	samplesPerFrame int64
+	sideInfoLength  int64
+}
+
+type id3Tag struct {
+	size   int64
+	length float64
+}
+
+func isMpegAudioFrame(header []byte) bool {
+	_, ok := parseMpegFrame(header)
+	return ok
+}
+
+func parseMpegFrame(header []byte) (mpegFrame, bool) {
+	if len(header) < 4 || header[0] != 0xff || header[1]&0xe0 != 0xe0 {
+		return mpegFrame{}, false
+	}
+	version, layer := int(header[1]>>3)&0x03, int(header[1]>>1)&0x03
+	bitRateIndex, sampleRateIndex := int(header[2]>>4), int(header[2]>>2)&0x03
+	if version == 1 || layer == 0 || bitRateIndex == 0 || bitRateIndex == 15 || sampleRateIndex == 3 {
+		return mpegFrame{}, false
+	}
+	frame := mpegFrame{
+		version:    version,
+		layer:      layer,
+		bitRate:    mpegBitRates[version == mpegVersion1][layer][bitRateIndex] * 1000,
+		sampleRate: mpegSampleRates[version][sampleRateIndex],
+		channels:   2,
+	}
+	if int(header[3]>>6) == mpegChannelModeMono {
+		frame.channels = 1
+	}
+	padding := int64(header[2]>>1) & 0x01
+	switch {
+	case layer == mpegLayer1:
+		frame.samplesPerFrame = 384
+		frame.length = (12*frame.bitRate/frame.sampleRate + padding) * 4
+	case layer == mpegLayer3 && version != mpegVersion1:
+		frame.samplesPerFrame = 576
+		frame.length = 72*frame.bitRate/frame.sampleRate + padding
+	default:
+		frame.samplesPerFrame = 1152
+		frame.length = 144*frame.bitRate/frame.sampleRate + padding
+	}
+	switch {
+	case version == mpegVersion1 && frame.channels == 2:
+		frame.sideInfoLength = 32
+	case version == mpegVersion1 || frame.channels == 2:
+		frame.sideInfoLength = 17
+	default:
+		frame.sideInfoLength = 9
+	}
+	return frame, true
+}
+
+/*
+readMp3 finds the first frame after the ID3 tag, and confirms it by the frame that follows it.
+The number of frames in the Xing or the VBRI header of the first frame gives the duration of a variable bit rate mp3,
+otherwise the length in the ID3 tag or the size of the audio at the bit rate of the first frame is used.
+*/
+func readMp3(reader io.ReaderAt, size int64, audioStart int64, id3Length float64) (*AudioVideo, error) {
+	offset, frame, err := findMpegFrame(reader, audioStart)
+	if err != nil {
+		return nil, err
+	}
+	audioVideo := &AudioVideo{
+		Format:     FormatMp3,
+		SampleRate: frame.sampleRate,
+		Channels:   frame.channels,
+		AudioCodec: mpegCodecs[frame.layer],
+	}
+	if frames, ok := vbrFrames(reader, offset, frame); ok {
+		audioVideo.Duration = float64(frames*frame.samplesPerFrame) / float64(frame.sampleRate)
+		return audioVideo, nil
+	}
+	if id3Length > 0 {
+		audioVideo.Duration = id3Length
+		return audioVideo, nil
+	}
+	audioEnd := size
+	if trailer, err := readAt(reader, size-id3v1Length, 3); err == nil && string(trailer) == "TAG" {
+		audioEnd = size - id3v1Length
+	}
+	audioVideo.Duration = float64((audioEnd-offset)*8) / float64(frame.bitRate)
+	audioVideo.BitRate = frame.bitRate
+	return audioVideo, nil
+}
+
+func findMpegFrame(reader io.ReaderAt, start int64) (int64, mpegFrame, error) {
+	window := readAvailable(reader, start, maxMp3SyncSearchLength)
+	for index := 0; index+4 <= len(window); index++ {
+		frame, ok := parseMpegFrame(window[index : index+4])
+		if !ok {
+			continue
+		}
+		next, err := readAt(reader, start+int64(index)+frame.length, 4)
+		if err == nil && !isMpegAudioFrame(next) {
+			continue
+		}
+		return start + int64(index), frame, nil
+	}
+	return 0, mpegFrame{}, errors.New("mp3 without an audio frame")
+}
+
+func vbrFrames(reader io.ReaderAt, offset int64, frame mpegFrame) (int64, bool) {
+	if xing, err := readAt(reader, offset+4+frame.sideInfoLength, 12); err == nil {
+		if string(xing[0:4]) == "Xing" || string(xing[0:4]) == "Info" {
+			if binary.BigEndian.Uint32(xing[4:8])&0x01 == 0x01 {
+				return int64(binary.BigEndian.Uint32(xing[8:12])), true
+			}
+		}
+	}
+	if vbri, err := readAt(reader, offset+36, 18); err == nil && string(vbri[0:4]) == "VBRI" {
+		return int64(binary.BigEndian.Uint32(vbri[14:18])), true
+	}
+	return 0, false
+}
+
+/*
+readId3 reads the size of the ID3v2 tag at the beginning of a file, and the length in milliseconds from its TLEN frame.
+Only the first MiB of the tag is read for the frames, the pictures stored in a tag can be much larger.
+*/
+func readId3(reader io.ReaderAt) (id3Tag, error) {
+	header, err := readAt(reader, 0, 10)
+	if err != nil {
+		return id3Tag{}, err
+	}
+	version, flags := header[3], header[5]
+	tag := id3Tag{size: 10 + syncSafe(header[6:10])}
+	if flags&0x10 == 0x10 {
+		tag.size = tag.size + 10
+	}
+	frames := readAvailable(reader, 10, int(minimum(syncSafe(header[6:10]), maxId3ReadLength)))
+	idLength, headerLength, lengthFrame := 4, 10, "TLEN"
+	if version == 2 {
+		idLength, headerLength, lengthFrame = 3, 6, "TLE"
+	}
+	for offset := 0; offset+headerLength <= len(frames) && frames[offset] != 0; {
+		var frameSize int
+		switch version {
+		case 2:
+			frameSize = int(frames[offset+3])<<16 | int(frames[offset+4])<<8 | int(frames[offset+5])
+		case 4:
+			frameSize = int(syncSafe(frames[offset+4 : offset+8]))
+		default:
+			frameSize = int(binary.BigEndian.Uint32(frames[offset+4 : offset+8]))
+		}
+		body := offset + headerLength
+		if frameSize <= 0 || body+frameSize > len(frames) {
+			break
+		}
+		if string(frames[offset:offset+idLength]) == lengthFrame {
+			text := strings.Trim(string(frames[body+1:body+frameSize]), "\x00 ")
+			if milliseconds, err := strconv.ParseFloat(text, 64); err == nil {
+				tag.length = milliseconds / 1000
+			}
+		}
+		offset = body + frameSize
+	}
+	return tag, nil
+}
+
+func syncSafe(value []byte) int64 {
+	return int64(value[0]&0x7f)<<21 | int64(value[1]&0x7f)<<14 | int64(value[2]&0x7f)<<7 | int64(value[3]&0x7f)
+}
+
+func readAvailable(reader io.ReaderAt, offset int64, length int) []byte {
+	buffer := make([]byte, length)
+	read, _ := reader.ReadAt(buffer, offset)
+	return buffer[0:read]
+}
+
+func minimum(first int64, second int64) int64 {
+	if first < second {
+		return first
+	}
+	return second
+}
@@ -0,0 +1,130 @@
+package media
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"strings"
+)
+
+const (
+	tiffTypeAscii = 2
+	tiffTypeShort = 3
+	tiffTypeLong  = 4
+
+	maxIfdEntries       = 1024
+	maxTiffStringLength = 4096
+)
+
+type tiff struct {
+	reader io.ReaderAt
+	order  binary.ByteOrder
+	ifd0   uint32
+}
+
+type ifdEntry struct {
+	kind  uint16
+	count uint32
+	value []byte
+}
+
+type ifd map[uint16]ifdEntry
+
+/*
+newTiff reads the header of the TIFF structure used by the TIFF images and by the EXIF blocks of the other formats.
+All the offsets in the structure are relative to the beginning of the reader.
+*/
+func newTiff(reader io.ReaderAt) (*tiff, error) {
+	header, err := readAt(reader, 0, 8)
+	if err != nil {
+		return nil, err
+	}
+	var order binary.ByteOrder
+	switch string(header[0:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return nil, errors.New("invalid tiff byte order")
+	}
+	if order.Uint16(header[2:4]) != 42 {
+		return nil, errors.New("invalid tiff header")
+	}
+	return &tiff{reader: reader, order: order, ifd0: order.Uint32(header[4:8])}, nil
+}
+
+func isTiff(header []byte) bool {
+	return len(header) >= 4 && (string(header[0:4]) == "II*\x00" || string(header[0:4]) == "MM\x00*")
+}
+
+func (tiff *tiff) readIfd(offset uint32) (ifd, error) {
+	countBytes, err := readAt(tiff.reader, int64(offset), 2)
+	if err != nil {
+		return nil, err
+	}
+	count := int(tiff.order.Uint16(countBytes))
+	if count > maxIfdEntries {
+		return nil, errors.New("too many tiff directory entries")
+	}
+	entries, err := readAt(tiff.reader, int64(offset)+2, count*12)
+	if err != nil {
+		return nil, err
+	}
+	directory := make(ifd, count)
+	for index := 0; index < count; index++ {
+		entry := entries[index*12 : index*12+12]
+		directory[tiff.order.Uint16(entry[0:2])] = ifdEntry{
+			kind:  tiff.order.Uint16(entry[2:4]),
+			count: tiff.order.Uint32(entry[4:8]),
+			value: entry[8:12],
+		}
+	}
+	return directory, nil
+}
+
+func (tiff *tiff) uint(directory ifd, tag uint16) (uint32, bool) {
+	entry, ok := directory[tag]
+	if !ok || entry.count == 0 {
+		return 0, false
+	}
+	switch entry.kind {
+	case tiffTypeShort:
+		return uint32(tiff.order.Uint16(entry.value[0:2])), true
+	case tiffTypeLong:
+		return tiff.order.Uint32(entry.value), true
+	}
+	return 0, false
+}
+
+func (tiff *tiff) string(directory ifd, tag uint16) string {
+	entry, ok := directory[tag]
+	if !ok || entry.kind != tiffTypeAscii || entry.count == 0 || entry.count > maxTiffStringLength {
+		return ""
+	}
+	var value []byte
+	if entry.count <= 4 {
+		value = entry.value[0:entry.count]
+	} else {
+		var err error
+		if value, err = readAt(tiff.reader, int64(tiff.order.Uint32(entry.value)), int(entry.count)); err != nil {
+			return ""
+		}
+	}
+	if end := strings.IndexByte(string(value), 0); end >= 0 {
+		value = value[0:end]
+	}
+	return strings.TrimSpace(string(value))
+}
+
+func readAt(reader io.ReaderAt, offset int64, length int) ([]byte, error) {
+	buffer := make([]byte, length)
+	read, err := reader.ReadAt(buffer, offset)
+	if read == length {
+		return buffer, nil
+	}
+	if err == nil || err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return nil, err
+}
@@ -0,0 +1,89 @@
+package media
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+const (
+	waveFormatPcm        = 0x0001
+	waveFormatAdpcm      = 0x0002
+	waveFormatFloat      = 0x0003
+	waveFormatAlaw       = 0x0006
+	waveFormatMulaw      = 0x0007
+	waveFormatImaAdpcm   = 0x0011
+	waveFormatMp3        = 0x0055
+	waveFormatExtensible = 0xfffe
+)
+
+/*
+readWav reads the 'fmt ' and the 'data' chunks of a wav file. The duration is the size of the data at the byte rate
+of the format, and the format of an extensible wav is read from the first two bytes of its sub format.
+*/
+func readWav(reader io.ReaderAt) (*AudioVideo, error) {
+	audioVideo := &AudioVideo{Format: FormatWav}
+	var byteRate, dataSize int64
+	hasFormat, hasData := false, false
+	for offset := int64(12); !hasFormat || !hasData; {
+		chunk, err := readAt(reader, offset, 8)
+		if err != nil {
+			break
+		}
+		length := int64(binary.LittleEndian.Uint32(chunk[4:8]))
+		switch string(chunk[0:4]) {
+		case "fmt ":
+			format, err := readAt(reader, offset+8, 16)
+			if err != nil {
+				return nil, err
+			}
+			formatTag := binary.LittleEndian.Uint16(format[0:2])
+			bitsPerSample := binary.LittleEndian.Uint16(format[14:16])
+			if formatTag == waveFormatExtensible {
+				if subFormat, err := readAt(reader, offset+8+24, 2); err == nil {
+					formatTag = binary.LittleEndian.Uint16(subFormat)
+				}
+			}
+			audioVideo.AudioCodec = waveCodec(formatTag, bitsPerSample)
+			audioVideo.Channels = int64(binary.LittleEndian.Uint16(format[2:4]))
+			audioVideo.SampleRate = int64(binary.LittleEndian.Uint32(format[4:8]))
+			byteRate = int64(binary.LittleEndian.Uint32(format[8:12]))
+			hasFormat = true
+		case "data":
+			dataSize, hasData = length, true
+		}
+		offset = offset + 8 + length + length%2
+	}
+	if !hasFormat {
+		return nil, errors.New("wav without a format chunk")
+	}
+	audioVideo.BitRate = byteRate * 8
+	if byteRate > 0 {
+		audioVideo.Duration = float64(dataSize) / float64(byteRate)
+	}
+	return audioVideo, nil
+}
+
+func waveCodec(formatTag uint16, bitsPerSample uint16) string {
+	switch formatTag {
+	case waveFormatPcm:
+		if bitsPerSample == 8 {
+			return "pcm_u8"
+		}
+		return fmt.Sprintf("pcm_s%vle", bitsPerSample)
+	case waveFormatFloat:
+		return fmt.Sprintf("pcm_f%vle", bitsPerSample)
+	case waveFormatAdpcm:
+		return "adpcm_ms"
+	case waveFormatAlaw:
+		return "pcm_alaw"
+	case waveFormatMulaw:
+		return "pcm_mulaw"
+	case waveFormatImaAdpcm:
+		return "adpcm_ima_wav"
+	case waveFormatMp3:
+		return "mp3"
+	}
+	return fmt.Sprintf("0x%04x", formatTag)
+}
@@ -3,7 +3,10 @@ package order
 import (
 	"errors"
 	"fmt"
+	"goselect/parser/context"
 	"goselect/parser/error/messages"
+	"goselect/parser/expression"
+	"goselect/parser/projection"
 	"goselect/parser/tokenizer"
 	"strconv"
 )
@@ -22,7 +25,20 @@ const (
 	sortingDirectionDescending     = 1
 )
 
-func NewOrder(iterator *tokenizer.TokenIterator, projectionCount int) (*Order, error) {
+/*
+order by:    positions Or aliases Or attributes Or functions Or expressions, each optionally followed by asc/desc
+positions:   1, 2 (refer to the projections)
+aliases:     hsize (refer to the projections with 'as' alias)
+attributes:  name, size etc
+functions:   lower(name), count() etc
+expressions: size * 2 etc
+attributes, functions and expressions that are not in the projection are evaluated but not displayed
+*/
+func NewOrder(
+	iterator *tokenizer.TokenIterator,
+	projections *projection.Projections,
+	ctx *context.ParsingApplicationContext,
+) (*Order, error) {
 	if !iterator.HasNext() {
 		return nil, nil
 	}
@@ -36,32 +52,32 @@ func NewOrder(iterator *tokenizer.TokenIterator, projectionCount int) (*Order, e
 	if iterator.HasNext() && !iterator.Peek().Equals("by") {
 		return nil, errors.New(messages.ErrorMessageMissingBy)
 	}
+	iterator.Next()
 
 	var attributes []AttributeRef
 	var directions []bool
 	var expectComma bool
 
+	parser := expression.NewExpressionParser(
+		iterator,
+		ctx,
+		[]string{"asc", "desc", "limit"},
+		messages.ErrorMessageInvalidOrderBy,
+		"",
+	)
 	for iterator.HasNext() && !iterator.Peek().Equals("limit") {
-		token := iterator.Next()
-		if token.Equals("by") {
-			continue
-		}
+		token := iterator.Peek()
 		switch {
 		case expectComma:
 			if !token.Equals(",") {
 				return nil, errors.New(messages.ErrorMessageMissingCommaOrderBy)
 			}
+			iterator.Next()
 			expectComma = false
 		default:
-			projectionPosition, err := strconv.Atoi(token.TokenValue)
+			projectionPosition, err := position(iterator, parser, projections)
 			if err != nil {
-				return nil, fmt.Errorf(messages.ErrorMessageNonZeroPositivePositionsWithExistingError, err)
-			}
-			if projectionPosition <= 0 {
-				return nil, errors.New(messages.ErrorMessageNonZeroPositivePositions)
-			}
-			if projectionPosition > projectionCount {
-				return nil, fmt.Errorf(messages.ErrorMessageOrderByPositionOutOfRange, 1, projectionCount)
+				return nil, err
 			}
 			attributes = append(attributes, AttributeRef{ProjectionPosition: projectionPosition})
 			if sortingDirection(iterator) == sortingDirectionDescending {
@@ -78,6 +94,46 @@ func NewOrder(iterator *tokenizer.TokenIterator, projectionCount int) (*Order, e
 	return &Order{Attributes: attributes, directions: directions}, nil
 }
 
+func position(
+	iterator *tokenizer.TokenIterator,
+	parser *expression.ExpressionParser,
+	projections *projection.Projections,
+) (int, error) {
+	token := iterator.Peek()
+	if isStandalone(iterator) {
+		if projectionPosition, err := strconv.Atoi(token.TokenValue); err == nil {
+			iterator.Next()
+			if projectionPosition <= 0 {
+				return 0, errors.New(messages.ErrorMessageNonZeroPositivePositions)
+			}
+			if projectionPosition > projections.Count() {
+				return 0, fmt.Errorf(messages.ErrorMessageOrderByPositionOutOfRange, 1, projections.Count())
+			}
+			return projectionPosition, nil
+		}
+		if projectionPosition := projections.PositionOfAlias(token.TokenValue); projectionPosition > 0 {
+			iterator.Next()
+			return projectionPosition, nil
+		}
+	}
+	anExpression, err := parser.Parse()
+	if err != nil {
+		return 0, err
+	}
+	if anExpression.IsAValue() {
+		return 0, fmt.Errorf(messages.ErrorMessageInvalidOrderByAttribute, token.TokenValue)
+	}
+	if projectionPosition := projections.PositionOf(anExpression); projectionPosition > 0 {
+		return projectionPosition, nil
+	}
+	return projections.AddHidden(anExpression), nil
+}
+
+func isStandalone(iterator *tokenizer.TokenIterator) bool {
+	next, ok := iterator.PeekAt(1)
+	return !ok || next.Equals(",") || next.Equals("asc") || next.Equals("desc") || next.Equals("limit")
+}
+
 func sortingDirection(iterator *tokenizer.TokenIterator) int {
 	if iterator.HasNext() && iterator.Peek().Equals("desc") {
 		iterator.Next()
@@ -4,15 +4,27 @@
 package order
 
 import (
+	"goselect/parser/context"
+	"goselect/parser/projection"
 	"goselect/parser/tokenizer"
 	"reflect"
+	"strings"
 	"testing"
 )
 
+func projectionsWithCount(count int) *projection.Projections {
+	attributes := []string{"name", "size"}
+	projections, _ := projection.NewProjections(
+		tokenizer.NewTokenizer(strings.Join(attributes[0:count], ",")).Tokenize().Iterator(),
+		context.NewContext(context.NewFunctions(), context.NewAttributes()),
+	)
+	return projections
+}
+
 func TestOrderWithoutAnyOrderByClause(t *testing.T) {
 	tokens := tokenizer.NewEmptyTokens()
 
-	order, _ := NewOrder(tokens.Iterator(), 1)
+	order, _ := NewOrder(tokens.Iterator(), projectionsWithCount(1), context.NewContext(context.NewFunctions(), context.NewAttributes()))
 	if order != nil {
 		t.Fatalf("Expected order to be nil but was not")
 	}
@@ -22,7 +34,7 @@ func TestOrderWithAKeywordOtherThanOrder(t *testing.T) {
 	tokens := tokenizer.NewEmptyTokens()
 	tokens.Add(tokenizer.NewToken(tokenizer.RawString, "unknown"))
 
-	order, _ := NewOrder(tokens.Iterator(), 1)
+	order, _ := NewOrder(tokens.Iterator(), projectionsWithCount(1), context.NewContext(context.NewFunctions(), context.NewAttributes()))
 	if order != nil {
 		t.Fatalf("Expected order to be nil but was not")
 	}
@@ -32,7 +44,7 @@ func TestOrderByWithMissingBy(t *testing.T) {
 	tokens := tokenizer.NewEmptyTokens()
 	tokens.Add(tokenizer.NewToken(tokenizer.Order, "order"))
 
-	_, err := NewOrder(tokens.Iterator(), 1)
+	_, err := NewOrder(tokens.Iterator(), projectionsWithCount(1), context.NewContext(context.NewFunctions(), context.NewAttributes()))
 	if err == nil {
 		t.Fatalf("Expected an error given order keyword without by")
 	}
@@ -43,7 +55,7 @@ func TestOrderByWithUnknownKeywordAfterOrder(t *testing.T) {
 	tokens.Add(tokenizer.NewToken(tokenizer.Order, "order"))
 	tokens.Add(tokenizer.NewToken(tokenizer.RawString, "unknown"))
 
-	_, err := NewOrder(tokens.Iterator(), 1)
+	_, err := NewOrder(tokens.Iterator(), projectionsWithCount(1), context.NewContext(context.NewFunctions(), context.NewAttributes()))
 	if err == nil {
 		t.Fatalf("Expected an error given order keyword without by")
 	}
@@ -56,7 +68,7 @@ func TestOrderByWithMissingComma(t *testing.T) {
 	tokens.Add(tokenizer.NewToken(tokenizer.RawString, "1"))
 	tokens.Add(tokenizer.NewToken(tokenizer.RawString, "2"))
 
-	_, err := NewOrder(tokens.Iterator(), 1)
+	_, err := NewOrder(tokens.Iterator(), projectionsWithCount(1), context.NewContext(context.NewFunctions(), context.NewAttributes()))
 	if err == nil {
 		t.Fatalf("Expected an error given order keyword with missing comma")
 	}
@@ -68,7 +80,7 @@ func TestOrderByWithNonNumericOrderByPosition(t *testing.T) {
 	tokens.Add(tokenizer.NewToken(tokenizer.By, "by"))
 	tokens.Add(tokenizer.NewToken(tokenizer.RawString, "a"))
 
-	_, err := NewOrder(tokens.Iterator(), 1)
+	_, err := NewOrder(tokens.Iterator(), projectionsWithCount(1), context.NewContext(context.NewFunctions(), context.NewAttributes()))
 	if err == nil {
 		t.Fatalf("Expected an error given order keyword with non-numeric order by position")
 	}
@@ -80,7 +92,7 @@ func TestOrderByAttributeInAscending(t *testing.T) {
 	tokens.Add(tokenizer.NewToken(tokenizer.By, "by"))
 	tokens.Add(tokenizer.NewToken(tokenizer.RawString, "1"))
 
-	order, _ := NewOrder(tokens.Iterator(), 1)
+	order, _ := NewOrder(tokens.Iterator(), projectionsWithCount(1), context.NewContext(context.NewFunctions(), context.NewAttributes()))
 	expectedOrder := Order{
 		Attributes: []AttributeRef{{ProjectionPosition: 1}},
 		directions: []bool{true},
@@ -98,7 +110,7 @@ func TestOrderByAnAttributeInAscendingWithExplicitAsc(t *testing.T) {
 	tokens.Add(tokenizer.NewToken(tokenizer.RawString, "1"))
 	tokens.Add(tokenizer.NewToken(tokenizer.AscendingOrder, "asc"))
 
-	order, _ := NewOrder(tokens.Iterator(), 1)
+	order, _ := NewOrder(tokens.Iterator(), projectionsWithCount(1), context.NewContext(context.NewFunctions(), context.NewAttributes()))
 	expectedOrder := Order{
 		Attributes: []AttributeRef{{ProjectionPosition: 1}},
 		directions: []bool{true},
@@ -117,7 +129,7 @@ func TestOrderBy2AttributesInAscending(t *testing.T) {
 	tokens.Add(tokenizer.NewToken(tokenizer.Comma, ","))
 	tokens.Add(tokenizer.NewToken(tokenizer.RawString, "2"))
 
-	order, _ := NewOrder(tokens.Iterator(), 2)
+	order, _ := NewOrder(tokens.Iterator(), projectionsWithCount(2), context.NewContext(context.NewFunctions(), context.NewAttributes()))
 	expectedOrder := Order{
 		Attributes: []AttributeRef{{ProjectionPosition: 1}, {ProjectionPosition: 2}},
 		directions: []bool{true, true},
@@ -135,7 +147,7 @@ func TestOrderByAAttributeInDescending(t *testing.T) {
 	tokens.Add(tokenizer.NewToken(tokenizer.RawString, "1"))
 	tokens.Add(tokenizer.NewToken(tokenizer.DescendingOrder, "desc"))
 
-	order, _ := NewOrder(tokens.Iterator(), 1)
+	order, _ := NewOrder(tokens.Iterator(), projectionsWithCount(1), context.NewContext(context.NewFunctions(), context.NewAttributes()))
 	expectedOrder := Order{
 		Attributes: []AttributeRef{{ProjectionPosition: 1}},
 		directions: []bool{false},
@@ -156,7 +168,7 @@ func TestOrderBy2AttributesInDescending(t *testing.T) {
 	tokens.Add(tokenizer.NewToken(tokenizer.RawString, "2"))
 	tokens.Add(tokenizer.NewToken(tokenizer.DescendingOrder, "desc"))
 
-	order, _ := NewOrder(tokens.Iterator(), 2)
+	order, _ := NewOrder(tokens.Iterator(), projectionsWithCount(2), context.NewContext(context.NewFunctions(), context.NewAttributes()))
 	expectedOrder := Order{
 		Attributes: []AttributeRef{{ProjectionPosition: 1}, {ProjectionPosition: 2}},
 		directions: []bool{false, false},
@@ -176,7 +188,7 @@ func TestOrderBy2AttributesOneInAscendingOtherInDescending(t *testing.T) {
 	tokens.Add(tokenizer.NewToken(tokenizer.RawString, "2"))
 	tokens.Add(tokenizer.NewToken(tokenizer.DescendingOrder, "desc"))
 
-	order, _ := NewOrder(tokens.Iterator(), 2)
+	order, _ := NewOrder(tokens.Iterator(), projectionsWithCount(2), context.NewContext(context.NewFunctions(), context.NewAttributes()))
 	expectedOrder := Order{
 		Attributes: []AttributeRef{{ProjectionPosition: 1}, {ProjectionPosition: 2}},
 		directions: []bool{true, false},
@@ -192,7 +204,7 @@ func TestThrowsAErrorGivenNoAttributeAfterOrderBy(t *testing.T) {
 	tokens.Add(tokenizer.NewToken(tokenizer.Order, "order"))
 	tokens.Add(tokenizer.NewToken(tokenizer.By, "by"))
 
-	_, err := NewOrder(tokens.Iterator(), 1)
+	_, err := NewOrder(tokens.Iterator(), projectionsWithCount(1), context.NewContext(context.NewFunctions(), context.NewAttributes()))
 
 	if err == nil {
 		t.Fatalf("Expected an error when no attributes are given after order by but received none")
@@ -205,7 +217,7 @@ func TestThrowsAErrorOrderByAttributePositionZero(t *testing.T) {
 	tokens.Add(tokenizer.NewToken(tokenizer.By, "by"))
 	tokens.Add(tokenizer.NewToken(tokenizer.RawString, "0"))
 
-	_, err := NewOrder(tokens.Iterator(), 1)
+	_, err := NewOrder(tokens.Iterator(), projectionsWithCount(1), context.NewContext(context.NewFunctions(), context.NewAttributes()))
 
 	if err == nil {
 		t.Fatalf("Expected an error when 0 is given as the order by position")
@@ -218,7 +230,7 @@ func TestThrowsAErrorOrderByAttributePositionNegative(t *testing.T) {
 	tokens.Add(tokenizer.NewToken(tokenizer.By, "by"))
 	tokens.Add(tokenizer.NewToken(tokenizer.RawString, "-9"))
 
-	_, err := NewOrder(tokens.Iterator(), 1)
+	_, err := NewOrder(tokens.Iterator(), projectionsWithCount(1), context.NewContext(context.NewFunctions(), context.NewAttributes()))
 
 	if err == nil {
 		t.Fatalf("Expected an error when -9 is given as the order by position")
@@ -231,7 +243,7 @@ func TestThrowsAErrorOrderByAttributePositionBeyondProjectCount(t *testing.T) {
 	tokens.Add(tokenizer.NewToken(tokenizer.By, "by"))
 	tokens.Add(tokenizer.NewToken(tokenizer.RawString, "2"))
 
-	_, err := NewOrder(tokens.Iterator(), 1)
+	_, err := NewOrder(tokens.Iterator(), projectionsWithCount(1), context.NewContext(context.NewFunctions(), context.NewAttributes()))
 
 	if err == nil {
 		t.Fatalf("Expected an error when 2 is given as the order by position and projection count is 1")
@@ -247,7 +259,7 @@ func TestOrderBy2AttributesWithOneAsTheProjectionPosition(t *testing.T) {
 	tokens.Add(tokenizer.NewToken(tokenizer.Comma, ","))
 	tokens.Add(tokenizer.NewToken(tokenizer.RawString, "2"))
 
-	order, _ := NewOrder(tokens.Iterator(), 2)
+	order, _ := NewOrder(tokens.Iterator(), projectionsWithCount(2), context.NewContext(context.NewFunctions(), context.NewAttributes()))
 	expectedOrder := Order{
 		Attributes: []AttributeRef{{ProjectionPosition: 1}, {ProjectionPosition: 2}},
 		directions: []bool{false, true},
@@ -267,7 +279,7 @@ func TestIsAscendingAtAnIndex(t *testing.T) {
 	tokens.Add(tokenizer.NewToken(tokenizer.Comma, ","))
 	tokens.Add(tokenizer.NewToken(tokenizer.RawString, "2"))
 
-	order, _ := NewOrder(tokens.Iterator(), 2)
+	order, _ := NewOrder(tokens.Iterator(), projectionsWithCount(2), context.NewContext(context.NewFunctions(), context.NewAttributes()))
 	isAscendingAt := order.IsAscendingAt(0)
 
 	if isAscendingAt {
@@ -284,7 +296,7 @@ func TestIsDescendingAtAnIndex(t *testing.T) {
 	tokens.Add(tokenizer.NewToken(tokenizer.Comma, ","))
 	tokens.Add(tokenizer.NewToken(tokenizer.RawString, "2"))
 
-	order, _ := NewOrder(tokens.Iterator(), 2)
+	order, _ := NewOrder(tokens.Iterator(), projectionsWithCount(2), context.NewContext(context.NewFunctions(), context.NewAttributes()))
 	isAscendingAt := order.IsAscendingAt(1)
 
 	if !isAscendingAt {
@@ -301,10 +313,72 @@ func TestIsAscendingAtAnIllegalIndex(t *testing.T) {
 	tokens.Add(tokenizer.NewToken(tokenizer.Comma, ","))
 	tokens.Add(tokenizer.NewToken(tokenizer.RawString, "2"))
 
-	order, _ := NewOrder(tokens.Iterator(), 2)
+	order, _ := NewOrder(tokens.Iterator(), projectionsWithCount(2), context.NewContext(context.NewFunctions(), context.NewAttributes()))
 	isAscendingAt := order.IsAscendingAt(3)
 
 	if isAscendingAt {
 		t.Fatalf("Expected descending order at index 3 but received ascending")
 	}
 }
+
+func TestOrderByAnAlias(t *testing.T) {
+	ctx := context.NewContext(context.NewFunctions(), context.NewAttributes())
+	projections, _ := projection.NewProjections(tokenizer.NewTokenizer("name, fmtsize(size) as hsize").Tokenize().Iterator(), ctx)
+	tokens := tokenizer.NewTokenizer("order by hsize desc").Tokenize()
+
+	order, _ := NewOrder(tokens.Iterator(), projections, ctx)
+	expected := []AttributeRef{{ProjectionPosition: 2}}
+
+	if !reflect.DeepEqual(expected, order.Attributes) {
+		t.Fatalf("Expected order attributes to be %v, received %v", expected, order.Attributes)
+	}
+	if order.IsAscendingAt(0) {
+		t.Fatalf("Expected descending order at index 0 but received ascending")
+	}
+}
+
+func TestOrderByAnAttributeInTheProjection(t *testing.T) {
+	ctx := context.NewContext(context.NewFunctions(), context.NewAttributes())
+	projections, _ := projection.NewProjections(tokenizer.NewTokenizer("name, size").Tokenize().Iterator(), ctx)
+	tokens := tokenizer.NewTokenizer("order by size").Tokenize()
+
+	order, _ := NewOrder(tokens.Iterator(), projections, ctx)
+	expected := []AttributeRef{{ProjectionPosition: 2}}
+
+	if !reflect.DeepEqual(expected, order.Attributes) {
+		t.Fatalf("Expected order attributes to be %v, received %v", expected, order.Attributes)
+	}
+	if projections.HiddenCount() != 0 {
+		t.Fatalf("Expected no hidden projections, received %v", projections.HiddenCount())
+	}
+}
+
+func TestOrderByAnExpressionNotInTheProjection(t *testing.T) {
+	ctx := context.NewContext(context.NewFunctions(), context.NewAttributes())
+	projections, _ := projection.NewProjections(tokenizer.NewTokenizer("name").Tokenize().Iterator(), ctx)
+	tokens := tokenizer.NewTokenizer("order by size * 2 desc, modtime limit 10").Tokenize()
+	iterator := tokens.Iterator()
+
+	order, _ := NewOrder(iterator, projections, ctx)
+	expected := []AttributeRef{{ProjectionPosition: 2}, {ProjectionPosition: 3}}
+
+	if !reflect.DeepEqual(expected, order.Attributes) {
+		t.Fatalf("Expected order attributes to be %v, received %v", expected, order.Attributes)
+	}
+	if projections.HiddenCount() != 2 {
+		t.Fatalf("Expected %v hidden projections, received %v", 2, projections.HiddenCount())
+	}
+	if !iterator.Peek().Equals("limit") {
+		t.Fatalf("Expected the next token to be %v, received %v", "limit", iterator.Peek().TokenValue)
+	}
+}
+
+func TestOrderByAnUnknownAttribute(t *testing.T) {
+	ctx := context.NewContext(context.NewFunctions(), context.NewAttributes())
+	tokens := tokenizer.NewTokenizer("order by unknown").Tokenize()
+
+	_, err := NewOrder(tokens.Iterator(), projectionsWithCount(1), ctx)
+	if err == nil {
+		t.Fatalf("Expected an error given order by an unknown attribute")
+	}
+}
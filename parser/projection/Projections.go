@@ -7,10 +7,13 @@ import (
 	"goselect/parser/error/messages"
 	"goselect/parser/expression"
 	"goselect/parser/tokenizer"
+	"strings"
 )
 
 type Projections struct {
 	expressions expression.Expressions
+	aliases     []string
+	hiddenCount int
 }
 
 func NewProjections(
@@ -18,18 +21,22 @@ func NewProjections(
 	context *context.ParsingApplicationContext,
 ) (*Projections, error) {
 
-	expressions, err := all(tokenIterator, context)
+	expressions, aliases, err := all(tokenIterator, context)
 	if err != nil {
 		return nil, err
 	}
 	if expressions.Count() == 0 {
 		return nil, errors.New(messages.ErrorMessageExpectedExpressionInProjection)
 	}
-	return &Projections{expressions: expressions}, nil
+	return &Projections{expressions: expressions, aliases: aliases}, nil
 }
 
 func (projections Projections) Count() int {
-	return projections.expressions.Count()
+	return projections.expressions.Count() - projections.hiddenCount
+}
+
+func (projections Projections) HiddenCount() int {
+	return projections.hiddenCount
 }
 
 func (projections Projections) AggregationCount() int {
@@ -37,11 +44,59 @@ func (projections Projections) AggregationCount() int {
 }
 
 func (projections Projections) HasAllAggregates() bool {
-	return projections.Count() == projections.AggregationCount()
+	for index := 0; index < projections.Count(); index++ {
+		if !projections.ExpressionAt(index).HasAnAggregate() {
+			return false
+		}
+	}
+	return true
 }
 
 func (projections Projections) DisplayableAttributes() []string {
-	return projections.expressions.DisplayableAttributes()
+	attributes := projections.expressions.DisplayableAttributes()[0:projections.Count()]
+	for index, alias := range projections.aliases {
+		if len(alias) > 0 {
+			attributes[index] = alias
+		}
+	}
+	return attributes
+}
+
+func (projections Projections) PositionOfAlias(alias string) int {
+	for index, anAlias := range projections.aliases {
+		if len(anAlias) > 0 && strings.EqualFold(anAlias, alias) {
+			return index + 1
+		}
+	}
+	return 0
+}
+
+func (projections Projections) PositionOf(anExpression *expression.Expression) int {
+	display := expression.Expressions{Expressions: []*expression.Expression{anExpression}}.DisplayableAttributes()[0]
+	for index, attribute := range projections.expressions.DisplayableAttributes()[0:projections.Count()] {
+		if strings.EqualFold(attribute, display) {
+			return index + 1
+		}
+	}
+	return 0
+}
+
+func (projections *Projections) AddHidden(anExpression *expression.Expression) int {
+	projections.expressions.Expressions = append(projections.expressions.Expressions, anExpression)
+	projections.hiddenCount = projections.hiddenCount + 1
+	return projections.expressions.Count()
+}
+
+func (projections Projections) ExpressionAt(index int) *expression.Expression {
+	return projections.expressions.ExpressionAt(index)
+}
+
+func (projections Projections) CloneWithInitialState(functions *context.AllFunctions) *Projections {
+	return &Projections{
+		expressions: projections.expressions.CloneWithInitialState(functions),
+		aliases:     projections.aliases,
+		hiddenCount: projections.hiddenCount,
+	}
 }
 
 func (projections Projections) EvaluateWith(
@@ -52,111 +107,68 @@ func (projections Projections) EvaluateWith(
 }
 
 /*
-projection:  attributes Or functions Or expressions
+projection:  attributes Or functions Or expressions, each optionally followed by 'as' alias
 attributes:  name, size etc
 functions: 	 min(size), lower(name), min(Count(size)) etc
-expressions: add(..), mul(..), gt(..)
+expressions: add(..), mul(..), gt(..), size * 2, size > 1024 etc
+aliases:     fmtsize(size) as hsize
 */
 func all(
 	tokenIterator *tokenizer.TokenIterator,
 	ctx *context.ParsingApplicationContext,
-) (expression.Expressions, error) {
+) (expression.Expressions, []string, error) {
 
 	var expressions []*expression.Expression
-	var expectComma bool
+	var aliases []string
+	var expectComma, isLastAWildcard bool
 
-	for tokenIterator.HasNext() && !tokenIterator.Peek().Equals("from") {
-		token := tokenIterator.Next()
+	if tokenIterator.HasNext() && tokenIterator.Peek().Equals("select") {
+		tokenIterator.Next()
+	}
+	parser := expression.NewExpressionParser(
+		tokenIterator,
+		ctx,
+		[]string{"from"},
+		messages.ErrorMessageInvalidProjection,
+		"",
+	)
+	for !parser.IsAtTerminal() {
+		token := tokenIterator.Peek()
 		switch {
+		case expectComma && token.Equals("as"):
+			tokenIterator.Next()
+			if isLastAWildcard {
+				return expression.Expressions{}, nil, errors.New(messages.ErrorMessageAliasOnWildcard)
+			}
+			if parser.IsAtTerminal() || tokenIterator.Peek().Equals(",") {
+				return expression.Expressions{}, nil, errors.New(messages.ErrorMessageMissingAliasAfterAs)
+			}
+			aliases[len(aliases)-1] = tokenIterator.Next().TokenValue
 		case expectComma:
 			if !token.Equals(",") {
-				return expression.Expressions{}, errors.New(messages.ErrorMessageMissingCommaProjection)
+				return expression.Expressions{}, nil, errors.New(messages.ErrorMessageMissingCommaProjection)
 			}
+			tokenIterator.Next()
 			expectComma = false
 		case context.IsAWildcardAttribute(token.TokenValue):
-			expressions = append(expressions, expression.WithAttributes(context.AttributesOnWildcard())...)
-			expectComma = true
-		case ctx.IsASupportedAttribute(token.TokenValue):
-			expressions = append(expressions, expression.WithAttribute(token.TokenValue))
-			expectComma = true
-		case ctx.IsASupportedFunction(token.TokenValue):
-			function, err := function(token, tokenIterator, ctx)
+			tokenIterator.Next()
+			for _, attribute := range context.AttributesOnWildcard() {
+				expressions = append(expressions, expression.WithAttribute(attribute))
+				aliases = append(aliases, "")
+			}
+			expectComma, isLastAWildcard = true, true
+		default:
+			anExpression, err := parser.Parse()
 			if err != nil {
-				return expression.Expressions{}, err
+				return expression.Expressions{}, nil, err
 			}
-			expressions = append(expressions, expression.WithFunctionInstance(function))
-			expectComma = true
-		}
-	}
-	return expression.Expressions{Expressions: expressions}, nil
-}
-
-func function(
-	functionNameToken tokenizer.Token,
-	tokenIterator *tokenizer.TokenIterator,
-	ctx *context.ParsingApplicationContext,
-) (*expression.FunctionInstance, error) {
-
-	var parseFunction func(functionNameToken tokenizer.Token) (*expression.FunctionInstance, error)
-
-	aggregateFunctionStateOrNil := func(fn string) *context.FunctionState {
-		if ctx.IsAnAggregateFunction(fn) {
-			return ctx.InitialState(fn)
-		}
-		return nil
-	}
-	parseFunction = func(functionNameToken tokenizer.Token) (*expression.FunctionInstance, error) {
-		var functionArgs []*expression.Expression
-		expectOpeningParentheses := true
-
-		for tokenIterator.HasNext() && !tokenIterator.Peek().Equals("from") {
-			token := tokenIterator.Next()
-			switch {
-			case expectOpeningParentheses:
-				if !token.Equals("(") {
-					return nil, fmt.Errorf(messages.ErrorMessageOpeningParenthesesProjection, functionNameToken.TokenValue)
-				}
-				expectOpeningParentheses = false
-			case token.Equals(")"):
-				var isAggregate bool
-				if ctx.IsAnAggregateFunction(functionNameToken.TokenValue) {
-					isAggregate = true
-				} else {
-					isAggregate = false
-				}
-				return expression.FunctionInstanceWith(
-					functionNameToken.TokenValue,
-					functionArgs,
-					aggregateFunctionStateOrNil(functionNameToken.TokenValue),
-					isAggregate,
-				), nil
-			case ctx.IsASupportedFunction(token.TokenValue):
-				fn, err := parseFunction(token)
-				if err != nil {
-					return nil, err
-				}
-				functionArgs = append(functionArgs, expression.WithFunctionInstance(fn))
-			case ctx.IsASupportedAttribute(token.TokenValue):
-				functionArgs = append(functionArgs, expression.WithAttribute(token.TokenValue))
-				expectOpeningParentheses = false
-			default:
-				if !token.Equals(",") {
-					value, err := context.ToValue(token)
-					if err != nil {
-						value = context.StringValue(token.TokenValue)
-					}
-					functionArgs = append(functionArgs, expression.WithValue(value))
-				}
+			if anExpression.IsAValue() {
+				return expression.Expressions{}, nil, fmt.Errorf(messages.ErrorMessageUnsupportedValueInProjection, token.TokenValue)
 			}
+			expressions = append(expressions, anExpression)
+			aliases = append(aliases, "")
+			expectComma, isLastAWildcard = true, false
 		}
-		return nil, nil
-	}
-	fn, err := parseFunction(functionNameToken)
-	if err != nil {
-		return nil, err
-	}
-	if fn == nil {
-		return nil, errors.New(messages.ErrorMessageInvalidProjection)
 	}
-	return fn, nil
+	return expression.Expressions{Expressions: expressions}, aliases, nil
 }
@@ -5,6 +5,7 @@ package projection
 
 import (
 	"goselect/parser/context"
+	"goselect/parser/expression"
 	"goselect/parser/tokenizer"
 	"reflect"
 	"testing"
@@ -422,3 +423,61 @@ func TestProjectionEvaluation4(t *testing.T) {
 		t.Fatalf("Expected fullyEvaluated to be %v, received %v", fullyEvaluated, fullyEvaluated[0])
 	}
 }
+
+func TestProjectionsWithAliases(t *testing.T) {
+	tokens := tokenizer.NewTokenizer("select fmtsize(size) as hsize, name, lower(ext) AS lext from .").Tokenize()
+
+	projections, _ := NewProjections(tokens.Iterator(), context.NewContext(context.NewFunctions(), context.NewAttributes()))
+	expected := []string{"hsize", "name", "lext"}
+
+	if !reflect.DeepEqual(expected, projections.DisplayableAttributes()) {
+		t.Fatalf("Expected projections to be %v, received %v", expected, projections.DisplayableAttributes())
+	}
+}
+
+func TestProjectionsWithMissingAliasAfterAs(t *testing.T) {
+	tokens := tokenizer.NewTokenizer("select name as, size from .").Tokenize()
+
+	_, err := NewProjections(tokens.Iterator(), context.NewContext(context.NewFunctions(), context.NewAttributes()))
+	if err == nil {
+		t.Fatalf("Expected an error given a missing alias after as")
+	}
+}
+
+func TestProjectionsWithAnAliasOnWildcard(t *testing.T) {
+	tokens := tokenizer.NewTokenizer("select * as all from .").Tokenize()
+
+	_, err := NewProjections(tokens.Iterator(), context.NewContext(context.NewFunctions(), context.NewAttributes()))
+	if err == nil {
+		t.Fatalf("Expected an error given an alias on wildcard")
+	}
+}
+
+func TestProjectionsPositionOfAlias(t *testing.T) {
+	tokens := tokenizer.NewTokenizer("select name, fmtsize(size) as hsize from .").Tokenize()
+
+	projections, _ := NewProjections(tokens.Iterator(), context.NewContext(context.NewFunctions(), context.NewAttributes()))
+	if projections.PositionOfAlias("HSIZE") != 2 {
+		t.Fatalf("Expected position of alias to be %v, received %v", 2, projections.PositionOfAlias("HSIZE"))
+	}
+	if projections.PositionOfAlias("name") != 0 {
+		t.Fatalf("Expected position of a non-alias to be %v, received %v", 0, projections.PositionOfAlias("name"))
+	}
+}
+
+func TestProjectionsWithAHiddenExpression(t *testing.T) {
+	tokens := tokenizer.NewTokenizer("select name from .").Tokenize()
+
+	projections, _ := NewProjections(tokens.Iterator(), context.NewContext(context.NewFunctions(), context.NewAttributes()))
+	position := projections.AddHidden(expression.WithAttribute("size"))
+
+	if position != 2 {
+		t.Fatalf("Expected position of the hidden expression to be %v, received %v", 2, position)
+	}
+	if projections.Count() != 1 {
+		t.Fatalf("Expected count of visible projections to be %v, received %v", 1, projections.Count())
+	}
+	if !reflect.DeepEqual([]string{"name"}, projections.DisplayableAttributes()) {
+		t.Fatalf("Expected projections to be %v, received %v", []string{"name"}, projections.DisplayableAttributes())
+	}
+}
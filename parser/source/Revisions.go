@@ -0,0 +1,94 @@
+package source
+
+import (
+	"fmt"
+	"goselect/parser/error/messages"
+	"goselect/parser/filesystem"
+	"goselect/parser/git"
+	"path/filepath"
+	"strings"
+)
+
+const revisionPrefix = "git:"
+
+/*
+revisions creates the roots of the form 'git:<revision>:<path>', which read the tree of a revision from the
+repository that the path belongs to, without checking it out.
+The path is relative to the current directory like any other path, and defaults to '.'.
+The roots at the same revision of the same repository share a file system, so that a file reachable from more than
+one of them is returned once.
+*/
+type revisions struct {
+	fileSystem      filesystem.FileSystem
+	treeFileSystems map[string]*git.TreeFileSystem
+}
+
+func newRevisions(fileSystem filesystem.FileSystem) *revisions {
+	return &revisions{fileSystem: fileSystem, treeFileSystems: make(map[string]*git.TreeFileSystem)}
+}
+
+func (revisions *revisions) newRoot(source string) (*Root, error) {
+	revision, path := splitRevision(source)
+	if revision == "" {
+		return nil, fmt.Errorf(messages.ErrorMessageInvalidGitRevision, source, "missing revision")
+	}
+	path, err := ExpandDirectoryPath(path)
+	if err != nil {
+		return nil, err
+	}
+	location := rootFor(path)
+	repository, err := git.OpenRepository(revisions.fileSystem, location.Directory)
+	if err != nil {
+		return nil, err
+	}
+	absolutePath, err := revisions.fileSystem.Abs(location.Directory)
+	if err != nil {
+		return nil, err
+	}
+	relativePath, err := filepath.Rel(repository.Worktree(), absolutePath)
+	if err != nil || relativePath == ".." || strings.HasPrefix(relativePath, ".."+string(filepath.Separator)) {
+		return nil, fmt.Errorf(messages.ErrorMessageSourceOutsideRepository, source, repository.Worktree())
+	}
+	treeFileSystem, err := revisions.treeFileSystemFor(repository, revision)
+	if err != nil {
+		return nil, fmt.Errorf(messages.ErrorMessageInvalidGitRevision, source, err)
+	}
+	treePath := filepath.ToSlash(relativePath)
+	if location.Pattern != "" {
+		treePath = treePath + "/" + location.Pattern
+	}
+	root, err := newRoot(treePath, treeFileSystem)
+	if err != nil {
+		return nil, err
+	}
+	root.Revision = revision
+	root.FileSystem = treeFileSystem
+	treeFileSystem.AddQueriedPath(root.Directory)
+	return root, nil
+}
+
+func (revisions *revisions) treeFileSystemFor(repository *git.Repository, revision string) (*git.TreeFileSystem, error) {
+	commit, err := repository.ResolveRevision(revision)
+	if err != nil {
+		return nil, err
+	}
+	key := repository.Worktree() + ":" + commit.Hash.String()
+	if treeFileSystem, ok := revisions.treeFileSystems[key]; ok {
+		return treeFileSystem, nil
+	}
+	treeFileSystem := git.NewTreeFileSystem(repository, commit)
+	revisions.treeFileSystems[key] = treeFileSystem
+	return treeFileSystem, nil
+}
+
+/*
+splitRevision splits 'git:<revision>:<path>' into the revision and the path. The revision can not contain ':',
+so a path can, for example, 'git:HEAD:C:\projects\goselect'.
+*/
+func splitRevision(source string) (string, string) {
+	parts := strings.SplitN(strings.TrimPrefix(source, revisionPrefix), ":", 2)
+	if len(parts) < 2 || parts[1] == "" {
+		return parts[0], "."
+	}
+	return parts[0], parts[1]
+}
@@ -0,0 +1,89 @@
+//go:build unit
+// +build unit
+
+package source
+
+import (
+	"goselect/parser/filesystem"
+	"goselect/parser/tokenizer"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func newGitRepository(t *testing.T) string {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	directory := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(directory, "src"), 0755); err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(directory, "src", "main.go"), []byte("package main\n"), 0644); err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	for _, arguments := range [][]string{{"init", "-q"}, {"add", "-A"}, {"commit", "-q", "-m", "first"}} {
+		command := exec.Command("git", append([]string{"-c", "user.name=author", "-c", "user.email=author@example.com", "-c", "commit.gpgsign=false"}, arguments...)...)
+		command.Dir = directory
+		if output, err := command.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed with %v, %v", arguments, err, string(output))
+		}
+	}
+	return directory
+}
+
+func TestSplitRevision(t *testing.T) {
+	sources := map[string][2]string{
+		"git:HEAD":             {"HEAD", "."},
+		"git:HEAD:":            {"HEAD", "."},
+		"git:HEAD~10:./src":    {"HEAD~10", "./src"},
+		"git:v1.2:src/*.go":    {"v1.2", "src/*.go"},
+		"git:main:C:\\project": {"main", "C:\\project"},
+	}
+	for source, expected := range sources {
+		revision, path := splitRevision(source)
+		if revision != expected[0] || path != expected[1] {
+			t.Fatalf("Expected %v to split into %v and %v, received %v and %v", source, expected[0], expected[1], revision, path)
+		}
+	}
+}
+
+func TestCreatesANewSourceAtAGitRevision(t *testing.T) {
+	directory := newGitRepository(t)
+	source := "from git:HEAD:" + filepath.Join(directory, "src") + ", git:HEAD:" + filepath.Join(directory, "src", "*.go")
+	tokens := tokenizer.NewTokenizer(source).Tokenize()
+
+	newSource, err := NewSource(tokens.Iterator(), filesystem.Os())
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	first, second := newSource.Roots[0], newSource.Roots[1]
+	if first.Directory != "src" || first.Revision != "HEAD" || first.FileSystem == nil {
+		t.Fatalf("Expected directory and revision to be %v and %v, received %v and %v", "src", "HEAD", first.Directory, first.Revision)
+	}
+	if second.Directory != "src" || second.Pattern != "*.go" || second.FileSystem != first.FileSystem {
+		t.Fatalf("Expected the roots at the same revision to share the file system, with pattern %v, received %v", "*.go", second.Pattern)
+	}
+}
+
+func TestThrowsAnErrorForAnInvalidGitRevision(t *testing.T) {
+	directory := newGitRepository(t)
+	for _, source := range []string{"git::" + directory, "git:unknown:" + directory, "git:HEAD~1:" + directory} {
+		tokens := tokenizer.NewEmptyTokens()
+		tokens.Add(tokenizer.NewToken(tokenizer.RawString, source))
+
+		if _, err := NewSource(tokens.Iterator(), filesystem.Os()); err == nil {
+			t.Fatalf("Expected an error given the source %v, received no error", source)
+		}
+	}
+}
+
+func TestThrowsAnErrorForAGitRevisionOutsideARepository(t *testing.T) {
+	tokens := tokenizer.NewEmptyTokens()
+	tokens.Add(tokenizer.NewToken(tokenizer.RawString, "git:HEAD:"+t.TempDir()))
+
+	if _, err := NewSource(tokens.Iterator(), filesystem.Os()); err == nil {
+		t.Fatalf("Expected an error given a path outside a git repository, received no error")
+	}
+}
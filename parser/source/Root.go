@@ -0,0 +1,138 @@
+package source
+
+import (
+	"fmt"
+	"goselect/parser/error/messages"
+	"goselect/parser/filesystem"
+	"path/filepath"
+	"strings"
+)
+
+const globCharacters = "*?["
+
+/*
+Root is one of the paths in the 'from' clause.
+A glob pattern is split into the directory before the first segment with a glob character, which is walked,
+and the pattern relative to that directory, which the walked entries are matched against.
+For example, '~/logs/2022/*.log' is split into the directory '~/logs/2022' and the pattern '*.log'.
+'**' as a complete segment matches zero or more directories, and the other segments follow filepath.Match.
+A root at a git revision has the revision as written in the query and its own file system over the tree of the
+revision, and the path is relative to the root of the repository. The other roots use the file system of the query.
+The 'stdin' root is a list of the paths of the files, which are not walked.
+*/
+type Root struct {
+	Path       string
+	Directory  string
+	Pattern    string
+	Revision   string
+	FileSystem filesystem.FileSystem
+	file       bool
+	fileList   bool
+}
+
+func rootFor(path string) *Root {
+	segments := strings.Split(filepath.ToSlash(path), "/")
+	for index, segment := range segments {
+		if strings.ContainsAny(segment, globCharacters) {
+			directory := strings.Join(segments[0:index], "/")
+			if index == 0 {
+				directory = "."
+			} else if directory == "" {
+				directory = "/"
+			}
+			return &Root{
+				Path:      path,
+				Directory: filepath.FromSlash(directory),
+				Pattern:   strings.Join(segments[index:], "/"),
+			}
+		}
+	}
+	return &Root{Path: path, Directory: path}
+}
+
+func parentOf(path string) string {
+	index := strings.LastIndex(filepath.ToSlash(path), "/")
+	switch {
+	case index < 0:
+		return "."
+	case index == 0:
+		return path[0:1]
+	default:
+		return path[0:index]
+	}
+}
+
+func (root *Root) String() string {
+	if root.Revision != "" {
+		return revisionPrefix + root.Revision + ":" + root.Path
+	}
+	return root.Path
+}
+
+func (root *Root) IsFile() bool {
+	return root.file
+}
+
+func (root *Root) IsFileList() bool {
+	return root.fileList
+}
+
+func (root *Root) Matches(relativePath string) bool {
+	if root.Pattern == "" {
+		return true
+	}
+	return matchSegments(root.patternSegments(), strings.Split(filepath.ToSlash(relativePath), "/"))
+}
+
+func (root *Root) CanMatchBelow(relativeDirectory string) bool {
+	if root.Pattern == "" {
+		return true
+	}
+	return matchPrefixSegments(root.patternSegments(), strings.Split(filepath.ToSlash(relativeDirectory), "/"))
+}
+
+func (root *Root) patternSegments() []string {
+	return strings.Split(root.Pattern, "/")
+}
+
+func (root *Root) validatePattern() error {
+	for _, segment := range root.patternSegments() {
+		if _, err := filepath.Match(segment, ""); err != nil {
+			return fmt.Errorf(messages.ErrorMessageInvalidSourcePattern, root)
+		}
+	}
+	return nil
+}
+
+func matchSegments(pattern []string, path []string) bool {
+	if len(pattern) == 0 {
+		return len(path) == 0
+	}
+	if pattern[0] == "**" {
+		for index := 0; index <= len(path); index++ {
+			if matchSegments(pattern[1:], path[index:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(path) == 0 {
+		return false
+	}
+	matched, _ := filepath.Match(pattern[0], path[0])
+	return matched && matchSegments(pattern[1:], path[1:])
+}
+
+func matchPrefixSegments(pattern []string, directory []string) bool {
+	if len(directory) == 0 {
+		return len(pattern) > 0
+	}
+	if len(pattern) == 0 {
+		return false
+	}
+	if pattern[0] == "**" {
+		return true
+	}
+	matched, _ := filepath.Match(pattern[0], directory[0])
+	return matched && matchPrefixSegments(pattern[1:], directory[1:])
+}
@@ -0,0 +1,71 @@
+//go:build unit
+// +build unit
+
+package source
+
+import "testing"
+
+func TestRootWithoutAPattern(t *testing.T) {
+	root := rootFor("./src")
+	if root.Directory != "./src" || root.Pattern != "" {
+		t.Fatalf("Expected directory %v without a pattern, received %v and %v", "./src", root.Directory, root.Pattern)
+	}
+	if !root.Matches("any/file.go") || !root.CanMatchBelow("any") {
+		t.Fatalf("Expected a root without a pattern to match everything")
+	}
+}
+
+func TestRootWithAPatternInTheFirstSegment(t *testing.T) {
+	root := rootFor("*.log")
+	if root.Directory != "." || root.Pattern != "*.log" {
+		t.Fatalf("Expected directory %v and pattern %v, received %v and %v", ".", "*.log", root.Directory, root.Pattern)
+	}
+}
+
+func TestRootWithAnAbsolutePattern(t *testing.T) {
+	root := rootFor("/*.log")
+	if root.Directory != "/" || root.Pattern != "*.log" {
+		t.Fatalf("Expected directory %v and pattern %v, received %v and %v", "/", "*.log", root.Directory, root.Pattern)
+	}
+}
+
+func TestRootMatchesASingleLevelPattern(t *testing.T) {
+	root := rootFor("./logs/*.log")
+	if !root.Matches("a.log") {
+		t.Fatalf("Expected a.log to match")
+	}
+	if root.Matches("nested/a.log") {
+		t.Fatalf("Expected nested/a.log to not match")
+	}
+	if root.CanMatchBelow("nested") {
+		t.Fatalf("Expected nested to not be traversed")
+	}
+}
+
+func TestRootMatchesADoubleAsteriskPattern(t *testing.T) {
+	root := rootFor("./logs/**/*.log")
+	for _, path := range []string{"a.log", "2022/a.log", "2022/10/a.log"} {
+		if !root.Matches(path) {
+			t.Fatalf("Expected %v to match", path)
+		}
+	}
+	if root.Matches("2022/a.txt") {
+		t.Fatalf("Expected 2022/a.txt to not match")
+	}
+	if !root.CanMatchBelow("2022/10") {
+		t.Fatalf("Expected 2022/10 to be traversed")
+	}
+}
+
+func TestRootMatchesAPatternWithADirectorySegment(t *testing.T) {
+	root := rootFor("./src/*/test/*.go")
+	if !root.Matches("parser/test/a.go") {
+		t.Fatalf("Expected parser/test/a.go to match")
+	}
+	if !root.CanMatchBelow("parser") || !root.CanMatchBelow("parser/test") {
+		t.Fatalf("Expected parser and parser/test to be traversed")
+	}
+	if root.CanMatchBelow("parser/main") {
+		t.Fatalf("Expected parser/main to not be traversed")
+	}
+}
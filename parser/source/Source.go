@@ -4,40 +4,88 @@ import (
 	"errors"
 	"fmt"
 	"goselect/parser/error/messages"
+	"goselect/parser/filesystem"
 	"goselect/parser/tokenizer"
-	"os"
+	"io/fs"
+	"strings"
 )
 
+const standardInput = "stdin"
+
 type Source struct {
-	Directory string
+	Roots []*Root
 }
 
-func NewSource(tokenIterator *tokenizer.TokenIterator) (*Source, error) {
-	directory, err := getDirectory(tokenIterator)
+/*
+source:  a comma separated list of the paths after 'from'
+path:    a directory, a file or a glob pattern, optionally at a git revision. For example, 'from ./src, ./test',
+'from ~/logs/*.log', 'from ./README.md' or 'from git:v1.2:./src'. 'stdin' is the list of the files read by the
+executor, which is the standard input unless the context has another file list
+*/
+func NewSource(tokenIterator *tokenizer.TokenIterator, fileSystem filesystem.FileSystem) (*Source, error) {
+	paths, err := getPaths(tokenIterator)
 	if err != nil {
 		return nil, err
 	}
-	file, err := os.Stat(directory)
+	var roots []*Root
+	revisions := newRevisions(fileSystem)
+	for _, path := range paths {
+		var root *Root
+		if strings.EqualFold(path, standardInput) {
+			root = &Root{Path: standardInput, Directory: ".", fileList: true}
+		} else if strings.HasPrefix(path, revisionPrefix) {
+			root, err = revisions.newRoot(path)
+		} else {
+			root, err = newRoot(path, fileSystem)
+		}
+		if err != nil {
+			return nil, err
+		}
+		roots = append(roots, root)
+	}
+	return &Source{Roots: roots}, nil
+}
+
+func newRoot(path string, fileSystem filesystem.FileSystem) (*Root, error) {
+	root := rootFor(path)
+	if root.Pattern != "" {
+		if err := root.validatePattern(); err != nil {
+			return nil, err
+		}
+	}
+	file, err := fileSystem.Stat(root.Directory)
 	if err != nil {
-		if os.IsNotExist(err) {
-			return nil, fmt.Errorf(messages.ErrorMessageInaccessibleSource, directory)
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf(messages.ErrorMessageInaccessibleSource, root.Path)
 		}
 		return nil, err
 	}
 	if !file.IsDir() {
-		return nil, errors.New(messages.ErrorMessageSourceNotADirectory)
+		if root.Pattern != "" {
+			return nil, errors.New(messages.ErrorMessageSourceNotADirectory)
+		}
+		root.file = true
+		root.Directory = parentOf(root.Path)
 	}
-	return &Source{Directory: directory}, nil
+	return root, nil
 }
 
-func getDirectory(tokenIterator *tokenizer.TokenIterator) (string, error) {
+func getPaths(tokenIterator *tokenizer.TokenIterator) ([]string, error) {
 	if tokenIterator.HasNext() && tokenIterator.Peek().Equals("from") {
 		tokenIterator.Next()
 	}
-	if tokenIterator.HasNext() && !tokenIterator.Peek().Equals("where") {
+	var paths []string
+	for tokenIterator.HasNext() && !tokenIterator.Peek().Equals("where") {
 		token := tokenIterator.Next()
-		path := token.TokenValue
-		return ExpandDirectoryPath(path)
+		path, err := ExpandDirectoryPath(token.TokenValue)
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, path)
+		if !tokenIterator.HasNext() || tokenIterator.Peek().TokenType != tokenizer.Comma {
+			return paths, nil
+		}
+		tokenIterator.Next()
 	}
-	return "", errors.New(messages.ErrorMessageMissingSource)
+	return nil, errors.New(messages.ErrorMessageMissingSource)
 }
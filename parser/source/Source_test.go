@@ -4,6 +4,7 @@
 package source
 
 import (
+	"goselect/parser/filesystem"
 	"goselect/parser/tokenizer"
 	"os/user"
 	"testing"
@@ -13,9 +14,9 @@ func TestCreatesANewSourceFromCurrentDirectory1(t *testing.T) {
 	tokens := tokenizer.NewEmptyTokens()
 	tokens.Add(tokenizer.NewToken(tokenizer.RawString, "."))
 
-	source, _ := NewSource(tokens.Iterator())
-	if source.Directory != "." {
-		t.Fatalf("Expected Directory path to be %v, received %v", ".", source.Directory)
+	source, _ := NewSource(tokens.Iterator(), filesystem.Os())
+	if source.Roots[0].Directory != "." {
+		t.Fatalf("Expected Directory path to be %v, received %v", ".", source.Roots[0].Directory)
 	}
 }
 
@@ -24,9 +25,9 @@ func TestCreatesANewSourceFromCurrentDirectory2(t *testing.T) {
 	tokens.Add(tokenizer.NewToken(tokenizer.RawString, "."))
 	tokens.Add(tokenizer.NewToken(tokenizer.RawString, "where"))
 
-	source, _ := NewSource(tokens.Iterator())
-	if source.Directory != "." {
-		t.Fatalf("Expected Directory path to be %v, received %v", ".", source.Directory)
+	source, _ := NewSource(tokens.Iterator(), filesystem.Os())
+	if source.Roots[0].Directory != "." {
+		t.Fatalf("Expected Directory path to be %v, received %v", ".", source.Roots[0].Directory)
 	}
 }
 
@@ -35,9 +36,9 @@ func TestCreatesANewSourceFromCurrentDirectory3(t *testing.T) {
 	tokens.Add(tokenizer.NewToken(tokenizer.From, "from"))
 	tokens.Add(tokenizer.NewToken(tokenizer.RawString, "."))
 
-	source, _ := NewSource(tokens.Iterator())
-	if source.Directory != "." {
-		t.Fatalf("Expected Directory path to be %v, received %v", ".", source.Directory)
+	source, _ := NewSource(tokens.Iterator(), filesystem.Os())
+	if source.Roots[0].Directory != "." {
+		t.Fatalf("Expected Directory path to be %v, received %v", ".", source.Roots[0].Directory)
 	}
 }
 
@@ -45,11 +46,11 @@ func TestCreatesANewSourceWithHomeDirectorySymbol1(t *testing.T) {
 	tokens := tokenizer.NewEmptyTokens()
 	tokens.Add(tokenizer.NewToken(tokenizer.RawString, "~"))
 
-	source, _ := NewSource(tokens.Iterator())
+	source, _ := NewSource(tokens.Iterator(), filesystem.Os())
 	expectedPath := homeDirectory()
 
-	if source.Directory != expectedPath {
-		t.Fatalf("Expected Directory path to be %v, received %v", expectedPath, source.Directory)
+	if source.Roots[0].Directory != expectedPath {
+		t.Fatalf("Expected Directory path to be %v, received %v", expectedPath, source.Roots[0].Directory)
 	}
 }
 
@@ -57,11 +58,11 @@ func TestCreatesANewSourceWithHomeDirectorySymbol2(t *testing.T) {
 	tokens := tokenizer.NewEmptyTokens()
 	tokens.Add(tokenizer.NewToken(tokenizer.RawString, "~"))
 
-	source, _ := NewSource(tokens.Iterator())
+	source, _ := NewSource(tokens.Iterator(), filesystem.Os())
 	expectedPath := homeDirectory()
 
-	if source.Directory != expectedPath {
-		t.Fatalf("Expected Directory path to be %v, received %v", expectedPath, source.Directory)
+	if source.Roots[0].Directory != expectedPath {
+		t.Fatalf("Expected Directory path to be %v, received %v", expectedPath, source.Roots[0].Directory)
 	}
 }
 
@@ -69,25 +70,93 @@ func TestThrowsAnErrorForInaccessiblePath(t *testing.T) {
 	tokens := tokenizer.NewEmptyTokens()
 	tokens.Add(tokenizer.NewToken(tokenizer.RawString, "~/apps"))
 
-	_, err := NewSource(tokens.Iterator())
+	_, err := NewSource(tokens.Iterator(), filesystem.Os())
 	if err == nil {
 		t.Fatalf("Expected an error given an invalid path, received no error")
 	}
 }
 
-func TestThrowsAnErrorForSourcePathIfItIsNotADirectory(t *testing.T) {
+func TestCreatesANewSourceFromAFile(t *testing.T) {
 	tokens := tokenizer.NewEmptyTokens()
 	tokens.Add(tokenizer.NewToken(tokenizer.RawString, "./Source.go"))
 
-	_, err := NewSource(tokens.Iterator())
+	source, err := NewSource(tokens.Iterator(), filesystem.Os())
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	if !source.Roots[0].IsFile() || source.Roots[0].Directory != "." {
+		t.Fatalf("Expected a file root in the directory %v, received %v", ".", source.Roots[0].Directory)
+	}
+}
+
+func TestThrowsAnErrorForAGlobPatternInsideAFile(t *testing.T) {
+	tokens := tokenizer.NewEmptyTokens()
+	tokens.Add(tokenizer.NewToken(tokenizer.RawString, "./Source.go/*.go"))
+
+	_, err := NewSource(tokens.Iterator(), filesystem.Os())
 	if err == nil {
-		t.Fatalf("Expected an error given source path as a file, not a directory, received no error")
+		t.Fatalf("Expected an error given a glob pattern inside a file, received no error")
+	}
+}
+
+func TestCreatesANewSourceWithMultipleRoots(t *testing.T) {
+	tokens := tokenizer.NewTokenizer("from ., ../source where eq(1, 1)").Tokenize()
+
+	source, err := NewSource(tokens.Iterator(), filesystem.Os())
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	if len(source.Roots) != 2 || source.Roots[0].Path != "." || source.Roots[1].Path != "../source" {
+		t.Fatalf("Expected roots to be %v and %v, received %v", ".", "../source", source.Roots)
+	}
+}
+
+func TestCreatesANewSourceWithAGlobPattern(t *testing.T) {
+	tokens := tokenizer.NewTokenizer("from ../**/*.go").Tokenize()
+
+	source, err := NewSource(tokens.Iterator(), filesystem.Os())
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	root := source.Roots[0]
+	if root.Directory != ".." || root.Pattern != "**/*.go" {
+		t.Fatalf("Expected directory %v and pattern %v, received %v and %v", "..", "**/*.go", root.Directory, root.Pattern)
+	}
+}
+
+func TestThrowsAnErrorForAMissingRootAfterAComma(t *testing.T) {
+	tokens := tokenizer.NewTokenizer("from ., where eq(1, 1)").Tokenize()
+
+	_, err := NewSource(tokens.Iterator(), filesystem.Os())
+	if err == nil {
+		t.Fatalf("Expected an error given a missing root after a comma, received no error")
+	}
+}
+
+func TestThrowsAnErrorForAnInvalidGlobPattern(t *testing.T) {
+	tokens := tokenizer.NewTokenizer("from ./[a-").Tokenize()
+
+	_, err := NewSource(tokens.Iterator(), filesystem.Os())
+	if err == nil {
+		t.Fatalf("Expected an error given an invalid glob pattern, received no error")
+	}
+}
+
+func TestCreatesANewSourceFromStandardInput(t *testing.T) {
+	tokens := tokenizer.NewTokenizer("from stdin, . where eq(1, 1)").Tokenize()
+
+	source, err := NewSource(tokens.Iterator(), filesystem.Os())
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	if !source.Roots[0].IsFileList() || source.Roots[0].Path != "stdin" || source.Roots[1].IsFileList() {
+		t.Fatalf("Expected the first root to be the file list from stdin, received %v", source.Roots)
 	}
 }
 
 func TestThrowsAnErrorWithoutAnyTokens(t *testing.T) {
 	tokens := tokenizer.NewEmptyTokens()
-	_, err := NewSource(tokens.Iterator())
+	_, err := NewSource(tokens.Iterator(), filesystem.Os())
 
 	if err == nil {
 		t.Fatalf("Expected error to be non-nil when creating a source without any tokens")
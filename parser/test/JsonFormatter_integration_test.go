@@ -8,6 +8,7 @@ import (
 	"goselect/parser/context"
 	"goselect/parser/executor"
 	"goselect/parser/writer"
+	"strings"
 	"testing"
 )
 
@@ -30,3 +31,26 @@ func TestJsonFormatter(t *testing.T) {
 		t.Fatalf("Expected json formatter to format %v, received %v", expected, json)
 	}
 }
+
+func TestJsonFormatterOnStreamingRows(t *testing.T) {
+	newContext := context.NewContext(context.NewFunctions(), context.NewAttributes())
+	aParser, err := parser.NewParser("select lower(name) from ./resources/TestResultsWithProjections/single", newContext)
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	selectQuery, err := aParser.Parse()
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	queryResults, _ := executor.NewSelectQueryExecutor(selectQuery, newContext, executor.NewDefaultOptions()).ExecuteStreaming()
+
+	var json strings.Builder
+	if err := writer.NewJsonFormatter().FormatTo(&json, selectQuery.Projections, queryResults); err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	expected := "[{\"lower(name)\" : \"testresultswithprojections_a.txt\"}]"
+
+	if expected != json.String() {
+		t.Fatalf("Expected json formatter to format %v, received %v", expected, json.String())
+	}
+}
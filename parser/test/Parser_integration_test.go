@@ -253,3 +253,54 @@ func TestParsesAQueryIntoAnASTWithMultipleProjectionsInCaseInsensitiveManner(t *
 		t.Fatalf("Expected attributes to be %v, received %v", attributes, expectedAttributes)
 	}
 }
+
+func TestParsesAQueryIntoAnASTWithGroupBy(t *testing.T) {
+	parser, _ := parser.NewParser("SELECT ext, count() from ~ where eq(isdir, false) Group by 1, lower(name) Order by 2 Limit 10", context.NewContext(context.NewFunctions(), context.NewAttributes()))
+	selectStatement, _ := parser.Parse()
+
+	attributes := selectStatement.Group.DisplayableAttributes()
+	expectedAttributes := []string{"ext", "lower(name)"}
+
+	if !reflect.DeepEqual(attributes, expectedAttributes) {
+		t.Fatalf("Expected group by attributes to be %v, received %v", expectedAttributes, attributes)
+	}
+
+	attributeRefs := selectStatement.Order.Attributes
+	expectedAscending := []order.AttributeRef{{
+		ProjectionPosition: 2,
+	}}
+
+	if !reflect.DeepEqual(attributeRefs, expectedAscending) {
+		t.Fatalf("Expected ordering attributes to be %v, received %v", expectedAscending, attributeRefs)
+	}
+}
+
+func TestParsesASelectQueryWithAnErrorInGroupBy(t *testing.T) {
+	parser, _ := parser.NewParser("select name from . where eq(1,1) group order by 1", context.NewContext(context.NewFunctions(), context.NewAttributes()))
+	_, err := parser.Parse()
+
+	if err == nil {
+		t.Fatalf("Expected an error while parsing a select with an error in group by")
+	}
+}
+
+func TestParsesAQueryIntoAnASTWithHaving(t *testing.T) {
+	parser, _ := parser.NewParser("select ext, count() from . group by 1 having gt(count(), 1) order by 2", context.NewContext(context.NewFunctions(), context.NewAttributes()))
+	selectStatement, _ := parser.Parse()
+
+	having := selectStatement.Having.Display()
+	expected := "gt(count(),1)"
+
+	if having != expected {
+		t.Fatalf("Expected having clause to be %v, received %v", expected, having)
+	}
+}
+
+func TestParsesASelectQueryWithHavingWithoutGroupBy(t *testing.T) {
+	parser, _ := parser.NewParser("select name from . where eq(1,1) having gt(count(), 1)", context.NewContext(context.NewFunctions(), context.NewAttributes()))
+	_, err := parser.Parse()
+
+	if err == nil {
+		t.Fatalf("Expected an error while parsing a select with having but without group by")
+	}
+}
@@ -0,0 +1,137 @@
+//go:build integration
+// +build integration
+
+package test
+
+import (
+	"goselect/parser"
+	"goselect/parser/context"
+	"goselect/parser/executor"
+	"testing"
+)
+
+func TestResultsWithGroupByAnAttributeIncludingCount(t *testing.T) {
+	newContext := context.NewContext(context.NewFunctions(), context.NewAttributes())
+	aParser, err := parser.NewParser("select ext, count() from ./resources/TestResultsWithProjections/multi group by ext order by 1", newContext)
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	selectQuery, err := aParser.Parse()
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	queryResults, _ := executor.NewSelectQueryExecutor(selectQuery, newContext, executor.NewDefaultOptions()).Execute()
+	expected := [][]context.Value{
+		{context.StringValue(".log"), context.Uint32Value(2)},
+		{context.StringValue(".txt"), context.Uint32Value(2)},
+	}
+	executor.AssertMatch(t, expected, queryResults)
+}
+
+func TestResultsWithGroupByAPositionIncludingSum(t *testing.T) {
+	newContext := context.NewContext(context.NewFunctions(), context.NewAttributes())
+	aParser, err := parser.NewParser("select ext, sum(size), fmtsize(sum(size)) from ./resources/TestResultsWithProjections/multi group by 1 order by 1", newContext)
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	selectQuery, err := aParser.Parse()
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	queryResults, _ := executor.NewSelectQueryExecutor(selectQuery, newContext, executor.NewDefaultOptions()).Execute()
+	expected := [][]context.Value{
+		{context.StringValue(".log"), context.Float64Value(129), context.StringValue("129 B")},
+		{context.StringValue(".txt"), context.Float64Value(116), context.StringValue("116 B")},
+	}
+	executor.AssertMatch(t, expected, queryResults)
+}
+
+func TestResultsWithGroupByAFunctionIncludingMinAndMax(t *testing.T) {
+	newContext := context.NewContext(context.NewFunctions(), context.NewAttributes())
+	aParser, err := parser.NewParser("select upper(ext), min(size), max(size) from ./resources/TestResultsWithProjections/multi group by upper(ext) order by 1 desc", newContext)
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	selectQuery, err := aParser.Parse()
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	queryResults, _ := executor.NewSelectQueryExecutor(selectQuery, newContext, executor.NewDefaultOptions()).Execute()
+	expected := [][]context.Value{
+		{context.StringValue(".TXT"), context.Int64Value(58), context.Int64Value(58)},
+		{context.StringValue(".LOG"), context.Int64Value(58), context.Int64Value(71)},
+	}
+	executor.AssertMatch(t, expected, queryResults)
+}
+
+func TestResultsWithGroupByMultipleAttributes(t *testing.T) {
+	newContext := context.NewContext(context.NewFunctions(), context.NewAttributes())
+	aParser, err := parser.NewParser("select ext, size, count() from ./resources/TestResultsWithProjections/multi group by ext, size order by 1, 2", newContext)
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	selectQuery, err := aParser.Parse()
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	queryResults, _ := executor.NewSelectQueryExecutor(selectQuery, newContext, executor.NewDefaultOptions()).Execute()
+	expected := [][]context.Value{
+		{context.StringValue(".log"), context.Int64Value(58), context.Uint32Value(1)},
+		{context.StringValue(".log"), context.Int64Value(71), context.Uint32Value(1)},
+		{context.StringValue(".txt"), context.Int64Value(58), context.Uint32Value(2)},
+	}
+	executor.AssertMatch(t, expected, queryResults)
+}
+
+func TestResultsWithGroupByWithOnlyAggregateFunctionsInProjection(t *testing.T) {
+	newContext := context.NewContext(context.NewFunctions(), context.NewAttributes())
+	aParser, err := parser.NewParser("select count(), countdistinct(size) from ./resources/TestResultsWithProjections/multi group by ext order by 2", newContext)
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	selectQuery, err := aParser.Parse()
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	queryResults, _ := executor.NewSelectQueryExecutor(selectQuery, newContext, executor.NewDefaultOptions()).Execute()
+	expected := [][]context.Value{
+		{context.Uint32Value(2), context.Uint32Value(1)},
+		{context.Uint32Value(2), context.Uint32Value(2)},
+	}
+	executor.AssertMatch(t, expected, queryResults)
+}
+
+func TestResultsWithGroupByAndWhere(t *testing.T) {
+	newContext := context.NewContext(context.NewFunctions(), context.NewAttributes())
+	aParser, err := parser.NewParser("select ext, count() from ./resources/TestResultsWithProjections/multi where eq(size, 58) group by ext order by 1", newContext)
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	selectQuery, err := aParser.Parse()
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	queryResults, _ := executor.NewSelectQueryExecutor(selectQuery, newContext, executor.NewDefaultOptions()).Execute()
+	expected := [][]context.Value{
+		{context.StringValue(".log"), context.Uint32Value(1)},
+		{context.StringValue(".txt"), context.Uint32Value(2)},
+	}
+	executor.AssertMatch(t, expected, queryResults)
+}
+
+func TestResultsWithGroupByAndLimit(t *testing.T) {
+	newContext := context.NewContext(context.NewFunctions(), context.NewAttributes())
+	aParser, err := parser.NewParser("select ext, count() from ./resources/TestResultsWithProjections/multi group by ext order by 1 desc limit 1", newContext)
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	selectQuery, err := aParser.Parse()
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	queryResults, _ := executor.NewSelectQueryExecutor(selectQuery, newContext, executor.NewDefaultOptions()).Execute()
+	expected := [][]context.Value{
+		{context.StringValue(".txt"), context.Uint32Value(2)},
+	}
+	executor.AssertMatch(t, expected, queryResults)
+}
@@ -0,0 +1,96 @@
+//go:build integration
+// +build integration
+
+package test
+
+import (
+	"goselect/parser"
+	"goselect/parser/context"
+	"goselect/parser/executor"
+	"testing"
+)
+
+func TestResultsWithHavingOnCount(t *testing.T) {
+	newContext := context.NewContext(context.NewFunctions(), context.NewAttributes())
+	aParser, err := parser.NewParser("select ext, count() from ./resources/TestResultsWithProjections/multi group by ext, size having gt(count(), 1)", newContext)
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	selectQuery, err := aParser.Parse()
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	queryResults, _ := executor.NewSelectQueryExecutor(selectQuery, newContext, executor.NewDefaultOptions()).Execute()
+	expected := [][]context.Value{
+		{context.StringValue(".txt"), context.Uint32Value(2)},
+	}
+	executor.AssertMatch(t, expected, queryResults)
+}
+
+func TestResultsWithHavingOnAnAggregateNotInProjection(t *testing.T) {
+	newContext := context.NewContext(context.NewFunctions(), context.NewAttributes())
+	aParser, err := parser.NewParser("select ext from ./resources/TestResultsWithProjections/multi group by ext having gt(max(size), 60)", newContext)
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	selectQuery, err := aParser.Parse()
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	queryResults, _ := executor.NewSelectQueryExecutor(selectQuery, newContext, executor.NewDefaultOptions()).Execute()
+	expected := [][]context.Value{
+		{context.StringValue(".log")},
+	}
+	executor.AssertMatch(t, expected, queryResults)
+}
+
+func TestResultsWithHavingOnAGroupByAttributeAndAnAggregate(t *testing.T) {
+	newContext := context.NewContext(context.NewFunctions(), context.NewAttributes())
+	aParser, err := parser.NewParser("select ext, sum(size) from ./resources/TestResultsWithProjections/multi group by ext having and(eq(ext, .txt), gt(sum(size), 100))", newContext)
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	selectQuery, err := aParser.Parse()
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	queryResults, _ := executor.NewSelectQueryExecutor(selectQuery, newContext, executor.NewDefaultOptions()).Execute()
+	expected := [][]context.Value{
+		{context.StringValue(".txt"), context.Float64Value(116)},
+	}
+	executor.AssertMatch(t, expected, queryResults)
+}
+
+func TestResultsWithHavingAndOrderAndLimit(t *testing.T) {
+	newContext := context.NewContext(context.NewFunctions(), context.NewAttributes())
+	aParser, err := parser.NewParser("select ext, count() from ./resources/TestResultsWithProjections/multi group by ext having gte(count(), 2) order by 1 desc limit 1", newContext)
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	selectQuery, err := aParser.Parse()
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	queryResults, _ := executor.NewSelectQueryExecutor(selectQuery, newContext, executor.NewDefaultOptions()).Execute()
+	expected := [][]context.Value{
+		{context.StringValue(".txt"), context.Uint32Value(2)},
+	}
+	executor.AssertMatch(t, expected, queryResults)
+}
+
+func TestResultsWithHavingAndLimitWithoutOrder(t *testing.T) {
+	newContext := context.NewContext(context.NewFunctions(), context.NewAttributes())
+	aParser, err := parser.NewParser("select ext from ./resources/TestResultsWithProjections/multi group by ext having gt(max(size), 60) limit 1", newContext)
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	selectQuery, err := aParser.Parse()
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	queryResults, _ := executor.NewSelectQueryExecutor(selectQuery, newContext, executor.NewDefaultOptions()).Execute()
+	expected := [][]context.Value{
+		{context.StringValue(".log")},
+	}
+	executor.AssertMatch(t, expected, queryResults)
+}
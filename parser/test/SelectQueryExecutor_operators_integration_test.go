@@ -0,0 +1,134 @@
+//go:build integration
+// +build integration
+
+package test
+
+import (
+	"goselect/parser"
+	"goselect/parser/context"
+	"goselect/parser/executor"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResultsWithArithmeticOperatorsInProjection(t *testing.T) {
+	newContext := context.NewContext(context.NewFunctions(), context.NewAttributes())
+	aParser, err := parser.NewParser("select name, size * 2 + 1 from ./resources/TestResultsWithProjections/multi where eq(name, TestResultsWithProjections_A.log)", newContext)
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	selectQuery, err := aParser.Parse()
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	queryResults, _ := executor.NewSelectQueryExecutor(selectQuery, newContext, executor.NewDefaultOptions()).Execute()
+	expected := [][]context.Value{
+		{context.StringValue("TestResultsWithProjections_A.log"), context.Float64Value(143)},
+	}
+	executor.AssertMatch(t, expected, queryResults)
+}
+
+func TestResultsWithComparisonAndLogicalOperatorsInWhere(t *testing.T) {
+	newContext := context.NewContext(context.NewFunctions(), context.NewAttributes())
+	aParser, err := parser.NewParser("select name from ./resources/TestResultsWithProjections/multi where size > 60 or (ext = .txt and not name like 'C') order by 1", newContext)
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	selectQuery, err := aParser.Parse()
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	queryResults, _ := executor.NewSelectQueryExecutor(selectQuery, newContext, executor.NewDefaultOptions()).Execute()
+	expected := [][]context.Value{
+		{context.StringValue("TestResultsWithProjections_A.log")},
+		{context.StringValue("TestResultsWithProjections_D.txt")},
+	}
+	executor.AssertMatch(t, expected, queryResults)
+}
+
+func TestResultsWithOperatorsMixedWithFunctionsInWhere(t *testing.T) {
+	newContext := context.NewContext(context.NewFunctions(), context.NewAttributes())
+	aParser, err := parser.NewParser("select name from ./resources/TestResultsWithProjections/multi where eq(ext, .log) and size <= 58", newContext)
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	selectQuery, err := aParser.Parse()
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	queryResults, _ := executor.NewSelectQueryExecutor(selectQuery, newContext, executor.NewDefaultOptions()).Execute()
+	expected := [][]context.Value{
+		{context.StringValue("TestResultsWithProjections_B.log")},
+	}
+	executor.AssertMatch(t, expected, queryResults)
+}
+
+func TestResultsWithOperatorsOnAggregateFunctions(t *testing.T) {
+	newContext := context.NewContext(context.NewFunctions(), context.NewAttributes())
+	aParser, err := parser.NewParser("select ext, sum(size) / count() * 2 from ./resources/TestResultsWithProjections/multi group by ext having count() >= 2 and ext != .log", newContext)
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	selectQuery, err := aParser.Parse()
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	queryResults, _ := executor.NewSelectQueryExecutor(selectQuery, newContext, executor.NewDefaultOptions()).Execute()
+	expected := [][]context.Value{
+		{context.StringValue(".txt"), context.Float64Value(116)},
+	}
+	executor.AssertMatch(t, expected, queryResults)
+}
+
+func TestResultsWithAnOperatorInGroupBy(t *testing.T) {
+	newContext := context.NewContext(context.NewFunctions(), context.NewAttributes())
+	aParser, err := parser.NewParser("select size > 60, count() from ./resources/TestResultsWithProjections/multi group by size > 60 order by 2", newContext)
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	selectQuery, err := aParser.Parse()
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	queryResults, _ := executor.NewSelectQueryExecutor(selectQuery, newContext, executor.NewDefaultOptions()).Execute()
+	expected := [][]context.Value{
+		{context.BooleanValue(true), context.Uint32Value(1)},
+		{context.BooleanValue(false), context.Uint32Value(3)},
+	}
+	executor.AssertMatch(t, expected, queryResults)
+}
+
+func TestResultsWithAnErrorGivenAnAggregateFunctionInsideWhere(t *testing.T) {
+	newContext := context.NewContext(context.NewFunctions(), context.NewAttributes())
+	aParser, _ := parser.NewParser("select name from ./resources/TestResultsWithProjections/multi where count() > 1", newContext)
+
+	_, err := aParser.Parse()
+	if err == nil {
+		t.Fatalf("Expected an error given an aggregate function inside where")
+	}
+}
+
+func TestResultsWithAComparisonOperatorInTheSourcePathAndAFunctionArgument(t *testing.T) {
+	directoryName, _ := os.MkdirTemp(".", "a=b")
+	file, _ := os.Create(filepath.Join(directoryName, "c=d.txt"))
+	defer func() {
+		file.Close()
+		os.RemoveAll(directoryName)
+	}()
+
+	newContext := context.NewContext(context.NewFunctions(), context.NewAttributes())
+	aParser, err := parser.NewParser("select name from "+directoryName+" where eq(name, c=d.txt)", newContext)
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	selectQuery, err := aParser.Parse()
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	queryResults, _ := executor.NewSelectQueryExecutor(selectQuery, newContext, executor.NewDefaultOptions()).Execute()
+	expected := [][]context.Value{
+		{context.StringValue("c=d.txt")},
+	}
+	executor.AssertMatch(t, expected, queryResults)
+}
@@ -0,0 +1,99 @@
+//go:build integration
+// +build integration
+
+package test
+
+import (
+	"goselect/parser"
+	"goselect/parser/context"
+	"goselect/parser/executor"
+	"testing"
+)
+
+func TestResultsWithOrderByAnAlias(t *testing.T) {
+	newContext := context.NewContext(context.NewFunctions(), context.NewAttributes())
+	aParser, err := parser.NewParser("select size * 2 as double, name from ./resources/TestResultsWithProjections/multi order by double desc, name", newContext)
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	selectQuery, err := aParser.Parse()
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	queryResults, _ := executor.NewSelectQueryExecutor(selectQuery, newContext, executor.NewDefaultOptions()).Execute()
+	expected := [][]context.Value{
+		{context.Float64Value(142), context.StringValue("TestResultsWithProjections_A.log")},
+		{context.Float64Value(116), context.StringValue("TestResultsWithProjections_B.log")},
+		{context.Float64Value(116), context.StringValue("TestResultsWithProjections_C.txt")},
+		{context.Float64Value(116), context.StringValue("TestResultsWithProjections_D.txt")},
+	}
+	executor.AssertMatch(t, expected, queryResults)
+}
+
+func TestResultsWithOrderByAnAttributeNotInTheProjection(t *testing.T) {
+	newContext := context.NewContext(context.NewFunctions(), context.NewAttributes())
+	aParser, err := parser.NewParser("select name from ./resources/TestResultsWithProjections/multi order by size desc, name desc limit 2", newContext)
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	selectQuery, err := aParser.Parse()
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	queryResults, _ := executor.NewSelectQueryExecutor(selectQuery, newContext, executor.NewDefaultOptions()).Execute()
+	expected := [][]context.Value{
+		{context.StringValue("TestResultsWithProjections_A.log")},
+		{context.StringValue("TestResultsWithProjections_D.txt")},
+	}
+	executor.AssertMatch(t, expected, queryResults)
+}
+
+func TestResultsWithOrderByAnAggregateNotInTheProjection(t *testing.T) {
+	newContext := context.NewContext(context.NewFunctions(), context.NewAttributes())
+	aParser, err := parser.NewParser("select ext from ./resources/TestResultsWithProjections/multi group by ext order by sum(size)", newContext)
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	selectQuery, err := aParser.Parse()
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	queryResults, _ := executor.NewSelectQueryExecutor(selectQuery, newContext, executor.NewDefaultOptions()).Execute()
+	expected := [][]context.Value{
+		{context.StringValue(".txt")},
+		{context.StringValue(".log")},
+	}
+	executor.AssertMatch(t, expected, queryResults)
+}
+
+func TestResultsWithOrderByAnExpression(t *testing.T) {
+	newContext := context.NewContext(context.NewFunctions(), context.NewAttributes())
+	aParser, err := parser.NewParser("select name, size from ./resources/TestResultsWithProjections/multi order by lower(ext) desc, name limit 2", newContext)
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	selectQuery, err := aParser.Parse()
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	queryResults, _ := executor.NewSelectQueryExecutor(selectQuery, newContext, executor.NewDefaultOptions()).Execute()
+	expected := [][]context.Value{
+		{context.StringValue("TestResultsWithProjections_C.txt"), context.Int64Value(58)},
+		{context.StringValue("TestResultsWithProjections_D.txt"), context.Int64Value(58)},
+	}
+	executor.AssertMatch(t, expected, queryResults)
+}
+
+func TestResultsWithOrderByAndLimitMatchTheFullOrdering(t *testing.T) {
+	expected := allRowsOf(executeWithOptions(t, "select name, size from ./resources order by 2 desc, 1", executor.NewDefaultOptions()))[0:3]
+	queryResults := executeWithOptions(t, "select name, size from ./resources order by 2 desc, 1 limit 3", executor.NewDefaultOptions())
+
+	executor.AssertMatch(t, expected, queryResults)
+}
+
+func TestResultsWithOrderByOnEqualValuesAndLimitMatchTheFullOrdering(t *testing.T) {
+	expected := allRowsOf(executeWithOptions(t, "select name, isdir from ./resources order by 2 desc", executor.NewDefaultOptions()))[0:4]
+	queryResults := executeWithOptions(t, "select name, isdir from ./resources order by 2 desc limit 4", executor.NewDefaultOptions())
+
+	executor.AssertMatch(t, expected, queryResults)
+}
@@ -0,0 +1,100 @@
+//go:build integration
+// +build integration
+
+package test
+
+import (
+	"goselect/parser"
+	"goselect/parser/context"
+	"goselect/parser/executor"
+	"testing"
+)
+
+func TestResultsWithParallelismMatchASequentialRunWithOrderBy(t *testing.T) {
+	query := "select name, size, ext from ./resources order by 3, 1 desc"
+
+	expected := allRowsOf(executeWithOptions(t, query, executor.NewDefaultOptions()))
+	queryResults := executeWithOptions(t, query, executor.NewDefaultOptions().WithParallelism(4))
+
+	executor.AssertMatch(t, expected, queryResults)
+}
+
+func TestResultsWithParallelismMatchASequentialRunWithoutOrderBy(t *testing.T) {
+	query := "select path, isdir, isempty from ./resources"
+
+	expected := allRowsOf(executeWithOptions(t, query, executor.NewDefaultOptions()))
+	queryResults := executeWithOptions(t, query, executor.NewDefaultOptions().WithParallelism(3))
+
+	executor.AssertMatch(t, expected, queryResults)
+}
+
+func TestResultsWithParallelismMatchASequentialRunWithAggregates(t *testing.T) {
+	query := "select count(), sum(size), min(length(name)) from ./resources where eq(isdir, false)"
+
+	expected := allRowsOf(executeWithOptions(t, query, executor.NewDefaultOptions()))
+	queryResults := executeWithOptions(t, query, executor.NewDefaultOptions().WithParallelism(4))
+
+	executor.AssertMatch(t, expected, queryResults)
+}
+
+func TestResultsWithParallelismMatchASequentialRunWithGroupByAndHaving(t *testing.T) {
+	query := "select ext, count(), sum(size) from ./resources group by ext having gt(count(), 0) order by 2 desc, 1"
+
+	expected := allRowsOf(executeWithOptions(t, query, executor.NewDefaultOptions()))
+	queryResults := executeWithOptions(t, query, executor.NewDefaultOptions().WithParallelism(4))
+
+	executor.AssertMatch(t, expected, queryResults)
+}
+
+func TestResultsWithParallelismAndLimit(t *testing.T) {
+	query := "select name from ./resources where like(name, .*.log) limit 2"
+
+	queryResults := executeWithOptions(t, query, executor.NewDefaultOptions().WithParallelism(4))
+	if queryResults.Count() != 2 {
+		t.Fatalf("Expected 2 rows, received %v", queryResults.Count())
+	}
+}
+
+func TestResultsWithParallelismOnANonExistingDirectory(t *testing.T) {
+	newContext := context.NewContext(context.NewFunctions(), context.NewAttributes())
+	aParser, err := parser.NewParser("select name from ./resources", newContext)
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	selectQuery, err := aParser.Parse()
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	selectQuery.Source.Roots[0].Directory = "./resources/non-existing"
+
+	_, err = executor.NewSelectQueryExecutor(selectQuery, newContext, executor.NewDefaultOptions().WithParallelism(4)).Execute()
+	if err == nil {
+		t.Fatalf("Expected an error while reading a non-existing directory")
+	}
+}
+
+func executeWithOptions(t *testing.T, query string, options *executor.Options) *executor.EvaluatingRows {
+	newContext := context.NewContext(context.NewFunctions(), context.NewAttributes())
+	aParser, err := parser.NewParser(query, newContext)
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	selectQuery, err := aParser.Parse()
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	queryResults, err := executor.NewSelectQueryExecutor(selectQuery, newContext, options).Execute()
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	return queryResults
+}
+
+func allRowsOf(queryResults executor.Rows) [][]context.Value {
+	var rows [][]context.Value
+	iterator := queryResults.RowIterator()
+	for iterator.HasNext() {
+		rows = append(rows, iterator.Next().AllAttributes())
+	}
+	return rows
+}
@@ -644,3 +644,35 @@ func TestResultsWithAWhereClause30(t *testing.T) {
 	}
 	executor.AssertMatch(t, expected, queryResults)
 }
+
+func TestResultsWithImageDimensionsInWhere(t *testing.T) {
+	newContext := context.NewContext(context.NewFunctions(), context.NewAttributes())
+	aParser, err := parser.NewParser("select lower(name), imagewidth, imageheight, imageformat from ./resources/ where gt(imagewidth, 3000) order by exif_datetime", newContext)
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	selectQuery, err := aParser.Parse()
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	queryResults, _ := executor.NewSelectQueryExecutor(selectQuery, newContext, executor.NewDefaultOptions()).Execute()
+	expected := [][]context.Value{
+		{context.StringValue("where.png"), context.Int64Value(3078), context.Int64Value(204), context.StringValue("png")},
+	}
+	executor.AssertMatch(t, expected, queryResults)
+}
+
+func TestResultsWithBinaryTypeInWhere(t *testing.T) {
+	newContext := context.NewContext(context.NewFunctions(), context.NewAttributes())
+	aParser, err := parser.NewParser("select lower(name), binarytype, goversion from ./resources/ where or(gt(len(binarytype), 0), isstatic)", newContext)
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	selectQuery, err := aParser.Parse()
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	queryResults, _ := executor.NewSelectQueryExecutor(selectQuery, newContext, executor.NewDefaultOptions()).Execute()
+	var expected [][]context.Value
+	executor.AssertMatch(t, expected, queryResults)
+}
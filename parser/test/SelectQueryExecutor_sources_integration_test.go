@@ -0,0 +1,90 @@
+//go:build integration
+// +build integration
+
+package test
+
+import (
+	"goselect/parser/context"
+	"goselect/parser/executor"
+	"testing"
+)
+
+func TestResultsWithMultipleRoots(t *testing.T) {
+	query := "select root, name from ./resources/TestResultsWithProjections/single, ./resources/TestResultsWithProjections/empty order by 2"
+	queryResults := executeWithOptions(t, query, executor.NewDefaultOptions())
+
+	expected := [][]context.Value{
+		{context.StringValue("./resources/TestResultsWithProjections/empty"), context.StringValue("Empty.log")},
+		{context.StringValue("./resources/TestResultsWithProjections/single"), context.StringValue("TestResultsWithProjections_A.txt")},
+	}
+	executor.AssertMatch(t, expected, queryResults)
+}
+
+func TestResultsWithOverlappingRootsAreDeduplicated(t *testing.T) {
+	query := "select root, name from ./resources/TestResultsWithProjections/multi, ./resources/TestResultsWithProjections where like(name, .*.log) order by 2"
+	queryResults := executeWithOptions(t, query, executor.NewDefaultOptions())
+
+	expected := [][]context.Value{
+		{context.StringValue("./resources/TestResultsWithProjections"), context.StringValue("Empty.log")},
+		{context.StringValue("./resources/TestResultsWithProjections/multi"), context.StringValue("TestResultsWithProjections_A.log")},
+		{context.StringValue("./resources/TestResultsWithProjections/multi"), context.StringValue("TestResultsWithProjections_B.log")},
+	}
+	executor.AssertMatch(t, expected, queryResults)
+}
+
+func TestResultsWithOverlappingRootsAndParallelismAreDeduplicated(t *testing.T) {
+	query := "select root, name from ./resources/TestResultsWithProjections/multi, ./resources/TestResultsWithProjections where like(name, .*.log) order by 2"
+
+	expected := allRowsOf(executeWithOptions(t, query, executor.NewDefaultOptions()))
+	queryResults := executeWithOptions(t, query, executor.NewDefaultOptions().WithParallelism(3))
+
+	executor.AssertMatch(t, expected, queryResults)
+}
+
+func TestResultsWithAGlobPattern(t *testing.T) {
+	query := "select name, depth from ./resources/TestResultsWithProjections/**/*.txt order by 1"
+	queryResults := executeWithOptions(t, query, executor.NewDefaultOptions())
+
+	expected := [][]context.Value{
+		{context.StringValue("TestResultsWithProjections_A.txt"), context.IntValue(2)},
+		{context.StringValue("TestResultsWithProjections_C.txt"), context.IntValue(2)},
+		{context.StringValue("TestResultsWithProjections_D.txt"), context.IntValue(2)},
+	}
+	executor.AssertMatch(t, expected, queryResults)
+}
+
+func TestResultsWithAGlobPatternAndParallelism(t *testing.T) {
+	query := "select path from ./resources/TestResultsWithProjections/m*/*.log order by 1"
+
+	expected := allRowsOf(executeWithOptions(t, query, executor.NewDefaultOptions()))
+	queryResults := executeWithOptions(t, query, executor.NewDefaultOptions().WithParallelism(3))
+
+	if len(expected) != 2 {
+		t.Fatalf("Expected 2 rows, received %v", len(expected))
+	}
+	executor.AssertMatch(t, expected, queryResults)
+}
+
+func TestResultsWithAFileAsTheSource(t *testing.T) {
+	query := "select root, path, depth from ./resources/TestResultsWithProjections/single/TestResultsWithProjections_A.txt"
+	queryResults := executeWithOptions(t, query, executor.NewDefaultOptions())
+
+	expected := [][]context.Value{
+		{
+			context.StringValue("./resources/TestResultsWithProjections/single/TestResultsWithProjections_A.txt"),
+			context.StringValue("./resources/TestResultsWithProjections/single/TestResultsWithProjections_A.txt"),
+			context.IntValue(0),
+		},
+	}
+	executor.AssertMatch(t, expected, queryResults)
+}
+
+func TestResultsWithAnArchiveAsTheSource(t *testing.T) {
+	query := "select name, size, compressedsize, permission from ./resources/archive/README.md.zip"
+	queryResults := executeWithOptions(t, query, executor.NewDefaultOptions())
+
+	expected := [][]context.Value{
+		{context.StringValue("README.md"), context.Int64Value(25707), context.Int64Value(6462), context.StringValue("-rw-r--r--")},
+	}
+	executor.AssertMatch(t, expected, queryResults)
+}
@@ -0,0 +1,134 @@
+//go:build integration
+// +build integration
+
+package test
+
+import (
+	"goselect/parser"
+	"goselect/parser/context"
+	"goselect/parser/error/messages"
+	"goselect/parser/executor"
+	"testing"
+)
+
+func TestStreamingResultsMatchTheExecutedResults(t *testing.T) {
+	query := "select path, name, size from ./resources where eq(isdir, false)"
+
+	expected := allRowsOf(executeWithOptions(t, query, executor.NewDefaultOptions()))
+	queryResults := executeStreaming(t, query, executor.NewDefaultOptions())
+
+	assertStreamingMatch(t, expected, queryResults)
+}
+
+func TestStreamingResultsWithParallelismMatchTheExecutedResults(t *testing.T) {
+	query := "select path, name, size from ./resources"
+
+	expected := allRowsOf(executeWithOptions(t, query, executor.NewDefaultOptions()))
+	queryResults := executeStreaming(t, query, executor.NewDefaultOptions().WithParallelism(4))
+
+	assertStreamingMatch(t, expected, queryResults)
+}
+
+func TestStreamingResultsWithLimit(t *testing.T) {
+	queryResults := executeStreaming(t, "select name from ./resources limit 2", executor.NewDefaultOptions())
+
+	iterator := queryResults.RowIterator()
+	for iterator.HasNext() {
+		iterator.Next()
+	}
+	if queryResults.Count() != 2 {
+		t.Fatalf("Expected 2 rows, received %v", queryResults.Count())
+	}
+}
+
+func TestStreamingResultsWithAnError(t *testing.T) {
+	newContext := context.NewContext(context.NewFunctions(), context.NewAttributes())
+	aParser, err := parser.NewParser("select name from ./resources", newContext)
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	selectQuery, err := aParser.Parse()
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	selectQuery.Source.Roots[0].Directory = "./resources/non-existing"
+
+	queryResults, _ := executor.NewSelectQueryExecutor(selectQuery, newContext, executor.NewDefaultOptions()).ExecuteStreaming()
+	iterator := queryResults.RowIterator()
+	for iterator.HasNext() {
+		iterator.Next()
+	}
+	if queryResults.Err() == nil {
+		t.Fatalf("Expected an error while streaming the results of a non-existing directory")
+	}
+}
+
+func TestAttemptToStreamAQueryWithOrderBy(t *testing.T) {
+	newContext := context.NewContext(context.NewFunctions(), context.NewAttributes())
+	aParser, err := parser.NewParser("select name from ./resources order by 1", newContext)
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	selectQuery, err := aParser.Parse()
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	_, err = executor.NewSelectQueryExecutor(selectQuery, newContext, executor.NewDefaultOptions()).ExecuteStreaming()
+	if err == nil || err.Error() != messages.ErrorMessageStreamingNotSupported {
+		t.Fatalf("Expected an error %v while streaming a query with order by, received %v", messages.ErrorMessageStreamingNotSupported, err)
+	}
+}
+
+func TestAttemptToStreamAQueryWithAggregates(t *testing.T) {
+	newContext := context.NewContext(context.NewFunctions(), context.NewAttributes())
+	aParser, err := parser.NewParser("select count() from ./resources", newContext)
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	selectQuery, err := aParser.Parse()
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	if executor.NewSelectQueryExecutor(selectQuery, newContext, executor.NewDefaultOptions()).IsStreamable() {
+		t.Fatalf("Expected a query with aggregate functions to not be streamable")
+	}
+}
+
+func executeStreaming(t *testing.T, query string, options *executor.Options) *executor.StreamingRows {
+	newContext := context.NewContext(context.NewFunctions(), context.NewAttributes())
+	aParser, err := parser.NewParser(query, newContext)
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	selectQuery, err := aParser.Parse()
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	queryResults, err := executor.NewSelectQueryExecutor(selectQuery, newContext, options).ExecuteStreaming()
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	return queryResults
+}
+
+func assertStreamingMatch(t *testing.T, expected [][]context.Value, queryResults *executor.StreamingRows) {
+	actual := allRowsOf(queryResults)
+	if queryResults.Err() != nil {
+		t.Fatalf("error is %v", queryResults.Err())
+	}
+	if len(expected) != len(actual) {
+		t.Fatalf("Expected length of the query results to be %v, received %v", len(expected), len(actual))
+	}
+	for rowIndex, row := range expected {
+		for attributeIndex, attributeValue := range row {
+			if actual[rowIndex][attributeIndex].CompareTo(attributeValue) != 0 {
+				t.Fatalf("Expected %v to match %v at row index %v, attribute index %v",
+					attributeValue,
+					actual[rowIndex][attributeIndex],
+					rowIndex,
+					attributeIndex,
+				)
+			}
+		}
+	}
+}
@@ -73,3 +73,28 @@ func TestTableFormatterWithWidthOptions(t *testing.T) {
 		t.Fatalf("Expected Total Rows: 2 to be contained in the string but was not, received string is %v", str)
 	}
 }
+
+func TestTableFormatterWithPageSizeOnStreamingRows(t *testing.T) {
+	newContext := context.NewContext(context.NewFunctions(), context.NewAttributes())
+	aParser, err := parser.NewParser("select lower(name) from ./resources/TestResultsWithProjections/multi", newContext)
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	selectQuery, err := aParser.Parse()
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	queryResults, _ := executor.NewSelectQueryExecutor(selectQuery, newContext, executor.NewDefaultOptions()).ExecuteStreaming()
+
+	var builder strings.Builder
+	if err := writer.NewTableFormatter().WithPageSize(2).FormatTo(&builder, selectQuery.Projections, queryResults); err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	lower := strings.ToLower(builder.String())
+	if strings.Count(lower, "lower(name)") != 3 {
+		t.Fatalf("Expected lower(name) to be contained once per page in the string but was not, received string is %v", builder.String())
+	}
+	if !strings.Contains(lower, "rows: 4") {
+		t.Fatalf("Expected Total Rows: 4 to be contained in the string but was not, received string is %v", builder.String())
+	}
+}
@@ -20,6 +20,9 @@ const (
 	Numeric                = 11
 	FloatingPoint          = 12
 	Boolean                = 13
+	Group                  = 14
+	Having                 = 15
+	Operator               = 16
 )
 
 var numericRegexp, _ = regexp.Compile("^[-+]?(?:0|[1-9][0-9]*)$")
@@ -46,6 +49,10 @@ func tokenFrom(token string) Token {
 		return NewToken(From, token)
 	case casedToken == "where":
 		return NewToken(Where, token)
+	case casedToken == "group":
+		return NewToken(Group, token)
+	case casedToken == "having":
+		return NewToken(Having, token)
 	case casedToken == "order":
 		return NewToken(Order, token)
 	case casedToken == "by":
@@ -61,6 +68,21 @@ func tokenFrom(token string) Token {
 	}
 }
 
+func unquotedTokenFrom(token string) Token {
+	if isAnArithmeticOperator(token) {
+		return NewToken(Operator, token)
+	}
+	return tokenFrom(token)
+}
+
+func isAnArithmeticOperator(token string) bool {
+	return token == "+" || token == "-" || token == "*" || token == "/"
+}
+
+func isACharOfComparisonOperator(ch rune) bool {
+	return ch == '=' || ch == '<' || ch == '>' || ch == '!'
+}
+
 func determineTokenType(token string) int {
 	if numericRegexp.MatchString(token) {
 		return Numeric
@@ -78,6 +100,10 @@ func (token Token) Equals(value string) bool {
 	return strings.EqualFold(strings.ToLower(token.TokenValue), strings.ToLower(value))
 }
 
+func (token Token) IsAnOperator() bool {
+	return token.TokenType == Operator
+}
+
 func (token Token) isNumeric() bool {
 	return token.TokenType == Numeric
 }
@@ -12,16 +12,37 @@ func NewTokenizer(query string) *Tokenizer {
 	return &Tokenizer{query: query}
 }
 
+/*
+Tokenize splits the query into tokens. The comparison operators are split out of the unquoted words of the expressions,
+so that size>1000 is size, > and 1000, but never out of the source paths, and inside the arguments of a function
+only at the start of a word, so that the paths and the literals like a=b are kept intact.
+*/
 func (tokenizer *Tokenizer) Tokenize() *Tokens {
 	tokens := NewEmptyTokens()
 	queryLength := len(tokenizer.query)
 
 	var token strings.Builder
+	var functionCalls []bool
+	splitsOperatorsAt := func(index int) bool {
+		if tokens.isInSourceClause() {
+			return false
+		}
+		if len(functionCalls) > 0 && functionCalls[len(functionCalls)-1] && token.Len() > 0 {
+			return false
+		}
+		return tokenizer.isAComparisonOperatorAt(index)
+	}
 	for index := 0; index < queryLength; index++ {
 		ch := rune(tokenizer.query[index])
 		switch {
 		case isCharATokenSeparator(ch):
-			tokens.Add(tokenFrom(token.String()))
+			tokens.Add(unquotedTokenFrom(token.String()))
+			token.Reset()
+		case splitsOperatorsAt(index):
+			tokens.Add(unquotedTokenFrom(token.String()))
+			operator, newIndex := tokenizer.readComparisonOperatorFrom(index)
+			index = newIndex
+			tokens.Add(operator)
 			token.Reset()
 		case ch == '\\' && (index+1) < queryLength && tokenizer.query[index+1] == '\'':
 			literal, newIndex := tokenizer.readEmphasizedSingleQuotedLiteralFrom(index + 2)
@@ -34,37 +55,59 @@ func (tokenizer *Tokenizer) Tokenize() *Tokens {
 			tokens.Add(literal)
 			token.Reset()
 		case ch == '\'':
-			tokens.Add(tokenFrom(token.String()))
+			tokens.Add(unquotedTokenFrom(token.String()))
 			literal, newIndex := tokenizer.readSingleQuotedLiteralFrom(index + 1)
 			index = newIndex
 			tokens.Add(literal)
 			token.Reset()
 		case ch == '"':
-			tokens.Add(tokenFrom(token.String()))
+			tokens.Add(unquotedTokenFrom(token.String()))
 			literal, newIndex := tokenizer.readDoubleQuotedLiteralFrom(index + 1)
 			index = newIndex
 			tokens.Add(literal)
 			token.Reset()
 		case ch == ',':
-			tokens.Add(tokenFrom(token.String()))
+			tokens.Add(unquotedTokenFrom(token.String()))
 			tokens.Add(NewToken(Comma, string(ch)))
 			token.Reset()
 		case ch == '(':
-			tokens.Add(tokenFrom(token.String()))
+			functionCalls = append(functionCalls, token.Len() > 0 || tokens.endsWithAFunctionName())
+			tokens.Add(unquotedTokenFrom(token.String()))
 			tokens.Add(NewToken(OpeningParentheses, string(ch)))
 			token.Reset()
 		case ch == ')':
-			tokens.Add(tokenFrom(token.String()))
+			if len(functionCalls) > 0 {
+				functionCalls = functionCalls[:len(functionCalls)-1]
+			}
+			tokens.Add(unquotedTokenFrom(token.String()))
 			tokens.Add(NewToken(ClosingParentheses, string(ch)))
 			token.Reset()
 		default:
 			token.WriteRune(ch)
 		}
 	}
-	tokens.Add(tokenFrom(token.String()))
+	tokens.Add(unquotedTokenFrom(token.String()))
 	return tokens
 }
 
+func (tokenizer *Tokenizer) isAComparisonOperatorAt(index int) bool {
+	ch := rune(tokenizer.query[index])
+	if ch == '!' {
+		return index+1 < len(tokenizer.query) && tokenizer.query[index+1] == '='
+	}
+	return isACharOfComparisonOperator(ch)
+}
+
+func (tokenizer *Tokenizer) readComparisonOperatorFrom(index int) (Token, int) {
+	if index+1 < len(tokenizer.query) && tokenizer.query[index+1] == '=' && tokenizer.query[index] != '=' {
+		return NewToken(Operator, tokenizer.query[index:index+2]), index + 1
+	}
+	if tokenizer.query[index] == '<' && index+1 < len(tokenizer.query) && tokenizer.query[index+1] == '>' {
+		return NewToken(Operator, "!="), index + 1
+	}
+	return NewToken(Operator, tokenizer.query[index:index+1]), index
+}
+
 func (tokenizer *Tokenizer) readSingleQuotedLiteralFrom(index int) (Token, int) {
 	token, nextIndex := tokenizer.readQuotedLiteral(index, func(ch rune) bool {
 		return ch == '\''
@@ -108,6 +151,18 @@ func (tokenizer *Tokenizer) readQuotedLiteral(index int, breakOn func(ch rune) b
 	return token, runningIndex
 }
 
+/*
+eatBackSlash removes the backslashes that escape a quote or a whitespace, or end the literal before an escaped quote.
+The other backslashes are kept, so that a literal like 'TODO\(.*\)' can be used as a regular expression.
+*/
 func eatBackSlash(token strings.Builder) string {
-	return strings.ReplaceAll(token.String(), "\\", "")
+	literal := token.String()
+	var eaten strings.Builder
+	for index := 0; index < len(literal); index++ {
+		if literal[index] == '\\' && (index+1 == len(literal) || strings.ContainsRune(" \t'\"", rune(literal[index+1]))) {
+			continue
+		}
+		eaten.WriteByte(literal[index])
+	}
+	return eaten.String()
 }
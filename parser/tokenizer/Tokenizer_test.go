@@ -143,7 +143,7 @@ func TestTokenizerWithAllTokens8(t *testing.T) {
 	tokens := tokenizer.Tokenize()
 
 	iterator := tokens.Iterator()
-	expectedTokens := []string{"select", "size", "from", "/home/apps", "where", "name=*.txt", "order", "by", "modified"}
+	expectedTokens := []string{"select", "size", "from", "/home/apps", "where", "name", "=", "*.txt", "order", "by", "modified"}
 
 	for count := 1; count <= len(expectedTokens); count++ {
 		actualToken := iterator.Next()
@@ -159,7 +159,7 @@ func TestTokenizerWithAllTokens9(t *testing.T) {
 	tokens := tokenizer.Tokenize()
 
 	iterator := tokens.Iterator()
-	expectedTokens := []string{"select", "size", "from", "/home/apps", "where", "name=", "*.txt", "order", "by", "1"}
+	expectedTokens := []string{"select", "size", "from", "/home/apps", "where", "name", "=", "*.txt", "order", "by", "1"}
 
 	for count := 1; count <= len(expectedTokens); count++ {
 		actualToken := iterator.Next()
@@ -175,7 +175,7 @@ func TestTokenizerWithAllTokens10(t *testing.T) {
 	tokens := tokenizer.Tokenize()
 
 	iterator := tokens.Iterator()
-	expectedTokens := []string{"select", "size", "from", "/home/apps", "where", "name=", "*.txt", "order", "by", "1", "asc"}
+	expectedTokens := []string{"select", "size", "from", "/home/apps", "where", "name", "=", "*.txt", "order", "by", "1", "asc"}
 
 	for count := 1; count <= len(expectedTokens); count++ {
 		actualToken := iterator.Next()
@@ -191,7 +191,7 @@ func TestTokenizerWithAllTokens11(t *testing.T) {
 	tokens := tokenizer.Tokenize()
 
 	iterator := tokens.Iterator()
-	expectedTokens := []string{"select", "size", "from", "/home/apps", "where", "name=", "*.txt", "order", "by", "1", "desc"}
+	expectedTokens := []string{"select", "size", "from", "/home/apps", "where", "name", "=", "*.txt", "order", "by", "1", "desc"}
 
 	for count := 1; count <= len(expectedTokens); count++ {
 		actualToken := iterator.Next()
@@ -207,7 +207,7 @@ func TestTokenizerWithAllTokens12(t *testing.T) {
 	tokens := tokenizer.Tokenize()
 
 	iterator := tokens.Iterator()
-	expectedTokens := []string{"select", "1", "*", "2", ",", "name", "from", "/home/apps", "where", "size>1000"}
+	expectedTokens := []string{"select", "1", "*", "2", ",", "name", "from", "/home/apps", "where", "size", ">", "1000"}
 
 	for count := 1; count <= len(expectedTokens); count++ {
 		actualToken := iterator.Next()
@@ -460,3 +460,101 @@ func TestTokenEquality3(t *testing.T) {
 		t.Fatalf("Expected token equality to be false but was true")
 	}
 }
+
+func TestTokenizerWithComparisonOperators(t *testing.T) {
+	tokenizer := NewTokenizer("select name from . where size>=10 and size!=20 or size<>30 and name<='a'")
+	tokens := tokenizer.Tokenize()
+
+	iterator := tokens.Iterator()
+	expectedTokens := []string{"select", "name", "from", ".", "where", "size", ">=", "10", "and", "size", "!=", "20", "or", "size", "!=", "30", "and", "name", "<=", "a"}
+
+	for count := 1; count <= len(expectedTokens); count++ {
+		actualToken := iterator.Next()
+		expectedToken := expectedTokens[count-1]
+		if expectedToken != actualToken.TokenValue {
+			t.Fatalf("Expected token to be %v, received %v", expectedToken, actualToken)
+		}
+	}
+}
+
+func TestTokenizerWithArithmeticOperators(t *testing.T) {
+	tokenizer := NewTokenizer("select size * 2, size - 1, '-', -1 from /home/apps")
+	tokens := tokenizer.Tokenize()
+
+	iterator := tokens.Iterator()
+	expectedTokens := []Token{
+		NewToken(RawString, "select"),
+		NewToken(RawString, "size"),
+		NewToken(Operator, "*"),
+		NewToken(Numeric, "2"),
+		NewToken(Comma, ","),
+		NewToken(RawString, "size"),
+		NewToken(Operator, "-"),
+		NewToken(Numeric, "1"),
+		NewToken(Comma, ","),
+		NewToken(RawString, "-"),
+		NewToken(Comma, ","),
+		NewToken(Numeric, "-1"),
+		NewToken(From, "from"),
+		NewToken(RawString, "/home/apps"),
+	}
+
+	for count := 1; count <= len(expectedTokens); count++ {
+		actualToken := iterator.Next()
+		expectedToken := expectedTokens[count-1]
+		if expectedToken != actualToken {
+			t.Fatalf("Expected token to be %v, received %v", expectedToken, actualToken)
+		}
+	}
+}
+
+func TestTokenizerKeepsTheBackSlashesOfARegularExpressionInQuotes(t *testing.T) {
+	tokenizer := NewTokenizer("select path from . where grep(contents, 'TODO\\(.*\\)') or like(name, \"\\d+\\.log\")")
+	tokens := tokenizer.Tokenize()
+
+	iterator := tokens.Iterator()
+	expectedTokens := []string{"select", "path", "from", ".", "where", "grep", "(", "contents", ",", "TODO\\(.*\\)", ")", "or", "like", "(", "name", ",", "\\d+\\.log", ")"}
+
+	for count := 1; count <= len(expectedTokens); count++ {
+		actualToken := iterator.Next()
+		expectedToken := expectedTokens[count-1]
+
+		if expectedToken != actualToken.TokenValue {
+			t.Fatalf("Expected token to be %v, received %v", expectedToken, actualToken)
+		}
+	}
+}
+
+func TestTokenizerKeepsTheComparisonOperatorsInASourcePath(t *testing.T) {
+	tokenizer := NewTokenizer("select name from /tmp/a=b, ./c<d>e where size>10")
+	tokens := tokenizer.Tokenize()
+
+	iterator := tokens.Iterator()
+	expectedTokens := []string{"select", "name", "from", "/tmp/a=b", ",", "./c<d>e", "where", "size", ">", "10"}
+
+	for count := 1; count <= len(expectedTokens); count++ {
+		actualToken := iterator.Next()
+		expectedToken := expectedTokens[count-1]
+
+		if expectedToken != actualToken.TokenValue {
+			t.Fatalf("Expected token to be %v, received %v", expectedToken, actualToken)
+		}
+	}
+}
+
+func TestTokenizerKeepsTheComparisonOperatorsInsideAWordOfAFunctionArgument(t *testing.T) {
+	tokenizer := NewTokenizer("select name from . where eq(name, a=b) and (size>=10 or eq(size, >= 20))")
+	tokens := tokenizer.Tokenize()
+
+	iterator := tokens.Iterator()
+	expectedTokens := []string{"select", "name", "from", ".", "where", "eq", "(", "name", ",", "a=b", ")", "and", "(", "size", ">=", "10", "or", "eq", "(", "size", ",", ">=", "20", ")", ")"}
+
+	for count := 1; count <= len(expectedTokens); count++ {
+		actualToken := iterator.Next()
+		expectedToken := expectedTokens[count-1]
+
+		if expectedToken != actualToken.TokenValue {
+			t.Fatalf("Expected token to be %v, received %v", expectedToken, actualToken)
+		}
+	}
+}
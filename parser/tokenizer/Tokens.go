@@ -14,6 +14,33 @@ func (tokens *Tokens) Add(token Token) {
 	}
 }
 
+/*
+isInSourceClause returns true if the last keyword is from, where the tokens are the source paths.
+*/
+func (tokens *Tokens) isInSourceClause() bool {
+	for index := len(tokens.tokens) - 1; index >= 0; index-- {
+		switch tokens.tokens[index].TokenType {
+		case From:
+			return true
+		case Where, Group, Having, Order, Limit:
+			return false
+		}
+	}
+	return false
+}
+
+/*
+endsWithAFunctionName returns true if the last token can be the name of a function called with a space before the
+parentheses, that is a word other than the logical operators which are followed by a parenthesized expression.
+*/
+func (tokens *Tokens) endsWithAFunctionName() bool {
+	if len(tokens.tokens) == 0 {
+		return false
+	}
+	last := tokens.tokens[len(tokens.tokens)-1]
+	return last.TokenType == RawString && !last.Equals("and") && !last.Equals("or") && !last.Equals("not")
+}
+
 func (tokens *Tokens) count() int {
 	return len(tokens.tokens)
 }
@@ -41,3 +68,11 @@ func (tokenIterator *TokenIterator) Peek() Token {
 	token := tokenIterator.tokens[tokenIterator.index]
 	return token
 }
+
+func (tokenIterator *TokenIterator) PeekAt(offset int) (Token, bool) {
+	index := tokenIterator.index + offset
+	if index < len(tokenIterator.tokens) {
+		return tokenIterator.tokens[index], true
+	}
+	return Token{}, false
+}
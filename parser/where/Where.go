@@ -2,7 +2,6 @@ package where
 
 import (
 	"errors"
-	"fmt"
 	"goselect/parser/context"
 	"goselect/parser/error/messages"
 	"goselect/parser/expression"
@@ -51,12 +50,24 @@ func (where Where) EvaluateWith(
 	return true, nil
 }
 
+func (where Where) UpperBoundOf(attribute string, functions *context.AllFunctions) (int64, bool) {
+	if expr := where.expressions.ExpressionAt(0); expr != nil {
+		return expr.UpperBoundOf(attribute, functions)
+	}
+	return 0, false
+}
+
+/*
+where:       a single function supported in the 'where' clause Or an expression Or a boolean attribute
+functions:   eq(ext, .log), and(gt(size, 1024), eq(ext, .log)) etc
+attributes:  issetuid, isdir etc
+expressions: size > 1024 and ext = .log, not isdir, name like .*log etc
+*/
 func all(
 	tokenIterator *tokenizer.TokenIterator,
 	ctx *context.ParsingApplicationContext,
 ) (expression.Expressions, bool, error) {
 
-	var expressions []*expression.Expression
 	if !tokenIterator.HasNext() {
 		return expression.Expressions{}, false, nil
 	}
@@ -66,81 +77,23 @@ func all(
 	if tokenIterator.HasNext() && tokenIterator.Peek().Equals("where") {
 		tokenIterator.Next()
 	}
-	for tokenIterator.HasNext() &&
-		!tokenIterator.Peek().Equals("order") &&
-		!tokenIterator.Peek().Equals("limit") {
-
-		token := tokenIterator.Next()
-		switch {
-		case ctx.IsASupportedFunction(token.TokenValue) && ctx.FunctionContainsATag(token.TokenValue, "where"):
-			if function, err := function(token, tokenIterator, ctx); err != nil {
-				return expression.Expressions{}, true, err
-			} else {
-				expressions = append(expressions, expression.WithFunctionInstance(function))
-			}
-		default:
-			return expression.Expressions{}, true, errors.New(messages.ErrorMessageInvalidWhereFunctionUsed)
-		}
+	parser := expression.NewExpressionParser(
+		tokenIterator,
+		ctx,
+		[]string{"group", "having", "order", "limit"},
+		messages.ErrorMessageInvalidWhere,
+		messages.ErrorMessageAggregateFunctionInsideWhere,
+	)
+	if parser.IsAtTerminal() {
+		return expression.Expressions{}, true, nil
 	}
-	return expression.Expressions{Expressions: expressions}, true, nil
-}
-
-func function(
-	functionNameToken tokenizer.Token,
-	tokenIterator *tokenizer.TokenIterator,
-	ctx *context.ParsingApplicationContext,
-) (*expression.FunctionInstance, error) {
-
-	var parseFunction func(functionNameToken tokenizer.Token) (*expression.FunctionInstance, error)
-	parseFunction = func(functionNameToken tokenizer.Token) (*expression.FunctionInstance, error) {
-		var functionArgs []*expression.Expression
-		expectOpeningParentheses := true
-
-		for tokenIterator.HasNext() && !tokenIterator.Peek().Equals("order") {
-			token := tokenIterator.Next()
-			switch {
-			case expectOpeningParentheses:
-				if !token.Equals("(") {
-					return nil, fmt.Errorf(messages.ErrorMessageOpeningParenthesesProjection, functionNameToken.TokenValue)
-				}
-				expectOpeningParentheses = false
-			case token.Equals(")"):
-				return expression.FunctionInstanceWith(
-					functionNameToken.TokenValue,
-					functionArgs,
-					nil,
-					false,
-				), nil
-			case ctx.IsASupportedFunction(token.TokenValue):
-				if ctx.IsAnAggregateFunction(token.TokenValue) {
-					return nil, errors.New(messages.ErrorMessageAggregateFunctionInsideWhere)
-				}
-				fn, err := parseFunction(token)
-				if err != nil {
-					return nil, err
-				}
-				functionArgs = append(functionArgs, expression.WithFunctionInstance(fn))
-			case ctx.IsASupportedAttribute(token.TokenValue):
-				functionArgs = append(functionArgs, expression.WithAttribute(token.TokenValue))
-				expectOpeningParentheses = false
-			default:
-				if !token.Equals(",") {
-					value, err := context.ToValue(token)
-					if err != nil {
-						value = context.StringValue(token.TokenValue)
-					}
-					functionArgs = append(functionArgs, expression.WithValue(value))
-				}
-			}
-		}
-		return nil, nil
+	anExpression, err := parser.Parse()
+	if err != nil {
+		return expression.Expressions{}, true, err
 	}
-
-	if fn, err := parseFunction(functionNameToken); err != nil {
-		return nil, err
-	} else if fn == nil {
-		return nil, errors.New(messages.ErrorMessageInvalidWhere)
-	} else {
-		return fn, nil
+	isACondition := anExpression.IsAFunctionWithTag(ctx, "where") || anExpression.IsABooleanAttribute(ctx)
+	if !isACondition || !parser.IsAtTerminal() {
+		return expression.Expressions{}, true, errors.New(messages.ErrorMessageInvalidWhereFunctionUsed)
 	}
+	return expression.Expressions{Expressions: []*expression.Expression{anExpression}}, true, nil
 }
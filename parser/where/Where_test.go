@@ -5,7 +5,9 @@ package where
 
 import (
 	"goselect/parser/context"
+	"goselect/parser/error/messages"
 	"goselect/parser/tokenizer"
+	"os"
 	"testing"
 )
 
@@ -401,3 +403,79 @@ func TestEvaluatesWhereWithoutAnyCondition(t *testing.T) {
 		t.Fatalf("Expected where clause to evaluate to true but it did not")
 	}
 }
+
+func TestUpperBoundOfDepthWithLessThanEqual(t *testing.T) {
+	assertUpperBoundOfDepth(t, "where le(depth, 2)", 2, true)
+}
+
+func TestUpperBoundOfDepthWithLessThan(t *testing.T) {
+	assertUpperBoundOfDepth(t, "where lt(depth, 3)", 2, true)
+}
+
+func TestUpperBoundOfDepthWithTheAttributeOnTheRight(t *testing.T) {
+	assertUpperBoundOfDepth(t, "where gte(3, depth)", 3, true)
+}
+
+func TestUpperBoundOfDepthWithAnOperator(t *testing.T) {
+	assertUpperBoundOfDepth(t, "where depth <= 2 and ext = .log", 2, true)
+}
+
+func TestUpperBoundOfDepthWithTheSmallestBoundInAnd(t *testing.T) {
+	assertUpperBoundOfDepth(t, "where and(le(depth, 4), eq(isdir, false), lt(depth, 2))", 1, true)
+}
+
+func TestUpperBoundOfDepthWithOr(t *testing.T) {
+	assertUpperBoundOfDepth(t, "where or(le(depth, 2), eq(ext, .log))", 0, false)
+}
+
+func TestUpperBoundOfDepthWithALowerBound(t *testing.T) {
+	assertUpperBoundOfDepth(t, "where gt(depth, 2)", 0, false)
+}
+
+func TestUpperBoundOfDepthWithoutDepth(t *testing.T) {
+	assertUpperBoundOfDepth(t, "where le(size, 2)", 0, false)
+}
+
+func assertUpperBoundOfDepth(t *testing.T, clause string, expectedBound int64, expectedBounded bool) {
+	functions := context.NewFunctions()
+	where, err := NewWhere(tokenizer.NewTokenizer(clause).Tokenize().Iterator(), context.NewContext(functions, context.NewAttributes()))
+	if err != nil {
+		t.Fatalf("error is %v", err)
+	}
+	bound, bounded := where.UpperBoundOf(context.AttributeDepth, functions)
+	if bounded != expectedBounded || bound != expectedBound {
+		t.Fatalf("Expected upper bound of depth to be (%v, %v), received (%v, %v)", expectedBound, expectedBounded, bound, bounded)
+	}
+}
+
+func TestWhereWithABooleanAttribute(t *testing.T) {
+	tokens := tokenizer.NewEmptyTokens()
+	tokens.Add(tokenizer.NewToken(tokenizer.RawString, "where"))
+	tokens.Add(tokenizer.NewToken(tokenizer.RawString, "isdir"))
+
+	functions := context.NewFunctions()
+	applicationContext := context.NewContext(functions, context.NewAttributes())
+	where, err := NewWhere(tokens.Iterator(), applicationContext)
+	if err != nil {
+		t.Fatalf("Expected no error given a boolean attribute in where clause, received %v", err)
+	}
+	file, err := os.Stat("../test/resources/TestResultsWithProjections/single")
+	if err != nil {
+		panic(err)
+	}
+	passesWhere, _ := where.EvaluateWith(context.ToFileAttributes("../test/resources/TestResultsWithProjections/", file, applicationContext), functions)
+	if !passesWhere {
+		t.Fatalf("Expected a directory to pass the where clause isdir")
+	}
+}
+
+func TestWhereWithANonBooleanAttribute(t *testing.T) {
+	tokens := tokenizer.NewEmptyTokens()
+	tokens.Add(tokenizer.NewToken(tokenizer.RawString, "where"))
+	tokens.Add(tokenizer.NewToken(tokenizer.RawString, "name"))
+
+	_, err := NewWhere(tokens.Iterator(), context.NewContext(context.NewFunctions(), context.NewAttributes()))
+	if err == nil || err.Error() != messages.ErrorMessageInvalidWhereFunctionUsed {
+		t.Fatalf("Expected the error %v given a non boolean attribute in where clause, received %v", messages.ErrorMessageInvalidWhereFunctionUsed, err)
+	}
+}
@@ -6,6 +6,7 @@ package writer
 import (
 	"bytes"
 	"errors"
+	"io"
 	"testing"
 )
 
@@ -29,6 +30,31 @@ func TestConsoleWriterWithAnError(t *testing.T) {
 	}
 }
 
+func TestConsoleWriterUsingAFormat(t *testing.T) {
+	backingWriter := new(bytes.Buffer)
+	_ = NewWriter(backingWriter).WriteUsing(func(writer io.Writer) error {
+		_, err := io.WriteString(writer, "[{\"a\": \"b\"}]")
+		return err
+	})
+
+	op := backingWriter.String()
+	expected := "[{\"a\": \"b\"}]\n"
+
+	if expected != op {
+		t.Fatalf("Expected console writer to write %v, received %v", expected, op)
+	}
+}
+
+func TestConsoleWriterUsingAFormatWithAnError(t *testing.T) {
+	err := NewWriter(new(bytes.Buffer)).WriteUsing(func(writer io.Writer) error {
+		return errors.New("test throws an error")
+	})
+
+	if err == nil {
+		t.Fatalf("Expected an error while writing using console writer but received none")
+	}
+}
+
 type alwaysThrowErrorWriter struct {
 }
 
@@ -3,8 +3,23 @@ package writer
 import (
 	"goselect/parser/executor"
 	"goselect/parser/projection"
+	"io"
+	"strings"
 )
 
 type Formatter interface {
-	Format(projections *projection.Projections, rows *executor.EvaluatingRows) string
+	Format(projections *projection.Projections, rows executor.Rows) string
+	FormatTo(writer io.Writer, projections *projection.Projections, rows executor.Rows) error
+}
+
+func formatAsString(formatter Formatter, projections *projection.Projections, rows executor.Rows) string {
+	var result = new(strings.Builder)
+	_ = formatter.FormatTo(result, projections, rows)
+	return result.String()
+}
+
+func flush(builder *strings.Builder, writer io.Writer) error {
+	_, err := io.WriteString(writer, builder.String())
+	builder.Reset()
+	return err
 }
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"goselect/parser/executor"
 	"goselect/parser/projection"
+	"io"
 	"strings"
 )
 
@@ -13,19 +14,28 @@ func NewHtmlFormatter() *HtmlFormatter {
 	return &HtmlFormatter{}
 }
 
-func (htmlFormatter HtmlFormatter) Format(projections *projection.Projections, rows *executor.EvaluatingRows) string {
+func (htmlFormatter HtmlFormatter) Format(projections *projection.Projections, rows executor.Rows) string {
+	return formatAsString(htmlFormatter, projections, rows)
+}
+
+func (htmlFormatter HtmlFormatter) FormatTo(writer io.Writer, projections *projection.Projections, rows executor.Rows) error {
 	var result = new(strings.Builder)
 	htmlFormatter.beginHtml(result)
 	htmlFormatter.beginBody(result)
 	htmlFormatter.beginTable(result)
 	htmlFormatter.beginTableHeader(result, projections)
-	htmlFormatter.beginTableContent(result, rows)
+	if err := flush(result, writer); err != nil {
+		return err
+	}
+	if err := htmlFormatter.beginTableContent(result, rows, writer); err != nil {
+		return err
+	}
 	htmlFormatter.beginFooterRow(result, projections, rows)
 	htmlFormatter.closeTable(result)
 	htmlFormatter.closeBody(result)
 	htmlFormatter.closeHtml(result)
 
-	return result.String()
+	return flush(result, writer)
 }
 
 func (htmlFormatter HtmlFormatter) beginHtml(html *strings.Builder) {
@@ -48,7 +58,7 @@ func (htmlFormatter HtmlFormatter) beginTableHeader(html *strings.Builder, proje
 	htmlFormatter.closeRow(html)
 }
 
-func (htmlFormatter HtmlFormatter) beginTableContent(html *strings.Builder, rows *executor.EvaluatingRows) {
+func (htmlFormatter HtmlFormatter) beginTableContent(html *strings.Builder, rows executor.Rows, writer io.Writer) error {
 	iterator := rows.RowIterator()
 	for iterator.HasNext() {
 		row := iterator.Next()
@@ -57,10 +67,14 @@ func (htmlFormatter HtmlFormatter) beginTableContent(html *strings.Builder, rows
 			htmlFormatter.writeColumnContent(html, attribute.GetAsString())
 		}
 		htmlFormatter.closeRow(html)
+		if err := flush(html, writer); err != nil {
+			return err
+		}
 	}
+	return nil
 }
 
-func (htmlFormatter HtmlFormatter) beginFooterRow(html *strings.Builder, projections *projection.Projections, rows *executor.EvaluatingRows) {
+func (htmlFormatter HtmlFormatter) beginFooterRow(html *strings.Builder, projections *projection.Projections, rows executor.Rows) {
 	htmlFormatter.beginRow(html)
 	html.WriteString(fmt.Sprintf("<td colspan=\"%v\" style=\"border: 1px solid black\">", projections.Count()))
 	html.WriteString(fmt.Sprintf("Rows: %v", rows.Count()))
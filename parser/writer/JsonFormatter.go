@@ -4,6 +4,7 @@ import (
 	"goselect/parser/context"
 	"goselect/parser/executor"
 	"goselect/parser/projection"
+	"io"
 	"strings"
 )
 
@@ -13,7 +14,11 @@ func NewJsonFormatter() *JsonFormatter {
 	return &JsonFormatter{}
 }
 
-func (jsonFormatter JsonFormatter) Format(projections *projection.Projections, rows *executor.EvaluatingRows) string {
+func (jsonFormatter JsonFormatter) Format(projections *projection.Projections, rows executor.Rows) string {
+	return formatAsString(jsonFormatter, projections, rows)
+}
+
+func (jsonFormatter JsonFormatter) FormatTo(writer io.Writer, projections *projection.Projections, rows executor.Rows) error {
 
 	attributeNameAsString := func(attribute string) string {
 		var value strings.Builder
@@ -31,7 +36,7 @@ func (jsonFormatter JsonFormatter) Format(projections *projection.Projections, r
 
 		return value.String()
 	}
-	buildJson := func() string {
+	buildJson := func() error {
 		attributes := projections.DisplayableAttributes()
 		var json = new(strings.Builder)
 		jsonFormatter.begin(json)
@@ -40,6 +45,9 @@ func (jsonFormatter JsonFormatter) Format(projections *projection.Projections, r
 
 		for rowIndex := uint32(0); iterator.HasNext(); rowIndex++ {
 			row := iterator.Next()
+			if rowIndex != 0 {
+				jsonFormatter.writeSeparator(json)
+			}
 			jsonFormatter.beginRow(json)
 			for attributeIndex, attributeValue := range row.AllAttributes() {
 				jsonFormatter.writeAttribute(json, attributeNameAsString(attributes[attributeIndex]), attributeValueAsString(attributeValue))
@@ -48,12 +56,12 @@ func (jsonFormatter JsonFormatter) Format(projections *projection.Projections, r
 				}
 			}
 			jsonFormatter.closeRow(json)
-			if rowIndex != rows.Count()-1 {
-				jsonFormatter.writeSeparator(json)
+			if err := flush(json, writer); err != nil {
+				return err
 			}
 		}
 		jsonFormatter.end(json)
-		return json.String()
+		return flush(json, writer)
 	}
 	return buildJson()
 }
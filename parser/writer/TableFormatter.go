@@ -7,6 +7,7 @@ import (
 	"github.com/jedib0t/go-pretty/v6/table"
 	"goselect/parser/executor"
 	"goselect/parser/projection"
+	"io"
 )
 
 const (
@@ -19,6 +20,7 @@ const (
 type TableFormatter struct {
 	tableWriter table.Writer
 	options     *AttributeWidthOptions
+	pageSize    int
 }
 
 type AttributeWidthOptions struct {
@@ -38,24 +40,55 @@ func NewTableFormatter() *TableFormatter {
 }
 
 func NewTableFormatterWithWidthOptions(attributeWidthOptions *AttributeWidthOptions) *TableFormatter {
+	return &TableFormatter{
+		tableWriter: newTableWriter(),
+		options:     attributeWidthOptions,
+	}
+}
+
+func newTableWriter() table.Writer {
 	var buffer bytes.Buffer
 	tableWriter := table.NewWriter()
 	tableWriter.SetOutputMirror(bufio.NewWriter(&buffer))
 	tableWriter.SetStyle(table.StyleColoredBlackOnCyanWhite)
 	tableWriter.Style().Options.SeparateColumns = true
+	return tableWriter
+}
 
-	return &TableFormatter{
-		tableWriter: tableWriter,
-		options:     attributeWidthOptions,
-	}
+func (tableFormatter *TableFormatter) WithPageSize(pageSize int) *TableFormatter {
+	tableFormatter.pageSize = pageSize
+	return tableFormatter
 }
 
-func (tableFormatter *TableFormatter) Format(projections *projection.Projections, rows *executor.EvaluatingRows) string {
+func (tableFormatter *TableFormatter) Format(projections *projection.Projections, rows executor.Rows) string {
+	return formatAsString(tableFormatter, projections, rows)
+}
+
+/*
+Without a page size, all the rows are rendered as a single table.
+With a page size, every page of rows is rendered as a separate table as soon as it is available,
+and the last page carries the footer.
+*/
+func (tableFormatter *TableFormatter) FormatTo(writer io.Writer, projections *projection.Projections, rows executor.Rows) error {
 	tableFormatter.addHeader(projections)
-	tableFormatter.addContent(rows)
+	rowsInPage, iterator := 0, rows.RowIterator()
+	for iterator.HasNext() {
+		tableFormatter.addRow(iterator.Next())
+		rowsInPage = rowsInPage + 1
+
+		if tableFormatter.pageSize > 0 && rowsInPage == tableFormatter.pageSize {
+			if _, err := io.WriteString(writer, tableFormatter.tableWriter.Render()+"\n"); err != nil {
+				return err
+			}
+			tableFormatter.tableWriter = newTableWriter()
+			tableFormatter.addHeader(projections)
+			rowsInPage = 0
+		}
+	}
 	tableFormatter.addFooter(rows)
 
-	return tableFormatter.tableWriter.Render()
+	_, err := io.WriteString(writer, tableFormatter.tableWriter.Render())
+	return err
 }
 
 func (tableFormatter *TableFormatter) addHeader(projections *projection.Projections) {
@@ -82,18 +115,15 @@ func (tableFormatter *TableFormatter) addHeader(projections *projection.Projecti
 	tableFormatter.tableWriter.AppendHeader(attributes)
 }
 
-func (tableFormatter *TableFormatter) addContent(rows *executor.EvaluatingRows) {
-	iterator := rows.RowIterator()
-	for iterator.HasNext() {
-		var attributes []interface{}
-		for _, attribute := range iterator.Next().AllAttributes() {
-			attributes = append(attributes, attribute.GetAsString())
-		}
-		tableFormatter.tableWriter.AppendRow(attributes)
+func (tableFormatter *TableFormatter) addRow(row *executor.EvaluatingRow) {
+	var attributes []interface{}
+	for _, attribute := range row.AllAttributes() {
+		attributes = append(attributes, attribute.GetAsString())
 	}
+	tableFormatter.tableWriter.AppendRow(attributes)
 }
 
-func (tableFormatter *TableFormatter) addFooter(rows *executor.EvaluatingRows) {
+func (tableFormatter *TableFormatter) addFooter(rows executor.Rows) {
 	tableFormatter.tableWriter.AppendFooter(table.Row{fmt.Sprintf("Rows: %v", rows.Count())})
 }
 
@@ -8,6 +8,7 @@ import (
 
 type Writer interface {
 	Write(result string) error
+	WriteUsing(format func(writer io.Writer) error) error
 }
 
 type ConsoleWriter struct {
@@ -39,6 +40,16 @@ func (writer ConsoleWriter) Write(result string) error {
 	return nil
 }
 
+func (writer ConsoleWriter) WriteUsing(format func(writer io.Writer) error) error {
+	if err := format(writer.backingWriter); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintln(writer.backingWriter); err != nil {
+		return err
+	}
+	return nil
+}
+
 func (writer FileWriter) Write(result string) error {
 	if _, err := writer.file.WriteString(result); err != nil {
 		return err
@@ -46,3 +57,11 @@ func (writer FileWriter) Write(result string) error {
 	_ = writer.file.Sync()
 	return nil
 }
+
+func (writer FileWriter) WriteUsing(format func(writer io.Writer) error) error {
+	if err := format(writer.file); err != nil {
+		return err
+	}
+	_ = writer.file.Sync()
+	return nil
+}
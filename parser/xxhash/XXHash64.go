@@ -0,0 +1,138 @@
+package xxhash
+
+import (
+	"encoding/binary"
+	"hash"
+	"math/bits"
+)
+
+const (
+	prime1 uint64 = 11400714785074694791
+	prime2 uint64 = 14029467366897019727
+	prime3 uint64 = 1609587929392839161
+	prime4 uint64 = 9650029242287828579
+	prime5 uint64 = 2870177450012600261
+)
+
+const (
+	Size      = 8
+	BlockSize = 32
+)
+
+/*
+Digest computes the 64 bit xxHash (XXH64) of the data written to it, with a seed of 0.
+It is not a cryptographic hash, it is used to compare the contents of the files quickly.
+*/
+type Digest struct {
+	accumulators [4]uint64
+	total        uint64
+	buffer       [BlockSize]byte
+	buffered     int
+}
+
+func New() hash.Hash64 {
+	digest := &Digest{}
+	digest.Reset()
+	return digest
+}
+
+func Sum64(data []byte) uint64 {
+	digest := New()
+	_, _ = digest.Write(data)
+	return digest.Sum64()
+}
+
+func (digest *Digest) Reset() {
+	var seed uint64
+	digest.accumulators = [4]uint64{seed + prime1 + prime2, seed + prime2, seed, seed - prime1}
+	digest.total = 0
+	digest.buffered = 0
+}
+
+func (digest *Digest) Size() int {
+	return Size
+}
+
+func (digest *Digest) BlockSize() int {
+	return BlockSize
+}
+
+func (digest *Digest) Write(data []byte) (int, error) {
+	length := len(data)
+	digest.total = digest.total + uint64(length)
+	if digest.buffered+len(data) < BlockSize {
+		digest.buffered = digest.buffered + copy(digest.buffer[digest.buffered:], data)
+		return length, nil
+	}
+	if digest.buffered > 0 {
+		copied := copy(digest.buffer[digest.buffered:], data)
+		digest.consume(digest.buffer[:])
+		data = data[copied:]
+		digest.buffered = 0
+	}
+	for ; len(data) >= BlockSize; data = data[BlockSize:] {
+		digest.consume(data[0:BlockSize])
+	}
+	digest.buffered = copy(digest.buffer[:], data)
+	return length, nil
+}
+
+func (digest *Digest) Sum(data []byte) []byte {
+	var sum [Size]byte
+	binary.BigEndian.PutUint64(sum[:], digest.Sum64())
+	return append(data, sum[:]...)
+}
+
+func (digest *Digest) Sum64() uint64 {
+	var result uint64
+	if digest.total >= BlockSize {
+		accumulators := digest.accumulators
+		result = bits.RotateLeft64(accumulators[0], 1) + bits.RotateLeft64(accumulators[1], 7) +
+			bits.RotateLeft64(accumulators[2], 12) + bits.RotateLeft64(accumulators[3], 18)
+		for _, accumulator := range accumulators {
+			result = mergeRound(result, accumulator)
+		}
+	} else {
+		result = prime5
+	}
+	result = result + digest.total
+
+	remaining := digest.buffer[0:digest.buffered]
+	for ; len(remaining) >= 8; remaining = remaining[8:] {
+		result = result ^ round(0, binary.LittleEndian.Uint64(remaining))
+		result = bits.RotateLeft64(result, 27)*prime1 + prime4
+	}
+	if len(remaining) >= 4 {
+		result = result ^ uint64(binary.LittleEndian.Uint32(remaining))*prime1
+		result = bits.RotateLeft64(result, 23)*prime2 + prime3
+		remaining = remaining[4:]
+	}
+	for _, value := range remaining {
+		result = result ^ uint64(value)*prime5
+		result = bits.RotateLeft64(result, 11) * prime1
+	}
+
+	result = result ^ (result >> 33)
+	result = result * prime2
+	result = result ^ (result >> 29)
+	result = result * prime3
+	result = result ^ (result >> 32)
+	return result
+}
+
+func (digest *Digest) consume(block []byte) {
+	for index := range digest.accumulators {
+		digest.accumulators[index] = round(digest.accumulators[index], binary.LittleEndian.Uint64(block[index*8:]))
+	}
+}
+
+func round(accumulator, input uint64) uint64 {
+	accumulator = accumulator + input*prime2
+	accumulator = bits.RotateLeft64(accumulator, 31)
+	return accumulator * prime1
+}
+
+func mergeRound(result, accumulator uint64) uint64 {
+	result = result ^ round(0, accumulator)
+	return result*prime1 + prime4
+}
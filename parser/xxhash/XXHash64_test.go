@@ -0,0 +1,49 @@
+//go:build unit
+// +build unit
+
+package xxhash
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSum64(t *testing.T) {
+	expected := map[string]uint64{
+		"":    0xef46db3751d8e999,
+		"a":   0xd24ec4f1a98c6e5b,
+		"abc": 0x44bc2cf5ad770999,
+		"Nobody inspects the spammish repetition": 0xfbcea83c8a378bf1,
+	}
+	for input, expectedSum := range expected {
+		if sum := Sum64([]byte(input)); sum != expectedSum {
+			t.Fatalf("Expected xxhash of %q to be %x, received %x", input, expectedSum, sum)
+		}
+	}
+}
+
+func TestSum64IsTheSameForAllTheWrites(t *testing.T) {
+	data := []byte(strings.Repeat("goselect queries the files like sql. ", 20))
+	expected := Sum64(data)
+	for _, chunkSize := range []int{1, 3, 7, 31, 32, 33, 100} {
+		digest := New()
+		for start := 0; start < len(data); start = start + chunkSize {
+			end := start + chunkSize
+			if end > len(data) {
+				end = len(data)
+			}
+			_, _ = digest.Write(data[start:end])
+		}
+		if sum := digest.Sum64(); sum != expected {
+			t.Fatalf("Expected xxhash written in chunks of %v to be %x, received %x", chunkSize, expected, sum)
+		}
+	}
+}
+
+func TestSum(t *testing.T) {
+	digest := New()
+	_, _ = digest.Write([]byte("abc"))
+	if sum := digest.Sum(nil); len(sum) != Size || sum[0] != 0x44 || sum[7] != 0x99 {
+		t.Fatalf("Expected the sum to be big endian bytes of %x, received %x", uint64(0x44bc2cf5ad770999), sum)
+	}
+}